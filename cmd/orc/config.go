@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/runner"
+	cli "github.com/urfave/cli/v3"
+)
+
+func configCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "config",
+		Usage: "Print the workflow config",
+		Description: "Prints the config file as written. With --resolved, prints the config after\n" +
+			"validation has filled in defaults (model, effort, timeout, loop.min, ...),\n" +
+			"as canonical YAML showing every effective value.",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "resolved", Usage: "Print the validated config with defaults applied"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			cfgErr := func(err error) error {
+				return &runner.ExitError{Code: runner.ExitConfigError, Err: err}
+			}
+
+			projectRoot, err := findProjectRoot()
+			if err != nil {
+				return cfgErr(err)
+			}
+			_, configPath, err := resolveWorkflow(projectRoot, cmd.Root().String("workflow"))
+			if err != nil {
+				return cfgErr(err)
+			}
+			if err := printConfig(os.Stdout, configPath, projectRoot, cmd.Bool("resolved")); err != nil {
+				return cfgErr(err)
+			}
+			return nil
+		},
+	}
+}
+
+// printConfig writes the config at configPath to w: verbatim, or — when
+// resolved is set — validated and re-marshaled so defaults are visible.
+func printConfig(w io.Writer, configPath, projectRoot string, resolved bool) error {
+	if !resolved {
+		data, err := os.ReadFile(configPath)
+		if err != nil {
+			return fmt.Errorf("reading config: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	cfg, err := config.Load(configPath, projectRoot)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	out, err := config.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("marshaling config: %w", err)
+	}
+	_, err = w.Write(out)
+	return err
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/debug"
+	"github.com/jorge-barreto/orc/internal/runner"
+	"github.com/jorge-barreto/orc/internal/state"
+	"github.com/jorge-barreto/orc/internal/ux"
+	cli "github.com/urfave/cli/v3"
+)
+
+func getCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "get",
+		Usage:     "Print or copy a ticket's deliverables",
+		ArgsUsage: "[ticket]",
+		UsageText: "orc get\n   orc get PROJ-123\n   orc get PROJ-123 --copy ./results",
+		Description: "Finds the files listed under the config's top-level 'deliverables' in the\n" +
+			"ticket's latest run — the live artifacts directory, or the newest archived\n" +
+			"run once a completed run has been moved to history — and prints their\n" +
+			"absolute paths, one per line. --copy copies them into a directory instead,\n" +
+			"keeping their paths relative to the artifacts directory.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "copy", Usage: "Copy the deliverables into this directory"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			cfgErr := func(err error) error {
+				return &runner.ExitError{Code: runner.ExitConfigError, Err: err}
+			}
+
+			projectRoot, err := findProjectRoot()
+			if err != nil {
+				return cfgErr(err)
+			}
+			workflowName, configPath, err := resolveWorkflow(projectRoot, cmd.Root().String("workflow"))
+			if err != nil {
+				return cfgErr(err)
+			}
+			cfg, err := config.Load(configPath, projectRoot)
+			if err != nil {
+				return cfgErr(fmt.Errorf("loading config: %w", err))
+			}
+			if len(cfg.Deliverables) == 0 {
+				return cfgErr(fmt.Errorf("no deliverables declared — list the files that matter under a top-level 'deliverables' key"))
+			}
+			ticket := cmd.Args().First()
+			if ticket == "" {
+				ticket, err = debug.FindMostRecentTicket(projectRoot, workflowName)
+				if err != nil {
+					return cfgErr(err)
+				}
+			}
+			if err := validateTicketPath(ticket); err != nil {
+				return cfgErr(err)
+			}
+
+			artifactsDir := state.ArtifactsDirForWorkflow(projectRoot, workflowName, ticket)
+			runDir, found, missing, err := findDeliverables(artifactsDir, cfg.DeliverablePaths())
+			if err != nil {
+				return err
+			}
+			for _, m := range missing {
+				fmt.Fprintf(os.Stderr, "%swarning:%s deliverable %s not found\n", ux.Yellow, ux.Reset, m)
+			}
+			if len(found) == 0 {
+				return fmt.Errorf("no deliverables found for %s", ticket)
+			}
+
+			dest := cmd.String("copy")
+			for _, rel := range found {
+				src := filepath.Join(runDir, rel)
+				if dest == "" {
+					fmt.Println(src)
+					continue
+				}
+				dst := filepath.Join(dest, rel)
+				if err := copyDeliverable(src, dst); err != nil {
+					return fmt.Errorf("copying deliverable %s: %w", rel, err)
+				}
+				fmt.Println(dst)
+			}
+			return nil
+		},
+	}
+}
+
+// findDeliverables locates the run holding the deliverables: the live
+// artifacts directory if any deliverable is there, otherwise the newest
+// archived run that has one. It returns that directory and the deliverable
+// paths (relative to it) that do and don't exist.
+func findDeliverables(artifactsDir string, paths []string) (runDir string, found, missing []string, err error) {
+	dirs := []string{artifactsDir}
+	history, err := state.ListHistory(artifactsDir)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("listing history: %w", err)
+	}
+	for _, h := range history {
+		dirs = append(dirs, h.Dir)
+	}
+	for _, dir := range dirs {
+		found, missing = nil, nil
+		for _, p := range paths {
+			if _, err := os.Stat(filepath.Join(dir, p)); err == nil {
+				found = append(found, p)
+			} else if errors.Is(err, fs.ErrNotExist) {
+				missing = append(missing, p)
+			} else {
+				return "", nil, nil, err
+			}
+		}
+		if len(found) > 0 {
+			return dir, found, missing, nil
+		}
+	}
+	return artifactsDir, nil, paths, nil
+}
+
+// copyDeliverable copies the file at src to dst, creating dst's directory.
+func copyDeliverable(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/state"
+)
+
+func writeDeliverableRun(t *testing.T, dir string, files ...string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	st := &state.State{Ticket: "T-1", Status: state.StatusCompleted}
+	if err := st.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFindDeliverables_ArchivedRun(t *testing.T) {
+	artifactsDir := t.TempDir()
+	histDir := state.HistoryDir(artifactsDir)
+	writeDeliverableRun(t, filepath.Join(histDir, "2026-01-01T10-00-00.000"), "report.md", "pr-url.txt")
+	newest := filepath.Join(histDir, "2026-01-02T10-00-00.000")
+	writeDeliverableRun(t, newest, "outputs/review/report.md")
+
+	cfg := &config.Config{
+		PhaseOutputDirs: true,
+		Deliverables:    []string{"report.md", "pr-url.txt"},
+		Phases:          []config.Phase{{Name: "review", Type: "script", Outputs: []string{"report.md"}}},
+	}
+	runDir, found, missing, err := findDeliverables(artifactsDir, cfg.DeliverablePaths())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runDir != newest {
+		t.Errorf("runDir = %q, want the newest archived run %q", runDir, newest)
+	}
+	if want := []string{filepath.Join("outputs", "review", "report.md")}; !reflect.DeepEqual(found, want) {
+		t.Errorf("found = %v, want %v", found, want)
+	}
+	if want := []string{"pr-url.txt"}; !reflect.DeepEqual(missing, want) {
+		t.Errorf("missing = %v, want %v", missing, want)
+	}
+}
+
+func TestFindDeliverables_PrefersLiveRun(t *testing.T) {
+	artifactsDir := t.TempDir()
+	writeDeliverableRun(t, filepath.Join(state.HistoryDir(artifactsDir), "2026-01-01T10-00-00.000"), "report.md")
+	writeDeliverableRun(t, artifactsDir, "report.md")
+
+	runDir, found, missing, err := findDeliverables(artifactsDir, []string{"report.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runDir != artifactsDir || len(found) != 1 || len(missing) != 0 {
+		t.Errorf("runDir=%q found=%v missing=%v, want the live run with report.md", runDir, found, missing)
+	}
+}
+
+func TestCopyDeliverable(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "report.md")
+	os.WriteFile(src, []byte("done"), 0644)
+	dst := filepath.Join(t.TempDir(), "results", "outputs", "report.md")
+	if err := copyDeliverable(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(dst); string(data) != "done" {
+		t.Errorf("copied content = %q", data)
+	}
+}
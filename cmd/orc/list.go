@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/runner"
+	"github.com/jorge-barreto/orc/internal/ux"
+	cli "github.com/urfave/cli/v3"
+)
+
+func listCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "List phases with their resolved settings",
+		Description: "Prints a table of every phase — index, name, type, model, timeout, outputs,\n" +
+			"loop target, and parallel/condition markers. The config is validated first,\n" +
+			"so defaulted values (model, timeout) show what a run would actually use.",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			cfgErr := func(err error) error {
+				return &runner.ExitError{Code: runner.ExitConfigError, Err: err}
+			}
+
+			projectRoot, err := findProjectRoot()
+			if err != nil {
+				return cfgErr(err)
+			}
+			_, configPath, err := resolveWorkflow(projectRoot, cmd.Root().String("workflow"))
+			if err != nil {
+				return cfgErr(err)
+			}
+			cfg, err := config.Load(configPath, projectRoot)
+			if err != nil {
+				return cfgErr(fmt.Errorf("loading config: %w", err))
+			}
+			ux.RenderPhaseList(cfg)
+			return nil
+		},
+	}
+}
@@ -1,24 +1,33 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime/debug"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/contextgather"
 	"github.com/jorge-barreto/orc/internal/dispatch"
 	"github.com/jorge-barreto/orc/internal/docs"
 	"github.com/jorge-barreto/orc/internal/doctor"
 	"github.com/jorge-barreto/orc/internal/improve"
+	"github.com/jorge-barreto/orc/internal/metrics"
 	"github.com/jorge-barreto/orc/internal/runner"
 	"github.com/jorge-barreto/orc/internal/scaffold"
 	"github.com/jorge-barreto/orc/internal/state"
+	"github.com/jorge-barreto/orc/internal/tui"
 	"github.com/jorge-barreto/orc/internal/ux"
 	cli "github.com/urfave/cli/v3"
 )
@@ -63,22 +72,36 @@ func main() {
 		Flags: []cli.Flag{
 			&cli.BoolFlag{Name: "no-color", Usage: "Disable colored output"},
 			&cli.StringFlag{Name: "workflow", Aliases: []string{"w"}, Usage: "Select a named workflow from .orc/workflows/"},
+			&cli.BoolFlag{Name: "compact-artifacts", Usage: "Write state and audit JSON files compactly instead of indented"},
+			&cli.StringFlag{Name: "phase-prefix", Usage: "Prefix phase log/prompt, feedback and state file names so workflows can share an artifacts dir (overrides artifacts-prefix)"},
 		},
 		Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
 			if cmd.Bool("no-color") || os.Getenv("NO_COLOR") != "" || os.Getenv("ORC_NO_COLOR") != "" || !ux.IsTerminal(os.Stdout) {
 				ux.DisableColor()
 			}
+			if cmd.Bool("compact-artifacts") || os.Getenv("ORC_COMPACT_ARTIFACTS") != "" {
+				state.CompactArtifacts = true
+			}
+			if err := resolveFilePrefix(cmd); err != nil {
+				return ctx, &runner.ExitError{Code: runner.ExitConfigError, Err: err}
+			}
 			return ctx, nil
 		},
 		Commands: []*cli.Command{
 			initCmd(),
 			runCmd(),
 			validateCmd(),
+			configCmd(),
+			upgradeConfigCmd(),
 			flowCmd(),
+			listCmd(),
 			cancelCmd(),
 			statusCmd(),
 			historyCmd(),
 			statsCmd(),
+			timingCmd(),
+			getCmd(),
+			openCmd(),
 			evalCmd(),
 			reportCmd(),
 			doctorCmd(),
@@ -101,16 +124,45 @@ func runCmd() *cli.Command {
 		Name:      "run",
 		Usage:     "Run the workflow for a ticket",
 		ArgsUsage: "<ticket>",
-		UsageText: "orc run PROJ-123\n   orc run PROJ-123 --auto --verbose\n   orc run PROJ-123 --retry implement\n   orc run PROJ-123 --resume\n   orc run PROJ-123 --step\n   orc run PROJ-123 --headless",
+		UsageText: "orc run PROJ-123\n   orc run PROJ-123 --auto --verbose\n   orc run PROJ-123 --retry implement\n   orc run PROJ-123 --resume\n   orc run PROJ-123 --resume-gate\n   orc run PROJ-123 --step\n   orc run PROJ-123 --headless",
 		Flags: []cli.Flag{
 			&cli.BoolFlag{Name: "auto", Usage: "Unattended mode — skip gates, no interactive steering"},
 			&cli.StringFlag{Name: "retry", Usage: "Retry from phase number or name"},
 			&cli.StringFlag{Name: "from", Usage: "Start from phase number or name"},
+			&cli.BoolFlag{Name: "from-feedback", Usage: "Restart at the loop.goto target of the phase whose feedback is on disk"},
 			&cli.BoolFlag{Name: "dry-run", Usage: "Print phase plan without executing"},
+			&cli.StringFlag{Name: "bisect", Usage: "Find which phase between good..bad (numbers or names) introduces the failure at bad, re-running from saved outputs"},
+			&cli.StringFlag{Name: "bisect-run", Usage: "History run ID whose outputs seed --bisect trials (default: the newest completed run)"},
+			&cli.BoolFlag{Name: "confirm", Usage: "Show the phase count, estimated cost, and gates, then ask y/n before running"},
+			&cli.BoolFlag{Name: "yes", Usage: "Answer yes to the --confirm prompt"},
+			&cli.StringFlag{Name: "prompt-only", Usage: "Print the claude command, working directory, and environment orc would use for this agent phase (number or name), without executing"},
 			&cli.BoolFlag{Name: "verbose", Aliases: []string{"v"}, Usage: "Save raw stream-json output to .stream.jsonl files"},
 			&cli.BoolFlag{Name: "resume", Usage: "Resume an interrupted agent phase using saved session"},
+			&cli.BoolFlag{Name: "resume-gate", Usage: "Re-answer the gate that stopped the run, then continue"},
 			&cli.BoolFlag{Name: "step", Usage: "Step-through mode — pause after each phase for inspection"},
 			&cli.BoolFlag{Name: "headless", Usage: "Non-interactive mode for CI/CD — JSONL output, implies --auto, disables color"},
+			&cli.BoolFlag{Name: "prefix-output", Usage: "Prefix each line of streamed phase output with [phase-name] (for captured CI logs)"},
+			&cli.BoolFlag{Name: "interactive-run", Usage: "Full-screen view: phase board (status, timing, cost), the current phase's output, and keys for gates and steering; plain output when not a terminal"},
+			&cli.BoolFlag{Name: "raw-stream", Usage: "Write agent text to the terminal as each delta arrives instead of a whole line at a time"},
+			&cli.BoolFlag{Name: "compact", Usage: "Print one line per phase ([3/8] test ... ok (0m 04s)) and hide streamed phase output (still logged)"},
+			&cli.StringFlag{Name: "replay", Usage: "Feed a recorded stream-json file to agent phases instead of invoking claude"},
+			&cli.StringFlag{Name: "record", Usage: "Save raw claude stdout for each agent phase to <dir>/phase-N.jsonl"},
+			&cli.BoolFlag{Name: "no-stream", Usage: "Invoke claude with plain --output-format text instead of stream-json (no live output, cost, or session tracking)"},
+			&cli.IntFlag{Name: "workflow-retries", Usage: "When the run fails, archive it and start over from phase 1, up to this many times (overrides workflow-retries)"},
+			&cli.BoolFlag{Name: "eval-conditions", Usage: "With --dry-run, evaluate each phase's condition and mark it would-run or would-skip"},
+			&cli.StringFlag{Name: "labels", Usage: "Run only phases carrying any of these comma-separated labels (plus their parallel partners)"},
+			&cli.IntFlag{Name: "max-phases", Usage: fmt.Sprintf("Stop after this many total phase dispatches in one run (default %d)", runner.DefaultMaxDispatches)},
+			&cli.IntFlag{Name: "events-fd", Usage: "Write the live JSONL event stream to this open file descriptor (see 'orc docs runner')"},
+			&cli.StringFlag{Name: "events-pipe", Usage: "Write the live JSONL event stream to this named pipe or file"},
+			&cli.StringFlag{Name: "metrics-addr", Usage: "Serve Prometheus-style run metrics at http://<addr>/metrics while the run is active (e.g. :9090)"},
+			&cli.StringFlag{Name: "log-format", Value: "text", Usage: "Agent log format: text, or json to also write normalized stream events to logs/phase-N.events.jsonl"},
+			&cli.StringSliceFlag{Name: "var", Usage: "Set a custom variable for this run: KEY=VALUE, overriding config vars (repeatable)"},
+			&cli.StringSliceFlag{Name: "prompt", Usage: "Use a different prompt file for one agent phase: <phase>=<path> (repeatable)"},
+			&cli.BoolFlag{Name: "auto-parallel", Usage: "Run adjacent script phases with no shared outputs as parallel pairs"},
+			&cli.BoolFlag{Name: "keep-going", Usage: "Let every parallel branch finish when one fails, and report all failures together"},
+			&cli.BoolFlag{Name: "phase-output-dir", Usage: "Write each phase's outputs to its own outputs/<phase>/ subdirectory ($PHASE_OUTPUT_DIR)"},
+			&cli.DurationFlag{Name: "agent-timeout", Usage: "Override the timeout of every agent phase for this run (e.g. 5m)"},
+			&cli.DurationFlag{Name: "script-timeout", Usage: "Override the timeout of every script phase for this run (e.g. 90s)"},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			cfgErr := func(err error) error {
@@ -125,6 +177,18 @@ func runCmd() *cli.Command {
 			headless := cmd.Bool("headless") || os.Getenv("ORC_HEADLESS") != ""
 			if headless {
 				ux.EnableQuiet()
+			} else if cmd.Bool("compact") {
+				ux.EnableCompact()
+			}
+			ux.PrefixOutput = cmd.Bool("prefix-output")
+			ux.RawStream = cmd.Bool("raw-stream")
+			interactiveRun := cmd.Bool("interactive-run")
+			if interactiveRun && (headless || cmd.Bool("compact")) {
+				return cfgErr(fmt.Errorf("--interactive-run is mutually exclusive with --headless and --compact"))
+			}
+			if interactiveRun && !ux.IsTerminal(os.Stdout) {
+				fmt.Fprintf(os.Stderr, "note: --interactive-run needs a terminal; using plain output\n")
+				interactiveRun = false
 			}
 
 			projectRoot, err := findProjectRoot()
@@ -183,22 +247,135 @@ func runCmd() *cli.Command {
 				}
 			}
 
+			if err := applyVarOverrides(cfg, cmd.StringSlice("var")); err != nil {
+				return cfgErr(fmt.Errorf("--var: %w", err))
+			}
+
+			if !headless {
+				for _, msg := range config.AuditVars(cfg, projectRoot) {
+					fmt.Fprintf(os.Stderr, "warning: %s\n", msg)
+				}
+			}
+
+			if cmd.Bool("phase-output-dir") {
+				cfg.PhaseOutputDirs = true
+			}
+			// The root hook resolved the prefix for -w's workflow; a workflow
+			// named positionally may declare a different one.
+			if !cmd.Root().IsSet("phase-prefix") {
+				state.FilePrefix = cfg.ArtifactsPrefix
+			}
+
 			artifactsDir := state.ArtifactsDirForWorkflow(projectRoot, workflowName, ticket)
 
 			env := &dispatch.Environment{
-				ProjectRoot:       projectRoot,
-				WorkDir:           projectRoot,
-				ArtifactsDir:      artifactsDir,
-				Ticket:            ticket,
-				Workflow:          workflowName,
-				AutoMode:          cmd.Bool("auto") || headless,
-				Verbose:           cmd.Bool("verbose"),
-				PhaseCount:        len(cfg.Phases),
-				DefaultAllowTools: cfg.DefaultAllowTools,
+				ProjectRoot:        projectRoot,
+				WorkDir:            projectRoot,
+				ArtifactsDir:       artifactsDir,
+				Ticket:             ticket,
+				Workflow:           workflowName,
+				WorkflowName:       cfg.Name,
+				AutoMode:           cmd.Bool("auto") || headless,
+				Verbose:            cmd.Bool("verbose"),
+				PhaseCount:         len(cfg.Phases),
+				DefaultAllowTools:  cfg.DefaultAllowTools,
+				MaxStreamLineBytes: cfg.MaxStreamLineBytes,
 			}
 
 			if len(cfg.Vars) > 0 {
 				env.CustomVars = dispatch.ExpandConfigVars(cfg.Vars, env.Vars())
+				env.UnexportedVars = cfg.UnexportedVars()
+			}
+
+			if replay := cmd.String("replay"); replay != "" {
+				abs, err := filepath.Abs(replay)
+				if err != nil {
+					return cfgErr(fmt.Errorf("--replay: %w", err))
+				}
+				if _, err := os.Stat(abs); err != nil {
+					return cfgErr(fmt.Errorf("--replay: recording %q not found", replay))
+				}
+				env.ReplayFile = abs
+			}
+			if record := cmd.String("record"); record != "" {
+				if env.ReplayFile != "" {
+					return cfgErr(fmt.Errorf("--record and --replay are mutually exclusive"))
+				}
+				abs, err := filepath.Abs(record)
+				if err != nil {
+					return cfgErr(fmt.Errorf("--record: %w", err))
+				}
+				env.RecordDir = abs
+			}
+			if cmd.Bool("no-stream") {
+				if env.ReplayFile != "" {
+					return cfgErr(fmt.Errorf("--no-stream and --replay are mutually exclusive"))
+				}
+				if err := checkNoStreamCost(cfg); err != nil {
+					return cfgErr(err)
+				}
+				env.NoStream = true
+			}
+
+			switch format := cmd.String("log-format"); format {
+			case "text":
+			case "json":
+				env.LogFormat = format
+			default:
+				return cfgErr(fmt.Errorf("--log-format must be text or json, got %q", format))
+			}
+			if cmd.Bool("eval-conditions") && !cmd.Bool("dry-run") {
+				return cfgErr(fmt.Errorf("--eval-conditions requires --dry-run"))
+			}
+
+			if overrides := cmd.StringSlice("prompt"); len(overrides) > 0 {
+				env.PromptOverrides, err = parsePromptOverrides(overrides, cfg)
+				if err != nil {
+					return cfgErr(fmt.Errorf("--prompt: %w", err))
+				}
+			}
+
+			for _, name := range []string{"agent-timeout", "script-timeout"} {
+				if cmd.Duration(name) < 0 {
+					return cfgErr(fmt.Errorf("--%s must be positive", name))
+				}
+			}
+			env.AgentTimeout = cmd.Duration("agent-timeout")
+			env.ScriptTimeout = cmd.Duration("script-timeout")
+
+			if ref := cmd.String("prompt-only"); ref != "" {
+				idx, err := config.ResolvePhaseRef(ref, cfg.Phases)
+				if err != nil {
+					return cfgErr(fmt.Errorf("--prompt-only: %w", err))
+				}
+				phase := cfg.Phases[idx]
+				if phase.Type != "agent" {
+					return cfgErr(fmt.Errorf("--prompt-only: phase %q is a %s phase, not an agent phase", phase.Name, phase.Type))
+				}
+				env.PhaseIndex = idx
+				env.PhaseDescription = phase.Description
+				env.PhaseOutputDir = filepath.Join(artifactsDir, cfg.OutputDir(phase))
+				env.SecretEnv = phase.SecretEnv
+				_, err = dispatch.PrintAgentCommand(os.Stdout, phase, env)
+				return err
+			}
+
+			eventsOut, err := openEventsOutput(cmd.Int("events-fd"), cmd.String("events-pipe"))
+			if err != nil {
+				return cfgErr(err)
+			}
+			if eventsOut != nil {
+				defer eventsOut.Close()
+				env.Events = dispatch.NewEventSink(eventsOut)
+			}
+			var runMetrics *metrics.Registry
+			if addr := cmd.String("metrics-addr"); addr != "" {
+				runMetrics = metrics.New()
+				stopMetrics, err := serveMetrics(addr, runMetrics)
+				if err != nil {
+					return cfgErr(fmt.Errorf("--metrics-addr: %w", err))
+				}
+				defer stopMetrics()
 			}
 
 			// Load or create state
@@ -210,16 +387,46 @@ func runCmd() *cli.Command {
 			st.SetWorkflow(workflowName)
 			st.SetStatus(state.StatusRunning)
 
-			// Handle --retry, --from, and --resume (mutually exclusive)
+			// Handle --retry, --from, --from-feedback, and --resume (mutually exclusive)
 			retryVal := cmd.String("retry")
 			fromVal := cmd.String("from")
 			resumeFlag := cmd.Bool("resume")
+			fromFeedback := cmd.Bool("from-feedback")
 			if retryVal != "" && fromVal != "" {
 				return cfgErr(fmt.Errorf("--retry and --from are mutually exclusive"))
 			}
+			if fromFeedback && (retryVal != "" || fromVal != "" || resumeFlag || cmd.Bool("resume-gate")) {
+				return cfgErr(fmt.Errorf("--from-feedback is mutually exclusive with --retry, --from, --resume, and --resume-gate"))
+			}
 			if resumeFlag && (retryVal != "" || fromVal != "") {
 				return cfgErr(fmt.Errorf("--resume is mutually exclusive with --retry and --from"))
 			}
+			resumeGate := cmd.Bool("resume-gate")
+			if resumeGate && (resumeFlag || retryVal != "" || fromVal != "") {
+				return cfgErr(fmt.Errorf("--resume-gate is mutually exclusive with --resume, --retry, and --from"))
+			}
+			if resumeGate && (cmd.Bool("auto") || headless) {
+				return cfgErr(fmt.Errorf("--resume-gate requires interactive input (incompatible with --auto and --headless)"))
+			}
+			bisectVal := cmd.String("bisect")
+			if bisectVal != "" && (retryVal != "" || fromVal != "" || resumeFlag || resumeGate || fromFeedback || cmd.Bool("dry-run")) {
+				return cfgErr(fmt.Errorf("--bisect is mutually exclusive with --retry, --from, --from-feedback, --resume, --resume-gate, and --dry-run"))
+			}
+			if cmd.String("bisect-run") != "" && bisectVal == "" {
+				return cfgErr(fmt.Errorf("--bisect-run requires --bisect"))
+			}
+			var bisectGood, bisectBad int
+			var bisectSeed string
+			if bisectVal != "" {
+				bisectGood, bisectBad, err = parseBisectRange(bisectVal, cfg.Phases)
+				if err != nil {
+					return cfgErr(fmt.Errorf("--bisect: %w", err))
+				}
+				bisectSeed, err = runner.BisectSeed(artifactsDir, cmd.String("bisect-run"))
+				if err != nil {
+					return cfgErr(fmt.Errorf("--bisect: %w", err))
+				}
+			}
 			if retryVal != "" {
 				idx, err := config.ResolvePhaseRef(retryVal, cfg.Phases)
 				if err != nil {
@@ -235,6 +442,27 @@ func runCmd() *cli.Command {
 				st.SetPhase(idx)
 			}
 
+			if fromFeedback {
+				idx, source, err := feedbackRetryTarget(cfg, artifactsDir, st.GetPhaseIndex())
+				if err != nil {
+					return cfgErr(fmt.Errorf("--from-feedback: %w", err))
+				}
+				if idx < 0 {
+					return cfgErr(fmt.Errorf("--from-feedback: loop.goto of %q not found", source))
+				}
+				// Keep other loops' progress; only the failed loop starts over.
+				counts, err := state.LoadLoopCounts(artifactsDir)
+				if err != nil {
+					return cfgErr(fmt.Errorf("loading loop counts: %w", err))
+				}
+				delete(counts, source)
+				if err := state.SaveLoopCounts(artifactsDir, counts); err != nil {
+					return cfgErr(fmt.Errorf("resetting loop counts: %w", err))
+				}
+				st.SetPhase(idx)
+				fmt.Fprintf(os.Stderr, "note: restarting at phase %d (%s), the loop target for feedback from %s\n", idx+1, cfg.Phases[idx].Name, source)
+			}
+
 			// Reset loop counts when resuming from a specific phase
 			if retryVal != "" || fromVal != "" {
 				if err := state.EnsureDir(artifactsDir); err != nil {
@@ -261,29 +489,103 @@ func runCmd() *cli.Command {
 				return cfgErr(fmt.Errorf("--step and --headless are mutually exclusive (step-through requires interactive input)"))
 			}
 
-			if err := dispatch.Preflight(cfg.Phases); err != nil {
+			preflightPhases := cfg.Phases
+			if env.ReplayFile != "" {
+				// Replayed agent phases never spawn claude.
+				preflightPhases = nil
+				for _, p := range cfg.Phases {
+					if p.Type != "agent" {
+						preflightPhases = append(preflightPhases, p)
+					}
+				}
+			}
+			if err := dispatch.Preflight(preflightPhases); err != nil {
 				return cfgErr(err)
 			}
 
-			r := &runner.Runner{
-				Config:       cfg,
-				State:        st,
-				Env:          env,
-				Dispatcher:   &dispatch.DefaultDispatcher{},
-				StepMode:     stepMode,
-				HistoryLimit: cfg.HistoryLimit,
+			maxPhases := cmd.Int("max-phases")
+			if maxPhases < 0 {
+				return cfgErr(fmt.Errorf("--max-phases must be positive"))
 			}
+			workflowRetries := cfg.WorkflowRetries
+			if cmd.IsSet("workflow-retries") {
+				if cmd.Int("workflow-retries") < 0 {
+					return cfgErr(fmt.Errorf("--workflow-retries must not be negative"))
+				}
+				workflowRetries = int(cmd.Int("workflow-retries"))
+			}
+			var labels []string
+			for _, l := range strings.Split(cmd.String("labels"), ",") {
+				if l = strings.TrimSpace(l); l != "" {
+					labels = append(labels, l)
+				}
+			}
+			var onEvent func(dispatch.LogEvent)
+			var screen *tui.Screen
+			if interactiveRun {
+				board := tui.NewBoard(cfg, ticket)
+				if screen, err = tui.NewScreen(board); err != nil {
+					fmt.Fprintf(os.Stderr, "note: --interactive-run can't take over the terminal (%v); printing the board inline\n", err)
+					onEvent = board.Follow(os.Stdout)
+				} else {
+					onEvent = screen.Observe
+				}
+			}
+			newRunner := func(st *state.State, resumeGate bool) *runner.Runner {
+				return &runner.Runner{
+					Config:         cfg,
+					State:          st,
+					Env:            env,
+					Dispatcher:     newDispatcher(),
+					StepMode:       stepMode,
+					ResumeGate:     resumeGate,
+					KeepGoing:      cmd.Bool("keep-going"),
+					Labels:         labels,
+					EvalConditions: cmd.Bool("eval-conditions"),
+					AutoParallel:   cmd.Bool("auto-parallel"),
+					Metrics:        runMetrics,
+					OnEvent:        onEvent,
+					HistoryLimit:   cfg.HistoryLimit,
+					MaxDispatches:  int(maxPhases),
+				}
+			}
+			r := newRunner(st, resumeGate)
 
 			// Handle --dry-run
 			if cmd.Bool("dry-run") {
-				r.DryRunPrint()
+				r.DryRunPrint(ctx)
+				return nil
+			}
+
+			// Handle --bisect: trials run in scratch copies, so the live
+			// artifacts and state are left as they are.
+			if bisectVal != "" {
+				ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+				defer stop()
+				culprit, err := r.Bisect(ctx, bisectGood, bisectBad, bisectSeed)
+				if err != nil {
+					return err
+				}
+				if culprit < 0 {
+					fmt.Printf("\nbisect: failure did not reproduce starting at phase %d (%s)\n", bisectGood+2, cfg.Phases[bisectGood+1].Name)
+					return nil
+				}
+				fmt.Printf("\n%sbisect:%s phase %d (%s) introduces the failure — starting there fails, starting at the next phase passes\n",
+					ux.Bold, ux.Reset, culprit+1, cfg.Phases[culprit].Name)
 				return nil
 			}
 
+			if cmd.Bool("confirm") && !cmd.Bool("yes") && !env.AutoMode {
+				ux.ConfirmSummary(r.ConfirmPreview())
+				if !confirmRun() {
+					return &runner.ExitError{Code: runner.ExitInterrupted, Err: errors.New("run cancelled at the --confirm prompt")}
+				}
+			}
+
 			// Archive stale artifacts from a prior run before saving fresh state.
 			// Must happen before st.Save() overwrites the on-disk state.
-			// Only fires for genuinely stale state, not --resume/--retry/--from.
-			if !resumeFlag && retryVal == "" && fromVal == "" && state.HasState(artifactsDir) {
+			// Only fires for genuinely stale state, not --resume/--resume-gate/--retry/--from/--from-feedback.
+			if !resumeFlag && !resumeGate && retryVal == "" && fromVal == "" && !fromFeedback && state.HasState(artifactsDir) {
 				existing, existErr := state.Load(artifactsDir)
 				if existErr == nil {
 					if shouldArchiveStale(existing.GetStatus()) {
@@ -298,10 +600,7 @@ func runCmd() *cli.Command {
 			}
 
 			// Ensure artifacts directory exists and save initial state
-			if err := state.EnsureDir(artifactsDir); err != nil {
-				return cfgErr(err)
-			}
-			if err := st.Save(artifactsDir); err != nil {
+			if err := saveInitialState(st, artifactsDir, configPath); err != nil {
 				return cfgErr(err)
 			}
 
@@ -309,7 +608,33 @@ func runCmd() *cli.Command {
 			ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 			defer stop()
 
-			return r.Run(ctx)
+			if screen != nil {
+				if err := screen.Start(); err != nil {
+					return fmt.Errorf("starting --interactive-run: %w", err)
+				}
+				defer screen.Stop()
+			}
+
+			for attempt := 1; ; attempt++ {
+				err := r.Run(ctx)
+				if err == nil || attempt > workflowRetries || ctx.Err() != nil || !retryableFailure(err, r.State) {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "%sworkflow failed:%s %v — starting over from phase 1 (retry %d of %d)\n",
+					ux.Yellow, ux.Reset, err, attempt, workflowRetries)
+				if _, err := state.ArchiveRun(artifactsDir); err != nil {
+					return fmt.Errorf("archiving failed attempt: %w", err)
+				}
+				if err := state.PruneHistory(artifactsDir, cfg.HistoryLimit); err != nil {
+					fmt.Fprintf(os.Stderr, "warning: failed to prune history: %v\n", err)
+				}
+				st = &state.State{Ticket: ticket, Workflow: workflowName, Status: state.StatusRunning}
+				if err := saveInitialState(st, artifactsDir, configPath); err != nil {
+					return cfgErr(err)
+				}
+				env.ResumeSessionID = ""
+				r = newRunner(st, false)
+			}
 		},
 	}
 }
@@ -497,13 +822,53 @@ func statusCmd() *cli.Command {
 	}
 }
 
+// openEventsOutput opens the destination for --events-fd or --events-pipe,
+// or returns nil if neither is set. Opening a FIFO blocks until a reader
+// attaches.
+func openEventsOutput(fd int, pipe string) (*os.File, error) {
+	switch {
+	case fd != 0 && pipe != "":
+		return nil, fmt.Errorf("--events-fd and --events-pipe are mutually exclusive")
+	case fd != 0:
+		if fd < 0 {
+			return nil, fmt.Errorf("--events-fd must be a positive file descriptor, got %d", fd)
+		}
+		f := os.NewFile(uintptr(fd), "events-fd")
+		if _, err := f.Stat(); err != nil {
+			return nil, fmt.Errorf("--events-fd %d: %w", fd, err)
+		}
+		return f, nil
+	case pipe != "":
+		f, err := os.OpenFile(pipe, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("--events-pipe: %w", err)
+		}
+		return f, nil
+	}
+	return nil, nil
+}
+
 func doctorCmd() *cli.Command {
 	return &cli.Command{
 		Name:      "doctor",
 		Usage:     "Diagnose a failed workflow run using AI",
 		ArgsUsage: "<ticket>",
+		Description: "With --env, prints a report of the runtime environment instead (orc, Go,\n" +
+			"OS, config path, CLAUDECODE, and where bash/claude/git resolve on PATH).\n" +
+			"No ticket or failed run is needed.",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "env", Usage: "Print a runtime environment report for bug reports (no ticket needed)"},
+			&cli.BoolFlag{Name: "diff", Usage: "Include config changes since the last successful run"},
+			&cli.BoolFlag{Name: "no-ai", Aliases: []string{"explain-failure"}, Usage: "Print the failed phase's config, log tail, and feedback without calling claude"},
+			&cli.BoolFlag{Name: "json", Usage: "Print a structured diagnosis (root_cause, classification, suggested_fixes, recommended_command) as JSON"},
+			&cli.BoolFlag{Name: "brief", Aliases: []string{"summary-only"}, Usage: "Ask for a few-line diagnosis: root cause, classification, one fix, and the next command"},
+		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			cfgErr := func(err error) error { return &runner.ExitError{Code: runner.ExitConfigError, Err: err} }
+			if cmd.Bool("env") {
+				doctor.WriteEnv(ctx, os.Stdout, envOptions(cmd.Root().String("workflow")))
+				return nil
+			}
 			ticket := cmd.Args().First()
 			if ticket == "" {
 				return cfgErr(fmt.Errorf("ticket argument is required"))
@@ -511,6 +876,12 @@ func doctorCmd() *cli.Command {
 			if err := validateTicketPath(ticket); err != nil {
 				return cfgErr(err)
 			}
+			if cmd.Bool("json") && cmd.Bool("no-ai") {
+				return cfgErr(fmt.Errorf("--json and --no-ai are mutually exclusive"))
+			}
+			if cmd.Bool("brief") && (cmd.Bool("json") || cmd.Bool("no-ai")) {
+				return cfgErr(fmt.Errorf("--brief is mutually exclusive with --json and --no-ai"))
+			}
 
 			projectRoot, err := findProjectRoot()
 			if err != nil {
@@ -539,11 +910,39 @@ func doctorCmd() *cli.Command {
 				return fmt.Errorf("loading state: %w", err)
 			}
 
-			return doctor.Run(ctx, auditDir, stateDir, cfg, st)
+			return doctor.Run(ctx, auditDir, stateDir, cfg, st, doctor.Options{
+				Diff:        cmd.Bool("diff"),
+				ConfigPath:  configPath,
+				TicketDir:   artifactsDir,
+				NoAI:        cmd.Bool("no-ai"),
+				JSON:        cmd.Bool("json"),
+				Brief:       cmd.Bool("brief"),
+				ProjectRoot: projectRoot,
+			})
 		},
 	}
 }
 
+// envOptions resolves the workflow config for 'orc doctor --env'. Failures
+// are recorded in the options rather than returned — the report is most
+// useful exactly when something is broken.
+func envOptions(flagWorkflow string) doctor.EnvOptions {
+	opts := doctor.EnvOptions{OrcVersion: versionString()}
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		opts.ConfigErr = err
+		return opts
+	}
+	_, configPath, err := resolveWorkflow(projectRoot, flagWorkflow)
+	if err != nil {
+		opts.ConfigErr = err
+		return opts
+	}
+	opts.ConfigPath = configPath
+	opts.Config, opts.ConfigErr = config.Load(configPath, projectRoot)
+	return opts
+}
+
 func initCmd() *cli.Command {
 	return &cli.Command{
 		Name:      "init",
@@ -553,6 +952,10 @@ func initCmd() *cli.Command {
 			&cli.StringFlag{Name: "recipe", Usage: "Scaffold from a recipe (simple, standard, full-pipeline, review-loop)"},
 			&cli.BoolFlag{Name: "list-recipes", Usage: "Show available recipes"},
 			&cli.StringFlag{Name: "add-workflow", Usage: "Add a named workflow to an existing .orc/ project"},
+			&cli.BoolFlag{Name: "interactive", Aliases: []string{"i"}, Usage: "Confirm the detected project type, test command, and ticket pattern before generating"},
+			&cli.BoolFlag{Name: "dump-context", Usage: "Print the gathered project context and the full init prompt instead of calling claude"},
+			&cli.StringSliceFlag{Name: "skip-dir", Usage: "Leave a directory (name or project-relative path) out of the gathered project tree (repeatable)"},
+			&cli.StringSliceFlag{Name: "include-file", Usage: "Also gather this project-relative file or glob pattern as context (repeatable)"},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			if cmd.Bool("list-recipes") {
@@ -570,7 +973,17 @@ func initCmd() *cli.Command {
 				return scaffold.InitRecipe(dir, recipe)
 			}
 			userPrompt := cmd.Args().First()
-			return scaffold.Init(ctx, dir, userPrompt)
+			opts := scaffold.InitOptions{
+				Interactive: cmd.Bool("interactive"),
+				Gather: contextgather.Options{
+					SkipDirs:     cmd.StringSlice("skip-dir"),
+					IncludeFiles: cmd.StringSlice("include-file"),
+				},
+			}
+			if cmd.Bool("dump-context") {
+				return scaffold.DumpInitContext(os.Stdout, dir, userPrompt, opts)
+			}
+			return scaffold.Init(ctx, dir, userPrompt, opts)
 		},
 	}
 }
@@ -623,10 +1036,183 @@ func improveCmd() *cli.Command {
 // archived before starting a fresh run. Currently unconditional — every status
 // (completed, running, failed, interrupted, unknown) gets archived rather than
 // silently discarded.
+// newDispatcher returns the dispatcher 'orc run' hands to the runner.
+// Tests replace it to script phase results.
+var newDispatcher = func() dispatch.Dispatcher { return &dispatch.DefaultDispatcher{} }
+
+// parseBisectRange parses a --bisect value of the form good..bad, where each
+// side is a phase number or name, into 0-based indices with good before bad.
+func parseBisectRange(val string, phases []config.Phase) (int, int, error) {
+	goodRef, badRef, ok := strings.Cut(val, "..")
+	if !ok {
+		return 0, 0, fmt.Errorf("expected good..bad, got %q", val)
+	}
+	good, err := config.ResolvePhaseRef(strings.TrimSpace(goodRef), phases)
+	if err != nil {
+		return 0, 0, err
+	}
+	bad, err := config.ResolvePhaseRef(strings.TrimSpace(badRef), phases)
+	if err != nil {
+		return 0, 0, err
+	}
+	if good >= bad {
+		return 0, 0, fmt.Errorf("good phase %d must come before bad phase %d", good+1, bad+1)
+	}
+	return good, bad, nil
+}
+
+// saveInitialState writes st as the run's starting state and snapshots the
+// config so 'orc doctor --diff' can compare against this run later.
+func saveInitialState(st *state.State, artifactsDir, configPath string) error {
+	if err := state.EnsureDir(artifactsDir); err != nil {
+		return err
+	}
+	if err := st.Save(artifactsDir); err != nil {
+		return err
+	}
+	if data, err := os.ReadFile(configPath); err == nil {
+		if err := state.WriteFileAtomic(state.ConfigSnapshotPath(artifactsDir), data, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to snapshot config: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// serveMetrics serves m at http://<addr>/metrics in the background and
+// returns a function that shuts the server down.
+func serveMetrics(addr string, m *metrics.Registry) (func(), error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln) //nolint:errcheck
+	return func() { srv.Close() }, nil
+}
+
+// confirmInput is where the --confirm answer is read from. It is a var so
+// tests can supply the answer.
+var confirmInput io.Reader = os.Stdin
+
+// confirmRun asks whether to proceed; only y or yes does.
+func confirmRun() bool {
+	fmt.Print("Proceed? [y/N]: ")
+	line, _ := bufio.NewReader(confirmInput).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
 func shouldArchiveStale(_ string) bool {
 	return true
 }
 
+// feedbackRetryTarget picks where --from-feedback resumes. It finds the
+// phase whose feedback is on disk — preferring failedIdx, the phase the
+// saved run stopped at, when several left feedback — and returns the index
+// of its loop.goto target. A parallel group's feedback resolves through
+// whichever partner carries the loop. source is the feedback's phase or
+// group name, whose loop count the caller resets.
+func feedbackRetryTarget(cfg *config.Config, artifactsDir string, failedIdx int) (target int, source string, err error) {
+	sources, err := state.FeedbackSources(artifactsDir)
+	if err != nil {
+		return 0, "", fmt.Errorf("reading feedback: %w", err)
+	}
+	var known []string
+	for _, src := range sources {
+		if _, _, ok := cfg.PhaseByName(strings.Split(src, "+")[0]); ok {
+			known = append(known, src)
+		}
+	}
+	switch {
+	case len(known) == 0:
+		return 0, "", fmt.Errorf("no feedback from a failed phase in %s (use --retry to pick a phase)", filepath.Join(artifactsDir, "feedback"))
+	case len(known) == 1:
+		source = known[0]
+	default:
+		for _, src := range known {
+			if failedIdx >= 0 && failedIdx < len(cfg.Phases) && slices.Contains(strings.Split(src, "+"), cfg.Phases[failedIdx].Name) {
+				source = src
+			}
+		}
+		if source == "" {
+			return 0, "", fmt.Errorf("feedback from several phases (%s); use --retry to pick one", strings.Join(known, ", "))
+		}
+	}
+
+	members := strings.Split(source, "+")
+	for _, name := range members {
+		if p, _, ok := cfg.PhaseByName(name); ok && p.Loop != nil {
+			return cfg.PhaseIndex(p.Loop.Goto), source, nil
+		}
+	}
+	// No loop to follow (feedback predates a config change): rerun the phase itself.
+	return cfg.PhaseIndex(members[0]), source, nil
+}
+
+// parsePromptOverrides parses --prompt <phase>=<path> values into a map from
+// agent phase name to absolute prompt path. The phase may be given by name
+// or number; the file must exist.
+func parsePromptOverrides(values []string, cfg *config.Config) (map[string]string, error) {
+	overrides := make(map[string]string, len(values))
+	for _, v := range values {
+		ref, path, ok := strings.Cut(v, "=")
+		if !ok || ref == "" || path == "" {
+			return nil, fmt.Errorf("%q must be <phase>=<path>", v)
+		}
+		idx, err := config.ResolvePhaseRef(ref, cfg.Phases)
+		if err != nil {
+			return nil, err
+		}
+		phase := cfg.Phases[idx]
+		if phase.Type != "agent" {
+			return nil, fmt.Errorf("phase %q is a %s phase, not an agent phase", phase.Name, phase.Type)
+		}
+		if _, dup := overrides[phase.Name]; dup {
+			return nil, fmt.Errorf("phase %q is overridden more than once", phase.Name)
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			return nil, fmt.Errorf("prompt file %q for phase %q not found", path, phase.Name)
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("prompt file %q for phase %q is a directory", path, phase.Name)
+		}
+		overrides[phase.Name] = abs
+	}
+	return overrides, nil
+}
+
+// applyVarOverrides merges run --var KEY=VALUE values over cfg.Vars. A key
+// already declared in vars takes the new value in place, so vars that
+// reference it see the override; new keys are appended in flag order.
+func applyVarOverrides(cfg *config.Config, values []string) error {
+	for _, v := range values {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("%q must be KEY=VALUE", v)
+		}
+		if err := config.ValidateVarName(key); err != nil {
+			return err
+		}
+		i := slices.IndexFunc(cfg.Vars, func(e config.VarEntry) bool { return e.Key == key })
+		if i >= 0 {
+			cfg.Vars[i].Value = value
+		} else {
+			cfg.Vars = append(cfg.Vars, config.VarEntry{Key: key, Value: value})
+		}
+	}
+	return nil
+}
+
 // validateTicketPath rejects ticket values that would escape the artifacts directory.
 func validateTicketPath(ticket string) error {
 	if ticket != filepath.Base(ticket) || ticket == ".." || ticket == "." {
@@ -686,6 +1272,61 @@ func discoverWorkflows(projectRoot string) []string {
 	return names
 }
 
+// checkNoStreamCost rejects --no-stream for a config that sets a cost cap:
+// plain text output carries no cost, so the cap could never be enforced.
+func checkNoStreamCost(cfg *config.Config) error {
+	if cfg.MaxCost > 0 {
+		return fmt.Errorf("--no-stream cannot enforce max-cost: plain text output reports no cost")
+	}
+	for _, p := range cfg.Phases {
+		if p.Type == "agent" && p.MaxCost > 0 {
+			return fmt.Errorf("--no-stream cannot enforce max-cost of phase %q: plain text output reports no cost", p.Name)
+		}
+	}
+	return nil
+}
+
+// retryableFailure reports whether --workflow-retries may start a failed run
+// over: only phase failures a fresh attempt can plausibly fix. Gate
+// rejections, cost overruns, timeouts, rate limits and infrastructure
+// errors would fail the same way again, so they end the run.
+func retryableFailure(err error, st *state.State) bool {
+	if runner.ExitCodeFrom(err) != runner.ExitPhaseFailure || st.GetStatus() != state.StatusFailed {
+		return false
+	}
+	switch st.GetFailureCategory() {
+	case state.FailCategoryScriptFailure, state.FailCategoryAgentError, state.FailCategoryLoopExhaustion,
+		state.FailCategoryOutputMissing, state.FailCategoryOutputInvalid, state.FailCategoryVerifyFailure:
+		return true
+	}
+	return false
+}
+
+// resolveFilePrefix sets state.FilePrefix from --phase-prefix, or else from
+// the artifacts-prefix of the workflow -w selects. It runs before every
+// command, so status, cancel, timing and the rest read the same prefixed
+// files run wrote. Outside a project the prefix stays empty.
+func resolveFilePrefix(cmd *cli.Command) error {
+	if cmd.IsSet("phase-prefix") {
+		prefix := cmd.String("phase-prefix")
+		if err := config.ValidateArtifactsPrefix(prefix); err != nil {
+			return fmt.Errorf("--phase-prefix: %w", err)
+		}
+		state.FilePrefix = prefix
+		return nil
+	}
+	projectRoot, err := findProjectRoot()
+	if err != nil {
+		return nil
+	}
+	_, configPath, err := resolveWorkflow(projectRoot, cmd.String("workflow"))
+	if err != nil {
+		return nil
+	}
+	state.FilePrefix = config.ReadArtifactsPrefix(configPath)
+	return nil
+}
+
 // resolveWorkflow determines which workflow to use.
 // Returns (workflowName, configPath, error).
 // workflowName is empty for single-config flat layout.
@@ -7,10 +7,14 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 
+	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/dispatch"
 	"github.com/jorge-barreto/orc/internal/runner"
+	"github.com/jorge-barreto/orc/internal/state"
 	"github.com/jorge-barreto/orc/internal/ux"
 	"github.com/jorge-barreto/orc/internal/ux/uxtest"
 	cli "github.com/urfave/cli/v3"
@@ -390,6 +394,51 @@ func TestRunCmd_ResumeNoSession_ExitResumeFailure(t *testing.T) {
 	}
 }
 
+func TestResolveFilePrefix(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".orc", "workflows"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".orc", "workflows", "build.yaml"),
+		[]byte("name: build\nartifacts-prefix: build-\nphases: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	origWd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origWd) //nolint:errcheck
+	origPrefix := state.FilePrefix
+	t.Cleanup(func() { state.FilePrefix = origPrefix })
+
+	resolve := func(args ...string) (string, error) {
+		state.FilePrefix = ""
+		app := &cli.Command{
+			Name: "orc",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "workflow", Aliases: []string{"w"}},
+				&cli.StringFlag{Name: "phase-prefix"},
+			},
+			Before: func(ctx context.Context, cmd *cli.Command) (context.Context, error) {
+				return ctx, resolveFilePrefix(cmd)
+			},
+			Commands: []*cli.Command{{Name: "status", Action: func(context.Context, *cli.Command) error { return nil }}},
+		}
+		err := app.Run(context.Background(), append([]string{"orc"}, args...))
+		return state.FilePrefix, err
+	}
+
+	if got, err := resolve("status"); err != nil || got != "build-" {
+		t.Errorf("from config: prefix = %q, %v; want build-", got, err)
+	}
+	if got, err := resolve("status", "--phase-prefix", "x-"); err != nil || got != "x-" {
+		t.Errorf("flag after the command: prefix = %q, %v; want x-", got, err)
+	}
+	if _, err := resolve("--phase-prefix", "../x", "status"); err == nil || !strings.Contains(err.Error(), "--phase-prefix") {
+		t.Errorf("invalid prefix: err = %v", err)
+	}
+}
+
 func TestShouldArchiveStale(t *testing.T) {
 	// shouldArchiveStale is unconditionally true for all statuses.
 	if !shouldArchiveStale("anything") {
@@ -924,3 +973,403 @@ func TestRunCmd_NoDisambiguationHint_SingleArg(t *testing.T) {
 		t.Errorf("expected no hint for single-arg invocation, got: %q", buf.String())
 	}
 }
+
+func TestOpenEventsOutput(t *testing.T) {
+	if f, err := openEventsOutput(0, ""); f != nil || err != nil {
+		t.Fatalf("unset: got %v, %v; want nil, nil", f, err)
+	}
+	if _, err := openEventsOutput(3, "x"); err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Fatalf("expected mutually exclusive error, got %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	f, err := openEventsOutput(0, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("events file not created: %v", err)
+	}
+}
+
+func TestRunCmd_FromFeedbackResumesAtLoopTarget(t *testing.T) {
+	uxtest.SaveState(t)
+	dir := t.TempDir()
+	orcDir := filepath.Join(dir, ".orc")
+	if err := os.MkdirAll(orcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := `name: test
+phases:
+  - name: setup
+    type: script
+    run: echo setup >> "$ORC_PROJECT_ROOT/ran.txt"
+  - name: implement
+    type: script
+    run: echo implement >> "$ORC_PROJECT_ROOT/ran.txt"
+  - name: test
+    type: script
+    run: echo test >> "$ORC_PROJECT_ROOT/ran.txt"
+    loop:
+      goto: implement
+      max: 2
+`
+	if err := os.WriteFile(filepath.Join(orcDir, "config.yaml"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A previous run exhausted the test loop and left feedback behind.
+	artifactsDir := state.ArtifactsDirForWorkflow(dir, "", "TEST-1")
+	if err := state.EnsureDir(artifactsDir); err != nil {
+		t.Fatal(err)
+	}
+	st, err := state.Load(artifactsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.SetTicket("TEST-1")
+	st.SetPhase(2)
+	st.SetStatus(state.StatusFailed)
+	if err := st.Save(artifactsDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.SaveLoopCounts(artifactsDir, map[string]int{"test": 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.WriteFeedback(artifactsDir, "test", "FAIL: TestParse"); err != nil {
+		t.Fatal(err)
+	}
+
+	orig, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(orig) //nolint:errcheck
+	t.Setenv("CLAUDECODE", "")
+
+	app := &cli.Command{
+		Name:     "orc",
+		Commands: []*cli.Command{runCmd()},
+	}
+	if err := app.Run(context.Background(), []string{"orc", "run", "TEST-1", "--from-feedback"}); err != nil {
+		t.Fatalf("run --from-feedback: %v", err)
+	}
+
+	ran, err := os.ReadFile(filepath.Join(dir, "ran.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(ran), "implement\ntest\n"; got != want {
+		t.Fatalf("phases run = %q, want %q (restart at test's loop.goto)", got, want)
+	}
+}
+
+func TestRunCmd_FromFeedbackWithoutFeedback(t *testing.T) {
+	uxtest.SaveState(t)
+	dir := t.TempDir()
+	orcDir := filepath.Join(dir, ".orc")
+	if err := os.MkdirAll(orcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(orcDir, "config.yaml"), []byte("name: test\nphases:\n  - name: a\n    type: script\n    run: echo ok\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	orig, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(orig) //nolint:errcheck
+	t.Setenv("CLAUDECODE", "")
+
+	app := &cli.Command{
+		Name:     "orc",
+		Commands: []*cli.Command{runCmd()},
+	}
+	err := app.Run(context.Background(), []string{"orc", "run", "TEST-1", "--from-feedback"})
+	var exitErr *runner.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != runner.ExitConfigError {
+		t.Fatalf("expected config error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "no feedback") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParsePromptOverrides(t *testing.T) {
+	dir := t.TempDir()
+	prompt := filepath.Join(dir, "alt.md")
+	os.WriteFile(prompt, []byte("alt"), 0644)
+	cfg := &config.Config{Phases: []config.Phase{
+		{Name: "plan", Type: "agent"},
+		{Name: "test", Type: "script"},
+		{Name: "review", Type: "agent"},
+	}}
+
+	got, err := parsePromptOverrides([]string{"plan=" + prompt, "3=" + prompt}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["plan"] != prompt || got["review"] != prompt || len(got) != 2 {
+		t.Fatalf("overrides = %v", got)
+	}
+
+	for _, tt := range []struct {
+		values []string
+		want   string
+	}{
+		{[]string{"plan"}, "must be <phase>=<path>"},
+		{[]string{"nope=" + prompt}, "nope"},
+		{[]string{"test=" + prompt}, "not an agent phase"},
+		{[]string{"plan=" + filepath.Join(dir, "missing.md")}, "not found"},
+		{[]string{"plan=" + dir}, "is a directory"},
+		{[]string{"plan=" + prompt, "1=" + prompt}, "more than once"},
+	} {
+		if _, err := parsePromptOverrides(tt.values, cfg); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: expected error containing %q, got %v", tt.values, tt.want, err)
+		}
+	}
+}
+
+type flakyDispatcher struct {
+	calls *[]string
+}
+
+func (d flakyDispatcher) Dispatch(_ context.Context, phase config.Phase, _ *dispatch.Environment) (*dispatch.Result, error) {
+	*d.calls = append(*d.calls, phase.Name)
+	// The first whole run fails at "test"; the second passes.
+	if phase.Name == "test" && len(*d.calls) == 2 {
+		return &dispatch.Result{ExitCode: 1, Output: "flaky"}, nil
+	}
+	return &dispatch.Result{Output: "ok"}, nil
+}
+
+func TestRunCmd_WorkflowRetriesStartsOver(t *testing.T) {
+	uxtest.SaveState(t)
+	dir := t.TempDir()
+	orcDir := filepath.Join(dir, ".orc")
+	if err := os.MkdirAll(orcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := "name: test\nphases:\n  - name: build\n    type: script\n    run: make\n  - name: test\n    type: script\n    run: make test\n"
+	if err := os.WriteFile(filepath.Join(orcDir, "config.yaml"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var calls []string
+	orig := newDispatcher
+	newDispatcher = func() dispatch.Dispatcher { return flakyDispatcher{calls: &calls} }
+	defer func() { newDispatcher = orig }()
+
+	origWd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origWd) //nolint:errcheck
+	t.Setenv("CLAUDECODE", "")
+
+	app := &cli.Command{
+		Name:     "orc",
+		Commands: []*cli.Command{runCmd()},
+	}
+	if err := app.Run(context.Background(), []string{"orc", "run", "TEST-1", "--workflow-retries", "1"}); err != nil {
+		t.Fatalf("run --workflow-retries 1: %v", err)
+	}
+	if got, want := strings.Join(calls, ","), "build,test,build,test"; got != want {
+		t.Fatalf("dispatched %s, want %s (second run from the top)", got, want)
+	}
+
+	artifactsDir := state.ArtifactsDirForWorkflow(dir, "", "TEST-1")
+	history, err := state.ListHistory(artifactsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var statuses []string
+	for _, h := range history {
+		st, err := state.Load(h.Dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		statuses = append(statuses, st.GetStatus())
+	}
+	sort.Strings(statuses)
+	if got := strings.Join(statuses, ","); got != "completed,failed" {
+		t.Fatalf("archived run statuses = %s, want the failed attempt and the completed retry", got)
+	}
+}
+
+type costlyFlakyDispatcher struct {
+	calls *[]string
+}
+
+func (d costlyFlakyDispatcher) Dispatch(_ context.Context, phase config.Phase, _ *dispatch.Environment) (*dispatch.Result, error) {
+	*d.calls = append(*d.calls, phase.Name)
+	if phase.Name == "test" && len(*d.calls) == 2 {
+		return &dispatch.Result{ExitCode: 1, Output: "flaky"}, nil
+	}
+	if phase.Type == "agent" {
+		return &dispatch.Result{Output: "ok", CostUSD: 0.5, InputTokens: 1, OutputTokens: 1}, nil
+	}
+	return &dispatch.Result{Output: "ok"}, nil
+}
+
+func TestRunCmd_WorkflowRetriesCarriesCostAndStopsOnOverrun(t *testing.T) {
+	uxtest.SaveState(t)
+	dir := t.TempDir()
+	orcDir := filepath.Join(dir, ".orc")
+	if err := os.MkdirAll(filepath.Join(orcDir, "prompts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(orcDir, "prompts", "plan.md"), []byte("plan"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := "name: test\nmax-cost: 0.75\nphases:\n  - name: plan\n    type: agent\n    prompt: .orc/prompts/plan.md\n  - name: test\n    type: script\n    run: make test\n"
+	if err := os.WriteFile(filepath.Join(orcDir, "config.yaml"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var calls []string
+	orig := newDispatcher
+	newDispatcher = func() dispatch.Dispatcher { return costlyFlakyDispatcher{calls: &calls} }
+	defer func() { newDispatcher = orig }()
+
+	origWd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origWd) //nolint:errcheck
+	t.Setenv("CLAUDECODE", "")
+
+	app := &cli.Command{
+		Name:     "orc",
+		Commands: []*cli.Command{runCmd()},
+	}
+	// The retry's plan pushes the run past max-cost only if the first
+	// attempt's spend carries over; the overrun itself is not retried.
+	err := app.Run(context.Background(), []string{"orc", "run", "TEST-1", "--workflow-retries", "2"})
+	if code := runner.ExitCodeFrom(err); code != runner.ExitCostLimit {
+		t.Fatalf("exit code = %d (%v), want ExitCostLimit", code, err)
+	}
+	if got, want := strings.Join(calls, ","), "plan,test,plan"; got != want {
+		t.Fatalf("dispatched %s, want %s", got, want)
+	}
+}
+
+func TestCheckNoStreamCost(t *testing.T) {
+	cfg := &config.Config{Phases: []config.Phase{{Name: "plan", Type: "agent"}, {Name: "test", Type: "script"}}}
+	if err := checkNoStreamCost(cfg); err != nil {
+		t.Fatalf("no cost cap: %v", err)
+	}
+	cfg.Phases[0].MaxCost = 2
+	if err := checkNoStreamCost(cfg); err == nil || !strings.Contains(err.Error(), `"plan"`) {
+		t.Errorf("phase max-cost: err = %v", err)
+	}
+	cfg.Phases[0].MaxCost = 0
+	cfg.MaxCost = 5
+	if err := checkNoStreamCost(cfg); err == nil || !strings.Contains(err.Error(), "max-cost") {
+		t.Errorf("run max-cost: err = %v", err)
+	}
+}
+
+func TestRetryableFailure(t *testing.T) {
+	phaseErr := &runner.ExitError{Code: runner.ExitPhaseFailure, Err: errors.New("failed")}
+	for _, tt := range []struct {
+		err      error
+		category string
+		want     bool
+	}{
+		{phaseErr, state.FailCategoryScriptFailure, true},
+		{phaseErr, state.FailCategoryAgentError, true},
+		{phaseErr, state.FailCategoryGateRejection, false},
+		{phaseErr, state.FailCategoryStateSave, false},
+		{&runner.ExitError{Code: runner.ExitTimeout, Err: errors.New("timed out")}, state.FailCategoryTimeout, false},
+		{&runner.ExitError{Code: runner.ExitRateLimit, Err: errors.New("limited")}, state.FailCategoryRateLimit, false},
+	} {
+		st := &state.State{Status: state.StatusFailed}
+		st.SetFailure(tt.category, "detail")
+		if got := retryableFailure(tt.err, st); got != tt.want {
+			t.Errorf("%s (exit %d): retryable = %v, want %v", tt.category, runner.ExitCodeFrom(tt.err), got, tt.want)
+		}
+	}
+}
+
+func TestRunCmd_ConfirmNoDoesNotRun(t *testing.T) {
+	uxtest.SaveState(t)
+	dir := t.TempDir()
+	orcDir := filepath.Join(dir, ".orc")
+	if err := os.MkdirAll(orcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := "name: test\nphases:\n  - name: build\n    type: script\n    run: make\n  - name: review\n    type: gate\n"
+	if err := os.WriteFile(filepath.Join(orcDir, "config.yaml"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var calls []string
+	orig := newDispatcher
+	newDispatcher = func() dispatch.Dispatcher { return flakyDispatcher{calls: &calls} }
+	defer func() { newDispatcher = orig }()
+	origInput := confirmInput
+	confirmInput = strings.NewReader("n\n")
+	defer func() { confirmInput = origInput }()
+
+	origWd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origWd) //nolint:errcheck
+	t.Setenv("CLAUDECODE", "")
+
+	app := &cli.Command{
+		Name:     "orc",
+		Commands: []*cli.Command{runCmd()},
+	}
+	err := app.Run(context.Background(), []string{"orc", "run", "TEST-1", "--confirm"})
+	if code := runner.ExitCodeFrom(err); code != runner.ExitInterrupted {
+		t.Fatalf("run --confirm answered no: exit %d (%v), want %d", code, err, runner.ExitInterrupted)
+	}
+	if len(calls) != 0 {
+		t.Fatalf("dispatched %v after answering no, want nothing", calls)
+	}
+	if state.HasState(state.ArtifactsDirForWorkflow(dir, "", "TEST-1")) {
+		t.Fatal("state was saved after answering no")
+	}
+}
+
+func TestRunCmd_VarOverridesConfigVars(t *testing.T) {
+	uxtest.SaveState(t)
+	dir := t.TempDir()
+	orcDir := filepath.Join(dir, ".orc")
+	if err := os.MkdirAll(orcDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := "name: test\nvars:\n  ENV: dev\n  HOST: api.$ENV.example.com\nphases:\n" +
+		"  - name: deploy\n    type: script\n    run: echo \"$HOST $REGION\" > out.txt; echo \"$ORC_ENV $ORC_REGION\" >> out.txt\n"
+	if err := os.WriteFile(filepath.Join(orcDir, "config.yaml"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origWd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(origWd) //nolint:errcheck
+	t.Setenv("CLAUDECODE", "")
+
+	app := &cli.Command{
+		Name:     "orc",
+		Commands: []*cli.Command{runCmd()},
+	}
+	if err := app.Run(context.Background(), []string{"orc", "run", "TEST-1", "--var", "ENV=staging", "--var", "REGION=us-east-1"}); err != nil {
+		t.Fatalf("run --var: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "api.staging.example.com us-east-1\nstaging us-east-1\n"; got != want {
+		t.Fatalf("out.txt = %q, want %q", got, want)
+	}
+
+	for _, bad := range []string{"TICKET=x", "1BAD=x", "NOEQUALS"} {
+		if err := app.Run(context.Background(), []string{"orc", "run", "TEST-1", "--var", bad}); err == nil {
+			t.Errorf("--var %s: expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/debug"
+	"github.com/jorge-barreto/orc/internal/runner"
+	"github.com/jorge-barreto/orc/internal/state"
+	cli "github.com/urfave/cli/v3"
+)
+
+// openCommand returns the OS default opener and the arguments that precede
+// the path. It is a var so tests can replace it.
+var openCommand = func() (string, []string) {
+	switch runtime.GOOS {
+	case "darwin":
+		return "open", nil
+	case "windows":
+		return "cmd", []string{"/c", "start", ""}
+	}
+	return "xdg-open", nil
+}
+
+// launchOpener starts the opener without waiting for the viewer to exit. It
+// is a var so tests can capture the launch instead of opening a window.
+var launchOpener = func(name string, args ...string) error {
+	return exec.Command(name, args...).Start()
+}
+
+func openCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "open",
+		Usage:     "Open a run's artifact or phase log in the default viewer",
+		ArgsUsage: "[ticket] <artifact>",
+		UsageText: "orc open report.md\n   orc open PROJ-123 report.md\n   orc open PROJ-123 test",
+		Description: "Resolves <artifact> in the ticket's latest run — the live artifacts\n" +
+			"directory, or the newest archived run that has it — and launches it with\n" +
+			"the OS default opener (xdg-open, open, or start). <artifact> is a declared\n" +
+			"output, a path relative to the artifacts directory (logs/phase-3.log), or a\n" +
+			"phase name or number, which opens that phase's log. Without a ticket, the\n" +
+			"most recently run ticket is used. If no opener is available the path is\n" +
+			"printed instead.",
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			cfgErr := func(err error) error {
+				return &runner.ExitError{Code: runner.ExitConfigError, Err: err}
+			}
+
+			var ticket, artifact string
+			switch cmd.Args().Len() {
+			case 1:
+				artifact = cmd.Args().Get(0)
+			case 2:
+				ticket, artifact = cmd.Args().Get(0), cmd.Args().Get(1)
+			default:
+				return cfgErr(fmt.Errorf("usage: orc open [ticket] <artifact>"))
+			}
+
+			projectRoot, err := findProjectRoot()
+			if err != nil {
+				return cfgErr(err)
+			}
+			workflowName, configPath, err := resolveWorkflow(projectRoot, cmd.Root().String("workflow"))
+			if err != nil {
+				return cfgErr(err)
+			}
+			cfg, err := config.Load(configPath, projectRoot)
+			if err != nil {
+				return cfgErr(fmt.Errorf("loading config: %w", err))
+			}
+			if ticket == "" {
+				ticket, err = debug.FindMostRecentTicket(projectRoot, workflowName)
+				if err != nil {
+					return cfgErr(err)
+				}
+			}
+			if err := validateTicketPath(ticket); err != nil {
+				return cfgErr(err)
+			}
+
+			path, err := resolveArtifact(cfg, state.ArtifactsDirForWorkflow(projectRoot, workflowName, ticket), artifact)
+			if err != nil {
+				return err
+			}
+
+			name, args := openCommand()
+			if _, err := exec.LookPath(name); err != nil {
+				fmt.Println(path)
+				return fmt.Errorf("no opener found (%s is not installed) — the file is at the path above", name)
+			}
+			if err := launchOpener(name, append(args, path)...); err != nil {
+				return fmt.Errorf("opening %s: %w", path, err)
+			}
+			fmt.Println(path)
+			return nil
+		},
+	}
+}
+
+// resolveArtifact returns the absolute path of artifact in the ticket's
+// latest run that has it. artifact is tried as a declared output (placed
+// per OutputPaths), then as a path relative to the artifacts directory,
+// then as a phase name or number naming that phase's log.
+func resolveArtifact(cfg *config.Config, artifactsDir, artifact string) (string, error) {
+	var candidates []string
+	for _, p := range cfg.Phases {
+		for i, o := range p.Outputs {
+			if name, _ := config.SplitOutput(o); name == artifact {
+				candidates = append(candidates, cfg.OutputPaths(p)[i])
+			}
+		}
+	}
+	if filepath.IsLocal(artifact) {
+		candidates = append(candidates, artifact)
+	}
+	if idx, err := config.ResolvePhaseRef(artifact, cfg.Phases); err == nil {
+		rel, _ := filepath.Rel(artifactsDir, state.LogPath(artifactsDir, idx))
+		candidates = append(candidates, rel)
+	}
+	for _, c := range candidates {
+		runDir, found, _, err := findDeliverables(artifactsDir, []string{c})
+		if err != nil {
+			return "", err
+		}
+		if len(found) > 0 {
+			return filepath.Join(runDir, found[0]), nil
+		}
+	}
+	return "", fmt.Errorf("artifact %q not found in %s or its history", artifact, artifactsDir)
+}
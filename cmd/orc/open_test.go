@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/state"
+	cli "github.com/urfave/cli/v3"
+)
+
+func TestResolveArtifact(t *testing.T) {
+	artifactsDir := t.TempDir()
+	writeDeliverableRun(t, artifactsDir, "outputs/review/report.json", "notes.txt", "logs/phase-2.log")
+	archived := filepath.Join(state.HistoryDir(artifactsDir), "2026-01-01T10-00-00.000")
+	writeDeliverableRun(t, archived, "old.md")
+
+	cfg := &config.Config{
+		PhaseOutputDirs: true,
+		Phases: []config.Phase{
+			{Name: "plan", Type: "script"},
+			{Name: "review", Type: "script", Outputs: []string{"report.json:json"}},
+		},
+	}
+	for _, tc := range []struct {
+		artifact, want string
+	}{
+		{"report.json", filepath.Join(artifactsDir, "outputs", "review", "report.json")},
+		{"notes.txt", filepath.Join(artifactsDir, "notes.txt")},
+		{"review", filepath.Join(artifactsDir, "logs", "phase-2.log")},
+		{"2", filepath.Join(artifactsDir, "logs", "phase-2.log")},
+		{"old.md", filepath.Join(archived, "old.md")},
+	} {
+		got, err := resolveArtifact(cfg, artifactsDir, tc.artifact)
+		if err != nil {
+			t.Errorf("%s: %v", tc.artifact, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s: resolved %q, want %q", tc.artifact, got, tc.want)
+		}
+	}
+
+	for _, missing := range []string{"plan", "nope.md", "../escape.md"} {
+		if _, err := resolveArtifact(cfg, artifactsDir, missing); err == nil {
+			t.Errorf("%s: expected not-found error", missing)
+		}
+	}
+}
+
+func TestOpenCmd_LaunchesOpener(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, ".orc"), 0755)
+	os.WriteFile(filepath.Join(root, ".orc", "config.yaml"),
+		[]byte("name: test\nphases:\n  - name: report\n    type: script\n    run: \"true\"\n    outputs: [report.md]\n"), 0644)
+	artifactsDir := state.ArtifactsDirForWorkflow(root, "", "T-1")
+	writeDeliverableRun(t, artifactsDir, "report.md")
+
+	orig, _ := os.Getwd()
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(orig) })
+
+	oldCommand, oldLaunch := openCommand, launchOpener
+	t.Cleanup(func() { openCommand, launchOpener = oldCommand, oldLaunch })
+	openCommand = func() (string, []string) { return "true", []string{"--flag"} }
+	var launched []string
+	launchOpener = func(name string, args ...string) error {
+		launched = append([]string{name}, args...)
+		return nil
+	}
+
+	app := &cli.Command{Name: "orc", Commands: []*cli.Command{openCmd()}}
+	if err := app.Run(context.Background(), []string{"orc", "open", "T-1", "report.md"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"true", "--flag", filepath.Join(artifactsDir, "report.md")}
+	if !reflect.DeepEqual(launched, want) {
+		t.Fatalf("launched %v, want %v", launched, want)
+	}
+}
@@ -102,27 +102,35 @@ func testCmd() *cli.Command {
 				ArtifactsDir:      artifactsDir,
 				Ticket:            ticket,
 				Workflow:          workflowName,
+				WorkflowName:      cfg.Name,
 				AutoMode:          cmd.Bool("auto") || headless,
 				Verbose:           cmd.Bool("verbose"),
 				PhaseIndex:        phaseIdx,
+				PhaseDescription:  phase.Description,
+				PhaseOutputDir:    filepath.Join(artifactsDir, cfg.OutputDir(phase)),
+				SecretEnv:         phase.SecretEnv,
 				PhaseCount:        len(cfg.Phases),
 				DefaultAllowTools: cfg.DefaultAllowTools,
 			}
 			if len(cfg.Vars) > 0 {
 				env.CustomVars = dispatch.ExpandConfigVars(cfg.Vars, env.Vars())
+				env.UnexportedVars = cfg.UnexportedVars()
 			}
 
 			if err := state.EnsureDir(artifactsDir); err != nil {
 				return cfgErr(err)
 			}
+			if err := os.MkdirAll(env.PhaseOutputDir, 0755); err != nil {
+				return cfgErr(fmt.Errorf("creating output directory: %w", err))
+			}
 
-			checkMissingArtifacts(cfg.Phases, phaseIdx, artifactsDir)
+			checkMissingArtifacts(cfg, phaseIdx, artifactsDir)
 
 			if err := dispatch.Preflight([]config.Phase{phase}); err != nil {
 				return cfgErr(err)
 			}
 
-			ux.PhaseHeader(phaseIdx, len(cfg.Phases), phase)
+			ux.PhaseHeader(phaseIdx, cfg.Phases, nil)
 
 			withHooks := cmd.Bool("with-hooks")
 			start := time.Now()
@@ -142,9 +150,9 @@ func testCmd() *cli.Command {
 			}
 
 			if result.ExitCode == 0 {
-				ux.PhaseComplete(phaseIdx, phase.Name, duration)
+				ux.PhaseComplete(phaseIdx, len(cfg.Phases), phase.Name, duration)
 			} else {
-				ux.PhaseFail(phaseIdx, phase.Name, fmt.Sprintf("exit code %d", result.ExitCode))
+				ux.PhaseFail(phaseIdx, len(cfg.Phases), phase.Name, fmt.Sprintf("exit code %d", result.ExitCode))
 			}
 
 			if result.ExitCode != 0 {
@@ -158,7 +166,8 @@ func testCmd() *cli.Command {
 // checkMissingArtifacts checks for declared outputs from phases that precede
 // the target phase. For each missing file, it prints a warning showing which
 // earlier phase normally creates it.
-func checkMissingArtifacts(phases []config.Phase, targetIdx int, artifactsDir string) {
+func checkMissingArtifacts(cfg *config.Config, targetIdx int, artifactsDir string) {
+	phases := cfg.Phases
 	type missingArtifact struct {
 		output    string
 		phaseIdx  int
@@ -166,7 +175,7 @@ func checkMissingArtifacts(phases []config.Phase, targetIdx int, artifactsDir st
 	}
 	var missing []missingArtifact
 	for i := 0; i < targetIdx; i++ {
-		for _, output := range phases[i].Outputs {
+		for _, output := range cfg.OutputPaths(phases[i]) {
 			path := filepath.Join(artifactsDir, output)
 			if _, err := os.Stat(path); err != nil {
 				missing = append(missing, missingArtifact{
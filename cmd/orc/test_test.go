@@ -25,7 +25,7 @@ func TestCheckMissingArtifacts_NoPriorPhases(t *testing.T) {
 	r, w, _ := os.Pipe()
 	oldStderr := os.Stderr
 	os.Stderr = w
-	checkMissingArtifacts(phases, 0, tmpDir)
+	checkMissingArtifacts(&config.Config{Phases: phases}, 0, tmpDir)
 	w.Close()
 	os.Stderr = oldStderr
 	var buf bytes.Buffer
@@ -47,7 +47,7 @@ func TestCheckMissingArtifacts_AllPresent(t *testing.T) {
 	r, w, _ := os.Pipe()
 	oldStderr := os.Stderr
 	os.Stderr = w
-	checkMissingArtifacts(phases, 1, tmpDir)
+	checkMissingArtifacts(&config.Config{Phases: phases}, 1, tmpDir)
 	w.Close()
 	os.Stderr = oldStderr
 	var buf bytes.Buffer
@@ -66,7 +66,7 @@ func TestCheckMissingArtifacts_SomeMissing(t *testing.T) {
 	r, w, _ := os.Pipe()
 	oldStderr := os.Stderr
 	os.Stderr = w
-	checkMissingArtifacts(phases, 1, tmpDir)
+	checkMissingArtifacts(&config.Config{Phases: phases}, 1, tmpDir)
 	w.Close()
 	os.Stderr = oldStderr
 	var buf bytes.Buffer
@@ -92,7 +92,7 @@ func TestCheckMissingArtifacts_MultiplePriorPhases(t *testing.T) {
 	r, w, _ := os.Pipe()
 	oldStderr := os.Stderr
 	os.Stderr = w
-	checkMissingArtifacts(phases, 2, tmpDir)
+	checkMissingArtifacts(&config.Config{Phases: phases}, 2, tmpDir)
 	w.Close()
 	os.Stderr = oldStderr
 	var buf bytes.Buffer
@@ -135,7 +135,7 @@ func TestCheckMissingArtifacts_QuietMode_EmitsJSONL(t *testing.T) {
 	rErr, wErr, _ := os.Pipe()
 	os.Stderr = wErr
 
-	checkMissingArtifacts(phases, 2, tmpDir)
+	checkMissingArtifacts(&config.Config{Phases: phases}, 2, tmpDir)
 
 	wOut.Close()
 	os.Stdout = oldStdout
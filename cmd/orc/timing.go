@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jorge-barreto/orc/internal/debug"
+	"github.com/jorge-barreto/orc/internal/runner"
+	"github.com/jorge-barreto/orc/internal/state"
+	cli "github.com/urfave/cli/v3"
+)
+
+func timingCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "timing",
+		Usage:     "Export per-phase timing data as CSV or JSON",
+		ArgsUsage: "[ticket]",
+		UsageText: "orc timing\n   orc timing PROJ-123 --format csv > timing.csv\n   orc timing PROJ-123 --format json",
+		Description: "Reads timing.json from the ticket's archived runs (oldest first) and its\n" +
+			"live artifacts, and prints one row per timing entry: run, phase, attempt,\n" +
+			"start, end, and duration in seconds. A phase that ran more than once in a\n" +
+			"run (loops, retries) gets one row per attempt. The live run is labeled\n" +
+			"\"current\"; an entry still in progress has an empty end and duration.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "format", Value: "csv", Usage: "Output format: csv or json"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			cfgErr := func(err error) error {
+				return &runner.ExitError{Code: runner.ExitConfigError, Err: err}
+			}
+			format := cmd.String("format")
+			if format != "csv" && format != "json" {
+				return cfgErr(fmt.Errorf("--format must be csv or json, got %q", format))
+			}
+
+			projectRoot, err := findProjectRoot()
+			if err != nil {
+				return cfgErr(err)
+			}
+			workflowName, _, err := resolveWorkflow(projectRoot, cmd.Root().String("workflow"))
+			if err != nil {
+				return cfgErr(err)
+			}
+			ticket := cmd.Args().First()
+			if ticket == "" {
+				ticket, err = debug.FindMostRecentTicket(projectRoot, workflowName)
+				if err != nil {
+					return cfgErr(err)
+				}
+			}
+			if err := validateTicketPath(ticket); err != nil {
+				return cfgErr(err)
+			}
+
+			rows, err := collectTimingRows(
+				state.ArtifactsDirForWorkflow(projectRoot, workflowName, ticket),
+				state.AuditDirForWorkflow(projectRoot, workflowName, ticket))
+			if err != nil {
+				return err
+			}
+			return writeTimingRows(os.Stdout, rows, format)
+		},
+	}
+}
+
+// timingRow is one exported timing entry.
+type timingRow struct {
+	Run          string     `json:"run"`
+	Phase        string     `json:"phase"`
+	Attempt      int        `json:"attempt"` // 1-based count of this phase within the run
+	Start        time.Time  `json:"start"`
+	End          *time.Time `json:"end,omitempty"`
+	DurationSecs *float64   `json:"duration_secs,omitempty"`
+}
+
+// collectTimingRows loads timing for every archived run of the ticket, oldest
+// first, followed by the live run when it has any entries. The live run's
+// timing is read from auditDir, where the runner writes it, falling back to
+// artifactsDir for runs that predate the audit directory.
+func collectTimingRows(artifactsDir, auditDir string) ([]timingRow, error) {
+	entries, err := state.ListHistory(artifactsDir)
+	if err != nil {
+		return nil, fmt.Errorf("listing history: %w", err)
+	}
+	slices.Reverse(entries)
+
+	var rows []timingRow
+	for _, e := range entries {
+		t, err := state.LoadTiming(e.Dir)
+		if err != nil {
+			return nil, fmt.Errorf("loading timing for run %s: %w", e.RunID, err)
+		}
+		rows = append(rows, timingRowsForRun(e.RunID, t)...)
+	}
+	t, err := state.LoadTiming(auditDir)
+	if err == nil && len(t.Entries()) == 0 {
+		t, err = state.LoadTiming(artifactsDir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading timing: %w", err)
+	}
+	return append(rows, timingRowsForRun("current", t)...), nil
+}
+
+func timingRowsForRun(run string, t *state.Timing) []timingRow {
+	var rows []timingRow
+	attempts := make(map[string]int)
+	for _, e := range t.Entries() {
+		attempts[e.Phase]++
+		row := timingRow{Run: run, Phase: e.Phase, Attempt: attempts[e.Phase], Start: e.Start}
+		if !e.End.IsZero() {
+			end := e.End
+			secs := e.End.Sub(e.Start).Seconds()
+			row.End = &end
+			row.DurationSecs = &secs
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// writeTimingRows renders rows as CSV (with a header) or as an indented JSON array.
+func writeTimingRows(w io.Writer, rows []timingRow, format string) error {
+	if format == "json" {
+		if rows == nil {
+			rows = []timingRow{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"run", "phase", "attempt", "start", "end", "duration_secs"})
+	for _, r := range rows {
+		var end, secs string
+		if r.End != nil {
+			end = r.End.Format(time.RFC3339)
+			secs = strconv.FormatFloat(*r.DurationSecs, 'f', 3, 64)
+		}
+		cw.Write([]string{r.Run, r.Phase, strconv.Itoa(r.Attempt), r.Start.Format(time.RFC3339), end, secs})
+	}
+	cw.Flush()
+	return cw.Error()
+}
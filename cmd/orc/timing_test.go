@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jorge-barreto/orc/internal/state"
+)
+
+func writeTimingFixture(t *testing.T, dir string, entries []state.TimingEntry) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	st := &state.State{Ticket: "T-1", Status: state.StatusCompleted}
+	if err := st.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.NewTiming(entries).Flush(dir); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTimingExport_CSV(t *testing.T) {
+	artifactsDir := t.TempDir()
+	t0 := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	writeTimingFixture(t, filepath.Join(state.HistoryDir(artifactsDir), "2026-01-02T10-00-00.000"), []state.TimingEntry{
+		{Phase: "plan", Start: t0, End: t0.Add(90 * time.Second)},
+		{Phase: "implement", Start: t0.Add(2 * time.Minute), End: t0.Add(5 * time.Minute)},
+		{Phase: "implement", Start: t0.Add(6 * time.Minute), End: t0.Add(7 * time.Minute)},
+	})
+	// The live run's timing lives in the audit dir; a stale copy in the
+	// artifacts dir is ignored.
+	auditDir := t.TempDir()
+	writeTimingFixture(t, auditDir, []state.TimingEntry{
+		{Phase: "plan", Start: t0.Add(time.Hour), End: t0.Add(time.Hour + 30*time.Second)},
+		{Phase: "implement", Start: t0.Add(time.Hour + time.Minute)},
+	})
+	writeTimingFixture(t, artifactsDir, []state.TimingEntry{
+		{Phase: "stale", Start: t0, End: t0.Add(time.Second)},
+	})
+
+	rows, err := collectTimingRows(artifactsDir, auditDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := writeTimingRows(&buf, rows, "csv"); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"run", "phase", "attempt", "start", "end", "duration_secs"},
+		{"2026-01-02T10-00-00.000", "plan", "1", "2026-01-02T10:00:00Z", "2026-01-02T10:01:30Z", "90.000"},
+		{"2026-01-02T10-00-00.000", "implement", "1", "2026-01-02T10:02:00Z", "2026-01-02T10:05:00Z", "180.000"},
+		{"2026-01-02T10-00-00.000", "implement", "2", "2026-01-02T10:06:00Z", "2026-01-02T10:07:00Z", "60.000"},
+		{"current", "plan", "1", "2026-01-02T11:00:00Z", "2026-01-02T11:00:30Z", "30.000"},
+		{"current", "implement", "1", "2026-01-02T11:01:00Z", "", ""},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("got %d records, want %d (one per timing entry plus header):\n%v", len(records), len(want), records)
+	}
+	for i := range want {
+		for j := range want[i] {
+			if records[i][j] != want[i][j] {
+				t.Errorf("record %d col %d = %q, want %q", i, j, records[i][j], want[i][j])
+			}
+		}
+	}
+}
+
+func TestTimingExport_JSON(t *testing.T) {
+	// With no timing in the audit dir, the artifacts dir's copy is used.
+	artifactsDir := t.TempDir()
+	t0 := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	writeTimingFixture(t, artifactsDir, []state.TimingEntry{
+		{Phase: "plan", Start: t0, End: t0.Add(2 * time.Second)},
+	})
+
+	rows, err := collectTimingRows(artifactsDir, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := writeTimingRows(&buf, rows, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(got) != 1 || got[0]["phase"] != "plan" || got[0]["duration_secs"] != 2.0 || got[0]["attempt"] != 1.0 {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestTimingExport_EmptyJSONIsArray(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeTimingRows(&buf, nil, "json"); err != nil {
+		t.Fatal(err)
+	}
+	if got := bytes.TrimSpace(buf.Bytes()); string(got) != "[]" {
+		t.Fatalf("got %q, want []", got)
+	}
+}
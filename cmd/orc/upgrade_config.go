@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/runner"
+	"github.com/jorge-barreto/orc/internal/state"
+	cli "github.com/urfave/cli/v3"
+)
+
+func upgradeConfigCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "upgrade-config",
+		Usage: "Migrate the workflow config to the current config-version",
+		Description: "Applies the known migrations (field renames, unit conversions) from the\n" +
+			"config's config-version to the current one, keeps a backup at\n" +
+			"<config>.bak, and writes the upgraded config back in place. Comments\n" +
+			"and key order are preserved.",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "dry-run", Usage: "Print the upgraded config instead of writing it"},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			cfgErr := func(err error) error {
+				return &runner.ExitError{Code: runner.ExitConfigError, Err: err}
+			}
+
+			projectRoot, err := findProjectRoot()
+			if err != nil {
+				return cfgErr(err)
+			}
+			_, configPath, err := resolveWorkflow(projectRoot, cmd.Root().String("workflow"))
+			if err != nil {
+				return cfgErr(err)
+			}
+			if err := upgradeConfig(os.Stdout, configPath, cmd.Bool("dry-run")); err != nil {
+				return cfgErr(err)
+			}
+			return nil
+		},
+	}
+}
+
+// upgradeConfig migrates the config at configPath. Unless dryRun is set, the
+// original is copied to configPath+".bak" before the upgraded YAML replaces
+// it; with dryRun the upgraded YAML is written to w.
+func upgradeConfig(w io.Writer, configPath string, dryRun bool) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	out, applied, err := config.Upgrade(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", configPath, err)
+	}
+	if len(applied) == 0 {
+		fmt.Fprintf(w, "%s is already at config-version %d.\n", configPath, config.CurrentConfigVersion)
+		return nil
+	}
+	if dryRun {
+		_, err := w.Write(out)
+		return err
+	}
+
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return err
+	}
+	backup := configPath + ".bak"
+	if err := state.WriteFileAtomic(backup, data, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("writing backup: %w", err)
+	}
+	if err := state.WriteFileAtomic(configPath, out, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("writing upgraded config: %w", err)
+	}
+	fmt.Fprintf(w, "Upgraded %s (backup: %s):\n", configPath, backup)
+	for _, a := range applied {
+		fmt.Fprintf(w, "  %s\n", a)
+	}
+	return nil
+}
@@ -2,10 +2,12 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/jorge-barreto/orc/internal/config"
@@ -27,6 +29,9 @@ func runValidate(configPath, projectRoot string) (*config.Config, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
+	if err := config.MergeToolsFile(&cfg, projectRoot); err != nil {
+		return nil, err
+	}
 	if err := config.Validate(&cfg, projectRoot); err != nil {
 		return nil, err
 	}
@@ -39,13 +44,27 @@ func validateCmd() *cli.Command {
 		Usage: "Validate config without running",
 		Flags: []cli.Flag{
 			&cli.StringFlag{Name: "config", Usage: "Path to config file (default: .orc/config.yaml in project root)"},
+			&cli.BoolFlag{Name: "strict", Usage: "Treat undefined variable references as errors"},
+			&cli.StringFlag{Name: "format", Value: "text", Usage: "Error output format: text, or github for GitHub Actions ::error annotations"},
 		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			cfgErr := func(err error) error {
 				return &runner.ExitError{Code: runner.ExitConfigError, Err: err}
 			}
 
+			strict := cmd.Bool("strict")
+			format := cmd.String("format")
+			if format != "text" && format != "github" {
+				return cfgErr(fmt.Errorf("--format must be text or github, got %q", format))
+			}
 			var configPath, projectRoot string
+			// annotate emits a GitHub Actions annotation for a config error
+			// in --format github mode; it is a no-op otherwise.
+			annotate := func(path string, err error) {
+				if format == "github" {
+					fmt.Println(githubAnnotation(path, projectRoot, err))
+				}
+			}
 
 			if flagVal := cmd.String("config"); flagVal != "" {
 				absPath, err := filepath.Abs(flagVal)
@@ -76,9 +95,13 @@ func validateCmd() *cli.Command {
 					}
 					cfg, err := runValidate(resolvedPath, projectRoot)
 					if err != nil {
+						annotate(resolvedPath, err)
 						return cfgErr(err)
 					}
 					printConfigSummary(os.Stdout, cfg, projectRoot)
+					if err := reportVarAudit(os.Stderr, cfg, projectRoot, strict); err != nil {
+						return cfgErr(err)
+					}
 					return nil
 				}
 
@@ -88,10 +111,15 @@ func validateCmd() *cli.Command {
 				if hasConfig {
 					cfg, err := runValidate(filepath.Join(projectRoot, ".orc", "config.yaml"), projectRoot)
 					if err != nil {
+						annotate(filepath.Join(projectRoot, ".orc", "config.yaml"), err)
 						fmt.Fprintf(os.Stderr, "%s\u2717 default (config.yaml): %v%s\n", ux.Red, err, ux.Reset)
 						allValid = false
 					} else {
 						printConfigSummary(os.Stdout, cfg, projectRoot)
+						if err := reportVarAudit(os.Stderr, cfg, projectRoot, strict); err != nil {
+							fmt.Fprintf(os.Stderr, "%s\u2717 default (config.yaml): %v%s\n", ux.Red, err, ux.Reset)
+							allValid = false
+						}
 					}
 				}
 				for _, name := range workflows {
@@ -101,11 +129,16 @@ func validateCmd() *cli.Command {
 					}
 					cfg, err := runValidate(path, projectRoot)
 					if err != nil {
+						annotate(path, err)
 						fmt.Fprintf(os.Stderr, "%s\u2717 %s: %v%s\n", ux.Red, name, err, ux.Reset)
 						allValid = false
 					} else {
 						fmt.Printf("\n%s--- Workflow: %s ---%s\n", ux.Bold, name, ux.Reset)
 						printConfigSummary(os.Stdout, cfg, projectRoot)
+						if err := reportVarAudit(os.Stderr, cfg, projectRoot, strict); err != nil {
+							fmt.Fprintf(os.Stderr, "%s\u2717 %s: %v%s\n", ux.Red, name, err, ux.Reset)
+							allValid = false
+						}
 					}
 				}
 				if !allValid {
@@ -116,15 +149,71 @@ func validateCmd() *cli.Command {
 
 			cfg, err := runValidate(configPath, projectRoot)
 			if err != nil {
+				annotate(configPath, err)
 				return cfgErr(err)
 			}
 
 			printConfigSummary(os.Stdout, cfg, projectRoot)
+			if err := reportVarAudit(os.Stderr, cfg, projectRoot, strict); err != nil {
+				return cfgErr(err)
+			}
 			return nil
 		},
 	}
 }
 
+// yamlLineRe extracts the line number from yaml.v3 parse and type errors.
+var yamlLineRe = regexp.MustCompile(`\bline (\d+):`)
+
+// githubAnnotation formats a config error as a GitHub Actions workflow
+// command (::error file=...,line=...::message) so CI marks the offending line.
+// file is made relative to projectRoot. Line and column come from a
+// config.PositionError, or from the yaml parser's message; they are omitted
+// when unknown.
+func githubAnnotation(file, projectRoot string, err error) string {
+	if rel, relErr := filepath.Rel(projectRoot, file); relErr == nil && !strings.HasPrefix(rel, "..") {
+		file = rel
+	}
+	props := "file=" + escapeAnnotationProperty(filepath.ToSlash(file))
+	var posErr *config.PositionError
+	if errors.As(err, &posErr) {
+		props += fmt.Sprintf(",line=%d,col=%d", posErr.Line, posErr.Column)
+	} else if m := yamlLineRe.FindStringSubmatch(err.Error()); m != nil {
+		props += ",line=" + m[1]
+	}
+	return "::error " + props + "::" + escapeAnnotationData(err.Error())
+}
+
+// escapeAnnotationData escapes a workflow command message.
+func escapeAnnotationData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeAnnotationProperty escapes a workflow command property value.
+func escapeAnnotationProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// reportVarAudit prints a warning for each unreachable phase and each
+// undefined variable reference. In strict mode, any undefined reference is
+// returned as an error instead; unreachable phases stay warnings.
+func reportVarAudit(w io.Writer, cfg *config.Config, projectRoot string, strict bool) error {
+	for _, msg := range config.AuditReachability(cfg) {
+		fmt.Fprintf(w, "%swarning:%s %s\n", ux.Yellow, ux.Reset, msg)
+	}
+	warnings := config.AuditVars(cfg, projectRoot)
+	if len(warnings) == 0 {
+		return nil
+	}
+	if strict {
+		return fmt.Errorf("undefined variables:\n  %s\n  (define them under 'vars', or list expected environment variables under 'expected-env')", strings.Join(warnings, "\n  "))
+	}
+	for _, msg := range warnings {
+		fmt.Fprintf(w, "%swarning:%s %s\n", ux.Yellow, ux.Reset, msg)
+	}
+	return nil
+}
+
 // printConfigSummary prints a human-readable summary of a validated config.
 func printConfigSummary(w io.Writer, cfg *config.Config, projectRoot string) {
 	// Header
@@ -150,12 +239,14 @@ func printConfigSummary(w io.Writer, cfg *config.Config, projectRoot string) {
 	fmt.Fprintf(w, "  %-14s %s(built-in)%s\n", "ARTIFACTS_DIR", ux.Dim, ux.Reset)
 	fmt.Fprintf(w, "  %-14s %s(built-in)%s\n", "WORK_DIR", ux.Dim, ux.Reset)
 	fmt.Fprintf(w, "  %-14s %s(built-in)%s\n", "PROJECT_ROOT", ux.Dim, ux.Reset)
-	fmt.Fprintf(w, "  %s(per-phase: PHASE_INDEX, PHASE_COUNT)%s\n", ux.Dim, ux.Reset)
+	fmt.Fprintf(w, "  %-14s %s(built-in)%s\n", "WORKFLOW_NAME", ux.Dim, ux.Reset)
+	fmt.Fprintf(w, "  %s(per-phase: PHASE_INDEX, PHASE_COUNT, PHASE_DESCRIPTION)%s\n", ux.Dim, ux.Reset)
 
 	if len(cfg.Vars) > 0 {
 		builtins := map[string]string{
 			"TICKET":        "<ticket>",
 			"WORKFLOW":      "<workflow>",
+			"WORKFLOW_NAME": cfg.Name,
 			"ARTIFACTS_DIR": "<artifacts>",
 			"WORK_DIR":      projectRoot,
 			"PROJECT_ROOT":  projectRoot,
@@ -175,14 +266,16 @@ func printConfigSummary(w io.Writer, cfg *config.Config, projectRoot string) {
 		switch p.Type {
 		case "agent":
 			fmt.Fprintf(w, "  agent   model=%s effort=%s timeout=%dm prompt=%s\n", p.Model, p.Effort, p.Timeout, p.Prompt)
-		case "script":
+		case "script", "publish":
 			cmd := p.Run
 			if len(cmd) > 60 {
 				cmd = cmd[:57] + "..."
 			}
-			fmt.Fprintf(w, "  script  timeout=%dm  run: %s\n", p.Timeout, cmd)
+			fmt.Fprintf(w, "  %s  timeout=%dm  run: %s\n", p.Type, p.Timeout, cmd)
 		case "gate":
 			fmt.Fprintf(w, "  gate\n")
+		case "manual":
+			fmt.Fprintf(w, "  manual\n")
 		case "workflow":
 			fmt.Fprintf(w, "  workflow  ref=%s\n", p.WorkflowRef)
 		case "branch":
@@ -199,8 +292,11 @@ func printConfigSummary(w io.Writer, cfg *config.Config, projectRoot string) {
 
 		// Sub-lines
 		indent := strings.Repeat(" ", 18)
+		if p.Disabled {
+			fmt.Fprintf(w, "%sdisabled: true (skipped at run time)\n", indent)
+		}
 		if len(p.Outputs) > 0 {
-			fmt.Fprintf(w, "%soutputs: [%s]\n", indent, strings.Join(p.Outputs, ", "))
+			fmt.Fprintf(w, "%soutputs: [%s]\n", indent, strings.Join(cfg.OutputPaths(p), ", "))
 		}
 		if p.Loop != nil {
 			fmt.Fprintf(w, "%sloop: goto %s (min %d, max %d)\n", indent, p.Loop.Goto, p.Loop.Min, p.Loop.Max)
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,6 +13,75 @@ import (
 
 // --- runValidate tests ---
 
+func TestReportVarAudit_WarnsByDefault(t *testing.T) {
+	cfg := &config.Config{Name: "t", Phases: []config.Phase{{Name: "build", Type: "script", Run: "make $TIKCET"}}}
+	var buf bytes.Buffer
+	if err := reportVarAudit(&buf, cfg, t.TempDir(), false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "warning:") || !strings.Contains(buf.String(), "$TIKCET") {
+		t.Fatalf("expected warning for $TIKCET, got %q", buf.String())
+	}
+}
+
+func TestReportVarAudit_StrictErrors(t *testing.T) {
+	cfg := &config.Config{Name: "t", Phases: []config.Phase{{Name: "build", Type: "script", Run: "make $TIKCET"}}}
+	var buf bytes.Buffer
+	err := reportVarAudit(&buf, cfg, t.TempDir(), true)
+	if err == nil || !strings.Contains(err.Error(), "$TIKCET") {
+		t.Fatalf("expected strict error for $TIKCET, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("strict mode should not print warnings, got %q", buf.String())
+	}
+}
+
+func TestGithubAnnotation_DuplicatePhaseName(t *testing.T) {
+	root := t.TempDir()
+	cfgContent := `name: test-wf
+phases:
+  - name: build
+    type: script
+    run: make build
+  - name: build
+    type: script
+    run: make test
+`
+	configPath := filepath.Join(root, ".orc", "config.yaml")
+	os.MkdirAll(filepath.Dir(configPath), 0755)
+	os.WriteFile(configPath, []byte(cfgContent), 0644)
+
+	_, err := runValidate(configPath, root)
+	if err == nil {
+		t.Fatal("expected duplicate phase error")
+	}
+	got := githubAnnotation(configPath, root, err)
+	want := `::error file=.orc/config.yaml,line=6,col=5::config: duplicate phase name "build"`
+	if got != want {
+		t.Fatalf("annotation = %q, want %q", got, want)
+	}
+}
+
+func TestGithubAnnotation_YAMLSyntaxErrorAndEscaping(t *testing.T) {
+	root := t.TempDir()
+	configPath := filepath.Join(root, "config.yaml")
+	os.WriteFile(configPath, []byte("name: x\nphases:\n  - name: [unclosed\n"), 0644)
+
+	_, err := runValidate(configPath, root)
+	if err == nil {
+		t.Fatal("expected parse error")
+	}
+	got := githubAnnotation(configPath, root, err)
+	if !strings.HasPrefix(got, "::error file=config.yaml,line=") {
+		t.Fatalf("expected annotation with line, got %q", got)
+	}
+
+	got = githubAnnotation(configPath, root, fmt.Errorf("first\nsecond 100%%"))
+	if got != "::error file=config.yaml::first%0Asecond 100%25" {
+		t.Fatalf("expected escaped message without line, got %q", got)
+	}
+}
+
 func TestRunValidate_ValidConfig(t *testing.T) {
 	root := t.TempDir()
 	cfgContent := `name: test-wf
@@ -363,3 +433,63 @@ phases:
 		t.Errorf("Name = %q, want bugfix-wf", cfg2.Name)
 	}
 }
+
+func TestPrintConfig_ResolvedShowsDefaults(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, ".orc"), 0755)
+	os.WriteFile(filepath.Join(root, ".orc", "plan.md"), []byte("plan it"), 0644)
+	configPath := filepath.Join(root, ".orc", "config.yaml")
+	os.WriteFile(configPath, []byte(`name: resolved
+phases:
+  - name: plan
+    type: agent
+    prompt: .orc/plan.md
+`), 0644)
+
+	var raw bytes.Buffer
+	if err := printConfig(&raw, configPath, root, false); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(raw.String(), "model:") {
+		t.Fatalf("unresolved output should be the file as written, got:\n%s", raw.String())
+	}
+
+	var buf bytes.Buffer
+	if err := printConfig(&buf, configPath, root, true); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"model: opus", "timeout: 30", "effort: high", "history-limit: 10"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("resolved output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestUpgradeConfig_WritesBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	orig := "name: demo\nphases:\n  - name: a\n    type: script\n    run: echo\n"
+	os.WriteFile(path, []byte(orig), 0644)
+
+	var buf bytes.Buffer
+	if err := upgradeConfig(&buf, path, false); err != nil {
+		t.Fatal(err)
+	}
+	if backup, _ := os.ReadFile(path + ".bak"); string(backup) != orig {
+		t.Fatalf("backup = %q, want the original config", backup)
+	}
+	upgraded, _ := os.ReadFile(path)
+	if !strings.Contains(string(upgraded), "config-version: 1") {
+		t.Fatalf("upgraded config missing config-version:\n%s", upgraded)
+	}
+
+	// Running again is a no-op.
+	buf.Reset()
+	if err := upgradeConfig(&buf, path, false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "already at config-version") {
+		t.Fatalf("second run should report the config is current, got %q", buf.String())
+	}
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+)
+
+// builtinVars are the variables orc always provides to prompts and commands.
+var builtinVars = []string{
+	"TICKET", "WORKFLOW", "ARTIFACTS_DIR", "WORK_DIR", "PROJECT_ROOT",
+	"PHASE_INDEX", "PHASE_COUNT", "WORKFLOW_NAME", "PHASE_DESCRIPTION",
+	"PHASE_OUTPUT_DIR", "DELIVERABLES",
+}
+
+// standardEnvVars are common process environment variables that are always
+// considered defined.
+var standardEnvVars = []string{
+	"HOME", "PATH", "USER", "SHELL", "PWD", "TMPDIR", "LANG", "TERM",
+}
+
+// varRefRe matches $NAME and ${NAME...} references. Only upper-case names are
+// audited — lower-case names are almost always shell locals in run commands.
+var varRefRe = regexp.MustCompile(`\$(\{)?([A-Z_][A-Z0-9_]*)(:?[-=?+])?`)
+
+// shellAssignRe matches in-command assignments (FOO=bar, export FOO=bar,
+// for FOO in ...) so scripts that define their own variables aren't flagged.
+var shellAssignRe = regexp.MustCompile(`(?:^|[\s;&|(])(?:export\s+|local\s+|readonly\s+)?([A-Z_][A-Z0-9_]*)=|\b(?:for|read)\s+([A-Z_][A-Z0-9_]*)\b`)
+
+// AuditVars reports variable references in run, cwd, condition, hook,
+// check, verify, and on-success commands and in agent prompt files that
+// cannot be resolved from built-ins, config vars, the expected-env
+// allowlist, or the phase's own secret-env. It returns one
+// warning per undefined reference, in phase order. Validate must have
+// succeeded first.
+func AuditVars(cfg *Config, projectRoot string) []string {
+	known := make(map[string]bool)
+	for _, name := range builtinVars {
+		known[name] = true
+		known["ORC_"+name] = true
+	}
+	for _, name := range standardEnvVars {
+		known[name] = true
+	}
+	for _, v := range cfg.Vars {
+		known[v.Key] = true
+		known["ORC_"+v.Key] = true
+	}
+	for _, name := range cfg.ExpectedEnv {
+		known[name] = true
+	}
+
+	var warnings []string
+	// secrets holds the current phase's secret-env names, which only that
+	// phase's commands can see.
+	var secrets map[string]string
+	check := func(phase, field, text string, shell bool) {
+		local := make(map[string]bool)
+		if shell {
+			for _, m := range shellAssignRe.FindAllStringSubmatch(text, -1) {
+				local[m[1]] = true
+				local[m[2]] = true
+			}
+		}
+		seen := make(map[string]bool)
+		var undefined []string
+		for _, m := range varRefRe.FindAllStringSubmatch(text, -1) {
+			name := m[2]
+			// ${NAME:-default} and friends handle the unset case themselves.
+			if m[1] == "{" && m[3] != "" {
+				continue
+			}
+			_, secret := secrets[name]
+			if known[name] || secret || local[name] || seen[name] {
+				continue
+			}
+			seen[name] = true
+			undefined = append(undefined, name)
+		}
+		sort.Strings(undefined)
+		for _, name := range undefined {
+			warnings = append(warnings, fmt.Sprintf("phase %q: %s references undefined variable $%s", phase, field, name))
+		}
+	}
+
+	for _, p := range cfg.Phases {
+		secrets = p.SecretEnv
+		check(p.Name, "run", p.Run, true)
+		check(p.Name, "cwd", p.Cwd, false)
+		check(p.Name, "condition", p.Condition, true)
+		check(p.Name, "pre-run", p.PreRun, true)
+		check(p.Name, "post-run", p.PostRun, true)
+		check(p.Name, "check", p.Check, true)
+		check(p.Name, "verify", p.Verify, true)
+		check(p.Name, "on-success", p.OnSuccess, true)
+		check(p.Name, "stdin", p.Stdin, false)
+		check(p.Name, "stdin-file", p.StdinFile, false)
+		if p.Loop != nil {
+			check(p.Name, "loop.check", p.Loop.Check, true)
+		}
+		if p.Type == "gate" {
+			check(p.Name, "prompt", p.Prompt, false)
+		}
+		if p.Type == "agent" && p.Prompt != "" {
+			path, err := PromptPath(projectRoot, p.Prompt)
+			if err != nil {
+				continue
+			}
+			if data, err := os.ReadFile(path); err == nil {
+				check(p.Name, fmt.Sprintf("prompt %s", p.Prompt), string(data), false)
+			}
+		}
+	}
+	return warnings
+}
+
+// AuditReachability reports phases no run can reach from the first phase.
+// Conditions are treated as unknown, so a phase is reachable if any path of
+// fall-throughs, loop and on-exhaust gotos, and gate on-reject gotos leads
+// to it. A parallel group dispatches both partners and continues after the
+// later one, so phases placed between non-adjacent partners are reachable
+// only through a goto. It returns one warning per unreachable phase, in
+// phase order. Validate must have succeeded first.
+func AuditReachability(cfg *Config) []string {
+	n := len(cfg.Phases)
+	reached := make([]bool, n)
+	queue := []int{0}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		if i < 0 || i >= n || reached[i] {
+			continue
+		}
+		reached[i] = true
+		p := cfg.Phases[i]
+		next := i + 1
+		if p.ParallelWith != "" {
+			// A disabled partner leaves this phase to run alone.
+			if partner, j, ok := cfg.PhaseByName(p.ParallelWith); ok && j > i && !partner.Disabled {
+				next = j + 1
+				queue = append(queue, j)
+			}
+		}
+		queue = append(queue, next)
+		if p.Loop != nil {
+			queue = append(queue, cfg.PhaseIndex(p.Loop.Goto))
+			if p.Loop.OnExhaust != nil && p.Loop.OnExhaust.Goto != "" {
+				queue = append(queue, cfg.PhaseIndex(p.Loop.OnExhaust.Goto))
+			}
+		}
+		if p.Type == "gate" && p.OnReject != nil && p.OnReject.Action == "goto" {
+			queue = append(queue, cfg.PhaseIndex(p.OnReject.Goto))
+		}
+	}
+
+	var warnings []string
+	for i, p := range cfg.Phases {
+		if !reached[i] {
+			warnings = append(warnings, fmt.Sprintf("phase %q is unreachable: no path from the first phase leads to it", p.Name))
+		}
+	}
+	return warnings
+}
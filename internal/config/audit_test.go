@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditVars_TypoInRun(t *testing.T) {
+	cfg := minimalConfig(Phase{Name: "build", Type: "script", Run: "make $TIKCET"})
+	warnings := AuditVars(cfg, t.TempDir())
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %v", warnings)
+	}
+	if !strings.Contains(warnings[0], `phase "build"`) || !strings.Contains(warnings[0], "$TIKCET") {
+		t.Fatalf("unexpected warning: %q", warnings[0])
+	}
+}
+
+func TestAuditVars_KnownVarsClean(t *testing.T) {
+	cfg := minimalConfig(
+		Phase{Name: "a", Type: "script", Run: "echo $TICKET ${ARTIFACTS_DIR} $ORC_WORK_DIR $SRC $ORC_SRC $HOME"},
+		Phase{Name: "b", Type: "script", Run: "echo ok", Cwd: "$PROJECT_ROOT/sub", Condition: "test -n \"$CI_TOKEN\""},
+	)
+	cfg.Vars = OrderedVars{{Key: "SRC", Value: "src"}}
+	cfg.ExpectedEnv = []string{"CI_TOKEN"}
+	if warnings := AuditVars(cfg, t.TempDir()); len(warnings) != 0 {
+		t.Fatalf("expected no warnings, got %v", warnings)
+	}
+}
+
+func TestAuditVars_ShellLocalsIgnored(t *testing.T) {
+	cfg := minimalConfig(Phase{
+		Name: "a", Type: "script",
+		Run: "OUT=$(ls); for F in $OUT; do echo $F $lower; done; echo ${MAYBE:-none}",
+	})
+	if warnings := AuditVars(cfg, t.TempDir()); len(warnings) != 0 {
+		t.Fatalf("expected no warnings, got %v", warnings)
+	}
+}
+
+func TestAuditVars_PromptFile(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".orc", "phases"), 0755)
+	os.WriteFile(filepath.Join(dir, ".orc", "phases", "plan.md"), []byte("Plan $TICKET into $ARTIFACT_DIR/plan.md"), 0644)
+
+	cfg := minimalConfig(Phase{Name: "plan", Type: "agent", Prompt: ".orc/phases/plan.md"})
+	warnings := AuditVars(cfg, dir)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "$ARTIFACT_DIR") || !strings.Contains(warnings[0], "plan.md") {
+		t.Fatalf("expected prompt warning for $ARTIFACT_DIR, got %v", warnings)
+	}
+}
+
+func TestAuditVars_ReportsEachNameOnce(t *testing.T) {
+	cfg := minimalConfig(Phase{Name: "a", Type: "script", Run: "echo $NOPE $NOPE", PostRun: "echo $NOPE"})
+	warnings := AuditVars(cfg, t.TempDir())
+	if len(warnings) != 2 {
+		t.Fatalf("expected one warning per field, got %v", warnings)
+	}
+}
+
+func TestAuditVars_Verify(t *testing.T) {
+	cfg := minimalConfig(Phase{Name: "report", Type: "script", Run: "make report", Verify: "jq empty $ARTIFACTS_DIR/$REPROT_FILE"})
+	warnings := AuditVars(cfg, t.TempDir())
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `phase "report": verify references undefined variable $REPROT_FILE`) {
+		t.Fatalf("expected verify warning for $REPROT_FILE, got %v", warnings)
+	}
+}
+
+func TestAuditVars_OnSuccess(t *testing.T) {
+	cfg := minimalConfig(Phase{Name: "deploy", Type: "script", Run: "make deploy", OnSuccess: "curl -X POST $SLACK_HOOK_URL"})
+	warnings := AuditVars(cfg, t.TempDir())
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `phase "deploy": on-success references undefined variable $SLACK_HOOK_URL`) {
+		t.Fatalf("expected on-success warning for $SLACK_HOOK_URL, got %v", warnings)
+	}
+}
+
+func TestAuditVars_SecretEnvScopedToPhase(t *testing.T) {
+	cfg := minimalConfig(
+		Phase{Name: "push", Type: "script", Run: "gh pr create", SecretEnv: map[string]string{"GITHUB_TOKEN": "GH_PAT"},
+			PostRun: "test -n \"$GITHUB_TOKEN\""},
+		Phase{Name: "ship", Type: "publish", Run: "upload $GITHUB_TOKEN"},
+	)
+	warnings := AuditVars(cfg, t.TempDir())
+	joined := strings.Join(warnings, "\n")
+	if strings.Contains(joined, `phase "push"`) {
+		t.Errorf("secret-env key flagged in its own phase: %v", warnings)
+	}
+	if want := `phase "ship": run references undefined variable $GITHUB_TOKEN`; !strings.Contains(joined, want) {
+		t.Errorf("missing warning %q in %v", want, warnings)
+	}
+}
+
+func TestValidate_ExpectedEnvInvalidName(t *testing.T) {
+	cfg := minimalConfig(scriptPhase("a"))
+	cfg.ExpectedEnv = []string{"BAD-NAME"}
+	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "expected-env") {
+		t.Fatalf("expected expected-env error, got %v", err)
+	}
+}
+
+func TestAuditReachability(t *testing.T) {
+	review := func(onReject *OnReject) Phase {
+		return Phase{Name: "review", Type: "gate", OnReject: onReject}
+	}
+	lint := Phase{Name: "lint", Type: "script", Run: "make lint", ParallelWith: "test"}
+	tests := []struct {
+		name        string
+		phases      []Phase
+		unreachable []string
+	}{
+		{"sequential", []Phase{review(nil), scriptPhase("lint"), scriptPhase("docs"), scriptPhase("test")}, nil},
+		{"between parallel partners", []Phase{review(nil), lint, scriptPhase("docs"), scriptPhase("test"), scriptPhase("ship")}, []string{"docs"}},
+		// The forward goto lands past docs, so it adds no path to it.
+		{"forward on-reject goto past the gap", []Phase{review(&OnReject{Action: "goto", Goto: "test"}), lint, scriptPhase("docs"), scriptPhase("test"), scriptPhase("ship")}, []string{"docs"}},
+		{"forward on-reject goto into the gap", []Phase{review(&OnReject{Action: "goto", Goto: "docs"}), lint, scriptPhase("docs"), scriptPhase("test"), scriptPhase("ship")}, nil},
+		{"disabled partner", []Phase{review(nil), lint, scriptPhase("docs"), {Name: "test", Type: "script", Run: "make test", Disabled: true}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := minimalConfig(tt.phases...)
+			if err := Validate(cfg, t.TempDir()); err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			warnings := AuditReachability(cfg)
+			if len(warnings) != len(tt.unreachable) {
+				t.Fatalf("expected warnings for %v, got %v", tt.unreachable, warnings)
+			}
+			for i, name := range tt.unreachable {
+				if !strings.Contains(warnings[i], fmt.Sprintf("phase %q is unreachable", name)) {
+					t.Errorf("warning %d = %q, want phase %q", i, warnings[i], name)
+				}
+			}
+		})
+	}
+}
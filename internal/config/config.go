@@ -1,9 +1,11 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -13,15 +15,15 @@ import (
 // OnFail is kept for YAML parsing so we can provide a migration error.
 // It is rejected at validation time — use Loop instead.
 type OnFail struct {
-	Goto string `yaml:"goto"`
-	Max  int    `yaml:"max"`
+	Goto string `yaml:"goto,omitempty"`
+	Max  int    `yaml:"max,omitempty"`
 }
 
 // OnExhaust defines outer recovery when a loop exhausts.
 // Accepts both string form (on-exhaust: plan) and object form (on-exhaust: {goto: plan, max: 2}).
 type OnExhaust struct {
-	Goto string `yaml:"goto"`
-	Max  int    `yaml:"max"`
+	Goto string `yaml:"goto,omitempty"`
+	Max  int    `yaml:"max,omitempty"`
 }
 
 // UnmarshalYAML allows on-exhaust to be a simple string (phase name) or an object.
@@ -34,40 +36,156 @@ func (oe *OnExhaust) UnmarshalYAML(value *yaml.Node) error {
 	return value.Decode((*plain)(oe))
 }
 
+// OnReject says what a gate does when the operator rejects it. Accepts a
+// string (on-reject: continue) or an object naming a phase to jump to
+// (on-reject: {goto: address-feedback}).
+type OnReject struct {
+	Action string `yaml:"action,omitempty"` // "stop" (default), "continue", or "goto"
+	Goto   string `yaml:"goto,omitempty"`   // goto: the phase to jump to
+}
+
+// UnmarshalYAML allows on-reject to be a simple string or an object.
+func (o *OnReject) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		o.Action = value.Value
+		return nil
+	}
+	type plain OnReject
+	if err := value.Decode((*plain)(o)); err != nil {
+		return err
+	}
+	if o.Action == "" && o.Goto != "" {
+		o.Action = "goto"
+	}
+	return nil
+}
+
+// Container runs a script phase inside a container instead of on the host.
+// It can be written as just the image (container: golang:1.22) or as an
+// object with a runtime and extra volume mounts.
+type Container struct {
+	Image   string   `yaml:"image"`
+	Runtime string   `yaml:"runtime,omitempty"` // "docker" (default) or "podman"
+	Volumes []string `yaml:"volumes,omitempty"` // extra host:container[:options] mounts, expanded with vars
+}
+
+// UnmarshalYAML allows container to be an image name or an object.
+func (c *Container) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		c.Image = value.Value
+		return nil
+	}
+	type plain Container
+	return value.Decode((*plain)(c))
+}
+
+// RuntimeOrDefault returns the container runtime binary, defaulting to docker.
+func (c *Container) RuntimeOrDefault() string {
+	if c.Runtime == "" {
+		return "docker"
+	}
+	return c.Runtime
+}
+
 // Loop defines a backward jump for convergent iteration or simple retry.
 type Loop struct {
-	Goto      string     `yaml:"goto"`
-	Min       int        `yaml:"min"`
-	Max       int        `yaml:"max"`
-	Check     string     `yaml:"check"`
-	OnExhaust *OnExhaust `yaml:"on-exhaust"`
+	Goto      string     `yaml:"goto,omitempty"`
+	Min       int        `yaml:"min,omitempty"`
+	Max       int        `yaml:"max,omitempty"`
+	Check     string     `yaml:"check,omitempty"`
+	OnExhaust *OnExhaust `yaml:"on-exhaust,omitempty"`
+	// FeedbackHistory keeps the failure output of the last N attempts in the
+	// phase's feedback file instead of only the latest; 0 or 1 keeps one.
+	FeedbackHistory int `yaml:"feedback-history,omitempty"`
 }
 
 type Phase struct {
-	Name         string            `yaml:"name"`
-	Type         string            `yaml:"type"`
-	Description  string            `yaml:"description"`
-	Prompt       string            `yaml:"prompt"`
-	Run          string            `yaml:"run"`
-	Model        string            `yaml:"model"`
-	Effort       string            `yaml:"effort"`
-	Timeout      int               `yaml:"timeout"`
-	MaxCost      float64           `yaml:"max-cost"`
-	Outputs      []string          `yaml:"outputs"`
-	AllowTools   []string          `yaml:"allow-tools"`
-	MCPConfig    string            `yaml:"mcp-config"`
-	Condition    string            `yaml:"condition"`
-	ParallelWith string            `yaml:"parallel-with"`
-	OnFail       *OnFail           `yaml:"on-fail"`
-	Loop         *Loop             `yaml:"loop"`
-	Cwd          string            `yaml:"cwd"`
-	PreRun       string            `yaml:"pre-run"`
-	PostRun      string            `yaml:"post-run"`
-	OnRateLimit  string            `yaml:"on-rate-limit"`      // "" (inherit from Config), "wait", or "exit"
-	WorkflowRef  string            `yaml:"workflow,omitempty"` // workflow/branch: name of a workflow in .orc/workflows/
-	Check        string            `yaml:"check,omitempty"`    // branch: shell cmd whose stdout selects a branch key
-	Branches     map[string]string `yaml:"branches,omitempty"` // branch: key → workflow name
-	Default      string            `yaml:"default,omitempty"`  // branch: fallback workflow if key unmatched
+	Name                string            `yaml:"name"`
+	Type                string            `yaml:"type"`
+	Description         string            `yaml:"description,omitempty"`
+	Disabled            bool              `yaml:"disabled,omitempty"`
+	Labels              []string          `yaml:"labels,omitempty"` // free-form tags selected by run --labels
+	Group               string            `yaml:"group,omitempty"`  // run summary section; consecutive phases only
+	Prompt              string            `yaml:"prompt,omitempty"`
+	Run                 string            `yaml:"run,omitempty"`
+	Model               string            `yaml:"model,omitempty"`
+	FallbackModel       string            `yaml:"fallback-model,omitempty"` // agent: model to retry a turn with when Model is overloaded
+	Effort              string            `yaml:"effort,omitempty"`
+	Timeout             int               `yaml:"timeout,omitempty"`
+	InactivityTimeout   int               `yaml:"inactivity-timeout,omitempty"` // minutes without output before the phase is killed; 0 disables
+	SecretEnv           map[string]string `yaml:"secret-env,omitempty"`         // child env var → parent env var it is read from; this phase only
+	MaxCost             float64           `yaml:"max-cost,omitempty"`
+	Outputs             []string          `yaml:"outputs,omitempty"`
+	AllowTools          []string          `yaml:"allow-tools,omitempty"`
+	MCPConfig           string            `yaml:"mcp-config,omitempty"`
+	ClaudeSettings      string            `yaml:"claude-settings,omitempty"`
+	Condition           string            `yaml:"condition,omitempty"`
+	ConditionTimeout    int               `yaml:"condition-timeout,omitempty"`    // seconds the condition may run; defaults to the config's, then 60
+	OnConditionTimeout  string            `yaml:"on-condition-timeout,omitempty"` // "skip" (default) or "fail"
+	ParallelWith        string            `yaml:"parallel-with,omitempty"`
+	OnFail              *OnFail           `yaml:"on-fail,omitempty"`
+	Loop                *Loop             `yaml:"loop,omitempty"`
+	Cwd                 string            `yaml:"cwd,omitempty"`
+	CreateCwd           bool              `yaml:"create-cwd,omitempty"`         // create the resolved cwd before the phase runs
+	SuccessExitCodes    []int             `yaml:"success-exit-codes,omitempty"` // script: extra exit codes that count as success
+	KeepGoing           bool              `yaml:"keep-going,omitempty"`         // parallel: a failing branch doesn't cancel its sibling
+	PreRun              string            `yaml:"pre-run,omitempty"`
+	PostRun             string            `yaml:"post-run,omitempty"`
+	OnSuccess           string            `yaml:"on-success,omitempty"`            // shell cmd run after post-run, only when the phase succeeded
+	OnSuccessFail       string            `yaml:"on-success-fail,omitempty"`       // "warn" (default) or "fail" when on-success exits non-zero
+	Verify              string            `yaml:"verify,omitempty"`                // shell cmd run after a successful dispatch and output checks; non-zero fails the phase
+	OnRateLimit         string            `yaml:"on-rate-limit,omitempty"`         // "" (inherit from Config), "wait", or "exit"
+	OnDenial            string            `yaml:"on-denial,omitempty"`             // agent, unattended: "ignore" (default), "fail", or "approve-and-retry"
+	InjectAllFeedback   bool              `yaml:"inject-all-feedback,omitempty"`   // agent: also prepend feedback already archived to the audit dir
+	WorkflowRef         string            `yaml:"workflow,omitempty"`              // workflow/branch: name of a workflow in .orc/workflows/
+	Check               string            `yaml:"check,omitempty"`                 // branch: shell cmd whose stdout selects a branch key
+	Branches            map[string]string `yaml:"branches,omitempty"`              // branch: key → workflow name
+	Default             string            `yaml:"default,omitempty"`               // branch: fallback workflow if key unmatched
+	RequirePhrase       string            `yaml:"require-phrase,omitempty"`        // gate: exact text the operator must type to approve
+	OnReject            *OnReject         `yaml:"on-reject,omitempty"`             // gate: stop (default), continue, or goto a phase when rejected
+	Stdin               string            `yaml:"stdin,omitempty"`                 // script: literal stdin content, expanded with vars
+	StdinFile           string            `yaml:"stdin-file,omitempty"`            // script: file fed to stdin; relative paths resolve against the artifacts dir
+	Container           *Container        `yaml:"container,omitempty"`             // script: run inside this container image
+	RequireFreshOutputs bool              `yaml:"require-fresh-outputs,omitempty"` // outputs older than this dispatch's start count as missing
+	OutputsOptional     bool              `yaml:"outputs-optional,omitempty"`      // missing outputs are reported but don't re-prompt or fail the phase
+
+	line, column int // position of the phase in the YAML source; 0 if not loaded from YAML
+}
+
+// UnmarshalYAML decodes a phase and records where it appears in the source
+// so validation errors can point at it.
+func (p *Phase) UnmarshalYAML(value *yaml.Node) error {
+	type plain Phase
+	if err := value.Decode((*plain)(p)); err != nil {
+		return err
+	}
+	p.line, p.column = value.Line, value.Column
+	return nil
+}
+
+// PositionError is a config error tied to a line and column in the YAML
+// source. Its message is the wrapped error's, unchanged.
+type PositionError struct {
+	Line, Column int
+	Err          error
+}
+
+func (e *PositionError) Error() string { return e.Err.Error() }
+func (e *PositionError) Unwrap() error { return e.Err }
+
+// locate attaches the phase's source position to err, if known.
+func (p Phase) locate(err error) error {
+	if p.line == 0 {
+		return err
+	}
+	return &PositionError{Line: p.line, Column: p.column, Err: err}
+}
+
+// SucceedsWith reports whether a dispatch exiting with code counts as a
+// success for this phase: 0 always does, as does any code listed in
+// success-exit-codes.
+func (p Phase) SucceedsWith(code int) bool {
+	return code == 0 || slices.Contains(p.SuccessExitCodes, code)
 }
 
 // VarEntry holds a single key-value pair from the vars map.
@@ -101,21 +219,164 @@ func (ov *OrderedVars) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+// MarshalYAML writes the vars back as a mapping in declaration order.
+func (ov OrderedVars) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, v := range ov {
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v.Key},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v.Value},
+		)
+	}
+	return node, nil
+}
+
 type Config struct {
-	Name              string      `yaml:"name"`
-	TicketPattern     string      `yaml:"ticket-pattern"`
-	DefaultAllowTools []string    `yaml:"default-allow-tools"`
-	Model             string      `yaml:"model"`
-	Cwd               string      `yaml:"cwd"`
-	Effort            string      `yaml:"effort"`
-	MaxCost           float64     `yaml:"max-cost"`
-	HistoryLimit      int         `yaml:"history-limit"`
-	Vars              OrderedVars `yaml:"vars"`
-	OnRateLimit       string      `yaml:"on-rate-limit"` // "" (default: exit), "wait", or "exit"
-	Phases            []Phase     `yaml:"phases"`
-}
-
-// Load reads a YAML config file and returns a validated Config.
+	Name               string      `yaml:"name"`
+	ConfigVersion      int         `yaml:"config-version,omitempty"` // schema version; see 'orc upgrade-config'
+	TicketPattern      string      `yaml:"ticket-pattern,omitempty"`
+	DefaultAllowTools  []string    `yaml:"default-allow-tools,omitempty"`
+	ToolsFile          string      `yaml:"tools-file,omitempty"` // YAML list of tools merged into default-allow-tools; relative to project root
+	ClaudeSettings     string      `yaml:"claude-settings,omitempty"`
+	Model              string      `yaml:"model,omitempty"`
+	Cwd                string      `yaml:"cwd,omitempty"`
+	Effort             string      `yaml:"effort,omitempty"`
+	MaxCost            float64     `yaml:"max-cost,omitempty"`
+	WorkflowRetries    int         `yaml:"workflow-retries,omitempty"` // re-run a failed workflow from phase 1 up to this many times
+	HistoryLimit       int         `yaml:"history-limit,omitempty"`
+	Vars               OrderedVars `yaml:"vars,omitempty"`
+	ExportVars         *ExportVars `yaml:"export-vars,omitempty"` // which vars reach child process environments; nil exports all
+	ExpectedEnv        []string    `yaml:"expected-env,omitempty"`
+	OnRateLimit        string      `yaml:"on-rate-limit,omitempty"`         // "" (default: exit), "wait", or "exit"
+	ArtifactsGitignore *bool       `yaml:"artifacts-gitignore,omitempty"`   // nil means true
+	MaxStreamLineBytes int         `yaml:"max-stream-line-bytes,omitempty"` // 0 means the dispatch default (16MB)
+	MaxPromptBytes     int         `yaml:"max-prompt-bytes,omitempty"`      // dry-run warns about larger rendered prompts; 0 means the dispatch default
+	OutputsDir         string      `yaml:"outputs-dir,omitempty"`           // directory (relative to artifacts) that declared outputs resolve under
+	DoctorPrompt       string      `yaml:"doctor-prompt,omitempty"`         // file (relative to project root) with extra 'orc doctor' instructions
+	PhaseOutputDirs    bool        `yaml:"phase-output-dirs,omitempty"`     // each phase's outputs resolve under outputs/<phase>/
+	ConditionTimeout   int         `yaml:"condition-timeout,omitempty"`     // default seconds a phase condition may run; 0 means 60
+	ArtifactsPrefix    string      `yaml:"artifacts-prefix,omitempty"`      // prepended to phase log/prompt and state file names
+	Deliverables       []string    `yaml:"deliverables,omitempty"`          // the run's results, relative to artifacts; see 'orc get'
+	Phases             []Phase     `yaml:"phases"`
+}
+
+// ExportVars limits which custom vars are exported to child process
+// environments. Unexported vars are still expanded in prompts and other
+// templates. At most one of Only and Except may be set.
+type ExportVars struct {
+	Only   []string `yaml:"only,omitempty"`   // export just these vars
+	Except []string `yaml:"except,omitempty"` // export every var but these
+}
+
+// UnexportedVars returns the names of the config's vars that export-vars
+// keeps out of child environments, or nil when every var is exported.
+func (c *Config) UnexportedVars() map[string]bool {
+	if c.ExportVars == nil {
+		return nil
+	}
+	hidden := make(map[string]bool)
+	for _, v := range c.Vars {
+		if len(c.ExportVars.Only) > 0 && !slices.Contains(c.ExportVars.Only, v.Key) ||
+			slices.Contains(c.ExportVars.Except, v.Key) {
+			hidden[v.Key] = true
+		}
+	}
+	return hidden
+}
+
+// GitignoreArtifacts reports whether runs should keep a catch-all .gitignore
+// in the artifacts directory. Defaults to true.
+func (c *Config) GitignoreArtifacts() bool {
+	return c.ArtifactsGitignore == nil || *c.ArtifactsGitignore
+}
+
+// OutputDir returns the directory, relative to the artifacts directory, that
+// p's declared outputs resolve under: outputs-dir (or "outputs" when unset)
+// joined with the phase name when phase-output-dirs is on, otherwise
+// outputs-dir itself. "" means the artifacts directory.
+func (c *Config) OutputDir(p Phase) string {
+	if !c.PhaseOutputDirs {
+		return c.OutputsDir
+	}
+	base := c.OutputsDir
+	if base == "" {
+		base = "outputs"
+	}
+	return filepath.Join(base, p.Name)
+}
+
+// OutputTypes are the content types an output may be annotated with
+// ("report.json:json") to have its content validated, not just its existence.
+var OutputTypes = []string{"json", "yaml", "markdown"}
+
+// SplitOutput splits a declared output into its filename and optional
+// content type: "plan.md:markdown" → ("plan.md", "markdown").
+func SplitOutput(o string) (name, kind string) {
+	if i := strings.LastIndex(o, ":"); i >= 0 {
+		return o[:i], o[i+1:]
+	}
+	return o, ""
+}
+
+// OutputPaths returns p's declared outputs as paths relative to the
+// artifacts directory, placed under OutputDir(p), without type annotations.
+func (c *Config) OutputPaths(p Phase) []string {
+	dir := c.OutputDir(p)
+	paths := make([]string, len(p.Outputs))
+	for i, o := range p.Outputs {
+		name, _ := SplitOutput(o)
+		paths[i] = filepath.Join(dir, name)
+	}
+	return paths
+}
+
+// OutputContentTypes maps the path (as from OutputPaths) of each of p's
+// type-annotated outputs to its content type.
+func (c *Config) OutputContentTypes(p Phase) map[string]string {
+	types := make(map[string]string)
+	for i, path := range c.OutputPaths(p) {
+		if _, kind := SplitOutput(p.Outputs[i]); kind != "" {
+			types[path] = kind
+		}
+	}
+	return types
+}
+
+// PromptPath returns the absolute path of an agent phase's prompt file,
+// which is relative to projectRoot. A prompt that resolves outside the
+// project root after cleaning (../../etc/passwd) is an error, so a config
+// from an untrusted source can't read arbitrary files into a prompt.
+func PromptPath(projectRoot, prompt string) (string, error) {
+	path := filepath.Join(projectRoot, prompt)
+	if rel, err := filepath.Rel(projectRoot, path); err != nil || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("prompt %q resolves outside the project root", prompt)
+	}
+	return path, nil
+}
+
+// DeliverablePaths returns the deliverables as paths relative to the
+// artifacts directory. A deliverable that names a phase's declared output
+// resolves where that output does (see OutputPaths); any other is taken
+// as-is.
+func (c *Config) DeliverablePaths() []string {
+	paths := make([]string, len(c.Deliverables))
+	for i, d := range c.Deliverables {
+		paths[i] = d
+	phases:
+		for _, p := range c.Phases {
+			for j, o := range p.Outputs {
+				if name, _ := SplitOutput(o); name == d {
+					paths[i] = c.OutputPaths(p)[j]
+					break phases
+				}
+			}
+		}
+	}
+	return paths
+}
+
+// Load reads a YAML config file, merges its tools-file into
+// default-allow-tools, and returns a validated Config.
 func Load(path, projectRoot string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -125,12 +386,100 @@ func Load(path, projectRoot string) (*Config, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, err
 	}
+	if err := MergeToolsFile(&cfg, projectRoot); err != nil {
+		return nil, err
+	}
 	if err := Validate(&cfg, projectRoot); err != nil {
 		return nil, err
 	}
 	return &cfg, nil
 }
 
+// MergeToolsFile appends the entries of cfg's tools-file, if any, to its
+// default-allow-tools. Load calls it before Validate; callers that
+// unmarshal a config themselves must too.
+func MergeToolsFile(cfg *Config, projectRoot string) error {
+	if cfg.ToolsFile == "" {
+		return nil
+	}
+	tools, err := loadToolsFile(cfg.ToolsFile, projectRoot)
+	if err != nil {
+		return fmt.Errorf("config: tools-file %q: %w", cfg.ToolsFile, err)
+	}
+	cfg.DefaultAllowTools = mergeTools(cfg.DefaultAllowTools, tools)
+	return nil
+}
+
+// loadToolsFile reads a tools-file: a YAML list of allow-tools entries.
+// Each entry is checked like an inline default-allow-tools entry.
+func loadToolsFile(path, projectRoot string) ([]string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectRoot, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tools []string
+	if err := yaml.Unmarshal(data, &tools); err != nil {
+		return nil, fmt.Errorf("must be a YAML list of tool names: %w", err)
+	}
+	for _, tool := range tools {
+		if err := checkToolEntry(tool); err != nil {
+			return nil, err
+		}
+	}
+	return tools, nil
+}
+
+// mergeTools appends the entries of extra not already in tools, keeping
+// first-seen order.
+func mergeTools(tools, extra []string) []string {
+	seen := make(map[string]bool, len(tools)+len(extra))
+	var merged []string
+	for _, list := range [][]string{tools, extra} {
+		for _, tool := range list {
+			if !seen[tool] {
+				seen[tool] = true
+				merged = append(merged, tool)
+			}
+		}
+	}
+	return merged
+}
+
+// ReadArtifactsPrefix returns the artifacts-prefix declared by the config at
+// path without loading the rest of it. It returns "" when the file can't be
+// read or the prefix is invalid; Load reports those errors.
+func ReadArtifactsPrefix(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var cfg struct {
+		ArtifactsPrefix string `yaml:"artifacts-prefix"`
+	}
+	if yaml.Unmarshal(data, &cfg) != nil || ValidateArtifactsPrefix(cfg.ArtifactsPrefix) != nil {
+		return ""
+	}
+	return cfg.ArtifactsPrefix
+}
+
+// Marshal renders cfg as YAML. Fields left at their zero value are omitted,
+// so a validated config shows exactly the effective, defaulted settings.
+func Marshal(cfg *Config) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 // LoadWorkflow loads a named workflow config from .orc/workflows/<name>.yaml (or .yml).
 func LoadWorkflow(projectRoot, name string) (*Config, error) {
 	path := filepath.Join(projectRoot, ".orc", "workflows", name+".yaml")
@@ -164,6 +513,15 @@ func (c *Config) PhaseIndex(name string) int {
 	return -1
 }
 
+// PhaseByName returns a pointer to the named phase in c.Phases and its
+// index, or nil, -1, false if there is no such phase.
+func (c *Config) PhaseByName(name string) (*Phase, int, bool) {
+	if i := c.PhaseIndex(name); i >= 0 {
+		return &c.Phases[i], i, true
+	}
+	return nil, -1, false
+}
+
 // ResolvePhaseRef resolves a phase reference (1-indexed number or name) to a 0-based index.
 // Numbers take precedence over names — a phase literally named "3" would be
 // resolved as numeric index 3, not as a name lookup.
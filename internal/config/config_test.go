@@ -1,8 +1,13 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+
+	"gopkg.in/yaml.v3"
 )
 
 func TestResolvePhaseRef_Number(t *testing.T) {
@@ -123,3 +128,140 @@ func TestResolvePhaseRef_NegativeNumber(t *testing.T) {
 		t.Fatalf("expected 'out of range' in error, got: %v", err)
 	}
 }
+
+func TestPhaseByName_Found(t *testing.T) {
+	cfg := &Config{Phases: []Phase{{Name: "a"}, {Name: "b", Type: "script"}}}
+	p, idx, ok := cfg.PhaseByName("b")
+	if !ok || idx != 1 || p.Name != "b" || p.Type != "script" {
+		t.Fatalf("got %+v, %d, %v; want phase b at 1", p, idx, ok)
+	}
+	// The pointer refers to the config's own phase, not a copy.
+	p.Disabled = true
+	if !cfg.Phases[1].Disabled {
+		t.Fatal("expected PhaseByName to return a pointer into cfg.Phases")
+	}
+}
+
+func TestPhaseByName_NotFound(t *testing.T) {
+	cfg := &Config{Phases: []Phase{{Name: "a"}}}
+	if p, idx, ok := cfg.PhaseByName("z"); ok || p != nil || idx != -1 {
+		t.Fatalf("got %+v, %d, %v; want nil, -1, false", p, idx, ok)
+	}
+}
+
+func TestMarshal_RoundTripsValidatedConfig(t *testing.T) {
+	src := `name: rt
+vars:
+  ZED: "1"
+  ALPHA: yes
+  MID: $TICKET/out
+phases:
+  - name: build
+    type: script
+    run: |
+      make
+      make test
+  - name: implement
+    type: agent
+    prompt: p.md
+    loop:
+      goto: build
+      max: 2
+`
+	var cfg Config
+	if err := yaml.Unmarshal([]byte(src), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "p.md"), []byte("do it"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(&cfg, root); err != nil {
+		t.Fatal(err)
+	}
+	out, err := Marshal(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var back Config
+	if err := yaml.Unmarshal(out, &back); err != nil {
+		t.Fatalf("re-parsing marshaled config: %v\n%s", err, out)
+	}
+	if !reflect.DeepEqual(back.Vars, cfg.Vars) {
+		t.Errorf("vars = %+v, want %+v (order preserved)", back.Vars, cfg.Vars)
+	}
+	if back.Phases[0].Run != cfg.Phases[0].Run || back.Phases[1].Timeout != 30 || back.Phases[1].Loop.Min != 1 {
+		t.Errorf("phases did not round-trip:\n%s", out)
+	}
+	if strings.Contains(string(out), "prompt: \"\"") || strings.Contains(string(out), "disabled:") {
+		t.Errorf("zero-valued fields should be omitted:\n%s", out)
+	}
+}
+
+func TestOutputPaths_PhaseOutputDirs(t *testing.T) {
+	p := Phase{Name: "api", Outputs: []string{"summary.md"}}
+	tests := []struct {
+		cfg  Config
+		want string
+	}{
+		{Config{}, "summary.md"},
+		{Config{OutputsDir: "reports"}, filepath.Join("reports", "summary.md")},
+		{Config{PhaseOutputDirs: true}, filepath.Join("outputs", "api", "summary.md")},
+		{Config{OutputsDir: "reports", PhaseOutputDirs: true}, filepath.Join("reports", "api", "summary.md")},
+	}
+	for _, tt := range tests {
+		if got := tt.cfg.OutputPaths(p); len(got) != 1 || got[0] != tt.want {
+			t.Errorf("OutputPaths with %+v = %v, want [%s]", tt.cfg, got, tt.want)
+		}
+	}
+}
+
+func TestLoad_ToolsFileMergedIntoDefaultAllowTools(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "tools.yaml"), []byte("- mcp__github__*\n- Bash(git *)\n- Bash\n"), 0644)
+	path := filepath.Join(dir, "config.yaml")
+	os.WriteFile(path, []byte(`name: t
+tools-file: tools.yaml
+default-allow-tools: [Bash, WebFetch]
+phases:
+  - name: a
+    type: script
+    run: echo
+`), 0644)
+	cfg, err := Load(path, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Bash", "WebFetch", "mcp__github__*", "Bash(git *)"}
+	if !reflect.DeepEqual(cfg.DefaultAllowTools, want) {
+		t.Fatalf("DefaultAllowTools = %v, want %v", cfg.DefaultAllowTools, want)
+	}
+
+	// Validate alone doesn't read the file, so re-validating a loaded config
+	// leaves the merged list as it is.
+	if err := Validate(cfg, dir); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.DefaultAllowTools, want) {
+		t.Fatalf("after revalidation DefaultAllowTools = %v, want %v", cfg.DefaultAllowTools, want)
+	}
+}
+
+func TestLoad_ToolsFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "blank.yaml"), []byte("- Read\n- \"  \"\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "map.yaml"), []byte("tools: [Read]\n"), 0644)
+	for file, want := range map[string]string{
+		"missing.yaml": "no such file",
+		"blank.yaml":   "entries must be non-empty",
+		"map.yaml":     "must be a YAML list",
+	} {
+		path := filepath.Join(dir, "config.yaml")
+		os.WriteFile(path, []byte("name: t\ntools-file: "+file+"\nphases:\n  - name: a\n    type: script\n    run: echo\n"), 0644)
+		_, err := Load(path, dir)
+		if err == nil || !strings.Contains(err.Error(), "tools-file") || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: got %v, want error containing %q", file, err, want)
+		}
+	}
+}
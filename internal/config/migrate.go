@@ -0,0 +1,164 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentConfigVersion is the config-version this orc writes and reads.
+// Bump it together with a new entry in migrations.
+const CurrentConfigVersion = 1
+
+// Migration upgrades a config from config-version From to From+1 by
+// rewriting the parsed YAML document in place.
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(root *yaml.Node) error
+}
+
+// migrations is the ordered upgrade path, one entry per version step.
+var migrations = []Migration{
+	{
+		From:        0,
+		Description: "record config-version (no field changes)",
+		Apply:       func(root *yaml.Node) error { return nil },
+	},
+}
+
+// Upgrade applies every migration from the config's config-version (0 when
+// absent) up to CurrentConfigVersion and returns the rewritten YAML and the
+// descriptions of the migrations applied. Comments and key order are kept.
+// A config already at the current version is returned unchanged.
+func Upgrade(data []byte) ([]byte, []string, error) {
+	return upgrade(data, migrations, CurrentConfigVersion)
+}
+
+// upgrade applies the steps of path that lead from the config's version to
+// target, and stops there even if path goes further.
+func upgrade(data []byte, path []Migration, target int) ([]byte, []string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("parsing config: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("config must be a YAML mapping")
+	}
+	root := doc.Content[0]
+
+	version := 0
+	if v := mappingValue(root, "config-version"); v != nil {
+		n, err := strconv.Atoi(v.Value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("config-version %q is not a number", v.Value)
+		}
+		// 0 is only ever implied by a missing field; no orc wrote it.
+		if n < 1 {
+			return nil, nil, fmt.Errorf("config-version %d is invalid (versions start at 1)", n)
+		}
+		version = n
+	}
+	if version > target {
+		return nil, nil, fmt.Errorf("config-version %d is newer than this orc supports (%d) — upgrade orc", version, target)
+	}
+	if version == target {
+		return data, nil, nil
+	}
+
+	var applied []string
+	for _, m := range path {
+		if version == target {
+			break
+		}
+		if m.From != version {
+			continue
+		}
+		if err := m.Apply(root); err != nil {
+			return nil, nil, fmt.Errorf("migration %d→%d (%s): %w", m.From, m.From+1, m.Description, err)
+		}
+		applied = append(applied, fmt.Sprintf("%d→%d: %s", m.From, m.From+1, m.Description))
+		version++
+	}
+	if version != target {
+		return nil, nil, fmt.Errorf("no migration from config-version %d to %d", version, version+1)
+	}
+	setMappingValue(root, "config-version", strconv.Itoa(version))
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), applied, nil
+}
+
+// RenameKey renames key old to new in a YAML mapping node. It is a no-op if
+// old is absent, and an error if both are present.
+func RenameKey(mapping *yaml.Node, old, new string) error {
+	if mappingValue(mapping, old) == nil {
+		return nil
+	}
+	if mappingValue(mapping, new) != nil {
+		return fmt.Errorf("both %q and %q are set", old, new)
+	}
+	for i := 0; i < len(mapping.Content)-1; i += 2 {
+		if mapping.Content[i].Value == old {
+			mapping.Content[i].Value = new
+		}
+	}
+	return nil
+}
+
+// EachPhase calls fn with the mapping node of every entry in the config's
+// phases list.
+func EachPhase(root *yaml.Node, fn func(phase *yaml.Node) error) error {
+	phases := mappingValue(root, "phases")
+	if phases == nil || phases.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, p := range phases.Content {
+		if p.Kind != yaml.MappingNode {
+			continue
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i < len(mapping.Content)-1; i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue sets key to a scalar value, inserting it after "name"
+// (or first) when absent.
+func setMappingValue(mapping *yaml.Node, key, value string) {
+	if v := mappingValue(mapping, key); v != nil {
+		v.Kind, v.Tag, v.Value = yaml.ScalarNode, "!!int", value
+		return
+	}
+	k := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+	v := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: value}
+	at := 0
+	for i := 0; i < len(mapping.Content)-1; i += 2 {
+		if mapping.Content[i].Value == "name" {
+			at = i + 2
+			break
+		}
+	}
+	mapping.Content = append(mapping.Content[:at], append([]*yaml.Node{k, v}, mapping.Content[at:]...)...)
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestUpgrade_AddsConfigVersion(t *testing.T) {
+	in := "name: demo\n# the only phase\nphases:\n  - name: a\n    type: script\n    run: echo\n"
+	out, applied, err := Upgrade([]byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 1 {
+		t.Fatalf("applied = %v, want the 0→1 migration", applied)
+	}
+	s := string(out)
+	if !strings.HasPrefix(s, "name: demo\nconfig-version: 1\n") {
+		t.Fatalf("config-version should follow name:\n%s", s)
+	}
+	if !strings.Contains(s, "# the only phase") {
+		t.Fatalf("comments should survive the upgrade:\n%s", s)
+	}
+}
+
+func TestUpgrade_SampleRenameMigration(t *testing.T) {
+	path := append(append([]Migration(nil), migrations...), Migration{
+		From:        CurrentConfigVersion,
+		Description: "rename phase timeout-minutes to timeout",
+		Apply: func(root *yaml.Node) error {
+			return EachPhase(root, func(p *yaml.Node) error { return RenameKey(p, "timeout-minutes", "timeout") })
+		},
+	})
+
+	in := "name: demo\nphases:\n  - name: a\n    type: script\n    run: echo\n    timeout-minutes: 5\n"
+	out, applied, err := upgrade([]byte(in), path, CurrentConfigVersion+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 2 {
+		t.Fatalf("applied = %v, want both migrations", applied)
+	}
+	s := string(out)
+	if strings.Contains(s, "timeout-minutes") || !strings.Contains(s, "timeout: 5") {
+		t.Fatalf("timeout-minutes should be renamed to timeout:\n%s", s)
+	}
+	if !strings.Contains(s, "config-version: 2") {
+		t.Fatalf("config-version should record the last migration applied:\n%s", s)
+	}
+}
+
+func TestMigrations_ReachCurrentVersion(t *testing.T) {
+	for i, m := range migrations {
+		if m.From != i {
+			t.Fatalf("migrations[%d].From = %d, want %d (one step per version, in order)", i, m.From, i)
+		}
+	}
+	if len(migrations) != CurrentConfigVersion {
+		t.Fatalf("%d migrations lead to version %d, but CurrentConfigVersion is %d", len(migrations), len(migrations), CurrentConfigVersion)
+	}
+}
+
+func TestUpgrade_AlreadyCurrent(t *testing.T) {
+	in := "name: demo\nconfig-version: 1\nphases: []\n"
+	out, applied, err := Upgrade([]byte(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 0 || string(out) != in {
+		t.Fatalf("current config should be unchanged, got applied=%v:\n%s", applied, out)
+	}
+}
+
+func TestUpgrade_NewerVersionRejected(t *testing.T) {
+	if _, _, err := Upgrade([]byte("name: demo\nconfig-version: 99\n")); err == nil || !strings.Contains(err.Error(), "newer than this orc supports") {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestUpgrade_StopsAtTarget(t *testing.T) {
+	path := append(append([]Migration(nil), migrations...), Migration{
+		From:        CurrentConfigVersion,
+		Description: "not released yet",
+		Apply:       func(root *yaml.Node) error { return nil },
+	})
+	out, applied, err := upgrade([]byte("name: demo\nphases: []\n"), path, CurrentConfigVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != CurrentConfigVersion || !strings.Contains(string(out), "config-version: 1\n") {
+		t.Fatalf("applied = %v, want only the steps up to %d:\n%s", applied, CurrentConfigVersion, out)
+	}
+
+	if _, _, err := upgrade([]byte("name: demo\n"), migrations, CurrentConfigVersion+1); err == nil || !strings.Contains(err.Error(), "no migration from config-version 1") {
+		t.Fatalf("missing step: got %v", err)
+	}
+}
+
+func TestUpgrade_VersionBelowOneRejected(t *testing.T) {
+	for _, v := range []string{"0", "-2"} {
+		if _, _, err := Upgrade([]byte("name: demo\nconfig-version: " + v + "\n")); err == nil || !strings.Contains(err.Error(), "invalid") {
+			t.Errorf("config-version %s: got %v", v, err)
+		}
+	}
+}
+
+func TestRenameKey_BothSet(t *testing.T) {
+	var doc yaml.Node
+	yaml.Unmarshal([]byte("old: 1\nnew: 2\n"), &doc)
+	if err := RenameKey(doc.Content[0], "old", "new"); err == nil {
+		t.Fatal("expected an error when both keys are set")
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"unicode"
 )
 
 var validModels = map[string]bool{
@@ -28,8 +31,30 @@ var validRateLimitPolicies = map[string]bool{
 	"exit": true,
 }
 
+var validDenialPolicies = map[string]bool{
+	"":                  true,
+	"ignore":            true,
+	"fail":              true,
+	"approve-and-retry": true,
+}
+
 var varNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
 
+// scopedToolRe matches a pattern-scoped tool entry such as "Bash(git *)".
+var scopedToolRe = regexp.MustCompile(`^[^()\s]+\((.*)\)$`)
+
+// ValidateVarName checks a custom variable name, from config vars or run
+// --var: it must be a valid identifier and must not shadow a built-in.
+func ValidateVarName(name string) error {
+	if !varNameRe.MatchString(name) {
+		return fmt.Errorf("%q is not a valid variable name (must match [A-Za-z_][A-Za-z0-9_]*)", name)
+	}
+	if slices.Contains(builtinVars, name) {
+		return fmt.Errorf("%q overrides a built-in variable", name)
+	}
+	return nil
+}
+
 // Validate checks the config for errors and sets defaults.
 func Validate(cfg *Config, projectRoot string) error {
 	if cfg.Name == "" {
@@ -38,24 +63,28 @@ func Validate(cfg *Config, projectRoot string) error {
 	if len(cfg.Phases) == 0 {
 		return fmt.Errorf("config: at least one phase is required")
 	}
+	if cfg.ConfigVersion < 0 {
+		return fmt.Errorf("config: config-version %d is invalid (versions start at 1)", cfg.ConfigVersion)
+	}
+	if cfg.ConfigVersion > CurrentConfigVersion {
+		return fmt.Errorf("config: config-version %d is newer than this orc supports (%d) — upgrade orc", cfg.ConfigVersion, CurrentConfigVersion)
+	}
 
 	// Validate vars
 	builtins := map[string]bool{
 		"TICKET": true, "ARTIFACTS_DIR": true,
 		"WORK_DIR": true, "PROJECT_ROOT": true,
 		"PHASE_INDEX": true, "PHASE_COUNT": true,
-		"WORKFLOW": true,
+		"WORKFLOW": true, "WORKFLOW_NAME": true,
+		"PHASE_DESCRIPTION": true, "PHASE_OUTPUT_DIR": true,
 	}
 	seenVars := make(map[string]bool)
 	for _, v := range cfg.Vars {
 		if v.Key == "" {
 			return fmt.Errorf("config: vars: empty variable name")
 		}
-		if !varNameRe.MatchString(v.Key) {
-			return fmt.Errorf("config: vars: %q is not a valid variable name (must match [A-Za-z_][A-Za-z0-9_]*)", v.Key)
-		}
-		if builtins[v.Key] {
-			return fmt.Errorf("config: vars: %q overrides a built-in variable", v.Key)
+		if err := ValidateVarName(v.Key); err != nil {
+			return fmt.Errorf("config: vars: %w", err)
 		}
 		if seenVars[v.Key] {
 			return fmt.Errorf("config: vars: duplicate variable %q", v.Key)
@@ -63,9 +92,61 @@ func Validate(cfg *Config, projectRoot string) error {
 		seenVars[v.Key] = true
 	}
 
+	// secret-env names are checked here, alongside vars, so they can't
+	// shadow a built-in or custom variable.
+	for _, p := range cfg.Phases {
+		for _, name := range sortedKeys(p.SecretEnv) {
+			src := p.SecretEnv[name]
+			switch {
+			case !varNameRe.MatchString(name):
+				return fmt.Errorf("config: phase %q: secret-env: %q is not a valid variable name (must match [A-Za-z_][A-Za-z0-9_]*)", p.Name, name)
+			case strings.HasPrefix(name, "ORC_") || strings.HasPrefix(name, "CLAUDECODE"):
+				return fmt.Errorf("config: phase %q: secret-env: %q uses a reserved prefix", p.Name, name)
+			case builtins[name] || seenVars[name]:
+				return fmt.Errorf("config: phase %q: secret-env: %q overrides a built-in or custom variable", p.Name, name)
+			case !varNameRe.MatchString(src):
+				return fmt.Errorf("config: phase %q: secret-env: %s must name an environment variable to read (got %q)", p.Name, name, src)
+			}
+		}
+	}
+
+	if ev := cfg.ExportVars; ev != nil {
+		if len(ev.Only) > 0 && len(ev.Except) > 0 {
+			return fmt.Errorf("config: export-vars: 'only' and 'except' are mutually exclusive")
+		}
+		for _, name := range append(slices.Clone(ev.Only), ev.Except...) {
+			if !seenVars[name] {
+				return fmt.Errorf("config: export-vars: %q is not a declared var", name)
+			}
+		}
+	}
+
+	for _, name := range cfg.ExpectedEnv {
+		if !varNameRe.MatchString(name) {
+			return fmt.Errorf("config: expected-env: %q is not a valid variable name (must match [A-Za-z_][A-Za-z0-9_]*)", name)
+		}
+	}
+
+	if err := ValidateArtifactsPrefix(cfg.ArtifactsPrefix); err != nil {
+		return fmt.Errorf("config: artifacts-prefix: %w", err)
+	}
+
 	for _, tool := range cfg.DefaultAllowTools {
-		if strings.TrimSpace(tool) == "" {
-			return fmt.Errorf("config: 'default-allow-tools' entries must be non-empty")
+		if err := checkToolEntry(tool); err != nil {
+			return fmt.Errorf("config: 'default-allow-tools' %w", err)
+		}
+	}
+
+	if err := checkSettingsFile(cfg.ClaudeSettings, projectRoot); err != nil {
+		return fmt.Errorf("config: 'claude-settings': %w", err)
+	}
+	if cfg.DoctorPrompt != "" {
+		path := cfg.DoctorPrompt
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectRoot, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("config: doctor-prompt file %q not found — create the file or update the 'doctor-prompt' field", path)
 		}
 	}
 
@@ -82,12 +163,36 @@ func Validate(cfg *Config, projectRoot string) error {
 	if cfg.MaxCost < 0 {
 		return fmt.Errorf("config: 'max-cost' must not be negative (got %.2f)", cfg.MaxCost)
 	}
+	if cfg.ConditionTimeout < 0 {
+		return fmt.Errorf("config: 'condition-timeout' must be >= 0 (got %d)", cfg.ConditionTimeout)
+	}
 	if cfg.HistoryLimit < 0 {
 		return fmt.Errorf("config: 'history-limit' must not be negative (got %d)", cfg.HistoryLimit)
 	}
 	if cfg.HistoryLimit == 0 {
 		cfg.HistoryLimit = 10 // default
 	}
+	if cfg.OutputsDir != "" {
+		clean := filepath.Clean(cfg.OutputsDir)
+		if filepath.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("config: 'outputs-dir' must be a relative path inside the artifacts directory (got %q)", cfg.OutputsDir)
+		}
+	}
+	for _, d := range cfg.Deliverables {
+		clean := filepath.Clean(d)
+		if d == "" || filepath.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("config: deliverable %q must be a relative path inside the artifacts directory", d)
+		}
+	}
+	if cfg.MaxStreamLineBytes < 0 {
+		return fmt.Errorf("config: 'max-stream-line-bytes' must not be negative (got %d)", cfg.MaxStreamLineBytes)
+	}
+	if cfg.WorkflowRetries < 0 {
+		return fmt.Errorf("config: 'workflow-retries' must not be negative (got %d)", cfg.WorkflowRetries)
+	}
+	if cfg.MaxPromptBytes < 0 {
+		return fmt.Errorf("config: 'max-prompt-bytes' must not be negative (got %d)", cfg.MaxPromptBytes)
+	}
 
 	// Compile ticket-pattern eagerly so bad regex is caught at config-load
 	// time, not at first run. Mirrors the anchoring logic in ValidateTicket.
@@ -102,215 +207,475 @@ func Validate(cfg *Config, projectRoot string) error {
 		}
 	}
 
+	publish := ""
+	for _, p := range cfg.Phases {
+		if p.Type != "publish" {
+			continue
+		}
+		if publish != "" {
+			return fmt.Errorf("config: at most one publish phase is allowed (found %q and %q)", publish, p.Name)
+		}
+		publish = p.Name
+	}
+
 	seen := make(map[string]bool)
+	normalized := make(map[string]string) // lower-cased name → name as authored
 	for i := range cfg.Phases {
-		p := &cfg.Phases[i]
+		if err := validatePhase(cfg, i, projectRoot, seen, normalized); err != nil {
+			return cfg.Phases[i].locate(err)
+		}
+	}
+
+	return nil
+}
+
+// validatePhase checks the phase at index i. seen and normalized track
+// the names of phases already checked, for duplicate detection.
+func validatePhase(cfg *Config, i int, projectRoot string, seen map[string]bool, normalized map[string]string) error {
+	p := &cfg.Phases[i]
+
+	if p.Name == "" {
+		return fmt.Errorf("config: phase %d: 'name' is required", i+1)
+	}
+	if p.Type == "" {
+		return fmt.Errorf("config: phase %q: 'type' is required", p.Name)
+	}
+	if strings.TrimSpace(p.Name) != p.Name {
+		return fmt.Errorf("config: phase %d: name %q must not have leading or trailing whitespace", i+1, p.Name)
+	}
+	if strings.ContainsAny(p.Name, "+:") || strings.ContainsFunc(p.Name, unicode.IsControl) {
+		return fmt.Errorf("config: phase %d: name %q must not contain '+', ':', or control characters", i+1, p.Name)
+	}
+	if seen[p.Name] {
+		return fmt.Errorf("config: duplicate phase name %q", p.Name)
+	}
+	seen[p.Name] = true
+	// Names that differ only in case are easy to mistype in goto,
+	// parallel-with, and --from references, so treat them as duplicates.
+	if prev, ok := normalized[strings.ToLower(p.Name)]; ok {
+		return fmt.Errorf("config: duplicate phase name %q (differs from %q only in case)", p.Name, prev)
+	}
+	normalized[strings.ToLower(p.Name)] = p.Name
 
-		if p.Name == "" {
-			return fmt.Errorf("config: phase %d: 'name' is required", i+1)
+	if p.Name != filepath.Base(p.Name) || p.Name == ".." || p.Name == "." {
+		return fmt.Errorf("config: phase %d: name %q must not contain path separators", i+1, p.Name)
+	}
+
+	switch p.Type {
+	case "agent":
+		if p.Prompt == "" {
+			return fmt.Errorf("config: agent phase %q: 'prompt' is required", p.Name)
+		}
+		promptPath, err := PromptPath(projectRoot, p.Prompt)
+		if err != nil {
+			return fmt.Errorf("config: agent phase %q: %w", p.Name, err)
+		}
+		if _, err := os.Stat(promptPath); err != nil {
+			return fmt.Errorf("config: agent phase %q: prompt file %q not found — create the file or update the 'prompt' field", p.Name, promptPath)
+		}
+		if p.Model == "" && cfg.Model != "" {
+			p.Model = cfg.Model
+		}
+		if p.Model == "" {
+			p.Model = "opus"
+		}
+		if p.Effort == "" && cfg.Effort != "" {
+			p.Effort = cfg.Effort
+		}
+		if p.Effort == "" {
+			p.Effort = "high"
+		}
+		if p.ClaudeSettings == "" && cfg.ClaudeSettings != "" {
+			p.ClaudeSettings = cfg.ClaudeSettings
+		} else if err := checkSettingsFile(p.ClaudeSettings, projectRoot); err != nil {
+			return fmt.Errorf("config: agent phase %q: 'claude-settings': %w", p.Name, err)
+		}
+		if p.Cwd == "" && cfg.Cwd != "" {
+			p.Cwd = cfg.Cwd
+		}
+		if p.Timeout == 0 {
+			p.Timeout = 30
+		}
+	case "script":
+		if p.Run == "" {
+			return fmt.Errorf("config: script phase %q: 'run' is required", p.Name)
+		}
+		if p.Cwd == "" && cfg.Cwd != "" {
+			p.Cwd = cfg.Cwd
+		}
+		if p.Timeout == 0 {
+			p.Timeout = 10
+		}
+		if p.Stdin != "" && p.StdinFile != "" {
+			return fmt.Errorf("config: script phase %q: 'stdin' and 'stdin-file' are mutually exclusive", p.Name)
+		}
+	case "gate":
+		if p.Cwd != "" && p.Run == "" {
+			return fmt.Errorf("config: gate phase %q: 'cwd' requires 'run' on gate phases", p.Name)
+		}
+		if p.Cwd == "" && cfg.Cwd != "" && p.Run != "" {
+			p.Cwd = cfg.Cwd
+		}
+		if p.RequirePhrase != "" && strings.TrimSpace(p.RequirePhrase) != p.RequirePhrase {
+			return fmt.Errorf("config: gate phase %q: 'require-phrase' must not have leading or trailing whitespace", p.Name)
+		}
+	case "manual":
+		if strings.TrimSpace(p.Description) == "" {
+			return fmt.Errorf("config: manual phase %q: 'description' is required (the instructions shown to the operator)", p.Name)
+		}
+		if p.Run != "" {
+			return fmt.Errorf("config: manual phase %q: 'run' is not valid on manual phases", p.Name)
+		}
+		if p.Prompt != "" {
+			return fmt.Errorf("config: manual phase %q: 'prompt' is not valid on manual phases", p.Name)
+		}
+		if p.Cwd != "" {
+			return fmt.Errorf("config: manual phase %q: 'cwd' is not valid on manual phases", p.Name)
+		}
+		if p.ParallelWith != "" {
+			return fmt.Errorf("config: manual phase %q: 'parallel-with' is not valid on manual phases", p.Name)
+		}
+	case "workflow":
+		if p.WorkflowRef == "" {
+			return fmt.Errorf("config: workflow phase %q: 'workflow' is required", p.Name)
+		}
+		if !WorkflowExists(projectRoot, p.WorkflowRef) {
+			return fmt.Errorf("config: workflow phase %q: workflow %q not found in .orc/workflows/", p.Name, p.WorkflowRef)
+		}
+		if p.Prompt != "" {
+			return fmt.Errorf("config: workflow phase %q: 'prompt' is not valid on workflow phases", p.Name)
+		}
+		if p.Run != "" {
+			return fmt.Errorf("config: workflow phase %q: 'run' is not valid on workflow phases", p.Name)
 		}
-		if p.Type == "" {
-			return fmt.Errorf("config: phase %q: 'type' is required", p.Name)
+		if p.ParallelWith != "" {
+			return fmt.Errorf("config: workflow phase %q: 'parallel-with' is not valid on workflow phases", p.Name)
+		}
+	case "branch":
+		if p.Check == "" {
+			return fmt.Errorf("config: branch phase %q: 'check' is required", p.Name)
+		}
+		if len(p.Branches) == 0 {
+			return fmt.Errorf("config: branch phase %q: 'branches' is required and must have at least one entry", p.Name)
+		}
+		for key, wf := range p.Branches {
+			if !WorkflowExists(projectRoot, wf) {
+				return fmt.Errorf("config: branch phase %q: branch %q references workflow %q not found in .orc/workflows/", p.Name, key, wf)
+			}
+		}
+		if p.Default != "" && !WorkflowExists(projectRoot, p.Default) {
+			return fmt.Errorf("config: branch phase %q: default workflow %q not found in .orc/workflows/", p.Name, p.Default)
+		}
+		if p.Prompt != "" {
+			return fmt.Errorf("config: branch phase %q: 'prompt' is not valid on branch phases", p.Name)
+		}
+		if p.Run != "" {
+			return fmt.Errorf("config: branch phase %q: 'run' is not valid on branch phases", p.Name)
+		}
+		if p.ParallelWith != "" {
+			return fmt.Errorf("config: branch phase %q: 'parallel-with' is not valid on branch phases", p.Name)
+		}
+	case "publish":
+		if p.Run == "" {
+			return fmt.Errorf("config: publish phase %q: 'run' is required (the command that uploads $DELIVERABLES)", p.Name)
+		}
+		if i != len(cfg.Phases)-1 {
+			return fmt.Errorf("config: publish phase %q: must be the last phase (it runs after the rest of the workflow)", p.Name)
+		}
+		if p.ParallelWith != "" {
+			return fmt.Errorf("config: publish phase %q: 'parallel-with' is not valid on publish phases", p.Name)
 		}
-		if seen[p.Name] {
-			return fmt.Errorf("config: duplicate phase name %q", p.Name)
+		if p.Cwd == "" && cfg.Cwd != "" {
+			p.Cwd = cfg.Cwd
 		}
-		seen[p.Name] = true
+		if p.Timeout == 0 {
+			p.Timeout = 10
+		}
+	default:
+		return fmt.Errorf("config: phase %q: unknown type %q (must be agent, script, gate, manual, workflow, branch, or publish)", p.Name, p.Type)
+	}
 
-		if p.Name != filepath.Base(p.Name) || p.Name == ".." || p.Name == "." {
-			return fmt.Errorf("config: phase %d: name %q must not contain path separators", i+1, p.Name)
+	if len(p.SuccessExitCodes) > 0 && p.Type != "script" {
+		return fmt.Errorf("config: phase %q: 'success-exit-codes' is only valid on script phases", p.Name)
+	}
+	for _, code := range p.SuccessExitCodes {
+		if code < 0 || code > 255 {
+			return fmt.Errorf("config: phase %q: 'success-exit-codes' entry %d out of range (0-255)", p.Name, code)
 		}
+	}
 
-		switch p.Type {
-		case "agent":
-			if p.Prompt == "" {
-				return fmt.Errorf("config: agent phase %q: 'prompt' is required", p.Name)
-			}
-			promptPath := filepath.Join(projectRoot, p.Prompt)
-			if _, err := os.Stat(promptPath); err != nil {
-				return fmt.Errorf("config: agent phase %q: prompt file %q not found — create the file or update the 'prompt' field", p.Name, promptPath)
-			}
-			if p.Model == "" && cfg.Model != "" {
-				p.Model = cfg.Model
-			}
-			if p.Model == "" {
-				p.Model = "opus"
-			}
-			if p.Effort == "" && cfg.Effort != "" {
-				p.Effort = cfg.Effort
-			}
-			if p.Effort == "" {
-				p.Effort = "high"
-			}
-			if p.Cwd == "" && cfg.Cwd != "" {
-				p.Cwd = cfg.Cwd
-			}
-			if p.Timeout == 0 {
-				p.Timeout = 30
-			}
-		case "script":
-			if p.Run == "" {
-				return fmt.Errorf("config: script phase %q: 'run' is required", p.Name)
-			}
-			if p.Cwd == "" && cfg.Cwd != "" {
-				p.Cwd = cfg.Cwd
-			}
-			if p.Timeout == 0 {
-				p.Timeout = 10
-			}
-		case "gate":
-			if p.Cwd != "" && p.Run == "" {
-				return fmt.Errorf("config: gate phase %q: 'cwd' requires 'run' on gate phases", p.Name)
-			}
-			if p.Cwd == "" && cfg.Cwd != "" && p.Run != "" {
-				p.Cwd = cfg.Cwd
-			}
-		case "workflow":
-			if p.WorkflowRef == "" {
-				return fmt.Errorf("config: workflow phase %q: 'workflow' is required", p.Name)
-			}
-			if !WorkflowExists(projectRoot, p.WorkflowRef) {
-				return fmt.Errorf("config: workflow phase %q: workflow %q not found in .orc/workflows/", p.Name, p.WorkflowRef)
-			}
-			if p.Prompt != "" {
-				return fmt.Errorf("config: workflow phase %q: 'prompt' is not valid on workflow phases", p.Name)
-			}
-			if p.Run != "" {
-				return fmt.Errorf("config: workflow phase %q: 'run' is not valid on workflow phases", p.Name)
-			}
-			if p.ParallelWith != "" {
-				return fmt.Errorf("config: workflow phase %q: 'parallel-with' is not valid on workflow phases", p.Name)
-			}
-		case "branch":
-			if p.Check == "" {
-				return fmt.Errorf("config: branch phase %q: 'check' is required", p.Name)
-			}
-			if len(p.Branches) == 0 {
-				return fmt.Errorf("config: branch phase %q: 'branches' is required and must have at least one entry", p.Name)
-			}
-			for key, wf := range p.Branches {
-				if !WorkflowExists(projectRoot, wf) {
-					return fmt.Errorf("config: branch phase %q: branch %q references workflow %q not found in .orc/workflows/", p.Name, key, wf)
-				}
+	if p.CreateCwd {
+		if p.Type == "gate" || p.Type == "manual" {
+			return fmt.Errorf("config: phase %q: 'create-cwd' is not valid on %s phases", p.Name, p.Type)
+		}
+		if p.Cwd == "" {
+			return fmt.Errorf("config: phase %q: 'create-cwd' requires 'cwd' (on the phase or the config)", p.Name)
+		}
+	}
+
+	if (p.Stdin != "" || p.StdinFile != "") && p.Type != "script" {
+		return fmt.Errorf("config: phase %q: 'stdin' and 'stdin-file' are only valid on script phases", p.Name)
+	}
+	if p.Container != nil {
+		if p.Type != "script" {
+			return fmt.Errorf("config: phase %q: 'container' is only valid on script phases", p.Name)
+		}
+		if strings.TrimSpace(p.Container.Image) == "" {
+			return fmt.Errorf("config: phase %q: container.image is required", p.Name)
+		}
+		if rt := p.Container.Runtime; rt != "" && rt != "docker" && rt != "podman" {
+			return fmt.Errorf("config: phase %q: unknown container.runtime %q (must be docker or podman)", p.Name, rt)
+		}
+		for _, v := range p.Container.Volumes {
+			if parts := strings.Split(v, ":"); len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+				return fmt.Errorf("config: phase %q: container volume %q must be host:container[:options]", p.Name, v)
 			}
-			if p.Default != "" && !WorkflowExists(projectRoot, p.Default) {
-				return fmt.Errorf("config: branch phase %q: default workflow %q not found in .orc/workflows/", p.Name, p.Default)
+		}
+	}
+
+	if p.RequirePhrase != "" && p.Type != "gate" {
+		return fmt.Errorf("config: phase %q: 'require-phrase' is only valid on gate phases", p.Name)
+	}
+
+	if p.OnReject != nil {
+		if p.Type != "gate" {
+			return fmt.Errorf("config: phase %q: 'on-reject' is only valid on gate phases", p.Name)
+		}
+		switch p.OnReject.Action {
+		case "stop", "continue":
+			if p.OnReject.Goto != "" {
+				return fmt.Errorf("config: gate phase %q: 'on-reject' goto is only valid with action goto", p.Name)
 			}
-			if p.Prompt != "" {
-				return fmt.Errorf("config: branch phase %q: 'prompt' is not valid on branch phases", p.Name)
+		case "goto":
+			if p.OnReject.Goto == "" {
+				return fmt.Errorf("config: gate phase %q: 'on-reject' goto requires a phase name", p.Name)
 			}
-			if p.Run != "" {
-				return fmt.Errorf("config: branch phase %q: 'run' is not valid on branch phases", p.Name)
+			if p.OnReject.Goto == p.Name {
+				return fmt.Errorf("config: gate phase %q: 'on-reject' goto must name a different phase", p.Name)
 			}
-			if p.ParallelWith != "" {
-				return fmt.Errorf("config: branch phase %q: 'parallel-with' is not valid on branch phases", p.Name)
+			if cfg.PhaseIndex(p.OnReject.Goto) < 0 {
+				return fmt.Errorf("config: gate phase %q: 'on-reject' goto %q references unknown phase", p.Name, p.OnReject.Goto)
 			}
 		default:
-			return fmt.Errorf("config: phase %q: unknown type %q (must be agent, script, gate, workflow, or branch)", p.Name, p.Type)
+			return fmt.Errorf("config: gate phase %q: 'on-reject' must be stop, continue, or {goto: <phase>}, got %q", p.Name, p.OnReject.Action)
 		}
-
-		if len(p.AllowTools) > 0 && p.Type != "agent" {
-			return fmt.Errorf("config: phase %q: 'allow-tools' is only valid on agent phases", p.Name)
+		if p.OnReject.Action != "stop" && p.ParallelWith != "" {
+			return fmt.Errorf("config: gate phase %q: 'on-reject' cannot be combined with 'parallel-with'", p.Name)
 		}
-		for _, tool := range p.AllowTools {
-			if strings.TrimSpace(tool) == "" {
-				return fmt.Errorf("config: phase %q: 'allow-tools' entries must be non-empty", p.Name)
-			}
+		if p.OnReject.Action != "stop" && p.Loop != nil {
+			return fmt.Errorf("config: gate phase %q: 'on-reject' cannot be combined with 'loop' — both decide where a rejection goes", p.Name)
 		}
+	}
 
-		if p.MCPConfig != "" && p.Type != "agent" {
-			return fmt.Errorf("config: phase %q: 'mcp-config' is only valid on agent phases", p.Name)
+	if len(p.AllowTools) > 0 && p.Type != "agent" {
+		return fmt.Errorf("config: phase %q: 'allow-tools' is only valid on agent phases", p.Name)
+	}
+	for _, tool := range p.AllowTools {
+		if err := checkToolEntry(tool); err != nil {
+			return fmt.Errorf("config: phase %q: 'allow-tools' %w", p.Name, err)
 		}
+	}
+
+	if p.MCPConfig != "" && p.Type != "agent" {
+		return fmt.Errorf("config: phase %q: 'mcp-config' is only valid on agent phases", p.Name)
+	}
 
-		if !validModels[p.Model] {
-			return fmt.Errorf("config: phase %q: unknown model %q (must be opus, sonnet, or haiku)", p.Name, p.Model)
+	if p.ClaudeSettings != "" && p.Type != "agent" {
+		return fmt.Errorf("config: phase %q: 'claude-settings' is only valid on agent phases", p.Name)
+	}
+
+	if !validModels[p.Model] {
+		return fmt.Errorf("config: phase %q: unknown model %q (must be opus, sonnet, or haiku)", p.Name, p.Model)
+	}
+	if p.FallbackModel != "" {
+		if p.Type != "agent" {
+			return fmt.Errorf("config: phase %q: 'fallback-model' is only valid on agent phases", p.Name)
 		}
+		if !validModels[p.FallbackModel] {
+			return fmt.Errorf("config: phase %q: unknown fallback-model %q (must be opus, sonnet, or haiku)", p.Name, p.FallbackModel)
+		}
+		if p.FallbackModel == p.Model {
+			return fmt.Errorf("config: phase %q: 'fallback-model' must differ from 'model' (both %q)", p.Name, p.Model)
+		}
+	}
 
-		if !validEfforts[p.Effort] {
-			return fmt.Errorf("config: phase %q: unknown effort %q (must be low, medium, or high)", p.Name, p.Effort)
+	if !validEfforts[p.Effort] {
+		return fmt.Errorf("config: phase %q: unknown effort %q (must be low, medium, or high)", p.Name, p.Effort)
+	}
+	if !validRateLimitPolicies[p.OnRateLimit] {
+		return fmt.Errorf("config: phase %q: unknown on-rate-limit %q (must be \"wait\" or \"exit\")", p.Name, p.OnRateLimit)
+	}
+	if !validDenialPolicies[p.OnDenial] {
+		return fmt.Errorf("config: phase %q: unknown on-denial %q (must be ignore, fail, or approve-and-retry)", p.Name, p.OnDenial)
+	}
+	if p.OnDenial != "" && p.Type != "agent" {
+		return fmt.Errorf("config: phase %q: 'on-denial' is only valid on agent phases", p.Name)
+	}
+	for _, l := range p.Labels {
+		if strings.TrimSpace(l) == "" || strings.Contains(l, ",") {
+			return fmt.Errorf("config: phase %q: labels must be non-empty and must not contain commas", p.Name)
 		}
-		if !validRateLimitPolicies[p.OnRateLimit] {
-			return fmt.Errorf("config: phase %q: unknown on-rate-limit %q (must be \"wait\" or \"exit\")", p.Name, p.OnRateLimit)
+	}
+	if p.Group != "" && i > 0 && cfg.Phases[i-1].Group != p.Group {
+		for j := 0; j < i-1; j++ {
+			if cfg.Phases[j].Group == p.Group {
+				return fmt.Errorf("config: phase %q: group %q must be contiguous — list its phases together", p.Name, p.Group)
+			}
 		}
+	}
+	if p.InjectAllFeedback && p.Type != "agent" {
+		return fmt.Errorf("config: phase %q: 'inject-all-feedback' is only valid on agent phases", p.Name)
+	}
+
+	if p.OnSuccess != "" && strings.TrimSpace(p.OnSuccess) == "" {
+		return fmt.Errorf("config: phase %q: 'on-success' must be a non-empty command", p.Name)
+	}
+	switch p.OnSuccessFail {
+	case "", "warn", "fail":
+	default:
+		return fmt.Errorf("config: phase %q: unknown on-success-fail %q (must be warn or fail)", p.Name, p.OnSuccessFail)
+	}
+	if p.OnSuccessFail != "" && p.OnSuccess == "" {
+		return fmt.Errorf("config: phase %q: 'on-success-fail' requires 'on-success'", p.Name)
+	}
+	if p.Verify != "" && strings.TrimSpace(p.Verify) == "" {
+		return fmt.Errorf("config: phase %q: 'verify' must be a non-empty command", p.Name)
+	}
+
+	if p.Timeout < 0 {
+		return fmt.Errorf("config: phase %q: timeout must be >= 0 (got %d)", p.Name, p.Timeout)
+	}
 
-		if p.Timeout < 0 {
-			return fmt.Errorf("config: phase %q: timeout must be >= 0 (got %d)", p.Name, p.Timeout)
+	if p.InactivityTimeout < 0 {
+		return fmt.Errorf("config: phase %q: 'inactivity-timeout' must be >= 0 (got %d)", p.Name, p.InactivityTimeout)
+	}
+	if p.InactivityTimeout > 0 && p.Type != "agent" && p.Type != "script" {
+		return fmt.Errorf("config: phase %q: 'inactivity-timeout' is only supported on agent and script phases", p.Name)
+	}
+	if p.ConditionTimeout < 0 {
+		return fmt.Errorf("config: phase %q: 'condition-timeout' must be >= 0 (got %d)", p.Name, p.ConditionTimeout)
+	}
+	if p.Condition == "" && (p.ConditionTimeout != 0 || p.OnConditionTimeout != "") {
+		return fmt.Errorf("config: phase %q: 'condition-timeout' and 'on-condition-timeout' require 'condition'", p.Name)
+	}
+	if p.OnConditionTimeout != "" && p.OnConditionTimeout != "skip" && p.OnConditionTimeout != "fail" {
+		return fmt.Errorf("config: phase %q: unknown on-condition-timeout %q (must be \"skip\" or \"fail\")", p.Name, p.OnConditionTimeout)
+	}
+	if p.Condition != "" {
+		if p.ConditionTimeout == 0 {
+			p.ConditionTimeout = cfg.ConditionTimeout
+		}
+		if p.ConditionTimeout == 0 {
+			p.ConditionTimeout = 60
+		}
+		if p.OnConditionTimeout == "" {
+			p.OnConditionTimeout = "skip"
 		}
+	}
+
+	if p.MaxCost < 0 {
+		return fmt.Errorf("config: phase %q: 'max-cost' must not be negative (got %.2f)", p.Name, p.MaxCost)
+	}
+	if p.MaxCost > 0 && p.Type != "agent" {
+		return fmt.Errorf("config: phase %q: 'max-cost' is only valid on agent phases", p.Name)
+	}
 
-		if p.MaxCost < 0 {
-			return fmt.Errorf("config: phase %q: 'max-cost' must not be negative (got %.2f)", p.Name, p.MaxCost)
+	for _, o := range p.Outputs {
+		name, kind := SplitOutput(o)
+		if name != filepath.Base(name) || name == ".." || name == "." {
+			return fmt.Errorf("config: phase %q: output %q must be a simple filename", p.Name, o)
 		}
-		if p.MaxCost > 0 && p.Type != "agent" {
-			return fmt.Errorf("config: phase %q: 'max-cost' is only valid on agent phases", p.Name)
+		if strings.Contains(o, ":") && !slices.Contains(OutputTypes, kind) {
+			return fmt.Errorf("config: phase %q: output %q has unknown content type %q (must be one of: %s)", p.Name, o, kind, strings.Join(OutputTypes, ", "))
 		}
+	}
+	if p.RequireFreshOutputs && len(p.Outputs) == 0 {
+		return fmt.Errorf("config: phase %q: 'require-fresh-outputs' requires 'outputs'", p.Name)
+	}
+	if p.OutputsOptional && len(p.Outputs) == 0 {
+		return fmt.Errorf("config: phase %q: 'outputs-optional' requires 'outputs'", p.Name)
+	}
 
-		for _, o := range p.Outputs {
-			if o != filepath.Base(o) || o == ".." || o == "." {
-				return fmt.Errorf("config: phase %q: output %q must be a simple filename", p.Name, o)
+	// Reject deprecated on-fail with migration hint
+	if p.OnFail != nil {
+		return fmt.Errorf("config: phase %q: 'on-fail' has been replaced by 'loop'. "+
+			"Use loop: {goto: %q, max: %d} (note: loop.max is total iterations, not retries)",
+			p.Name, p.OnFail.Goto, p.OnFail.Max+1)
+	}
+
+	// Validate loop
+	if p.Loop != nil {
+		if p.Loop.Goto == "" {
+			return fmt.Errorf("config: phase %q: loop.goto is required", p.Name)
+		}
+		gotoIdx := -1
+		for j := 0; j < i; j++ {
+			if cfg.Phases[j].Name == p.Loop.Goto {
+				gotoIdx = j
+				break
 			}
 		}
-
-		// Reject deprecated on-fail with migration hint
-		if p.OnFail != nil {
-			return fmt.Errorf("config: phase %q: 'on-fail' has been replaced by 'loop'. "+
-				"Use loop: {goto: %q, max: %d} (note: loop.max is total iterations, not retries)",
-				p.Name, p.OnFail.Goto, p.OnFail.Max+1)
+		if gotoIdx < 0 {
+			return fmt.Errorf("config: phase %q: loop.goto %q must reference an earlier phase", p.Name, p.Loop.Goto)
 		}
-
-		// Validate loop
-		if p.Loop != nil {
-			if p.Loop.Goto == "" {
-				return fmt.Errorf("config: phase %q: loop.goto is required", p.Name)
+		if p.Loop.Min <= 0 {
+			p.Loop.Min = 1
+		}
+		if p.Loop.Max <= 0 {
+			return fmt.Errorf("config: phase %q: loop.max is required and must be > 0", p.Name)
+		}
+		if p.Loop.Max < p.Loop.Min {
+			return fmt.Errorf("config: phase %q: loop.max (%d) must be >= loop.min (%d)", p.Name, p.Loop.Max, p.Loop.Min)
+		}
+		if p.Loop.FeedbackHistory < 0 {
+			return fmt.Errorf("config: phase %q: loop.feedback-history must not be negative", p.Name)
+		}
+		if p.Loop.OnExhaust != nil {
+			if p.Loop.OnExhaust.Goto == "" {
+				return fmt.Errorf("config: phase %q: loop.on-exhaust.goto is required", p.Name)
 			}
-			gotoIdx := -1
+			exhaustIdx := -1
 			for j := 0; j < i; j++ {
-				if cfg.Phases[j].Name == p.Loop.Goto {
-					gotoIdx = j
+				if cfg.Phases[j].Name == p.Loop.OnExhaust.Goto {
+					exhaustIdx = j
 					break
 				}
 			}
-			if gotoIdx < 0 {
-				return fmt.Errorf("config: phase %q: loop.goto %q must reference an earlier phase", p.Name, p.Loop.Goto)
-			}
-			if p.Loop.Min <= 0 {
-				p.Loop.Min = 1
+			if exhaustIdx < 0 {
+				return fmt.Errorf("config: phase %q: loop.on-exhaust.goto %q must reference an earlier phase", p.Name, p.Loop.OnExhaust.Goto)
 			}
-			if p.Loop.Max <= 0 {
-				return fmt.Errorf("config: phase %q: loop.max is required and must be > 0", p.Name)
-			}
-			if p.Loop.Max < p.Loop.Min {
-				return fmt.Errorf("config: phase %q: loop.max (%d) must be >= loop.min (%d)", p.Name, p.Loop.Max, p.Loop.Min)
-			}
-			if p.Loop.OnExhaust != nil {
-				if p.Loop.OnExhaust.Goto == "" {
-					return fmt.Errorf("config: phase %q: loop.on-exhaust.goto is required", p.Name)
-				}
-				exhaustIdx := -1
-				for j := 0; j < i; j++ {
-					if cfg.Phases[j].Name == p.Loop.OnExhaust.Goto {
-						exhaustIdx = j
-						break
-					}
-				}
-				if exhaustIdx < 0 {
-					return fmt.Errorf("config: phase %q: loop.on-exhaust.goto %q must reference an earlier phase", p.Name, p.Loop.OnExhaust.Goto)
-				}
-				if p.Loop.OnExhaust.Max <= 0 {
-					p.Loop.OnExhaust.Max = 1
-				}
+			if p.Loop.OnExhaust.Max <= 0 {
+				p.Loop.OnExhaust.Max = 1
 			}
 		}
+	}
 
-		if p.ParallelWith != "" {
-			if !seen[p.ParallelWith] && !phaseExists(cfg.Phases, p.ParallelWith) {
-				return fmt.Errorf("config: phase %q: parallel-with %q references unknown phase", p.Name, p.ParallelWith)
+	if p.KeepGoing && p.ParallelWith == "" {
+		return fmt.Errorf("config: phase %q: 'keep-going' requires 'parallel-with'", p.Name)
+	}
+	if p.ParallelWith != "" {
+		partner, partnerIdx, ok := cfg.PhaseByName(p.ParallelWith)
+		if !ok {
+			return fmt.Errorf("config: phase %q: parallel-with %q references unknown phase", p.Name, p.ParallelWith)
+		}
+		// A loop on either partner is the group's loop: it retries the
+		// pair on failure only, so check and min are not supported.
+		if p.Loop != nil && partner.Loop != nil {
+			return fmt.Errorf("config: phase %q: parallel-with %q: only one phase of a parallel group may declare loop", p.Name, p.ParallelWith)
+		}
+		groupLoop := p.Loop
+		if groupLoop == nil {
+			groupLoop = partner.Loop
+		}
+		if groupLoop != nil {
+			if groupLoop.Check != "" || groupLoop.Min > 1 {
+				return fmt.Errorf("config: phase %q: parallel-with cannot be combined with loop.check or loop.min — a parallel group loop only retries on failure; split into separate phases", p.Name)
 			}
-			if p.Loop != nil {
-				return fmt.Errorf("config: phase %q: parallel-with and loop cannot be combined — split into separate phases", p.Name)
+			if gotoIdx := cfg.PhaseIndex(groupLoop.Goto); gotoIdx >= min(i, partnerIdx) {
+				return fmt.Errorf("config: phase %q: parallel group loop.goto %q must reference a phase before both %q and %q", p.Name, groupLoop.Goto, p.Name, p.ParallelWith)
 			}
 		}
 	}
-
 	return nil
 }
 
@@ -337,13 +702,20 @@ func ValidateTicket(pattern, ticket string) error {
 	return nil
 }
 
-func phaseExists(phases []Phase, name string) bool {
-	for _, p := range phases {
-		if p.Name == name {
-			return true
-		}
+// checkSettingsFile verifies that a claude-settings path exists on disk.
+// Relative paths resolve against the project root. Paths that reference
+// variables are expanded at dispatch time, so they are not checked here.
+func checkSettingsFile(path, projectRoot string) error {
+	if path == "" || strings.Contains(path, "$") {
+		return nil
 	}
-	return false
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectRoot, path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("settings file %q not found — create the file or update the 'claude-settings' field", path)
+	}
+	return nil
 }
 
 // HasWorkflowRefs reports whether the config contains any workflow or branch phases.
@@ -476,3 +848,42 @@ func hasUnescapedSuffix(s string, ch byte) bool {
 	}
 	return n%2 == 0
 }
+
+var artifactsPrefixRe = regexp.MustCompile(`^[A-Za-z0-9._-]*$`)
+
+// ValidateArtifactsPrefix checks that prefix is safe to prepend to artifact
+// file names: letters, digits, '.', '_' and '-' only, so it can never
+// escape the artifacts directory.
+func ValidateArtifactsPrefix(prefix string) error {
+	if !artifactsPrefixRe.MatchString(prefix) {
+		return fmt.Errorf("%q may only contain letters, digits, '.', '_' and '-'", prefix)
+	}
+	return nil
+}
+
+// checkToolEntry validates one allow-tools entry: a bare tool name ("Bash",
+// "mcp__jira__*") or a pattern-scoped one ("Bash(git *)"), which claude
+// matches against the tool's input. Entries are passed to claude verbatim.
+func checkToolEntry(tool string) error {
+	if strings.TrimSpace(tool) == "" {
+		return fmt.Errorf("entries must be non-empty")
+	}
+	if !strings.ContainsAny(tool, "()") {
+		return nil
+	}
+	m := scopedToolRe.FindStringSubmatch(tool)
+	if m == nil || strings.TrimSpace(m[1]) == "" {
+		return fmt.Errorf("entry %q: scoped entries must look like Tool(pattern)", tool)
+	}
+	return nil
+}
+
+// sortedKeys returns m's keys in sorted order, for deterministic error messages.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -52,6 +54,43 @@ func TestValidate_DuplicatePhaseNames(t *testing.T) {
 	}
 }
 
+func TestValidate_DuplicatePhaseNamesIgnoreCase(t *testing.T) {
+	cfg := minimalConfig(scriptPhase("Test"), scriptPhase("test"))
+	err := Validate(cfg, t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "duplicate") || !strings.Contains(err.Error(), "only in case") {
+		t.Fatalf("expected case-insensitive duplicate error, got %v", err)
+	}
+}
+
+func TestValidate_PhaseNameWhitespaceRejected(t *testing.T) {
+	for _, name := range []string{"build ", " build", "build\t"} {
+		cfg := minimalConfig(scriptPhase("build"), Phase{Name: name, Type: "script", Run: "echo"})
+		err := Validate(cfg, t.TempDir())
+		if err == nil || !strings.Contains(err.Error(), "leading or trailing whitespace") {
+			t.Fatalf("name %q: expected whitespace error, got %v", name, err)
+		}
+	}
+}
+
+func TestValidate_PhaseNameReservedCharsRejected(t *testing.T) {
+	for _, name := range []string{"lint+test", "a:exhaust", "bad\x00name"} {
+		cfg := minimalConfig(Phase{Name: name, Type: "script", Run: "echo"})
+		if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "must not contain") {
+			t.Fatalf("name %q: expected reserved character error, got %v", name, err)
+		}
+	}
+}
+
+func TestValidate_PhaseNameKeptAsAuthored(t *testing.T) {
+	cfg := minimalConfig(scriptPhase("Build"), scriptPhase("test"))
+	if err := Validate(cfg, t.TempDir()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Phases[0].Name != "Build" {
+		t.Fatalf("name = %q, want %q", cfg.Phases[0].Name, "Build")
+	}
+}
+
 func TestValidate_PhaseNameTraversalRejected(t *testing.T) {
 	cases := []struct {
 		name      string
@@ -80,6 +119,72 @@ func TestValidate_UnknownPhaseType(t *testing.T) {
 	}
 }
 
+func TestValidate_PublishPhase(t *testing.T) {
+	publish := Phase{Name: "upload", Type: "publish", Run: "aws s3 cp $DELIVERABLES s3://bucket/"}
+	for _, tc := range []struct {
+		name   string
+		phases []Phase
+		want   string
+	}{
+		{"valid", []Phase{scriptPhase("build"), publish}, ""},
+		{"no run", []Phase{scriptPhase("build"), {Name: "upload", Type: "publish"}}, "'run' is required"},
+		{"not last", []Phase{publish, scriptPhase("build")}, "must be the last phase"},
+		{"twice", []Phase{publish, {Name: "mirror", Type: "publish", Run: "true"}}, "at most one publish phase"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(minimalConfig(tc.phases...), t.TempDir())
+			if tc.want == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected %q error, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestValidate_ExportVars(t *testing.T) {
+	vars := OrderedVars{{Key: "API_HOST", Value: "h"}, {Key: "TOKEN_FILE", Value: "f"}}
+	for _, tc := range []struct {
+		name   string
+		export *ExportVars
+		want   string
+		hidden []string
+	}{
+		{"unset exports all", nil, "", nil},
+		{"only", &ExportVars{Only: []string{"TOKEN_FILE"}}, "", []string{"API_HOST"}},
+		{"except", &ExportVars{Except: []string{"TOKEN_FILE"}}, "", []string{"TOKEN_FILE"}},
+		{"both", &ExportVars{Only: []string{"API_HOST"}, Except: []string{"TOKEN_FILE"}}, "mutually exclusive", nil},
+		{"undeclared", &ExportVars{Except: []string{"NOPE"}}, "not a declared var", nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := minimalConfig(scriptPhase("a"))
+			cfg.Vars = vars
+			cfg.ExportVars = tc.export
+			err := Validate(cfg, t.TempDir())
+			if tc.want != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.want) {
+					t.Fatalf("expected %q error, got %v", tc.want, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var hidden []string
+			for k := range cfg.UnexportedVars() {
+				hidden = append(hidden, k)
+			}
+			if !reflect.DeepEqual(hidden, tc.hidden) {
+				t.Errorf("unexported = %v, want %v", hidden, tc.hidden)
+			}
+		})
+	}
+}
+
 func TestValidate_AgentRequiresPrompt(t *testing.T) {
 	cfg := minimalConfig(Phase{Name: "a", Type: "agent"})
 	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "'prompt' is required") {
@@ -106,6 +211,25 @@ func TestValidate_AgentPromptExists(t *testing.T) {
 	}
 }
 
+func TestValidate_AgentPromptOutsideRoot(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "project")
+	os.MkdirAll(filepath.Join(root, "prompts"), 0755)
+	os.WriteFile(filepath.Join(root, "prompts", "design.md"), []byte("prompt"), 0644)
+	os.WriteFile(filepath.Join(base, "secret.md"), []byte("secret"), 0644)
+
+	for _, prompt := range []string{"../secret.md", "prompts/../../secret.md"} {
+		cfg := minimalConfig(Phase{Name: "a", Type: "agent", Prompt: prompt})
+		if err := Validate(cfg, root); err == nil || !strings.Contains(err.Error(), "outside the project root") {
+			t.Errorf("%s: expected containment error, got %v", prompt, err)
+		}
+	}
+	cfg := minimalConfig(Phase{Name: "a", Type: "agent", Prompt: "prompts/../prompts/design.md"})
+	if err := Validate(cfg, root); err != nil {
+		t.Fatalf("relative prompt inside the root should be accepted, got %v", err)
+	}
+}
+
 func TestValidate_AgentDefaults(t *testing.T) {
 	root := t.TempDir()
 	os.WriteFile(filepath.Join(root, "p.md"), []byte("x"), 0644)
@@ -213,6 +337,73 @@ func TestValidate_NegativeTimeout(t *testing.T) {
 	}
 }
 
+func TestValidate_ArtifactsPrefix(t *testing.T) {
+	for _, prefix := range []string{"", "build-", "wf_2.", "A"} {
+		cfg := minimalConfig(scriptPhase("a"))
+		cfg.ArtifactsPrefix = prefix
+		if err := Validate(cfg, t.TempDir()); err != nil {
+			t.Errorf("prefix %q: %v", prefix, err)
+		}
+	}
+	for _, prefix := range []string{"../x", "a/b", "has space"} {
+		cfg := minimalConfig(scriptPhase("a"))
+		cfg.ArtifactsPrefix = prefix
+		if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "artifacts-prefix") {
+			t.Errorf("prefix %q: got %v", prefix, err)
+		}
+	}
+}
+
+func TestValidate_InactivityTimeout(t *testing.T) {
+	cfg := minimalConfig(Phase{Name: "a", Type: "script", Run: "echo", InactivityTimeout: -1})
+	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "'inactivity-timeout' must be >= 0") {
+		t.Fatalf("got %v", err)
+	}
+	cfg = minimalConfig(Phase{Name: "a", Type: "gate", InactivityTimeout: 5})
+	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "only supported on agent and script") {
+		t.Fatalf("got %v", err)
+	}
+	cfg = minimalConfig(Phase{Name: "a", Type: "script", Run: "echo", InactivityTimeout: 5})
+	if err := Validate(cfg, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidate_OutputsOptionalRequiresOutputs(t *testing.T) {
+	cfg := minimalConfig(Phase{Name: "a", Type: "script", Run: "echo", OutputsOptional: true})
+	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "'outputs-optional' requires 'outputs'") {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestValidate_SecretEnv(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{"GITHUB_TOKEN": "GH_PAT"}, ""},
+		{map[string]string{"bad-name": "GH_PAT"}, "not a valid variable name"},
+		{map[string]string{"ORC_TOKEN": "GH_PAT"}, "reserved prefix"},
+		{map[string]string{"TICKET": "GH_PAT"}, "overrides a built-in or custom variable"},
+		{map[string]string{"MY_VAR": "GH_PAT"}, "overrides a built-in or custom variable"},
+		{map[string]string{"GITHUB_TOKEN": "$GH_PAT"}, "must name an environment variable"},
+	}
+	for _, tt := range tests {
+		cfg := minimalConfig(Phase{Name: "a", Type: "script", Run: "echo", SecretEnv: tt.env})
+		cfg.Vars = OrderedVars{{Key: "MY_VAR", Value: "x"}}
+		err := Validate(cfg, t.TempDir())
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("%v: %v", tt.env, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: got %v, want %q", tt.env, err, tt.want)
+		}
+	}
+}
+
 func TestValidate_OutputsNoPathSeparators(t *testing.T) {
 	cfg := minimalConfig(Phase{Name: "a", Type: "script", Run: "echo", Outputs: []string{"sub/file.md"}})
 	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "simple filename") {
@@ -220,6 +411,81 @@ func TestValidate_OutputsNoPathSeparators(t *testing.T) {
 	}
 }
 
+func TestValidate_OutputContentTypes(t *testing.T) {
+	cfg := minimalConfig(Phase{Name: "a", Type: "script", Run: "echo", Outputs: []string{"report.json:json", "plan.md:markdown", "c.yaml:yaml", "notes.txt"}})
+	if err := Validate(cfg, t.TempDir()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.OutputPaths(cfg.Phases[0]); !reflect.DeepEqual(got, []string{"report.json", "plan.md", "c.yaml", "notes.txt"}) {
+		t.Errorf("OutputPaths = %v", got)
+	}
+	if got := cfg.OutputContentTypes(cfg.Phases[0]); !reflect.DeepEqual(got, map[string]string{"report.json": "json", "plan.md": "markdown", "c.yaml": "yaml"}) {
+		t.Errorf("OutputContentTypes = %v", got)
+	}
+
+	cfg = minimalConfig(Phase{Name: "a", Type: "script", Run: "echo", Outputs: []string{"report.json:xml"}})
+	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), `unknown content type "xml"`) {
+		t.Fatalf("expected unknown content type error, got %v", err)
+	}
+}
+
+func TestValidate_GroupContiguous(t *testing.T) {
+	a, b, c := scriptPhase("a"), scriptPhase("b"), scriptPhase("c")
+	a.Group, b.Group = "setup", "setup"
+	if err := Validate(minimalConfig(a, b, c), t.TempDir()); err != nil {
+		t.Fatalf("contiguous group should be valid, got %v", err)
+	}
+	b.Group, c.Group = "", "setup"
+	if err := Validate(minimalConfig(a, b, c), t.TempDir()); err == nil || !strings.Contains(err.Error(), "must be contiguous") {
+		t.Fatalf("expected contiguity error, got %v", err)
+	}
+}
+
+func TestValidate_ManualPhase(t *testing.T) {
+	cfg := minimalConfig(Phase{Name: "bump", Type: "manual", Description: "Bump the version"})
+	if err := Validate(cfg, t.TempDir()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg = minimalConfig(Phase{Name: "bump", Type: "manual"})
+	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "'description' is required") {
+		t.Fatalf("expected missing description error, got %v", err)
+	}
+
+	cfg = minimalConfig(Phase{Name: "bump", Type: "manual", Description: "x", Run: "echo"})
+	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "'run' is not valid") {
+		t.Fatalf("expected run error, got %v", err)
+	}
+}
+
+func TestValidate_OutputsDir(t *testing.T) {
+	for _, dir := range []string{"/abs/reports", "..", "../reports", "reports/../..", "."} {
+		cfg := minimalConfig(scriptPhase("a"))
+		cfg.OutputsDir = dir
+		if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "outputs-dir") {
+			t.Errorf("outputs-dir %q: expected error, got %v", dir, err)
+		}
+	}
+	cfg := minimalConfig(scriptPhase("a"))
+	cfg.OutputsDir = "reports/daily"
+	if err := Validate(cfg, t.TempDir()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestValidate_RequireFreshOutputsWithoutOutputs(t *testing.T) {
+	p := scriptPhase("a")
+	p.RequireFreshOutputs = true
+	err := Validate(minimalConfig(p), t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "'require-fresh-outputs' requires 'outputs'") {
+		t.Fatalf("expected require-fresh-outputs error, got %v", err)
+	}
+	p.Outputs = []string{"report.md"}
+	if err := Validate(minimalConfig(p), t.TempDir()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestValidate_OutputsTraversalRejected(t *testing.T) {
 	cases := []struct {
 		name   string
@@ -365,13 +631,129 @@ func TestValidate_ParallelWithUnknown(t *testing.T) {
 	}
 }
 
+func TestValidate_RequirePhraseOnlyOnGate(t *testing.T) {
+	cfg := minimalConfig(Phase{Name: "a", Type: "script", Run: "echo", RequirePhrase: "DEPLOY"})
+	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "only valid on gate phases") {
+		t.Fatalf("expected require-phrase error, got %v", err)
+	}
+}
+
+func TestValidate_OnReject(t *testing.T) {
+	tests := []struct {
+		name    string
+		phases  []Phase
+		wantErr string
+	}{
+		{"stop", []Phase{{Name: "g", Type: "gate", OnReject: &OnReject{Action: "stop"}}}, ""},
+		{"continue", []Phase{{Name: "g", Type: "gate", OnReject: &OnReject{Action: "continue"}}}, ""},
+		{"goto", []Phase{
+			{Name: "a", Type: "script", Run: "echo"},
+			{Name: "g", Type: "gate", OnReject: &OnReject{Action: "goto", Goto: "a"}},
+		}, ""},
+		{"not a gate", []Phase{{Name: "a", Type: "script", Run: "echo", OnReject: &OnReject{Action: "continue"}}}, "only valid on gate phases"},
+		{"unknown action", []Phase{{Name: "g", Type: "gate", OnReject: &OnReject{Action: "retry"}}}, "must be stop, continue"},
+		{"goto unknown", []Phase{{Name: "g", Type: "gate", OnReject: &OnReject{Action: "goto", Goto: "nope"}}}, "unknown phase"},
+		{"goto self", []Phase{{Name: "g", Type: "gate", OnReject: &OnReject{Action: "goto", Goto: "g"}}}, "different phase"},
+		{"goto missing target", []Phase{{Name: "g", Type: "gate", OnReject: &OnReject{Action: "goto"}}}, "requires a phase"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(minimalConfig(tt.phases...), t.TempDir())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOnReject_UnmarshalYAML(t *testing.T) {
+	var p struct {
+		A *OnReject `yaml:"a"`
+		B *OnReject `yaml:"b"`
+	}
+	if err := yaml.Unmarshal([]byte("a: continue\nb: {goto: implement}\n"), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.A.Action != "continue" {
+		t.Fatalf("A.Action = %q, want continue", p.A.Action)
+	}
+	if p.B.Action != "goto" || p.B.Goto != "implement" {
+		t.Fatalf("B = %+v, want goto implement", *p.B)
+	}
+}
+
+func TestValidate_StdinOnlyOnScript(t *testing.T) {
+	dir := t.TempDir()
+	cfg := minimalConfig(Phase{Name: "g", Type: "gate", Stdin: "hello"})
+	if err := Validate(cfg, dir); err == nil || !strings.Contains(err.Error(), "only valid on script phases") {
+		t.Fatalf("expected stdin error, got %v", err)
+	}
+}
+
+func TestValidate_StdinAndStdinFileExclusive(t *testing.T) {
+	cfg := minimalConfig(Phase{Name: "a", Type: "script", Run: "cat", Stdin: "x", StdinFile: "in.txt"})
+	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Fatalf("expected mutually exclusive error, got %v", err)
+	}
+}
+
 func TestValidate_ParallelWithLoop(t *testing.T) {
 	cfg := minimalConfig(
 		scriptPhase("a"),
 		Phase{Name: "b", Type: "script", Run: "echo", ParallelWith: "a", Loop: &Loop{Goto: "a", Max: 3}},
 	)
+	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "must reference a phase before both") {
+		t.Fatalf("expected group loop.goto error, got %v", err)
+	}
+}
+
+func TestValidate_ParallelGroupLoop(t *testing.T) {
+	cfg := minimalConfig(
+		scriptPhase("impl"),
+		Phase{Name: "lint", Type: "script", Run: "echo", ParallelWith: "test", Loop: &Loop{Goto: "impl", Max: 2}},
+		scriptPhase("test"),
+	)
+	if err := Validate(cfg, t.TempDir()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestValidate_ParallelGroupLoopOnPartner(t *testing.T) {
+	cfg := minimalConfig(
+		scriptPhase("impl"),
+		Phase{Name: "lint", Type: "script", Run: "echo", ParallelWith: "test"},
+		Phase{Name: "test", Type: "script", Run: "echo", Loop: &Loop{Goto: "impl", Max: 2}},
+	)
+	if err := Validate(cfg, t.TempDir()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestValidate_ParallelGroupLoopWithCheck(t *testing.T) {
+	cfg := minimalConfig(
+		scriptPhase("impl"),
+		Phase{Name: "lint", Type: "script", Run: "echo", ParallelWith: "test", Loop: &Loop{Goto: "impl", Max: 2, Check: "true"}},
+		scriptPhase("test"),
+	)
 	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "split into separate phases") {
-		t.Fatalf("expected parallel+loop error, got %v", err)
+		t.Fatalf("expected parallel+loop.check error, got %v", err)
+	}
+}
+
+func TestValidate_ParallelGroupLoopOnBoth(t *testing.T) {
+	cfg := minimalConfig(
+		scriptPhase("impl"),
+		Phase{Name: "lint", Type: "script", Run: "echo", ParallelWith: "test", Loop: &Loop{Goto: "impl", Max: 2}},
+		Phase{Name: "test", Type: "script", Run: "echo", Loop: &Loop{Goto: "impl", Max: 2}},
+	)
+	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "only one phase") {
+		t.Fatalf("expected both-loops error, got %v", err)
 	}
 }
 
@@ -874,6 +1256,30 @@ func TestValidate_AllowToolsWhitespaceEntry(t *testing.T) {
 	}
 }
 
+func TestValidate_AllowToolsScopedEntries(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "p.md"), []byte("x"), 0644)
+	cfg := minimalConfig(Phase{Name: "a", Type: "agent", Prompt: "p.md",
+		AllowTools: []string{"Bash(git *)", "Bash(npm run test:*)", "Read"}})
+	cfg.DefaultAllowTools = []string{"Bash(make $(TARGET))"}
+	if err := Validate(cfg, root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tool := range []string{"Bash()", "Bash(  )", "(git *)", "Bash(git *", "Bash(git *) extra", "Bash git)"} {
+		cfg := minimalConfig(Phase{Name: "a", Type: "agent", Prompt: "p.md", AllowTools: []string{tool}})
+		err := Validate(cfg, root)
+		if err == nil || !strings.Contains(err.Error(), "Tool(pattern)") {
+			t.Errorf("allow-tools %q: expected scoped entry error, got %v", tool, err)
+		}
+	}
+	cfg = minimalConfig(scriptPhase("a"))
+	cfg.DefaultAllowTools = []string{"Bash("}
+	if err := Validate(cfg, root); err == nil || !strings.Contains(err.Error(), "default-allow-tools") {
+		t.Errorf("expected default-allow-tools error, got %v", err)
+	}
+}
+
 func TestValidate_DefaultAllowToolsValid(t *testing.T) {
 	cfg := &Config{
 		Name:              "test",
@@ -943,6 +1349,69 @@ func TestValidate_MCPConfigEmpty(t *testing.T) {
 	}
 }
 
+// claude-settings validation
+
+func TestValidate_ClaudeSettingsExists(t *testing.T) {
+	tmp := t.TempDir()
+	os.WriteFile(filepath.Join(tmp, "p.md"), []byte("prompt"), 0644)
+	os.WriteFile(filepath.Join(tmp, "settings.json"), []byte("{}"), 0644)
+	cfg := minimalConfig(Phase{Name: "a", Type: "agent", Prompt: "p.md", ClaudeSettings: "settings.json"})
+	if err := Validate(cfg, tmp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestValidate_ClaudeSettingsMissing(t *testing.T) {
+	tmp := t.TempDir()
+	os.WriteFile(filepath.Join(tmp, "p.md"), []byte("prompt"), 0644)
+	cfg := minimalConfig(Phase{Name: "a", Type: "agent", Prompt: "p.md", ClaudeSettings: "missing.json"})
+	if err := Validate(cfg, tmp); err == nil || !strings.Contains(err.Error(), "settings file") {
+		t.Fatalf("expected settings file error, got %v", err)
+	}
+}
+
+func TestValidate_ClaudeSettingsTopLevelMissing(t *testing.T) {
+	tmp := t.TempDir()
+	cfg := minimalConfig(scriptPhase("a"))
+	cfg.ClaudeSettings = "missing.json"
+	if err := Validate(cfg, tmp); err == nil || !strings.Contains(err.Error(), "'claude-settings'") {
+		t.Fatalf("expected claude-settings error, got %v", err)
+	}
+}
+
+func TestValidate_ClaudeSettingsWithVarsSkipsCheck(t *testing.T) {
+	tmp := t.TempDir()
+	os.WriteFile(filepath.Join(tmp, "p.md"), []byte("prompt"), 0644)
+	cfg := minimalConfig(Phase{Name: "a", Type: "agent", Prompt: "p.md", ClaudeSettings: "$ARTIFACTS_DIR/settings.json"})
+	if err := Validate(cfg, tmp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestValidate_ClaudeSettingsInherited(t *testing.T) {
+	tmp := t.TempDir()
+	os.WriteFile(filepath.Join(tmp, "p.md"), []byte("prompt"), 0644)
+	os.WriteFile(filepath.Join(tmp, "settings.json"), []byte("{}"), 0644)
+	cfg := minimalConfig(Phase{Name: "a", Type: "agent", Prompt: "p.md"}, scriptPhase("b"))
+	cfg.ClaudeSettings = "settings.json"
+	if err := Validate(cfg, tmp); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Phases[0].ClaudeSettings != "settings.json" {
+		t.Fatalf("agent ClaudeSettings = %q, want settings.json", cfg.Phases[0].ClaudeSettings)
+	}
+	if cfg.Phases[1].ClaudeSettings != "" {
+		t.Fatalf("script ClaudeSettings = %q, want empty", cfg.Phases[1].ClaudeSettings)
+	}
+}
+
+func TestValidate_ClaudeSettingsOnScript(t *testing.T) {
+	cfg := minimalConfig(Phase{Name: "a", Type: "script", Run: "echo", ClaudeSettings: "settings.json"})
+	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "'claude-settings' is only valid on agent phases") {
+		t.Fatalf("expected claude-settings error, got %v", err)
+	}
+}
+
 // Top-level defaults tests
 
 func TestValidate_TopLevelModelInherited(t *testing.T) {
@@ -1332,6 +1801,75 @@ func TestValidate_HistoryLimit(t *testing.T) {
 	})
 }
 
+func TestValidate_MaxStreamLineBytesNegative(t *testing.T) {
+	cfg := minimalConfig(scriptPhase("a"))
+	cfg.MaxStreamLineBytes = -1
+	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "max-stream-line-bytes") {
+		t.Fatalf("expected max-stream-line-bytes error, got %v", err)
+	}
+}
+
+func TestValidate_OnSuccess(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		onSuccess     string
+		onSuccessFail string
+		want          string
+	}{
+		{"command", "git tag ok", "", ""},
+		{"fail policy", "git tag ok", "fail", ""},
+		{"blank command", "   ", "", "'on-success' must be a non-empty command"},
+		{"unknown policy", "git tag ok", "retry", "unknown on-success-fail"},
+		{"policy without command", "", "warn", "'on-success-fail' requires 'on-success'"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := scriptPhase("a")
+			p.OnSuccess = tc.onSuccess
+			p.OnSuccessFail = tc.onSuccessFail
+			err := Validate(minimalConfig(p), t.TempDir())
+			if tc.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestValidate_InjectAllFeedbackAgentOnly(t *testing.T) {
+	p := scriptPhase("a")
+	p.InjectAllFeedback = true
+	if err := Validate(minimalConfig(p), t.TempDir()); err == nil || !strings.Contains(err.Error(), "'inject-all-feedback' is only valid on agent phases") {
+		t.Fatalf("expected agent-only error, got %v", err)
+	}
+}
+
+func TestValidate_Labels(t *testing.T) {
+	p := scriptPhase("a")
+	p.Labels = []string{"slow", "needs-db", "team/infra"}
+	if err := Validate(minimalConfig(p), t.TempDir()); err != nil {
+		t.Fatalf("arbitrary labels should be valid, got %v", err)
+	}
+	for _, bad := range []string{"", "  ", "a,b"} {
+		p.Labels = []string{bad}
+		if err := Validate(minimalConfig(p), t.TempDir()); err == nil || !strings.Contains(err.Error(), "labels") {
+			t.Fatalf("label %q: expected labels error, got %v", bad, err)
+		}
+	}
+}
+
+func TestValidate_WorkflowRetriesNegative(t *testing.T) {
+	cfg := minimalConfig(scriptPhase("a"))
+	cfg.WorkflowRetries = -1
+	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "workflow-retries") {
+		t.Fatalf("expected workflow-retries error, got %v", err)
+	}
+}
+
 // writeWorkflowFile creates a minimal workflow config file in projectRoot/.orc/workflows/<name>.yaml.
 func writeWorkflowFile(t *testing.T, projectRoot, name string) {
 	t.Helper()
@@ -1530,3 +2068,258 @@ func TestValidateWorkflowGraph_BranchCycle(t *testing.T) {
 		t.Fatalf("expected cycle error, got %v", err)
 	}
 }
+
+// disabled phases
+
+func TestValidate_DisabledPhaseStillValidated(t *testing.T) {
+	cfg := minimalConfig(scriptPhase("a"), Phase{Name: "b", Type: "script", Disabled: true})
+	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "'run' is required") {
+		t.Fatalf("expected run-required error for disabled phase, got %v", err)
+	}
+}
+
+func TestValidate_DisabledPhaseValid(t *testing.T) {
+	b := scriptPhase("b")
+	b.Disabled = true
+	cfg := minimalConfig(scriptPhase("a"), b)
+	if err := Validate(cfg, t.TempDir()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Phases[1].Disabled {
+		t.Fatal("Disabled should be preserved")
+	}
+}
+
+func TestLoad_DisabledField(t *testing.T) {
+	var p Phase
+	if err := yaml.Unmarshal([]byte("name: x\ntype: script\nrun: echo\ndisabled: true\n"), &p); err != nil {
+		t.Fatal(err)
+	}
+	if !p.Disabled {
+		t.Fatal("expected Disabled = true")
+	}
+}
+
+func TestValidate_PhaseErrorCarriesPosition(t *testing.T) {
+	src := "name: t\nphases:\n  - name: a\n    type: script\n    run: echo\n  - name: b\n    type: bogus\n"
+	var cfg Config
+	if err := yaml.Unmarshal([]byte(src), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	err := Validate(&cfg, t.TempDir())
+	var posErr *PositionError
+	if !errors.As(err, &posErr) {
+		t.Fatalf("expected PositionError, got %T: %v", err, err)
+	}
+	if posErr.Line != 6 || posErr.Column != 5 {
+		t.Errorf("position = %d:%d, want 6:5", posErr.Line, posErr.Column)
+	}
+	if !strings.HasPrefix(err.Error(), `config: phase "b"`) {
+		t.Errorf("message should be unchanged, got %q", err.Error())
+	}
+
+	// Phases built in code have no position; errors are returned as-is.
+	err = Validate(minimalConfig(Phase{Name: "a", Type: "bogus"}), t.TempDir())
+	if err == nil || errors.As(err, &posErr) {
+		t.Errorf("expected plain error, got %T: %v", err, err)
+	}
+}
+
+func TestValidate_FallbackModel(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "p.md"), []byte("x"), 0644)
+	agent := func(model, fallback string) *Config {
+		return minimalConfig(Phase{Name: "a", Type: "agent", Prompt: "p.md", Model: model, FallbackModel: fallback})
+	}
+	if err := Validate(agent("opus", "sonnet"), root); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, tt := range []struct {
+		cfg  *Config
+		want string
+	}{
+		{agent("opus", "gpt-4"), `unknown fallback-model "gpt-4"`},
+		{agent("sonnet", "sonnet"), "must differ from 'model'"},
+		{minimalConfig(Phase{Name: "a", Type: "script", Run: "echo", FallbackModel: "haiku"}), "only valid on agent phases"},
+	} {
+		if err := Validate(tt.cfg, root); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("expected error containing %q, got %v", tt.want, err)
+		}
+	}
+}
+
+func TestValidate_OnDenial(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "p.md"), []byte("x"), 0644)
+	for _, policy := range []string{"ignore", "fail", "approve-and-retry"} {
+		cfg := minimalConfig(Phase{Name: "a", Type: "agent", Prompt: "p.md", OnDenial: policy})
+		if err := Validate(cfg, root); err != nil {
+			t.Errorf("on-denial %q: unexpected error: %v", policy, err)
+		}
+	}
+	for _, tt := range []struct {
+		cfg  *Config
+		want string
+	}{
+		{minimalConfig(Phase{Name: "a", Type: "agent", Prompt: "p.md", OnDenial: "retry"}), `unknown on-denial "retry"`},
+		{minimalConfig(Phase{Name: "a", Type: "script", Run: "echo", OnDenial: "fail"}), "'on-denial' is only valid on agent phases"},
+	} {
+		if err := Validate(tt.cfg, root); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("expected error containing %q, got %v", tt.want, err)
+		}
+	}
+}
+
+func TestValidate_Container(t *testing.T) {
+	ok := minimalConfig(Phase{Name: "a", Type: "script", Run: "make", Container: &Container{Image: "golang:1.22", Runtime: "podman", Volumes: []string{"/cache:/root/.cache:ro"}}})
+	if err := Validate(ok, t.TempDir()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, tt := range []struct {
+		cfg  *Config
+		want string
+	}{
+		{minimalConfig(Phase{Name: "a", Type: "gate", Container: &Container{Image: "alpine"}}), "'container' is only valid on script phases"},
+		{minimalConfig(Phase{Name: "a", Type: "script", Run: "make", Container: &Container{}}), "container.image is required"},
+		{minimalConfig(Phase{Name: "a", Type: "script", Run: "make", Container: &Container{Image: "alpine", Runtime: "lxc"}}), `unknown container.runtime "lxc"`},
+		{minimalConfig(Phase{Name: "a", Type: "script", Run: "make", Container: &Container{Image: "alpine", Volumes: []string{"/cache"}}}), "must be host:container"},
+	} {
+		if err := Validate(tt.cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("expected error containing %q, got %v", tt.want, err)
+		}
+	}
+}
+
+func TestContainer_UnmarshalShorthand(t *testing.T) {
+	var p Phase
+	if err := yaml.Unmarshal([]byte("name: a\ntype: script\nrun: make\ncontainer: golang:1.22\n"), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Container == nil || p.Container.Image != "golang:1.22" || p.Container.RuntimeOrDefault() != "docker" {
+		t.Errorf("container = %+v", p.Container)
+	}
+}
+
+func TestValidate_Deliverables(t *testing.T) {
+	ok := minimalConfig(Phase{Name: "a", Type: "script", Run: "echo"})
+	ok.Deliverables = []string{"report.md", "outputs/pr-url.txt"}
+	if err := Validate(ok, t.TempDir()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, d := range []string{"", "/tmp/report.md", "../report.md", "."} {
+		cfg := minimalConfig(Phase{Name: "a", Type: "script", Run: "echo"})
+		cfg.Deliverables = []string{d}
+		if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "relative path inside the artifacts directory") {
+			t.Errorf("deliverable %q: expected error, got %v", d, err)
+		}
+	}
+}
+
+func TestValidate_CreateCwd(t *testing.T) {
+	ok := minimalConfig(Phase{Name: "a", Type: "script", Run: "echo", Cwd: "$WORKTREE", CreateCwd: true})
+	if err := Validate(ok, t.TempDir()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	inherited := minimalConfig(Phase{Name: "a", Type: "script", Run: "echo", CreateCwd: true})
+	inherited.Cwd = "$WORKTREE"
+	if err := Validate(inherited, t.TempDir()); err != nil {
+		t.Fatalf("config-level cwd should satisfy create-cwd: %v", err)
+	}
+	for _, tt := range []struct {
+		cfg  *Config
+		want string
+	}{
+		{minimalConfig(Phase{Name: "a", Type: "gate", Run: "echo", Cwd: "/tmp/x", CreateCwd: true}), "'create-cwd' is not valid on gate phases"},
+		{minimalConfig(Phase{Name: "a", Type: "script", Run: "echo", CreateCwd: true}), "'create-cwd' requires 'cwd'"},
+	} {
+		if err := Validate(tt.cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("expected error containing %q, got %v", tt.want, err)
+		}
+	}
+}
+
+func TestValidate_SuccessExitCodes(t *testing.T) {
+	ok := minimalConfig(Phase{Name: "a", Type: "script", Run: "git commit", SuccessExitCodes: []int{0, 1}})
+	if err := Validate(ok, t.TempDir()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, tt := range []struct {
+		cfg  *Config
+		want string
+	}{
+		{minimalConfig(Phase{Name: "a", Type: "gate", SuccessExitCodes: []int{1}}), "only valid on script phases"},
+		{minimalConfig(Phase{Name: "a", Type: "script", Run: "echo", SuccessExitCodes: []int{256}}), "out of range"},
+	} {
+		if err := Validate(tt.cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("expected error containing %q, got %v", tt.want, err)
+		}
+	}
+}
+
+func TestValidate_KeepGoingRequiresParallelWith(t *testing.T) {
+	ok := minimalConfig(
+		Phase{Name: "a", Type: "script", Run: "echo", ParallelWith: "b", KeepGoing: true},
+		Phase{Name: "b", Type: "script", Run: "echo"},
+	)
+	if err := Validate(ok, t.TempDir()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bad := minimalConfig(Phase{Name: "a", Type: "script", Run: "echo", KeepGoing: true})
+	if err := Validate(bad, t.TempDir()); err == nil || !strings.Contains(err.Error(), "'keep-going' requires 'parallel-with'") {
+		t.Fatalf("expected keep-going error, got %v", err)
+	}
+}
+
+func TestValidate_DoctorPromptMissing(t *testing.T) {
+	cfg := minimalConfig(Phase{Name: "a", Type: "script", Run: "echo"})
+	cfg.DoctorPrompt = ".orc/doctor.md"
+	if err := Validate(cfg, t.TempDir()); err == nil || !strings.Contains(err.Error(), "doctor-prompt file") {
+		t.Fatalf("expected doctor-prompt error, got %v", err)
+	}
+}
+
+func TestValidate_ConditionTimeoutDefaults(t *testing.T) {
+	cfg := minimalConfig(
+		Phase{Name: "a", Type: "script", Run: "echo", Condition: "true"},
+		Phase{Name: "b", Type: "script", Run: "echo", Condition: "true", ConditionTimeout: 5, OnConditionTimeout: "fail"},
+		Phase{Name: "c", Type: "script", Run: "echo"},
+	)
+	cfg.ConditionTimeout = 20
+	if err := Validate(cfg, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	if p := cfg.Phases[0]; p.ConditionTimeout != 20 || p.OnConditionTimeout != "skip" {
+		t.Fatalf("a: got %d/%q, want the config default 20/skip", p.ConditionTimeout, p.OnConditionTimeout)
+	}
+	if p := cfg.Phases[1]; p.ConditionTimeout != 5 || p.OnConditionTimeout != "fail" {
+		t.Fatalf("b: got %d/%q, want 5/fail", p.ConditionTimeout, p.OnConditionTimeout)
+	}
+	if p := cfg.Phases[2]; p.ConditionTimeout != 0 {
+		t.Fatalf("c: phase without a condition got condition-timeout %d", p.ConditionTimeout)
+	}
+
+	cfg = minimalConfig(Phase{Name: "a", Type: "script", Run: "echo", Condition: "true"})
+	if err := Validate(cfg, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Phases[0].ConditionTimeout != 60 {
+		t.Fatalf("built-in default = %d, want 60", cfg.Phases[0].ConditionTimeout)
+	}
+}
+
+func TestValidate_ConditionTimeoutErrors(t *testing.T) {
+	tests := []struct {
+		phase   Phase
+		wantErr string
+	}{
+		{Phase{Name: "a", Type: "script", Run: "echo", ConditionTimeout: 5}, "require 'condition'"},
+		{Phase{Name: "a", Type: "script", Run: "echo", Condition: "true", ConditionTimeout: -1}, "must be >= 0"},
+		{Phase{Name: "a", Type: "script", Run: "echo", Condition: "true", OnConditionTimeout: "retry"}, "unknown on-condition-timeout"},
+	}
+	for _, tt := range tests {
+		err := Validate(minimalConfig(tt.phase), t.TempDir())
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%+v: got %v, want error containing %q", tt.phase, err, tt.wantErr)
+		}
+	}
+}
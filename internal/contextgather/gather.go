@@ -7,9 +7,24 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"unicode/utf8"
 )
 
-const maxFileSize = 32 * 1024 // 32KB per file
+const (
+	maxFileSize    = 32 * 1024  // 32KB per file
+	maxContextSize = 128 * 1024 // 128KB for the whole rendered context
+	maxTreeSize    = 16 * 1024  // directory listing share of the budget
+
+	// minTruncatedFile is the smallest useful slice of a file; below this a
+	// file that doesn't fit is omitted rather than truncated.
+	minTruncatedFile = 1024
+
+	// budgetNoteReserve leaves room for the note listing truncated and
+	// omitted files.
+	budgetNoteReserve = 1024
+)
+
+const budgetTruncMarker = "\n... (truncated to fit context budget)"
 
 // skipDirs are directories excluded from the tree listing.
 var skipDirs = map[string]bool{
@@ -49,54 +64,196 @@ type ProjectContext struct {
 	DirTree string            // top-level + one level deep listing
 	Files   map[string]string // relative path -> contents
 	GitLog  string            // last 10 commits
+
+	GitBranch     string // current branch ("" if detached or not a git repo)
+	GitMainBranch string // "main" or "master" if either exists locally
+	GitRemotes    string // fetch remotes from git remote -v, credentials redacted
+}
+
+// Options extends the built-in gathering rules for unusual project layouts.
+type Options struct {
+	// SkipDirs are extra directories left out of the tree listing, given as
+	// a directory name (matched at any listed depth) or a root-relative path.
+	SkipDirs []string
+	// IncludeFiles are extra root-relative files or glob patterns whose
+	// content is gathered alongside the well-known files.
+	IncludeFiles []string
 }
 
 // Gather collects project context from the given directory.
 func Gather(projectRoot string) (*ProjectContext, error) {
+	return GatherWith(projectRoot, Options{})
+}
+
+// GatherWith collects project context like Gather, applying opts on top of
+// the built-in skip list and file probes.
+func GatherWith(projectRoot string, opts Options) (*ProjectContext, error) {
+	for _, pattern := range opts.IncludeFiles {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("include pattern %q: %w", pattern, err)
+		}
+	}
 	pc := &ProjectContext{
 		Files: make(map[string]string),
 	}
 
-	pc.DirTree = buildTree(projectRoot)
-	gatherFiles(projectRoot, pc)
+	extraSkips := make(map[string]bool, len(opts.SkipDirs))
+	for _, d := range opts.SkipDirs {
+		extraSkips[filepath.Clean(d)] = true
+	}
+	pc.DirTree = buildTree(projectRoot, extraSkips)
+	gatherFiles(projectRoot, pc, opts.IncludeFiles)
 	pc.GitLog = gatherGitLog(projectRoot)
+	pc.GitBranch = gitOutput(projectRoot, "branch", "--show-current")
+	pc.GitMainBranch = gatherMainBranch(projectRoot)
+	pc.GitRemotes = gatherRemotes(projectRoot)
 
 	return pc, nil
 }
 
-// Render formats the context as a prompt section.
+// Render formats the context as a prompt section. The output is kept within
+// maxContextSize: files are included in priority order (see fileRank) rather
+// than alphabetically, and lower-priority files are truncated or dropped once
+// the budget runs out, with a note listing them.
 func (pc *ProjectContext) Render() string {
 	var buf strings.Builder
 
+	tree := pc.DirTree
+	if len(tree) > maxTreeSize {
+		cut := strings.LastIndex(tree[:maxTreeSize], "\n") + 1
+		tree = tree[:cut] + "... (truncated)\n"
+	}
 	buf.WriteString("## Project Directory Structure\n\n```\n")
-	buf.WriteString(pc.DirTree)
+	buf.WriteString(tree)
 	buf.WriteString("```\n")
 
+	var gitSection string
+	if repo := pc.renderRepoInfo(); repo != "" {
+		gitSection += "\n## Git Repository\n\n" + repo
+	}
+	if pc.GitLog != "" {
+		gitSection += "\n## Recent Git History\n\n```\n" + pc.GitLog + "```\n"
+	}
+
 	if len(pc.Files) > 0 {
 		buf.WriteString("\n## Key Files\n")
 
-		// Sort paths for deterministic output
 		paths := make([]string, 0, len(pc.Files))
 		for p := range pc.Files {
 			paths = append(paths, p)
 		}
-		sort.Strings(paths)
+		sort.Slice(paths, func(i, j int) bool {
+			pi, pj := filePriority(paths[i]), filePriority(paths[j])
+			if pi != pj {
+				return pi < pj
+			}
+			return paths[i] < paths[j]
+		})
 
+		remaining := maxContextSize - buf.Len() - len(gitSection) - budgetNoteReserve
+		var truncated, omitted []string
 		for _, p := range paths {
-			buf.WriteString(fmt.Sprintf("\n### %s\n\n```\n%s\n```\n", p, pc.Files[p]))
+			section := fmt.Sprintf("\n### %s\n\n```\n%s\n```\n", p, pc.Files[p])
+			if len(section) <= remaining {
+				buf.WriteString(section)
+				remaining -= len(section)
+				continue
+			}
+			overhead := len(section) - len(pc.Files[p]) + len(budgetTruncMarker)
+			if room := remaining - overhead; room >= minTruncatedFile {
+				section = fmt.Sprintf("\n### %s\n\n```\n%s%s\n```\n", p, truncateUTF8(pc.Files[p], room), budgetTruncMarker)
+				buf.WriteString(section)
+				remaining -= len(section)
+				truncated = append(truncated, p)
+				continue
+			}
+			omitted = append(omitted, p)
+		}
+
+		if len(truncated) > 0 || len(omitted) > 0 {
+			buf.WriteString(fmt.Sprintf("\n_Context budget of %dKB reached.", maxContextSize/1024))
+			if len(truncated) > 0 {
+				buf.WriteString(" Truncated: " + strings.Join(truncated, ", ") + ".")
+			}
+			if len(omitted) > 0 {
+				buf.WriteString(" Omitted: " + strings.Join(omitted, ", ") + ".")
+			}
+			buf.WriteString("_\n")
 		}
 	}
 
-	if pc.GitLog != "" {
-		buf.WriteString("\n## Recent Git History\n\n```\n")
-		buf.WriteString(pc.GitLog)
-		buf.WriteString("```\n")
+	buf.WriteString(gitSection)
+
+	return buf.String()
+}
+
+// truncateUTF8 returns at most n bytes of s, cut back to a rune boundary so
+// a multi-byte character is never split.
+func truncateUTF8(s string, n int) string {
+	if n >= len(s) {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
 	}
+	return s[:n]
+}
 
+// renderRepoInfo formats branch and remote details, or "" if none are known.
+func (pc *ProjectContext) renderRepoInfo() string {
+	var buf strings.Builder
+	if pc.GitBranch != "" {
+		buf.WriteString("Current branch: " + pc.GitBranch + "\n")
+	}
+	if pc.GitMainBranch != "" {
+		buf.WriteString("Main branch: " + pc.GitMainBranch + "\n")
+	}
+	if pc.GitRemotes != "" {
+		buf.WriteString("Remotes:\n\n```\n" + pc.GitRemotes + "\n```\n")
+	}
 	return buf.String()
 }
 
-func buildTree(root string) string {
+// fileRank orders well-known files in the rendered context: the README
+// first, then manifests, then CI workflows (matched by prefix in
+// filePriority), then agent instructions and build files. Anything else is
+// last.
+var fileRank = map[string]int{
+	"README.md":        0,
+	"readme.md":        0,
+	"README":           0,
+	"go.mod":           1,
+	"package.json":     1,
+	"pyproject.toml":   1,
+	"setup.py":         1,
+	"requirements.txt": 1,
+	"Cargo.toml":       1,
+	"CLAUDE.md":        3,
+	".cursorrules":     3,
+	"Makefile":         4,
+	"makefile":         4,
+}
+
+const (
+	ciRank    = 2
+	otherRank = 5
+)
+
+// filePriority ranks gathered files for ordering and the context budget;
+// lower is rendered (and kept) first.
+func filePriority(path string) int {
+	if r, ok := fileRank[path]; ok {
+		return r
+	}
+	if strings.HasPrefix(filepath.ToSlash(path), ".github/workflows/") {
+		return ciRank
+	}
+	return otherRank
+}
+
+// buildTree lists root and one level below it. extraSkips holds
+// user-supplied directory names and root-relative paths to leave out.
+func buildTree(root string, extraSkips map[string]bool) string {
 	entries, err := os.ReadDir(root)
 	if err != nil {
 		return "(unable to read directory)\n"
@@ -104,7 +261,7 @@ func buildTree(root string) string {
 
 	var buf strings.Builder
 	for _, e := range entries {
-		if skipDirs[e.Name()] {
+		if skipDirs[e.Name()] || (e.IsDir() && extraSkips[e.Name()]) {
 			continue
 		}
 		if e.IsDir() {
@@ -116,6 +273,9 @@ func buildTree(root string) string {
 				continue
 			}
 			for _, se := range subEntries {
+				if se.IsDir() && (extraSkips[se.Name()] || extraSkips[filepath.Join(e.Name(), se.Name())]) {
+					continue
+				}
 				if se.IsDir() {
 					buf.WriteString("  " + se.Name() + "/\n")
 				} else {
@@ -129,7 +289,9 @@ func buildTree(root string) string {
 	return buf.String()
 }
 
-func gatherFiles(root string, pc *ProjectContext) {
+// gatherFiles reads the well-known files and globs, plus the extra
+// patterns in include, into pc.Files.
+func gatherFiles(root string, pc *ProjectContext, include []string) {
 	// Direct file probes
 	for _, name := range wellKnownFiles {
 		path := filepath.Join(root, name)
@@ -144,8 +306,9 @@ func gatherFiles(root string, pc *ProjectContext) {
 		pc.Files[name] = content
 	}
 
-	// Glob patterns
-	for _, pattern := range wellKnownGlobs {
+	// Glob patterns, then the caller's extra includes
+	patterns := append(append([]string{}, wellKnownGlobs...), include...)
+	for _, pattern := range patterns {
 		matches, err := filepath.Glob(filepath.Join(root, pattern))
 		if err != nil {
 			continue
@@ -169,7 +332,13 @@ func gatherFiles(root string, pc *ProjectContext) {
 }
 
 func gatherGitLog(root string) string {
-	cmd := exec.Command("git", "log", "--oneline", "-10")
+	return gitOutput(root, "log", "--oneline", "-10")
+}
+
+// gitOutput runs a git command in root and returns its trimmed output, or ""
+// on any error (including root not being a git repository).
+func gitOutput(root string, args ...string) string {
+	cmd := exec.Command("git", args...)
 	cmd.Dir = root
 	out, err := cmd.Output()
 	if err != nil {
@@ -177,3 +346,45 @@ func gatherGitLog(root string) string {
 	}
 	return strings.TrimSpace(string(out))
 }
+
+// gatherMainBranch returns "main" or "master" if that local branch exists.
+func gatherMainBranch(root string) string {
+	for _, name := range []string{"main", "master"} {
+		if gitOutput(root, "rev-parse", "--verify", "--quiet", "refs/heads/"+name) != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// gatherRemotes returns one "name url" line per remote, with any credentials
+// embedded in the URL removed.
+func gatherRemotes(root string) string {
+	out := gitOutput(root, "remote", "-v")
+	if out == "" {
+		return ""
+	}
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[2] != "(fetch)" {
+			continue
+		}
+		lines = append(lines, fields[0]+" "+redactURL(fields[1]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// redactURL strips user info (tokens, passwords) from a remote URL.
+func redactURL(raw string) string {
+	scheme, rest, ok := strings.Cut(raw, "://")
+	if !ok {
+		return raw
+	}
+	if at := strings.LastIndex(rest, "@"); at >= 0 {
+		if slash := strings.Index(rest, "/"); slash < 0 || at < slash {
+			rest = rest[at+1:]
+		}
+	}
+	return scheme + "://" + rest
+}
@@ -128,16 +128,14 @@ func readFeedbackFiles(artifactsDir string) []FeedbackFile {
 		if e.IsDir() {
 			continue
 		}
-		name := e.Name()
-		if !strings.HasPrefix(name, "from-") || !strings.HasSuffix(name, ".md") {
+		phase, ok := state.FeedbackSource(e.Name())
+		if !ok {
 			continue
 		}
 		info, err := e.Info()
 		if err != nil || info.Size() == 0 {
 			continue
 		}
-		phase := strings.TrimPrefix(name, "from-")
-		phase = strings.TrimSuffix(phase, ".md")
 		files = append(files, FeedbackFile{FromPhase: phase, Size: info.Size()})
 	}
 	if len(files) == 0 {
@@ -303,11 +301,15 @@ func Run(projectRoot string, cfg *config.Config, phaseIdx int, ticket, workflow
 
 	// Build env + vars
 	env := &dispatch.Environment{
-		ProjectRoot:  projectRoot,
-		WorkDir:      projectRoot,
-		ArtifactsDir: artifactsDir,
-		Ticket:       ticket,
-		Workflow:     workflow,
+		ProjectRoot:      projectRoot,
+		WorkDir:          projectRoot,
+		ArtifactsDir:     artifactsDir,
+		Ticket:           ticket,
+		Workflow:         workflow,
+		WorkflowName:     cfg.Name,
+		PhaseIndex:       phaseIdx,
+		PhaseDescription: phase.Description,
+		PhaseOutputDir:   filepath.Join(artifactsDir, cfg.OutputDir(phase)),
 	}
 	vars := env.Vars()
 	if len(cfg.Vars) > 0 {
@@ -323,7 +325,7 @@ func Run(projectRoot string, cfg *config.Config, phaseIdx int, ticket, workflow
 
 	// Declared outputs
 	var outputs []OutputFile
-	for _, name := range phase.Outputs {
+	for _, name := range cfg.OutputPaths(phase) {
 		of := OutputFile{Name: name}
 		if info, err := os.Stat(filepath.Join(stateDir, name)); err == nil {
 			of.Exists = true
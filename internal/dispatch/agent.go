@@ -29,13 +29,13 @@ var defaultAllowTools = []string{
 // If sessionID is non-empty and isFirst is true, uses --session-id.
 // If sessionID is non-empty and isFirst is false, uses --resume.
 func buildAgentArgs(phase config.Phase, env *Environment, sessionID string, isFirst bool, extraTools []string) []string {
-	args := []string{"-p",
-		"--output-format", "stream-json",
-		"--verbose",
-		"--include-partial-messages",
-		"--model", phase.Model,
-		"--effort", phase.Effort,
+	args := []string{"-p"}
+	if env.NoStream {
+		args = append(args, "--output-format", "text")
+	} else {
+		args = append(args, "--output-format", "stream-json", "--verbose", "--include-partial-messages")
 	}
+	args = append(args, "--model", phase.Model, "--effort", phase.Effort)
 
 	if sessionID != "" {
 		if isFirst {
@@ -50,6 +50,14 @@ func buildAgentArgs(phase config.Phase, env *Environment, sessionID string, isFi
 		args = append(args, "--mcp-config", expanded)
 	}
 
+	if phase.ClaudeSettings != "" {
+		expanded := ExpandVars(phase.ClaudeSettings, env.Vars())
+		if !filepath.IsAbs(expanded) {
+			expanded = filepath.Join(env.ProjectRoot, expanded)
+		}
+		args = append(args, "--settings", expanded)
+	}
+
 	// Merge default tools, config-level tools, phase allow-tools, and dynamically approved tools
 	seen := make(map[string]bool)
 	var tools []string
@@ -71,19 +79,67 @@ func buildAgentArgs(phase config.Phase, env *Environment, sessionID string, isFi
 
 // turnResult holds the outcome of a single agent turn (subprocess invocation).
 type turnResult struct {
-	Stream   *StreamResult
-	ExitCode int
+	Stream     *StreamResult
+	ExitCode   int
+	Overloaded bool // the model was overloaded (from the result event or stderr)
+	Stalled    bool // the inactivity watchdog killed the turn
 }
 
-// runAgentTurn executes a single agent turn: starts subprocess, processes stream, waits.
+// runAgentTurn executes a single agent turn. If the phase's model is
+// overloaded and a fallback-model is configured, the turn is retried once
+// with the fallback model.
 func runAgentTurn(ctx context.Context, phase config.Phase, env *Environment, prompt, sessionID string, isFirst bool, logFile io.Writer, rawLog io.Writer, extraTools []string) (*turnResult, error) {
+	tr, err := runAgentTurnOnce(ctx, phase, env, prompt, sessionID, isFirst, logFile, rawLog, extraTools)
+	if err != nil || !tr.Overloaded || tr.Stalled || phase.FallbackModel == "" || ctx.Err() != nil {
+		return tr, err
+	}
+	msg := fmt.Sprintf("model %s is overloaded — retrying the turn with fallback model %s\n", phase.Model, phase.FallbackModel)
+	fmt.Fprint(os.Stderr, "  "+msg)
+	logMsg(logFile, msg)
+	phase.Model = phase.FallbackModel
+	return runAgentTurnOnce(ctx, phase, env, prompt, sessionID, isFirst, logFile, rawLog, extraTools)
+}
+
+// runAgentTurnOnce executes one agent subprocess: starts it, processes the stream, waits.
+func runAgentTurnOnce(ctx context.Context, phase config.Phase, env *Environment, prompt, sessionID string, isFirst bool, logFile io.Writer, rawLog io.Writer, extraTools []string) (*turnResult, error) {
+	var eventWriters []io.Writer
+	if env.LogFormat == "json" {
+		f, err := os.OpenFile(state.EventLogPath(env.ArtifactsDir, env.PhaseIndex), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		eventWriters = append(eventWriters, f)
+	}
+	if env.Events != nil {
+		eventWriters = append(eventWriters, env.Events)
+	}
+	var events *EventLog
+	if len(eventWriters) > 0 {
+		events = NewEventLog(io.MultiWriter(eventWriters...), phase.Name)
+	}
+
+	if env.ReplayFile != "" {
+		return replayAgentTurn(ctx, phase, env.ReplayFile, logFile, rawLog, events, env.MaxStreamLineBytes)
+	}
+
 	args := buildAgentArgs(phase, env, sessionID, isFirst, extraTools)
 
 	// Derive a cancellable subcontext so the in-flight cost monitor can
 	// terminate the subprocess when the cap is exceeded — without tearing
 	// down the parent phase's context (which would prevent post-mortem
-	// bookkeeping like cost flush and state save).
-	cmdCtx, cancelCmd := context.WithCancel(ctx)
+	// bookkeeping like cost flush and state save). The inactivity watchdog
+	// sits between the two: a silent stream cancels the subprocess and is
+	// reported as Stalled rather than as an error. --no-stream writes
+	// nothing until the turn ends, so silence there is not a hang and the
+	// watchdog stays off.
+	inactivity := InactivityTimeout(phase)
+	if env.NoStream {
+		inactivity = 0
+	}
+	wdCtx, wd, stopWatchdog := newWatchdog(ctx, inactivity)
+	defer stopWatchdog()
+	cmdCtx, cancelCmd := context.WithCancel(wdCtx)
 	defer cancelCmd()
 
 	cmd := exec.CommandContext(cmdCtx, "claude", args...)
@@ -95,19 +151,36 @@ func runAgentTurn(ctx context.Context, phase config.Phase, env *Environment, pro
 		return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
 	}
 	cmd.WaitDelay = 5 * time.Second
-	cmd.Stderr = io.MultiWriter(os.Stderr, logFile)
+	stderrTail := newTailWriter(4096)
+	cmd.Stderr = io.MultiWriter(ux.PhaseErrOutput(phase.Name), logFile, stderrTail, wd)
 
-	stdout, err := cmd.StdoutPipe()
+	pipe, err := cmd.StdoutPipe()
 	if err != nil {
 		return nil, fmt.Errorf("stdout pipe: %w", err)
 	}
+	var stdout io.Reader = io.TeeReader(pipe, wd)
+
+	if env.RecordDir != "" {
+		rec, err := openRecording(env.RecordDir, env.PhaseIndex)
+		if err != nil {
+			return nil, err
+		}
+		defer rec.Close()
+		stdout = io.TeeReader(stdout, rec)
+	}
 
 	if err := cmd.Start(); err != nil {
 		return nil, fmt.Errorf("starting claude: %w", err)
 	}
 
-	monitor := newCostMonitor(phase.MaxCost, phase.Model)
-	streamResult, streamErr := ProcessStreamWithMonitor(cmdCtx, stdout, os.Stdout, logFile, rawLog, monitor, cancelCmd)
+	var streamResult *StreamResult
+	var streamErr error
+	if env.NoStream {
+		streamResult, streamErr = readPlainOutput(stdout, ux.PhaseOutput(phase.Name), logFile)
+	} else {
+		monitor := newCostMonitor(phase.MaxCost, phase.Model)
+		streamResult, streamErr = ProcessStreamWithMonitor(cmdCtx, stdout, ux.StreamOutput(phase.Name), logFile, rawLog, events, monitor, cancelCmd, env.MaxStreamLineBytes)
+	}
 
 	code, waitErr := exitCode(cmd.Wait())
 	if waitErr != nil {
@@ -116,11 +189,73 @@ func runAgentTurn(ctx context.Context, phase config.Phase, env *Environment, pro
 	// If we killed the process because of cost overrun, the subprocess
 	// exits non-zero (SIGTERM). Don't propagate the context error — the
 	// CostOverrun flag on the result tells the caller what happened.
-	if streamErr != nil && ctx.Err() == nil && !(streamResult != nil && streamResult.CostOverrun) {
+	if streamErr != nil && ctx.Err() == nil && !wd.Fired() && !(streamResult != nil && streamResult.CostOverrun) {
 		return nil, streamErr
 	}
 
-	return &turnResult{Stream: streamResult, ExitCode: code}, nil
+	overloaded := streamResult != nil && streamResult.Overloaded
+	if code != 0 && isOverloadMessage(stderrTail.String()) {
+		overloaded = true
+	}
+	return &turnResult{Stream: streamResult, ExitCode: code, Overloaded: overloaded, Stalled: wd.Fired()}, nil
+}
+
+// readPlainOutput collects the whole output of a --no-stream turn
+// (--output-format text) and then writes it to display and logFile in one
+// piece. Plain text carries no session, cost, or tool events, so only Text is
+// set on the result.
+func readPlainOutput(stdout io.Reader, display io.Writer, logFile io.Writer) (*StreamResult, error) {
+	out, err := io.ReadAll(stdout)
+	if len(out) > 0 {
+		display.Write(out)
+		if logFile != nil {
+			logFile.Write(out)
+		}
+	}
+	if err != nil {
+		return &StreamResult{Text: string(out)}, fmt.Errorf("reading claude output: %w", err)
+	}
+	return &StreamResult{Text: string(out)}, nil
+}
+
+// recordingPath returns the path of the raw stdout recording for a phase
+// (1-indexed, matching the logs/ naming).
+func recordingPath(recordDir string, idx int) string {
+	return filepath.Join(recordDir, fmt.Sprintf("phase-%d.jsonl", idx+1))
+}
+
+// openRecording opens the phase's recording file for append, so every turn
+// of a multi-turn phase lands in the same file in order.
+func openRecording(recordDir string, idx int) (*os.File, error) {
+	if err := os.MkdirAll(recordDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating record dir: %w", err)
+	}
+	f, err := os.OpenFile(recordingPath(recordDir, idx), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening recording: %w", err)
+	}
+	return f, nil
+}
+
+// replayAgentTurn feeds a recorded stream-json file through the stream parser
+// in place of a live claude process, so parser and display behavior can be
+// reproduced offline. A replayed turn always exits 0.
+func replayAgentTurn(ctx context.Context, phase config.Phase, path string, logFile io.Writer, rawLog io.Writer, events *EventLog, maxLineBytes int) (*turnResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening replay file: %w", err)
+	}
+	defer f.Close()
+
+	replayCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	monitor := newCostMonitor(phase.MaxCost, phase.Model)
+	streamResult, err := ProcessStreamWithMonitor(replayCtx, f, ux.StreamOutput(phase.Name), logFile, rawLog, events, monitor, cancel, maxLineBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &turnResult{Stream: streamResult, Overloaded: streamResult.Overloaded}, nil
 }
 
 // resumePrompt is the continuation prompt used when resuming an interrupted session.
@@ -169,12 +304,61 @@ func dispatchWithResume(
 // feedback from previous failures, and saves the rendered prompt to artifacts/prompts/.
 // Returns the fully rendered prompt string.
 func RenderAndSavePrompt(phase config.Phase, env *Environment) (string, error) {
-	promptData, err := os.ReadFile(filepath.Join(env.ProjectRoot, phase.Prompt))
+	rendered, err := renderPrompt(phase, env)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(state.PromptPath(env.ArtifactsDir, env.PhaseIndex), []byte(rendered), 0644); err != nil {
+		return "", fmt.Errorf("saving rendered prompt: %w", err)
+	}
+	// Keep a copy per attempt so each loop-back's prompt stays inspectable.
+	if env.Attempt > 0 {
+		if err := os.WriteFile(state.AttemptPromptPath(env.ArtifactsDir, env.PhaseIndex, env.Attempt), []byte(rendered), 0644); err != nil {
+			return "", fmt.Errorf("saving rendered prompt: %w", err)
+		}
+	}
+	return rendered, nil
+}
+
+// PromptTemplatePath returns the prompt template file for an agent phase: the
+// run's --prompt override for it if there is one, otherwise the configured
+// prompt relative to the project root, which must not escape it.
+func PromptTemplatePath(phase config.Phase, env *Environment) (string, error) {
+	if path, ok := env.PromptOverrides[phase.Name]; ok {
+		return path, nil
+	}
+	return config.PromptPath(env.ProjectRoot, phase.Prompt)
+}
+
+// renderPrompt reads the prompt template, expands variables, and injects
+// feedback from previous failures. With inject-all-feedback, feedback that
+// was already archived to the ticket's audit dir — because the phase that
+// caused it later passed — is prepended too.
+func renderPrompt(phase config.Phase, env *Environment) (string, error) {
+	promptPath, err := PromptTemplatePath(phase, env)
 	if err != nil {
-		return "", fmt.Errorf("reading prompt template %q: %w", filepath.Join(env.ProjectRoot, phase.Prompt), err)
+		return "", err
+	}
+	promptData, err := os.ReadFile(promptPath)
+	if err != nil {
+		return "", fmt.Errorf("reading prompt template %q: %w", promptPath, err)
 	}
 	rendered := ExpandVars(string(promptData), env.Vars())
 
+	if phase.InjectAllFeedback {
+		archived, err := state.ReadArchivedFeedback(state.AuditDirForWorkflow(env.ProjectRoot, env.Workflow, env.Ticket))
+		if err != nil {
+			return "", fmt.Errorf("reading archived feedback: %w", err)
+		}
+		if archived != "" {
+			rendered = "## Earlier feedback in this ticket\n\n" +
+				"Phases failed with the following feedback before they were retried. " +
+				"It is already addressed or superseded; use it as context.\n\n" +
+				archived + "\n\n---\n\n" + rendered
+		}
+	}
+
 	feedback, err := state.ReadAllFeedback(env.ArtifactsDir)
 	if err != nil {
 		return "", fmt.Errorf("reading feedback: %w", err)
@@ -185,19 +369,15 @@ func RenderAndSavePrompt(phase config.Phase, env *Environment) (string, error) {
 			"You MUST address the following feedback before proceeding with any other work.\n\n" +
 			feedback
 	}
-
-	if err := os.WriteFile(state.PromptPath(env.ArtifactsDir, env.PhaseIndex), []byte(rendered), 0644); err != nil {
-		return "", fmt.Errorf("saving rendered prompt: %w", err)
-	}
 	return rendered, nil
 }
 
 // RunAgent executes an agent phase in unattended mode (no stdin monitoring).
 // Uses stream-json parsing for real-time output.
 func RunAgent(ctx context.Context, phase config.Phase, env *Environment) (*Result, error) {
-	if phase.Timeout > 0 {
+	if timeout := PhaseTimeout(phase, env); timeout > 0 {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, time.Duration(phase.Timeout)*time.Minute)
+		ctx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 	}
 
@@ -232,7 +412,7 @@ func RunAgent(ctx context.Context, phase config.Phase, env *Environment) (*Resul
 	}
 	newSID := func() string { return uuid.New().String() }
 	warn := func(err error) {
-		fmt.Fprintf(os.Stderr, "  warning: resume failed (%v), falling back to fresh start\n", err)
+		ux.Warnf("  warning: resume failed (%v), falling back to fresh start\n", err)
 	}
 
 	tr, sessionID, _, err := dispatchWithResume(env.ResumeSessionID, renderPrompt, newSID, dispatch, warn)
@@ -240,19 +420,44 @@ func RunAgent(ctx context.Context, phase config.Phase, env *Environment) (*Resul
 		return nil, err
 	}
 
-	// In unattended mode, log permission denials but don't retry
+	// With on-denial: approve-and-retry, approve the denied tools and resume
+	// the session once — handleDenials without the prompt.
+	turns := 1
+	var earlier []*StreamResult
+	if phase.OnDenial == "approve-and-retry" && tr.Stream != nil && len(tr.Stream.PermissionDenials) > 0 && !tr.Stalled && ctx.Err() == nil {
+		approved := deniedTools(tr.Stream.PermissionDenials)
+		msg := fmt.Sprintf("permission denials: %s — approving and retrying once (on-denial: approve-and-retry)\n", strings.Join(approved, ", "))
+		fmt.Fprint(os.Stderr, "  "+msg)
+		logMsg(logFile, msg)
+		earlier = append(earlier, tr.Stream)
+		tr, err = runAgentTurn(ctx, phase, env, deniedToolsApprovedPrompt, sessionID, false, logFile, rawLog, approved)
+		if err != nil {
+			return nil, err
+		}
+		turns++
+	}
+
+	// Otherwise log permission denials, and fail the phase if asked to
+	deniedFail := false
 	if tr.Stream != nil && len(tr.Stream.PermissionDenials) > 0 {
 		var names []string
 		for _, d := range tr.Stream.PermissionDenials {
 			names = append(names, d.String())
 		}
-		fmt.Fprintf(os.Stderr, "  permission denials: %s — add these tools to 'allow-tools' in your phase config, or run without --auto to approve interactively\n", strings.Join(names, ", "))
+		if phase.OnDenial == "fail" {
+			deniedFail = true
+			msg := fmt.Sprintf("permission denials: %s — failing the phase (on-denial: fail)\n", strings.Join(names, ", "))
+			fmt.Fprint(os.Stderr, "  "+msg)
+			logMsg(logFile, msg)
+		} else {
+			fmt.Fprintf(os.Stderr, "  permission denials: %s — add these tools to 'allow-tools' in your phase config, or run without --auto to approve interactively\n", strings.Join(names, ", "))
+		}
 	}
 
 	// In unattended mode, log user questions as warnings
 	if tr.Stream != nil && len(tr.Stream.UserQuestions) > 0 {
 		for _, q := range tr.Stream.UserQuestions {
-			fmt.Fprintf(os.Stderr, "  warning: agent asked %q (unanswered in --auto mode)\n", q.Question)
+			ux.Warnf("  warning: agent asked %q (unanswered in --auto mode)\n", q.Question)
 		}
 	}
 
@@ -260,22 +465,35 @@ func RunAgent(ctx context.Context, phase config.Phase, env *Environment) (*Resul
 	if tr.Stream != nil {
 		output = tr.Stream.Text
 	}
-	res := &Result{ExitCode: tr.ExitCode, Output: output, Turns: 1, SessionID: sessionID}
+	res := &Result{ExitCode: tr.ExitCode, Output: output, Turns: turns, SessionID: sessionID, Stalled: tr.Stalled}
+	if deniedFail && res.ExitCode == 0 {
+		res.ExitCode = 1
+	}
 	if ctx.Err() == context.DeadlineExceeded {
 		res.TimedOut = true
 	}
 	if tr.Stream != nil {
-		res.CostUSD = tr.Stream.CostUSD
-		res.InputTokens = tr.Stream.InputTokens
-		res.OutputTokens = tr.Stream.OutputTokens
-		res.CacheCreationInputTokens = tr.Stream.CacheCreationInputTokens
-		res.CacheReadInputTokens = tr.Stream.CacheReadInputTokens
-		res.ToolsUsed = tr.Stream.ToolsUsed
-		var denied []string
-		for _, d := range tr.Stream.PermissionDenials {
-			denied = append(denied, d.Tool)
+		toolsSeen := make(map[string]bool)
+		deniedSeen := make(map[string]bool)
+		for _, sr := range append(earlier, tr.Stream) {
+			res.CostUSD += sr.CostUSD
+			res.InputTokens += sr.InputTokens
+			res.OutputTokens += sr.OutputTokens
+			res.CacheCreationInputTokens += sr.CacheCreationInputTokens
+			res.CacheReadInputTokens += sr.CacheReadInputTokens
+			for _, t := range sr.ToolsUsed {
+				if !toolsSeen[t] {
+					toolsSeen[t] = true
+					res.ToolsUsed = append(res.ToolsUsed, t)
+				}
+			}
+			for _, d := range sr.PermissionDenials {
+				if !deniedSeen[d.Tool] {
+					deniedSeen[d.Tool] = true
+					res.ToolsDenied = append(res.ToolsDenied, d.Tool)
+				}
+			}
 		}
-		res.ToolsDenied = denied
 		res.RateLimited = tr.Stream.RateLimited
 		res.RateLimitResetAt = tr.Stream.RateLimitResetAt
 		res.CostOverrun = tr.Stream.CostOverrun
@@ -283,12 +501,28 @@ func RunAgent(ctx context.Context, phase config.Phase, env *Environment) (*Resul
 	return res, nil
 }
 
+// deniedToolsApprovedPrompt resumes a session after its denied tools were approved.
+const deniedToolsApprovedPrompt = "Continue — the previously denied tools have now been approved."
+
+// deniedTools returns the distinct tool names in denials, in order.
+func deniedTools(denials []PermissionDenial) []string {
+	seen := make(map[string]bool)
+	var tools []string
+	for _, d := range denials {
+		if !seen[d.Tool] {
+			seen[d.Tool] = true
+			tools = append(tools, d.Tool)
+		}
+	}
+	return tools
+}
+
 // RunAgentWithPrompt invokes claude with an explicit prompt string (for output re-prompting).
 // If sessionID is non-empty, resumes that session so the agent retains prior context.
 func RunAgentWithPrompt(ctx context.Context, phase config.Phase, env *Environment, prompt, sessionID string) (*Result, error) {
-	if phase.Timeout > 0 {
+	if timeout := PhaseTimeout(phase, env); timeout > 0 {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, time.Duration(phase.Timeout)*time.Minute)
+		ctx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 	}
 
@@ -317,7 +551,7 @@ func RunAgentWithPrompt(ctx context.Context, phase config.Phase, env *Environmen
 	if tr.Stream != nil {
 		output = tr.Stream.Text
 	}
-	res := &Result{ExitCode: tr.ExitCode, Output: output, Turns: 1, SessionID: sessionID}
+	res := &Result{ExitCode: tr.ExitCode, Output: output, Turns: 1, SessionID: sessionID, Stalled: tr.Stalled}
 	if ctx.Err() == context.DeadlineExceeded {
 		res.TimedOut = true
 	}
@@ -345,9 +579,16 @@ func RunAgentWithPrompt(ctx context.Context, phase config.Phase, env *Environmen
 // the conversation with that input. Permission denials prompt the user
 // to approve the denied tools.
 func RunAgentAttended(ctx context.Context, phase config.Phase, env *Environment) (*Result, error) {
-	if phase.Timeout > 0 {
+	return runAgentAttended(ctx, phase, env, os.Stdin)
+}
+
+func runAgentAttended(ctx context.Context, phase config.Phase, env *Environment, stdin io.Reader) (*Result, error) {
+	// The timeout is a phaseDeadline rather than context.WithTimeout so the
+	// operator can push it back mid-turn with "extend <minutes>".
+	var deadline *phaseDeadline
+	if timeout := PhaseTimeout(phase, env); timeout > 0 {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, time.Duration(phase.Timeout)*time.Minute)
+		ctx, deadline, cancel = withPhaseDeadline(ctx, timeout)
 		defer cancel()
 	}
 
@@ -374,7 +615,20 @@ func RunAgentAttended(ctx context.Context, phase config.Phase, env *Environment)
 		}
 	}
 
+	// Every turn starts with a separator in the log so a steered session
+	// can be read back turn by turn.
+	logTurnNo := 0
+	logTurn := func(label string) {
+		logTurnNo++
+		fmt.Fprintf(logFile, "\n--- turn %d · %s · %s ---\n", logTurnNo, label, time.Now().UTC().Format(time.RFC3339))
+	}
+
 	dispatch := func(prompt, sid string, first bool) (*turnResult, error) {
+		if first {
+			logTurn("initial prompt: " + filepath.Base(state.PromptPath(env.ArtifactsDir, env.PhaseIndex)))
+		} else {
+			logTurn("resumed session: " + sid)
+		}
 		return runAgentTurn(ctx, phase, env, prompt, sid, first, logFile, rawLog, nil)
 	}
 	renderFresh := func() (string, error) {
@@ -382,20 +636,38 @@ func RunAgentAttended(ctx context.Context, phase config.Phase, env *Environment)
 	}
 	newSID := func() string { return uuid.New().String() }
 	warn := func(err error) {
-		fmt.Fprintf(os.Stderr, "  warning: resume failed (%v), falling back to fresh start\n", err)
+		ux.Warnf("  warning: resume failed (%v), falling back to fresh start\n", err)
 	}
 
-	// First turn: handles resume-or-fresh decision (no stdin reader needed yet)
+	// The reader starts before the first turn so "extend" works during it;
+	// any other input is buffered as steering for after the turn.
+	reader := newInterceptingStdinReader(stdin, func(line string) bool {
+		by, ok := parseExtendCommand(line)
+		if !ok {
+			return false
+		}
+		if deadline == nil {
+			fmt.Fprintf(os.Stderr, "  phase has no timeout to extend\n")
+			return true
+		}
+		if until, ok := deadline.Extend(by); ok {
+			msg := fmt.Sprintf("timeout extended by %s — phase now times out at %s\n", by, until.Format("15:04:05"))
+			fmt.Fprint(os.Stderr, "  "+msg)
+			logMsg(logFile, msg)
+		}
+		return true
+	})
+	defer reader.Stop()
+
+	// First turn: handles resume-or-fresh decision
 	firstTR, sessionID, _, err := dispatchWithResume(env.ResumeSessionID, renderFresh, newSID, dispatch, warn)
 	if err != nil {
 		return nil, err
 	}
 
-	reader := NewStdinReader(os.Stdin)
-	defer reader.Stop()
-
 	var extraTools []string
-	var prompt string // for subsequent turns — set by denial/question/steering handlers
+	var prompt string    // for subsequent turns — set by denial/question/steering handlers
+	var turnLabel string // log separator label for the next turn, set alongside prompt
 	var lastTurn *turnResult
 	var totalCost float64
 	var totalInput, totalOutput int
@@ -410,6 +682,7 @@ func RunAgentAttended(ctx context.Context, phase config.Phase, env *Environment)
 	for {
 		// Dispatch subsequent turns (first turn already handled by dispatchWithResume)
 		if turns > 0 {
+			logTurn(turnLabel)
 			var err error
 			tr, err = runAgentTurn(ctx, phase, env, prompt, sessionID, false, logFile, rawLog, extraTools)
 			if err != nil {
@@ -438,12 +711,18 @@ func RunAgentAttended(ctx context.Context, phase config.Phase, env *Environment)
 			}
 		}
 
+		// A stalled turn ends the phase; don't resume a hung session
+		if tr.Stalled {
+			break
+		}
+
 		// Handle permission denials
 		if tr.Stream != nil && len(tr.Stream.PermissionDenials) > 0 {
 			approved := handleDenials(tr.Stream.PermissionDenials, reader)
 			if len(approved) > 0 {
 				extraTools = append(extraTools, approved...)
-				prompt = "Continue — the previously denied tools have now been approved."
+				prompt = deniedToolsApprovedPrompt
+				turnLabel = "tools approved: " + strings.Join(approved, ", ")
 				continue
 			}
 		}
@@ -467,6 +746,7 @@ func RunAgentAttended(ctx context.Context, phase config.Phase, env *Environment)
 			}
 			if lastAnswer != "" {
 				prompt = lastAnswer
+				turnLabel = "user answer: " + lastAnswer
 				continue
 			}
 		}
@@ -474,7 +754,7 @@ func RunAgentAttended(ctx context.Context, phase config.Phase, env *Environment)
 		// Check for user steering input
 		if line, ok := reader.ReadLine(); ok {
 			prompt = line
-			fmt.Fprintf(logFile, "\n--- user steering: %s ---\n", line)
+			turnLabel = "user steering: " + line
 			continue
 		}
 
@@ -499,7 +779,8 @@ func RunAgentAttended(ctx context.Context, phase config.Phase, env *Environment)
 	return &Result{
 		ExitCode:                 exitCode,
 		Output:                   output,
-		TimedOut:                 ctx.Err() == context.DeadlineExceeded,
+		TimedOut:                 deadline.Expired(),
+		Stalled:                  lastTurn != nil && lastTurn.Stalled,
 		CostUSD:                  totalCost,
 		InputTokens:              totalInput,
 		OutputTokens:             totalOutput,
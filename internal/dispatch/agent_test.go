@@ -124,6 +124,137 @@ func TestRenderAndSavePrompt_NoFeedback(t *testing.T) {
 	}
 }
 
+func TestRenderAndSavePrompt_InjectAllFeedback(t *testing.T) {
+	dir := t.TempDir()
+	artDir := filepath.Join(dir, "artifacts")
+	if err := state.EnsureDir(artDir); err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Join(dir, ".orc", "phases"), 0755)
+	os.WriteFile(filepath.Join(dir, ".orc", "phases", "triage.md"), []byte("Triage $TICKET."), 0644)
+
+	// Feedback from phases that later passed lives in the audit dir; feedback
+	// from a phase that is still failing is live in the artifacts dir.
+	auditDir := state.AuditDirForWorkflow(dir, "", "TEST-7")
+	archived := map[string]string{
+		state.AuditFeedbackPath(auditDir, 9, 1, "lint"): "lint: unused import",
+		state.AuditFeedbackPath(auditDir, 1, 2, "test"): "test: TestParse failed",
+	}
+	for path, content := range archived {
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+	if err := state.WriteFeedback(artDir, "review", "review: missing docs"); err != nil {
+		t.Fatal(err)
+	}
+
+	env := &Environment{ProjectRoot: dir, WorkDir: dir, ArtifactsDir: artDir, Ticket: "TEST-7"}
+	phase := config.Phase{Name: "triage", Type: "agent", Prompt: ".orc/phases/triage.md", InjectAllFeedback: true}
+	rendered, err := RenderAndSavePrompt(phase, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test := strings.Index(rendered, "test: TestParse failed")
+	lint := strings.Index(rendered, "lint: unused import")
+	body := strings.Index(rendered, "Triage TEST-7.")
+	review := strings.Index(rendered, "review: missing docs")
+	if test < 0 || lint < 0 || body < 0 || review < 0 {
+		t.Fatalf("rendered prompt is missing feedback or the prompt body:\n%s", rendered)
+	}
+	if !(test < lint && lint < body && body < review) {
+		t.Errorf("want archived feedback (phase 2 before phase 10) prepended and live feedback appended:\n%s", rendered)
+	}
+
+	// Without the flag, archived feedback stays out of the prompt.
+	phase.InjectAllFeedback = false
+	rendered, err = RenderAndSavePrompt(phase, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(rendered, "TestParse") {
+		t.Errorf("archived feedback injected without inject-all-feedback:\n%s", rendered)
+	}
+}
+
+func TestRenderAndSavePrompt_WorkflowNameAndPhaseDescription(t *testing.T) {
+	dir := t.TempDir()
+	artDir := filepath.Join(dir, "artifacts")
+	if err := state.EnsureDir(artDir); err != nil {
+		t.Fatal(err)
+	}
+	promptDir := filepath.Join(dir, ".orc", "phases")
+	os.MkdirAll(promptDir, 0755)
+	os.WriteFile(filepath.Join(promptDir, "plan.md"), []byte("Goal: $WORKFLOW_NAME. Step: $PHASE_DESCRIPTION."), 0644)
+
+	env := &Environment{
+		ProjectRoot:      dir,
+		WorkDir:          "/work",
+		ArtifactsDir:     artDir,
+		Ticket:           "TEST-1",
+		WorkflowName:     "ship-feature",
+		PhaseDescription: "Write an implementation plan",
+	}
+	phase := config.Phase{
+		Name:        "plan",
+		Type:        "agent",
+		Description: "Write an implementation plan",
+		Prompt:      ".orc/phases/plan.md",
+	}
+
+	rendered, err := RenderAndSavePrompt(phase, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rendered != "Goal: ship-feature. Step: Write an implementation plan." {
+		t.Fatalf("unexpected rendered prompt: %q", rendered)
+	}
+}
+
+func TestRenderAndSavePrompt_PromptOverride(t *testing.T) {
+	dir := t.TempDir()
+	artDir := filepath.Join(dir, "artifacts")
+	if err := state.EnsureDir(artDir); err != nil {
+		t.Fatal(err)
+	}
+	promptDir := filepath.Join(dir, ".orc", "phases")
+	os.MkdirAll(promptDir, 0755)
+	os.WriteFile(filepath.Join(promptDir, "plan.md"), []byte("Original prompt for $TICKET."), 0644)
+	override := filepath.Join(t.TempDir(), "experiment.md")
+	os.WriteFile(override, []byte("Experimental prompt for $TICKET."), 0644)
+
+	env := &Environment{
+		ProjectRoot:     dir,
+		WorkDir:         "/work",
+		ArtifactsDir:    artDir,
+		Ticket:          "TEST-1",
+		PhaseIndex:      2,
+		PromptOverrides: map[string]string{"plan": override},
+	}
+	phase := config.Phase{Name: "plan", Type: "agent", Prompt: ".orc/phases/plan.md"}
+
+	if _, err := RenderAndSavePrompt(phase, env); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := os.ReadFile(state.PromptPath(artDir, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(saved) != "Experimental prompt for TEST-1." {
+		t.Fatalf("saved prompt = %q, want the override's content", saved)
+	}
+
+	// Other phases keep their configured prompt.
+	other := config.Phase{Name: "review", Type: "agent", Prompt: ".orc/phases/plan.md"}
+	rendered, err := RenderAndSavePrompt(other, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rendered != "Original prompt for TEST-1." {
+		t.Fatalf("non-overridden phase rendered %q", rendered)
+	}
+}
+
 func TestRenderAndSavePrompt_MissingFile_ErrorContext(t *testing.T) {
 	dir := t.TempDir()
 	artDir := filepath.Join(dir, "artifacts")
@@ -452,6 +583,241 @@ func TestRunAgent_ResumeSucceeds_Integration(t *testing.T) {
 	}
 }
 
+// setupFakeClaudeOverloaded installs a fake claude that reports an API
+// overload for opus and succeeds for any other model.
+func setupFakeClaudeOverloaded(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	binDir := filepath.Join(dir, "bin")
+	os.MkdirAll(binDir, 0755)
+	script := `#!/bin/bash
+model=""
+prev=""
+for arg in "$@"; do
+  if [ "$prev" = "--model" ]; then model="$arg"; fi
+  prev="$arg"
+done
+echo "$model" >> "` + filepath.Join(dir, "models.txt") + `"
+if [ "$model" = "opus" ]; then
+  echo '{"type":"result","is_error":true,"result":"API Error: 529 {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}","session_id":"s"}'
+  exit 1
+fi
+echo '{"type":"result","result":"done","total_cost_usd":0.01,"session_id":"s","usage":{"input_tokens":10,"output_tokens":5}}'
+exit 0
+`
+	os.WriteFile(filepath.Join(binDir, "claude"), []byte(script), 0755)
+	t.Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+	return dir
+}
+
+func TestRunAgent_FallbackModelOnOverload(t *testing.T) {
+	dir := setupFakeClaudeOverloaded(t)
+	phase, env := makeIntegrationEnv(t, dir, "")
+	phase.Model = "opus"
+	phase.FallbackModel = "sonnet"
+
+	result, err := RunAgent(context.Background(), phase, env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ExitCode != 0 {
+		t.Fatalf("ExitCode = %d, want 0 after fallback", result.ExitCode)
+	}
+	models, _ := os.ReadFile(filepath.Join(dir, "models.txt"))
+	if string(models) != "opus\nsonnet\n" {
+		t.Fatalf("models invoked = %q, want opus then sonnet", models)
+	}
+	logData, _ := os.ReadFile(state.LogPath(env.ArtifactsDir, env.PhaseIndex))
+	if !strings.Contains(string(logData), "retrying the turn with fallback model sonnet") {
+		t.Errorf("expected fallback note in log, got:\n%s", logData)
+	}
+}
+
+// setupFakeClaudeDenying installs a fake claude that reports a Bash
+// permission denial unless Bash is among its --allowedTools, and appends each
+// invocation's arguments to args.txt.
+func setupFakeClaudeDenying(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	binDir := filepath.Join(dir, "bin")
+	os.MkdirAll(binDir, 0755)
+	script := `#!/bin/bash
+echo "$*" >> "` + filepath.Join(dir, "args.txt") + `"
+for arg in "$@"; do
+  if [ "$arg" = "Bash" ]; then
+    echo '{"type":"result","result":"done","total_cost_usd":0.02,"session_id":"s","usage":{"input_tokens":10,"output_tokens":5},"permission_denials":[]}'
+    exit 0
+  fi
+done
+echo '{"type":"result","result":"blocked","total_cost_usd":0.01,"session_id":"s","usage":{"input_tokens":10,"output_tokens":5},"permission_denials":[{"tool_name":"Bash","input":"make test"}]}'
+exit 0
+`
+	os.WriteFile(filepath.Join(binDir, "claude"), []byte(script), 0755)
+	t.Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+	return dir
+}
+
+func TestRunAgent_OnDenialApproveAndRetry(t *testing.T) {
+	dir := setupFakeClaudeDenying(t)
+	phase, env := makeIntegrationEnv(t, dir, "")
+	phase.OnDenial = "approve-and-retry"
+
+	result, err := RunAgent(context.Background(), phase, env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "args.txt"))
+	calls := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 claude invocations, got %d:\n%s", len(calls), data)
+	}
+	if !strings.Contains(calls[1], "--resume") || !strings.Contains(calls[1], " Bash") {
+		t.Errorf("retry should resume the session with Bash approved, got: %s", calls[1])
+	}
+	if result.ExitCode != 0 || result.Turns != 2 {
+		t.Errorf("result = exit %d, turns %d; want 0, 2", result.ExitCode, result.Turns)
+	}
+	if result.CostUSD < 0.029 {
+		t.Errorf("CostUSD = %f, want both turns counted", result.CostUSD)
+	}
+	if len(result.ToolsDenied) != 1 || result.ToolsDenied[0] != "Bash" {
+		t.Errorf("ToolsDenied = %v, want [Bash]", result.ToolsDenied)
+	}
+}
+
+func TestRunAgent_OnDenialFail(t *testing.T) {
+	dir := setupFakeClaudeDenying(t)
+	phase, env := makeIntegrationEnv(t, dir, "")
+	phase.OnDenial = "fail"
+
+	result, err := RunAgent(context.Background(), phase, env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ExitCode == 0 {
+		t.Error("expected a denied turn to fail the phase with on-denial: fail")
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "args.txt"))
+	if n := strings.Count(string(data), "\n"); n != 1 {
+		t.Errorf("expected no retry, got %d invocations", n)
+	}
+}
+
+func TestRunAgent_OverloadWithoutFallbackFails(t *testing.T) {
+	dir := setupFakeClaudeOverloaded(t)
+	phase, env := makeIntegrationEnv(t, dir, "")
+	phase.Model = "opus"
+
+	result, err := RunAgent(context.Background(), phase, env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ExitCode == 0 {
+		t.Fatal("expected the overloaded turn to fail without a fallback-model")
+	}
+	models, _ := os.ReadFile(filepath.Join(dir, "models.txt"))
+	if string(models) != "opus\n" {
+		t.Fatalf("models invoked = %q, want a single opus turn", models)
+	}
+}
+
+func TestRunAgent_Replay(t *testing.T) {
+	dir := t.TempDir()
+	// No claude on PATH — a replayed turn must never spawn the process.
+	t.Setenv("PATH", t.TempDir())
+	phase, env := makeIntegrationEnv(t, dir, "")
+
+	replay := filepath.Join(dir, "recorded.jsonl")
+	lines := strings.Join([]string{
+		`{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"Replayed"}}}`,
+		`{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":" output"}}}`,
+		`{"type":"result","total_cost_usd":0.03,"session_id":"rec-sess","usage":{"input_tokens":10,"output_tokens":5},"permission_denials":[]}`,
+	}, "\n") + "\n"
+	os.WriteFile(replay, []byte(lines), 0644)
+	env.ReplayFile = replay
+
+	result, err := RunAgent(context.Background(), phase, env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Output != "Replayed output" {
+		t.Errorf("Output = %q, want %q", result.Output, "Replayed output")
+	}
+	if result.ExitCode != 0 {
+		t.Errorf("ExitCode = %d, want 0", result.ExitCode)
+	}
+	if result.CostUSD != 0.03 {
+		t.Errorf("CostUSD = %f, want 0.03", result.CostUSD)
+	}
+}
+
+func TestRunAgentAttended_DelimitsTurnsInLog(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PATH", t.TempDir())
+	phase, env := makeIntegrationEnv(t, dir, "")
+	env.AutoMode = false
+
+	// Every replayed turn reports a Bash denial. The operator approves it
+	// once, so the session runs two turns: the initial one and the retry.
+	replay := filepath.Join(dir, "recorded.jsonl")
+	os.WriteFile(replay, []byte(`{"type":"result","total_cost_usd":0.01,"session_id":"s","usage":{"input_tokens":1,"output_tokens":1},"permission_denials":[{"tool_name":"Bash","input":"make"}]}`+"\n"), 0644)
+	env.ReplayFile = replay
+
+	captureGateStdout(t, func() {
+		result, err := runAgentAttended(context.Background(), phase, env, strings.NewReader("y\n"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Turns != 2 {
+			t.Fatalf("Turns = %d, want 2", result.Turns)
+		}
+	})
+
+	data, err := os.ReadFile(state.LogPath(env.ArtifactsDir, env.PhaseIndex))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := string(data)
+	first := strings.Index(log, "--- turn 1 · initial prompt: phase-1.md · ")
+	second := strings.Index(log, "--- turn 2 · tools approved: Bash · ")
+	if first < 0 || second < 0 || second < first {
+		t.Fatalf("expected turn 1 and turn 2 separators in order, got:\n%s", log)
+	}
+	if strings.Contains(log, "--- turn 3") {
+		t.Fatalf("unexpected third turn:\n%s", log)
+	}
+}
+
+func TestRunAgent_ReplayMissingFile(t *testing.T) {
+	dir := t.TempDir()
+	phase, env := makeIntegrationEnv(t, dir, "")
+	env.ReplayFile = filepath.Join(dir, "nope.jsonl")
+
+	if _, err := RunAgent(context.Background(), phase, env); err == nil {
+		t.Fatal("expected error for missing replay file")
+	}
+}
+
+func TestRunAgent_RecordsRawStream(t *testing.T) {
+	dir := setupFakeClaudeForResume(t, true)
+	phase, env := makeIntegrationEnv(t, dir, "")
+	env.PhaseIndex = 2
+	env.RecordDir = filepath.Join(dir, "recordings")
+
+	if _, err := RunAgent(context.Background(), phase, env); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(env.RecordDir, "phase-3.jsonl"))
+	if err != nil {
+		t.Fatalf("reading recording: %v", err)
+	}
+	want := `{"type":"result","total_cost_usd":0.02,"session_id":"kept-sess","usage":{"input_tokens":200,"output_tokens":100}}` + "\n"
+	if string(got) != want {
+		t.Errorf("recording = %q, want %q", got, want)
+	}
+}
+
 func TestRunAgentWithPrompt_SetsSessionID(t *testing.T) {
 	dir := setupFakeClaudeForResume(t, true)
 	phase, env := makeIntegrationEnv(t, dir, "")
@@ -0,0 +1,101 @@
+package dispatch
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jorge-barreto/orc/internal/config"
+)
+
+// containerArgs returns the runtime arguments that run command inside c as
+// the container name. The project root, the phase's working directory, and
+// the artifacts directory are each mounted at their host paths, and the
+// working directory is used as the container's, so $PROJECT_ROOT,
+// $WORK_DIR, $ARTIFACTS_DIR and declared outputs resolve unchanged. Each of
+// envKeys is passed through by name (-e KEY), so values such as secret-env
+// never appear on the command line.
+func containerArgs(c *config.Container, name, projectRoot, workDir, artifactsDir, command string, envKeys []string, interactive bool, vars map[string]string) []string {
+	args := []string{"run", "--rm", "--name", name}
+	if interactive {
+		args = append(args, "-i")
+	}
+	for _, dir := range containerMounts(projectRoot, workDir, artifactsDir) {
+		args = append(args, "-v", dir+":"+dir)
+	}
+	args = append(args, "-w", workDir)
+	for _, v := range c.Volumes {
+		args = append(args, "-v", ExpandVars(v, vars))
+	}
+	for _, k := range envKeys {
+		args = append(args, "-e", k)
+	}
+	return append(args, c.Image, "bash", "-c", command)
+}
+
+// containerMounts returns the host directories a container phase needs,
+// dropping any that another mount already contains.
+func containerMounts(dirs ...string) []string {
+	var mounts []string
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		covered := false
+		for _, other := range dirs {
+			if rel, err := filepath.Rel(other, dir); other != "" && other != dir && err == nil && !strings.HasPrefix(rel, "..") {
+				covered = true
+				break
+			}
+		}
+		if !covered && !slices.Contains(mounts, dir) {
+			mounts = append(mounts, dir)
+		}
+	}
+	return mounts
+}
+
+// containerName returns a unique name for a container phase's container,
+// so a cancelled phase can kill it by name.
+func containerName() string {
+	b := make([]byte, 6)
+	rand.Read(b) //nolint:errcheck // crypto/rand does not fail on supported platforms
+	return "orc-" + hex.EncodeToString(b)
+}
+
+// killContainer kills the named container. Signalling the runtime client
+// alone can leave the container running after orc gives up on the phase.
+// Errors are ignored: the container may already have exited.
+func killContainer(runtime, name string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	exec.CommandContext(ctx, runtime, "kill", name).Run() //nolint:errcheck
+}
+
+// containerEnvKeys returns the names of the variables orc itself sets in
+// childEnv (from BuildEnv): ORC_* variables, their unprefixed aliases and
+// custom vars, and the phase's secret-env. The rest of orc's environment
+// stays on the host.
+func containerEnvKeys(childEnv []string, env *Environment) []string {
+	present := make(map[string]bool, len(childEnv))
+	for _, e := range childEnv {
+		present[strings.SplitN(e, "=", 2)[0]] = true
+	}
+	seen := make(map[string]bool)
+	var keys []string
+	for _, e := range childEnv {
+		key := strings.SplitN(e, "=", 2)[0]
+		_, secret := env.SecretEnv[key]
+		if seen[key] || !(strings.HasPrefix(key, "ORC_") || present["ORC_"+key] || secret) {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,127 @@
+package dispatch
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jorge-barreto/orc/internal/config"
+)
+
+func TestContainerArgs(t *testing.T) {
+	c := &config.Container{Image: "golang:1.22", Volumes: []string{"$CACHE:/root/.cache", "/etc/ssl:/etc/ssl:ro"}}
+	got := containerArgs(c, "orc-1", "/proj", "/proj/svc", "/proj/.orc/artifacts/T-1", "make test", []string{"ORC_TICKET", "TICKET"}, true,
+		map[string]string{"CACHE": "/tmp/cache"})
+	want := []string{
+		"run", "--rm", "--name", "orc-1", "-i",
+		"-v", "/proj:/proj", "-w", "/proj/svc",
+		"-v", "/tmp/cache:/root/.cache",
+		"-v", "/etc/ssl:/etc/ssl:ro",
+		"-e", "ORC_TICKET", "-e", "TICKET",
+		"golang:1.22", "bash", "-c", "make test",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("containerArgs =\n  %v\nwant\n  %v", got, want)
+	}
+}
+
+func TestContainerMounts(t *testing.T) {
+	got := containerMounts("/proj", "/work", "/proj/.orc/artifacts/T-1")
+	if want := "/proj /work"; strings.Join(got, " ") != want {
+		t.Errorf("mounts = %v, want %s", got, want)
+	}
+	got = containerMounts("/proj", "/proj", "/art")
+	if want := "/proj /art"; strings.Join(got, " ") != want {
+		t.Errorf("mounts = %v, want %s", got, want)
+	}
+}
+
+func TestContainerEnvKeys(t *testing.T) {
+	t.Setenv("HOME_TOKEN", "s3cret")
+	env := &Environment{Ticket: "T-1", CustomVars: map[string]string{"REGION": "eu"}, SecretEnv: map[string]string{"TOKEN": "HOME_TOKEN"}}
+	keys := containerEnvKeys(BuildEnv(env), env)
+	for _, want := range []string{"ORC_TICKET", "TICKET", "ORC_REGION", "REGION", "TOKEN"} {
+		if !contains(keys, want) {
+			t.Errorf("missing %s in %v", want, keys)
+		}
+	}
+	for _, unwanted := range []string{"PATH", "HOME", "HOME_TOKEN"} {
+		if contains(keys, unwanted) {
+			t.Errorf("host variable %s should not be passed into the container: %v", unwanted, keys)
+		}
+	}
+}
+
+func TestRunScript_ContainerUsesRuntime(t *testing.T) {
+	binDir := t.TempDir()
+	argsFile := filepath.Join(binDir, "args.txt")
+	fake := "#!/bin/bash\necho \"$*\" > " + argsFile + "\necho \"ticket=$ORC_TICKET\"\n"
+	os.WriteFile(filepath.Join(binDir, "podman"), []byte(fake), 0755)
+	t.Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+
+	env := scriptEnv(t)
+	phase := config.Phase{Name: "test", Type: "script", Run: "go test ./...",
+		Container: &config.Container{Image: "golang:1.22", Runtime: "podman"}}
+	result, err := RunScript(context.Background(), phase, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExitCode != 0 || !strings.Contains(result.Output, "ticket=TEST-1") {
+		t.Fatalf("exit %d, output %q", result.ExitCode, result.Output)
+	}
+	args, _ := os.ReadFile(argsFile)
+	for _, want := range []string{"run --rm --name orc-", "-v " + env.WorkDir + ":" + env.WorkDir, "-w " + env.WorkDir, "-e ORC_TICKET", "golang:1.22 bash -c go test ./..."} {
+		if !strings.Contains(string(args), want) {
+			t.Errorf("runtime args missing %q: %s", want, args)
+		}
+	}
+}
+
+func TestRunScript_ContainerKilledOnCancel(t *testing.T) {
+	binDir := t.TempDir()
+	killed := filepath.Join(binDir, "killed.txt")
+	fake := "#!/bin/bash\nif [ \"$1\" = kill ]; then echo \"$2\" > " + killed + "; exit 0; fi\nexec sleep 30\n"
+	os.WriteFile(filepath.Join(binDir, "docker"), []byte(fake), 0755)
+	t.Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+
+	env := scriptEnv(t)
+	phase := config.Phase{Name: "test", Type: "script", Run: "make", Container: &config.Container{Image: "golang:1.22"}}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := RunScript(ctx, phase, env); err != nil {
+		t.Fatal(err)
+	}
+	name, err := os.ReadFile(killed)
+	if err != nil || !strings.HasPrefix(string(name), "orc-") {
+		t.Fatalf("container not killed by name on cancel: %q, %v", name, err)
+	}
+}
+
+func TestRunScript_Container_Docker(t *testing.T) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skip("docker not on PATH")
+	}
+	if err := exec.Command("docker", "info").Run(); err != nil {
+		t.Skip("docker daemon not available")
+	}
+	env := scriptEnv(t)
+	os.WriteFile(filepath.Join(env.WorkDir, "in.txt"), []byte("from host"), 0644)
+	phase := config.Phase{Name: "test", Type: "script",
+		Run:       `cat in.txt; echo "$ORC_TICKET" > "$ARTIFACTS_DIR/out.txt"`,
+		Container: &config.Container{Image: "bash:5"}}
+	result, err := RunScript(context.Background(), phase, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExitCode != 0 || !strings.Contains(result.Output, "from host") {
+		t.Fatalf("exit %d, output %q", result.ExitCode, result.Output)
+	}
+	out, err := os.ReadFile(filepath.Join(env.ArtifactsDir, "out.txt"))
+	if err != nil || strings.TrimSpace(string(out)) != "TEST-1" {
+		t.Errorf("artifacts dir not shared with the container: %q, %v", out, err)
+	}
+}
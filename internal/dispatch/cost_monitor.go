@@ -47,6 +47,18 @@ func lookupRates(model string) modelRates {
 	}
 }
 
+// charsPerToken is the rough characters-per-token ratio used to size prompts
+// before they are sent.
+const charsPerToken = 4
+
+// EstimatePromptCost estimates the input tokens in prompt (~4 characters per
+// token) and what sending them to model costs. Output tokens, tool results,
+// and caching are not included, so real phase costs run higher.
+func EstimatePromptCost(model, prompt string) (tokens int, costUSD float64) {
+	tokens = (len(prompt) + charsPerToken - 1) / charsPerToken
+	return tokens, float64(tokens) * lookupRates(model).inputPerToken
+}
+
 // costMonitor tracks running cost estimate during a claude stream and
 // reports when the cap is exceeded. Zero value is a disabled monitor
 // (maxCost <= 0 means no enforcement).
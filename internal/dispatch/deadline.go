@@ -0,0 +1,67 @@
+package dispatch
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// phaseDeadline is a phase timeout that can be pushed back while the phase
+// runs, which context.WithTimeout can't do. The derived context is
+// cancelled with context.DeadlineExceeded as its cause when time runs out.
+type phaseDeadline struct {
+	mu       sync.Mutex
+	timer    *time.Timer
+	deadline time.Time
+	expired  atomic.Bool
+}
+
+// withPhaseDeadline derives a context from ctx that is cancelled after
+// timeout unless the deadline is extended first.
+func withPhaseDeadline(ctx context.Context, timeout time.Duration) (context.Context, *phaseDeadline, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	d := &phaseDeadline{deadline: time.Now().Add(timeout)}
+	d.timer = time.AfterFunc(timeout, func() {
+		d.expired.Store(true)
+		cancel(context.DeadlineExceeded)
+	})
+	return ctx, d, func() {
+		d.timer.Stop()
+		cancel(context.Canceled)
+	}
+}
+
+// Extend pushes the deadline back by by and returns the new deadline. It
+// returns false if the deadline has already passed.
+func (d *phaseDeadline) Extend(by time.Duration) (time.Time, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.expired.Load() || !d.timer.Stop() {
+		return d.deadline, false
+	}
+	d.deadline = d.deadline.Add(by)
+	d.timer.Reset(time.Until(d.deadline))
+	return d.deadline, true
+}
+
+// Expired reports whether the phase ran out of time.
+func (d *phaseDeadline) Expired() bool {
+	return d != nil && d.expired.Load()
+}
+
+// parseExtendCommand recognizes the attended-mode steering command
+// "extend <minutes>" and returns the requested extension.
+func parseExtendCommand(line string) (time.Duration, bool) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 || fields[0] != "extend" {
+		return 0, false
+	}
+	mins, err := strconv.Atoi(fields[1])
+	if err != nil || mins <= 0 {
+		return 0, false
+	}
+	return time.Duration(mins) * time.Minute, true
+}
@@ -0,0 +1,117 @@
+package dispatch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jorge-barreto/orc/internal/state"
+)
+
+func TestParseExtendCommand(t *testing.T) {
+	tests := []struct {
+		line string
+		want time.Duration
+		ok   bool
+	}{
+		{"extend 10", 10 * time.Minute, true},
+		{"  extend   5 ", 5 * time.Minute, true},
+		{"extend", 0, false},
+		{"extend 0", 0, false},
+		{"extend ten", 0, false},
+		{"please extend 5", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseExtendCommand(tt.line)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseExtendCommand(%q) = %v, %v; want %v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPhaseDeadline_Extend(t *testing.T) {
+	ctx, d, cancel := withPhaseDeadline(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, ok := d.Extend(time.Hour); !ok {
+		t.Fatal("Extend before the deadline should succeed")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if ctx.Err() != nil || d.Expired() {
+		t.Fatal("extended deadline fired at the original time")
+	}
+
+	ctx, d, cancel = withPhaseDeadline(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	if !d.Expired() || context.Cause(ctx) != context.DeadlineExceeded {
+		t.Fatalf("Expired = %v, cause = %v", d.Expired(), context.Cause(ctx))
+	}
+	if _, ok := d.Extend(time.Hour); ok {
+		t.Fatal("Extend after expiry should fail")
+	}
+}
+
+func TestRunAgentAttended_ExtendProlongsTurn(t *testing.T) {
+	dir := t.TempDir()
+	binDir := filepath.Join(dir, "bin")
+	os.MkdirAll(binDir, 0755)
+	// A turn that takes longer than the phase's original timeout.
+	os.WriteFile(filepath.Join(binDir, "claude"), []byte(`#!/bin/bash
+sleep 1
+echo '{"type":"result","total_cost_usd":0.01,"session_id":"s","usage":{"input_tokens":1,"output_tokens":1}}'
+`), 0755)
+	t.Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+
+	phase, env := makeIntegrationEnv(t, dir, "")
+	env.AutoMode = false
+	env.AgentTimeout = 300 * time.Millisecond
+
+	var result *Result
+	captureGateStdout(t, func() {
+		var err error
+		result, err = runAgentAttended(context.Background(), phase, env, strings.NewReader("extend 1\n"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if result.TimedOut {
+		t.Fatal("turn timed out despite extend")
+	}
+	if result.ExitCode != 0 || result.Turns != 1 {
+		t.Fatalf("ExitCode = %d, Turns = %d; want 0, 1 (extend must not be sent as steering)", result.ExitCode, result.Turns)
+	}
+	data, _ := os.ReadFile(state.LogPath(env.ArtifactsDir, env.PhaseIndex))
+	if !strings.Contains(string(data), "timeout extended by 1m0s") {
+		t.Fatalf("log missing extension note:\n%s", data)
+	}
+}
+
+func TestRunAgentAttended_TimesOutWithoutExtend(t *testing.T) {
+	dir := t.TempDir()
+	binDir := filepath.Join(dir, "bin")
+	os.MkdirAll(binDir, 0755)
+	os.WriteFile(filepath.Join(binDir, "claude"), []byte(`#!/bin/bash
+sleep 1
+echo '{"type":"result","total_cost_usd":0.01,"session_id":"s","usage":{"input_tokens":1,"output_tokens":1}}'
+`), 0755)
+	t.Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+
+	phase, env := makeIntegrationEnv(t, dir, "")
+	env.AutoMode = false
+	env.AgentTimeout = 300 * time.Millisecond
+
+	var result *Result
+	captureGateStdout(t, func() {
+		var err error
+		result, err = runAgentAttended(context.Background(), phase, env, strings.NewReader(""))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !result.TimedOut {
+		t.Fatal("expected the turn to time out")
+	}
+}
@@ -4,28 +4,49 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/jorge-barreto/orc/internal/config"
 )
 
 // Environment holds the execution context for phase dispatch.
 type Environment struct {
-	ProjectRoot       string
-	WorkDir           string
-	ArtifactsDir      string
-	Ticket            string
-	Workflow          string
-	PhaseIndex        int
-	AutoMode          bool
-	Verbose           bool
-	ResumeSessionID   string // session ID from interrupted phase for --resume
-	PhaseCount        int
-	DefaultAllowTools []string
-	CustomVars        map[string]string
+	ProjectRoot        string
+	WorkDir            string
+	ArtifactsDir       string
+	Ticket             string
+	Workflow           string
+	WorkflowName       string // the config's name: field, as $WORKFLOW_NAME
+	PhaseIndex         int
+	PhaseDescription   string // the current phase's description, as $PHASE_DESCRIPTION
+	PhaseOutputDir     string // where the current phase's outputs go, as $PHASE_OUTPUT_DIR; "" means ArtifactsDir
+	Attempt            int    // 1-indexed dispatch attempt of the current phase; 0 if not tracked
+	AutoMode           bool
+	AutoApproveGates   bool // an operator answered "ya" at an earlier gate; approve the rest
+	Verbose            bool
+	ResumeSessionID    string        // session ID from interrupted phase for --resume
+	ReplayFile         string        // recorded stream-json fed to agent phases instead of claude (--replay)
+	RecordDir          string        // directory receiving raw claude stdout per phase (--record)
+	LogFormat          string        // "json" also writes normalized stream events per phase (--log-format)
+	MaxStreamLineBytes int           // longest stream-json line kept; 0 means DefaultMaxStreamLineBytes
+	NoStream           bool          // invoke claude with --output-format text instead of stream-json (--no-stream)
+	Events             *EventSink    // live event stream (--events-fd/--events-pipe); nil when unset
+	AgentTimeout       time.Duration // replaces agent phases' timeout (--agent-timeout); 0 keeps config
+	ScriptTimeout      time.Duration // replaces script phases' timeout (--script-timeout); 0 keeps config
+	PhaseCount         int
+	DefaultAllowTools  []string
+	CustomVars         map[string]string
+	UnexportedVars     map[string]bool   // custom vars kept out of child environments (export-vars); still expanded in templates
+	PromptOverrides    map[string]string // phase name → absolute prompt file used instead of the config's (--prompt)
+	SecretEnv          map[string]string // the current phase's secret-env: child var → parent var, resolved in BuildEnv only
+	Deliverables       []string          // publish phases only: the config's deliverables relative to ArtifactsDir; those present are passed as $DELIVERABLES
 }
 
-// Clone returns a deep copy of the Environment, including CustomVars.
+// Clone returns a deep copy of the Environment, including CustomVars,
+// UnexportedVars, and PromptOverrides.
 func (e *Environment) Clone() *Environment {
 	cp := *e
 	if e.DefaultAllowTools != nil {
@@ -38,13 +59,25 @@ func (e *Environment) Clone() *Environment {
 			cp.CustomVars[k] = v
 		}
 	}
+	if e.UnexportedVars != nil {
+		cp.UnexportedVars = make(map[string]bool, len(e.UnexportedVars))
+		for k, v := range e.UnexportedVars {
+			cp.UnexportedVars[k] = v
+		}
+	}
+	if e.PromptOverrides != nil {
+		cp.PromptOverrides = make(map[string]string, len(e.PromptOverrides))
+		for k, v := range e.PromptOverrides {
+			cp.PromptOverrides[k] = v
+		}
+	}
 	return &cp
 }
 
 // Vars returns the variable substitution map for prompts and commands.
 // Custom vars are included first; built-ins always win (defense in depth).
 func (e *Environment) Vars() map[string]string {
-	m := make(map[string]string, 5+len(e.CustomVars))
+	m := make(map[string]string, 8+len(e.CustomVars))
 	for k, v := range e.CustomVars {
 		m[k] = v
 	}
@@ -53,9 +86,33 @@ func (e *Environment) Vars() map[string]string {
 	m["ARTIFACTS_DIR"] = e.ArtifactsDir
 	m["WORK_DIR"] = e.WorkDir
 	m["PROJECT_ROOT"] = e.ProjectRoot
+	m["WORKFLOW_NAME"] = e.WorkflowName
+	m["PHASE_DESCRIPTION"] = e.PhaseDescription
+	m["PHASE_OUTPUT_DIR"] = e.outputDir()
 	return m
 }
 
+// outputDir returns PhaseOutputDir, falling back to the artifacts directory.
+func (e *Environment) outputDir() string {
+	if e.PhaseOutputDir == "" {
+		return e.ArtifactsDir
+	}
+	return e.PhaseOutputDir
+}
+
+// deliverables returns the absolute paths of the deliverables that exist in
+// the artifacts directory, one per line, for $DELIVERABLES.
+func (e *Environment) deliverables() string {
+	var paths []string
+	for _, d := range e.Deliverables {
+		path := filepath.Join(e.ArtifactsDir, d)
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	return strings.Join(paths, "\n")
+}
+
 // DryRunVars returns the variable substitution map for dry-run display expansion.
 // Includes both unprefixed (ARTIFACTS_DIR) and ORC_-prefixed (ORC_ARTIFACTS_DIR)
 // keys, matching what BuildEnv provides to child processes at runtime.
@@ -70,6 +127,9 @@ func (e *Environment) DryRunVars() map[string]string {
 	m["ORC_ARTIFACTS_DIR"] = e.ArtifactsDir
 	m["ORC_WORK_DIR"] = e.WorkDir
 	m["ORC_PROJECT_ROOT"] = e.ProjectRoot
+	m["ORC_WORKFLOW_NAME"] = e.WorkflowName
+	m["ORC_PHASE_DESCRIPTION"] = e.PhaseDescription
+	m["ORC_PHASE_OUTPUT_DIR"] = e.outputDir()
 	return m
 }
 
@@ -87,11 +147,25 @@ func PhaseWorkDir(phase config.Phase, env *Environment) string {
 	return env.WorkDir
 }
 
+// PhaseTimeout returns the effective timeout for a phase: the run's
+// --agent-timeout or --script-timeout override when one is set for the
+// phase's type, otherwise the configured timeout in minutes. 0 means none.
+func PhaseTimeout(phase config.Phase, env *Environment) time.Duration {
+	switch {
+	case phase.Type == "agent" && env.AgentTimeout > 0:
+		return env.AgentTimeout
+	case phase.Type == "script" && env.ScriptTimeout > 0:
+		return env.ScriptTimeout
+	}
+	return time.Duration(phase.Timeout) * time.Minute
+}
+
 // Result holds the outcome of a phase dispatch.
 type Result struct {
 	ExitCode                 int
 	Output                   string
 	TimedOut                 bool // true if killed by orc's phase timeout
+	Stalled                  bool // true if killed by the inactivity-timeout watchdog
 	CostOverrun              bool // true if killed mid-stream by in-flight cost monitor
 	CostUSD                  float64
 	InputTokens              int
@@ -104,6 +178,8 @@ type Result struct {
 	ToolsDenied              []string
 	RateLimited              bool
 	RateLimitResetAt         int64 // Unix timestamp — when the rate limit resets
+	ApproveAllGates          bool  // gate approved with "ya": auto-approve every later gate
+	Rejected                 bool  // gate rejected by the operator; Output holds their feedback
 }
 
 // BuildEnv returns the environment variables for child processes.
@@ -114,11 +190,16 @@ func BuildEnv(env *Environment) []string {
 	overridden := map[string]bool{
 		"TICKET": true, "ARTIFACTS_DIR": true,
 		"WORK_DIR": true, "PROJECT_ROOT": true,
-		"WORKFLOW": true,
+		"WORKFLOW": true, "WORKFLOW_NAME": true,
+		"PHASE_DESCRIPTION": true, "PHASE_OUTPUT_DIR": true,
+		"DELIVERABLES": true,
 	}
 	for k := range env.CustomVars {
 		overridden[k] = true
 	}
+	for k := range env.SecretEnv {
+		overridden[k] = true
+	}
 	var filtered []string
 	for _, e := range os.Environ() {
 		key := strings.SplitN(e, "=", 2)[0]
@@ -127,9 +208,15 @@ func BuildEnv(env *Environment) []string {
 		}
 		filtered = append(filtered, e)
 	}
-	result := make([]string, len(filtered), len(filtered)+12+2*len(env.CustomVars))
+	result := make([]string, len(filtered), len(filtered)+18+2*len(env.CustomVars))
 	copy(result, filtered)
-	for k, v := range env.CustomVars {
+	// Map keys are sorted so the child environment has a stable order.
+	// Vars left out by export-vars are stripped above but not re-added.
+	for _, k := range sortedKeys(env.CustomVars) {
+		if env.UnexportedVars[k] {
+			continue
+		}
+		v := env.CustomVars[k]
 		result = append(result, "ORC_"+k+"="+v)
 		result = append(result, k+"="+v)
 	}
@@ -139,6 +226,9 @@ func BuildEnv(env *Environment) []string {
 		"ORC_ARTIFACTS_DIR="+env.ArtifactsDir,
 		"ORC_WORK_DIR="+env.WorkDir,
 		"ORC_PROJECT_ROOT="+env.ProjectRoot,
+		"ORC_WORKFLOW_NAME="+env.WorkflowName,
+		"ORC_PHASE_DESCRIPTION="+env.PhaseDescription,
+		"ORC_PHASE_OUTPUT_DIR="+env.outputDir(),
 		fmt.Sprintf("ORC_PHASE_INDEX=%d", env.PhaseIndex),
 		fmt.Sprintf("ORC_PHASE_COUNT=%d", env.PhaseCount),
 		// Unprefixed aliases so external scripts can use $ARTIFACTS_DIR etc.
@@ -147,7 +237,16 @@ func BuildEnv(env *Environment) []string {
 		"ARTIFACTS_DIR="+env.ArtifactsDir,
 		"WORK_DIR="+env.WorkDir,
 		"PROJECT_ROOT="+env.ProjectRoot,
+		"WORKFLOW_NAME="+env.WorkflowName,
+		"PHASE_DESCRIPTION="+env.PhaseDescription,
+		"PHASE_OUTPUT_DIR="+env.outputDir(),
 	)
+	// Only a publish phase gets $DELIVERABLES, so other phases don't pay
+	// for a stat of every deliverable.
+	if env.Deliverables != nil {
+		list := env.deliverables()
+		result = append(result, "ORC_DELIVERABLES="+list, "DELIVERABLES="+list)
+	}
 	// Passthrough allowlist: re-emit the eval-mode contract vars stripped by the
 	// ORC_* filter above so they reach workflow phases (the ticket-fetch seam
 	// reads ORC_EVAL/ORC_SPEC_FILE). Only when actually set, so non-eval runs
@@ -157,9 +256,27 @@ func BuildEnv(env *Environment) []string {
 			result = append(result, k+"="+v)
 		}
 	}
+	// secret-env values are read from orc's own environment at spawn time,
+	// so they exist only in this child's env — never in Environment, state,
+	// or logs.
+	for _, k := range sortedKeys(env.SecretEnv) {
+		if v, ok := os.LookupEnv(env.SecretEnv[k]); ok {
+			result = append(result, k+"="+v)
+		}
+	}
 	return result
 }
 
+// sortedKeys returns m's keys in sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // FilteredEnv returns os.Environ() with CLAUDECODE entries stripped.
 // Used by scaffold, doctor, and improve when invoking claude directly (not via the runner).
 func FilteredEnv() []string {
@@ -190,7 +307,7 @@ func (d *DefaultDispatcher) Dispatch(ctx context.Context, phase config.Phase, en
 // Agent phases are routed to attended mode (with steering) unless AutoMode is set.
 func Dispatch(ctx context.Context, phase config.Phase, env *Environment) (*Result, error) {
 	switch phase.Type {
-	case "script":
+	case "script", "publish":
 		return RunScript(ctx, phase, env)
 	case "agent":
 		if env.AutoMode {
@@ -199,9 +316,11 @@ func Dispatch(ctx context.Context, phase config.Phase, env *Environment) (*Resul
 		return RunAgentAttended(ctx, phase, env)
 	case "gate":
 		return RunGate(ctx, phase, env)
+	case "manual":
+		return RunManual(ctx, phase, env)
 	case "workflow", "branch":
 		return nil, fmt.Errorf("phase %q: %s phases are dispatched by the runner, not the dispatcher", phase.Name, phase.Type)
 	default:
-		return nil, fmt.Errorf("unknown phase type %q for phase %q (must be agent, script, gate, workflow, or branch)", phase.Type, phase.Name)
+		return nil, fmt.Errorf("unknown phase type %q for phase %q (must be agent, script, gate, manual, workflow, branch, or publish)", phase.Type, phase.Name)
 	}
 }
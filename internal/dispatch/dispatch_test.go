@@ -1,23 +1,29 @@
 package dispatch
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/jorge-barreto/orc/internal/config"
 )
 
 func TestVars_AllKeys(t *testing.T) {
 	env := &Environment{
-		ProjectRoot:  "/proj",
-		WorkDir:      "/work",
-		ArtifactsDir: "/art",
-		Ticket:       "T-1",
-		Workflow:     "bugfix",
+		ProjectRoot:      "/proj",
+		WorkDir:          "/work",
+		ArtifactsDir:     "/art",
+		Ticket:           "T-1",
+		Workflow:         "bugfix",
+		WorkflowName:     "Bug fix",
+		PhaseDescription: "Reproduce the bug",
 	}
 	vars := env.Vars()
 	if vars["TICKET"] != "T-1" {
@@ -35,8 +41,17 @@ func TestVars_AllKeys(t *testing.T) {
 	if vars["WORKFLOW"] != "bugfix" {
 		t.Fatalf("WORKFLOW = %q", vars["WORKFLOW"])
 	}
-	if len(vars) != 5 {
-		t.Fatalf("expected 5 keys, got %d", len(vars))
+	if vars["WORKFLOW_NAME"] != "Bug fix" {
+		t.Fatalf("WORKFLOW_NAME = %q", vars["WORKFLOW_NAME"])
+	}
+	if vars["PHASE_DESCRIPTION"] != "Reproduce the bug" {
+		t.Fatalf("PHASE_DESCRIPTION = %q", vars["PHASE_DESCRIPTION"])
+	}
+	if vars["PHASE_OUTPUT_DIR"] != "/art" {
+		t.Fatalf("PHASE_OUTPUT_DIR = %q, want the artifacts dir when unset", vars["PHASE_OUTPUT_DIR"])
+	}
+	if len(vars) != 8 {
+		t.Fatalf("expected 8 keys, got %d", len(vars))
 	}
 }
 
@@ -142,8 +157,8 @@ func TestVars_IncludesCustomVars(t *testing.T) {
 	if vars["TICKET"] != "T-1" {
 		t.Fatalf("TICKET = %q", vars["TICKET"])
 	}
-	if len(vars) != 6 {
-		t.Fatalf("expected 6 keys, got %d", len(vars))
+	if len(vars) != 9 {
+		t.Fatalf("expected 9 keys, got %d", len(vars))
 	}
 }
 
@@ -168,6 +183,79 @@ func TestBuildEnv_IncludesCustomVars(t *testing.T) {
 	}
 }
 
+func TestBuildEnv_UnexportedVarStillExpands(t *testing.T) {
+	env := &Environment{
+		ProjectRoot:    "/proj",
+		WorkDir:        "/work",
+		ArtifactsDir:   "/art",
+		Ticket:         "T-1",
+		CustomVars:     map[string]string{"API_HOST": "api.internal", "MY_DIR": "/proj/sub"},
+		UnexportedVars: map[string]bool{"API_HOST": true},
+	}
+	if got := ExpandVars("https://$API_HOST/v1", env.Vars()); got != "https://api.internal/v1" {
+		t.Fatalf("ExpandVars = %q, want the unexported var expanded", got)
+	}
+	result := BuildEnv(env)
+	for _, e := range result {
+		if strings.HasPrefix(e, "API_HOST=") || strings.HasPrefix(e, "ORC_API_HOST=") {
+			t.Errorf("unexported var leaked into child env: %s", e)
+		}
+	}
+	if !slices.Contains(result, "ORC_MY_DIR=/proj/sub") || !slices.Contains(result, "MY_DIR=/proj/sub") {
+		t.Error("exported var missing from child env")
+	}
+}
+
+func TestBuildEnv_DeterministicOrder(t *testing.T) {
+	t.Setenv("SRC_A", "a")
+	t.Setenv("SRC_B", "b")
+	env := &Environment{
+		Ticket:     "T-1",
+		CustomVars: map[string]string{"ZETA": "z", "ALPHA": "a", "MID": "m", "BETA": "b", "OMEGA": "o"},
+		SecretEnv:  map[string]string{"TOKEN_B": "SRC_B", "TOKEN_A": "SRC_A"},
+	}
+	first := BuildEnv(env)
+	for i := 0; i < 20; i++ {
+		if got := BuildEnv(env); !reflect.DeepEqual(got, first) {
+			t.Fatalf("BuildEnv order changed between calls:\n%v\n%v", first, got)
+		}
+	}
+
+	var custom []string
+	for _, e := range first {
+		name, prefixed := strings.CutPrefix(strings.SplitN(e, "=", 2)[0], "ORC_")
+		if _, ok := env.CustomVars[name]; ok && prefixed {
+			custom = append(custom, e)
+		}
+	}
+	want := []string{"ORC_ALPHA=a", "ORC_BETA=b", "ORC_MID=m", "ORC_OMEGA=o", "ORC_ZETA=z"}
+	if !reflect.DeepEqual(custom, want) {
+		t.Errorf("custom vars = %v, want sorted %v", custom, want)
+	}
+}
+
+func TestBuildEnv_SecretEnv(t *testing.T) {
+	t.Setenv("ORC_TEST_GH_PAT", "s3cret")
+	t.Setenv("GITHUB_TOKEN", "inherited")
+	env := &Environment{Ticket: "T-1", SecretEnv: map[string]string{"GITHUB_TOKEN": "ORC_TEST_GH_PAT"}}
+	var got []string
+	for _, e := range BuildEnv(env) {
+		if strings.HasPrefix(e, "GITHUB_TOKEN=") {
+			got = append(got, e)
+		}
+	}
+	if len(got) != 1 || got[0] != "GITHUB_TOKEN=s3cret" {
+		t.Fatalf("GITHUB_TOKEN entries = %v, want exactly the secret-env value", got)
+	}
+
+	env.SecretEnv = nil
+	for _, e := range BuildEnv(env) {
+		if strings.Contains(e, "s3cret") && !strings.HasPrefix(e, "ORC_TEST_GH_PAT=") {
+			t.Fatalf("secret leaked without secret-env: %q", e)
+		}
+	}
+}
+
 func TestBuildEnv_StripsCLAUDECODE(t *testing.T) {
 	t.Setenv("CLAUDECODE_TEST", "should-be-stripped")
 
@@ -261,6 +349,59 @@ func TestRunScript_PropagatesEvalVars(t *testing.T) {
 	}
 }
 
+func TestDispatch_PublishReceivesDeliverables(t *testing.T) {
+	artifactsDir := t.TempDir()
+	for _, f := range []string{"logs/.keep", "report.md", "outputs/review/findings.json", "final notes.md"} {
+		path := filepath.Join(artifactsDir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outFile := filepath.Join(artifactsDir, "published.txt")
+	env := &Environment{
+		ProjectRoot:  artifactsDir,
+		WorkDir:      artifactsDir,
+		ArtifactsDir: artifactsDir,
+		Ticket:       "T-1",
+		AutoMode:     true,
+		Deliverables: []string{"report.md", "missing.md", "outputs/review/findings.json", "final notes.md"},
+	}
+	// One path per line, so a path with a space survives a line-wise read.
+	phase := config.Phase{
+		Name: "upload",
+		Type: "publish",
+		Run:  `printf '%s\n' "$DELIVERABLES" | while IFS= read -r f; do test -f "$f" && echo "$f"; done > "` + outFile + `"`,
+	}
+	res, err := Dispatch(context.Background(), phase, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ExitCode != 0 {
+		t.Fatalf("publish exit code = %d, want 0", res.ExitCode)
+	}
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(artifactsDir, "report.md") + "\n" + filepath.Join(artifactsDir, "outputs/review/findings.json") + "\n" +
+		filepath.Join(artifactsDir, "final notes.md") + "\n"
+	if string(data) != want {
+		t.Fatalf("publish command saw deliverables:\n%s\nwant (missing ones omitted):\n%s", data, want)
+	}
+}
+
+func TestBuildEnv_DeliverablesOnlyWhenSet(t *testing.T) {
+	t.Setenv("DELIVERABLES", "inherited")
+	for _, kv := range BuildEnv(&Environment{ArtifactsDir: t.TempDir()}) {
+		if strings.HasPrefix(kv, "DELIVERABLES=") || strings.HasPrefix(kv, "ORC_DELIVERABLES=") {
+			t.Errorf("non-publish env has %s", kv)
+		}
+	}
+}
+
 func TestBuildEnv_NoEvalVarsWhenUnset(t *testing.T) {
 	// When the eval contract vars are absent, BuildEnv must not invent them.
 	os.Unsetenv("ORC_EVAL")
@@ -394,6 +535,50 @@ func TestBuildAgentArgs_PromptViaStdin(t *testing.T) {
 	}
 }
 
+func TestBuildAgentArgs_NoStream(t *testing.T) {
+	phase := config.Phase{Model: "opus", Effort: "high"}
+	env := &Environment{ProjectRoot: "/proj", WorkDir: "/work", ArtifactsDir: "/art", Ticket: "T-1", NoStream: true}
+	args := buildAgentArgs(phase, env, "sid", true, nil)
+	joined := strings.Join(args, " ")
+	if !strings.Contains(joined, "--output-format text") {
+		t.Errorf("expected --output-format text, got %v", args)
+	}
+	for _, unwanted := range []string{"stream-json", "--include-partial-messages"} {
+		if contains(args, unwanted) {
+			t.Errorf("no-stream args should not contain %q: %v", unwanted, args)
+		}
+	}
+	if !strings.Contains(joined, "--session-id sid") {
+		t.Errorf("no-stream args should still pin the session: %v", args)
+	}
+}
+
+func TestReadPlainOutput(t *testing.T) {
+	var display, log bytes.Buffer
+	res, err := readPlainOutput(strings.NewReader("all done\n"), &display, &log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Text != "all done\n" || display.String() != "all done\n" || log.String() != "all done\n" {
+		t.Errorf("text=%q display=%q log=%q", res.Text, display.String(), log.String())
+	}
+}
+
+func TestBuildAgentArgs_ScopedToolsVerbatim(t *testing.T) {
+	phase := config.Phase{Model: "opus", Effort: "high", AllowTools: []string{"Bash(git *)"}}
+	env := &Environment{ProjectRoot: "/proj", WorkDir: "/work", ArtifactsDir: "/art", Ticket: "T-1",
+		DefaultAllowTools: []string{"Bash(npm run test:*)"}}
+	tools := toolsFromArgs(buildAgentArgs(phase, env, "", true, nil))
+	for _, want := range []string{"Bash(git *)", "Bash(npm run test:*)"} {
+		if !contains(tools, want) {
+			t.Errorf("scoped tool %q not passed verbatim; tools=%v", want, tools)
+		}
+	}
+	if contains(tools, "Bash") {
+		t.Errorf("scoped entries must not widen to bare Bash; tools=%v", tools)
+	}
+}
+
 func TestBuildAgentArgs_IncludesEffort(t *testing.T) {
 	phase := config.Phase{Model: "opus", Effort: "high"}
 	env := &Environment{ProjectRoot: "/proj", WorkDir: "/work", ArtifactsDir: "/art", Ticket: "T-1"}
@@ -506,6 +691,54 @@ func TestBuildAgentArgs_MCPConfigEmpty(t *testing.T) {
 	}
 }
 
+func TestBuildAgentArgs_ClaudeSettings(t *testing.T) {
+	env := &Environment{
+		ProjectRoot:  "/proj",
+		WorkDir:      "/work",
+		ArtifactsDir: "/art",
+		Ticket:       "T-1",
+	}
+	cases := []struct {
+		name     string
+		settings string
+		want     string
+	}{
+		{"relative", ".claude/orc-settings.json", "/proj/.claude/orc-settings.json"},
+		{"absolute", "/etc/claude/settings.json", "/etc/claude/settings.json"},
+		{"expanded", "$ARTIFACTS_DIR/settings.json", "/art/settings.json"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			phase := config.Phase{Model: "opus", Effort: "high", ClaudeSettings: tc.settings}
+			args := buildAgentArgs(phase, env, "", true, nil)
+			found := false
+			for i, a := range args {
+				if a == "--settings" && i+1 < len(args) {
+					if args[i+1] != tc.want {
+						t.Fatalf("--settings value = %q, want %q", args[i+1], tc.want)
+					}
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Fatalf("--settings not found in args: %v", args)
+			}
+		})
+	}
+}
+
+func TestBuildAgentArgs_ClaudeSettingsEmpty(t *testing.T) {
+	phase := config.Phase{Model: "opus", Effort: "high"}
+	env := &Environment{ProjectRoot: "/proj", WorkDir: "/work", ArtifactsDir: "/art"}
+	args := buildAgentArgs(phase, env, "", true, nil)
+	for _, a := range args {
+		if a == "--settings" {
+			t.Fatalf("--settings should not appear when ClaudeSettings is empty; args: %v", args)
+		}
+	}
+}
+
 // toolsFromArgs extracts the tool names following --allowedTools in args.
 func toolsFromArgs(args []string) []string {
 	for i, a := range args {
@@ -701,3 +934,24 @@ func TestDryRunVars_BuiltinORCKeysOverrideCustomVars(t *testing.T) {
 		t.Errorf("ORC_TICKET = %q, want REAL-1", vars["ORC_TICKET"])
 	}
 }
+
+func TestPhaseTimeout_OverrideAppliesOnlyToMatchingType(t *testing.T) {
+	agent := config.Phase{Name: "impl", Type: "agent", Timeout: 30}
+	script := config.Phase{Name: "test", Type: "script", Timeout: 10}
+
+	env := &Environment{AgentTimeout: 5 * time.Minute}
+	if got := PhaseTimeout(agent, env); got != 5*time.Minute {
+		t.Errorf("agent timeout = %v, want 5m (override)", got)
+	}
+	if got := PhaseTimeout(script, env); got != 10*time.Minute {
+		t.Errorf("script timeout = %v, want 10m (config)", got)
+	}
+
+	env = &Environment{ScriptTimeout: 90 * time.Second}
+	if got := PhaseTimeout(script, env); got != 90*time.Second {
+		t.Errorf("script timeout = %v, want 90s (override)", got)
+	}
+	if got := PhaseTimeout(agent, env); got != 30*time.Minute {
+		t.Errorf("agent timeout = %v, want 30m (config)", got)
+	}
+}
@@ -12,12 +12,13 @@ import (
 
 	"github.com/jorge-barreto/orc/internal/config"
 	"github.com/jorge-barreto/orc/internal/state"
+	"github.com/jorge-barreto/orc/internal/ux"
 )
 
 // logMsg writes msg to w. If the write fails, it logs a warning to stderr.
 func logMsg(w io.Writer, msg string) {
 	if _, err := io.WriteString(w, msg); err != nil {
-		fmt.Fprintf(os.Stderr, "warning: log write failed: %v\n", err)
+		ux.Warnf("warning: log write failed: %v\n", err)
 	}
 }
 
@@ -40,6 +41,14 @@ func runGate(ctx context.Context, phase config.Phase, env *Environment, stdin io
 		logMsg(logFile, msg)
 		return &Result{ExitCode: 0, Output: msg}, nil
 	}
+	// A require-phrase gate still needs its phrase typed: "ya" is a shortcut
+	// for routine approvals, not for the ones that asked to be deliberate.
+	if env.AutoApproveGates && phase.RequirePhrase == "" {
+		msg := fmt.Sprintf("Gate %q auto-approved (all remaining gates approved earlier)\n", phase.Name)
+		fmt.Print(msg)
+		logMsg(logFile, msg)
+		return &Result{ExitCode: 0, Output: msg}, nil
+	}
 
 	// Run pre-prompt command if specified
 	if phase.Run != "" {
@@ -63,8 +72,16 @@ func runGate(ctx context.Context, phase config.Phase, env *Environment, stdin io
 		fmt.Printf("\n  %s\n\n", phase.Description)
 	}
 
-	// Prompt user
-	fmt.Printf("  [y to continue / feedback to revise]: ")
+	// Prompt user. On gate phases, prompt is the operator message itself.
+	hint := "y to continue, ya to approve all gates"
+	if phase.RequirePhrase != "" {
+		hint = fmt.Sprintf("type %s to continue", phase.RequirePhrase)
+	}
+	if phase.Prompt != "" {
+		fmt.Printf("  %s [%s / feedback to revise]: ", ExpandVars(phase.Prompt, env.Vars()), hint)
+	} else {
+		fmt.Printf("  [%s / feedback to revise]: ", hint)
+	}
 
 	reader := NewStdinReader(stdin)
 	defer reader.Stop()
@@ -89,18 +106,35 @@ func runGate(ctx context.Context, phase config.Phase, env *Environment, stdin io
 			return nil, io.EOF
 		}
 		input := strings.TrimSpace(lr.text)
-		switch strings.ToLower(input) {
-		case "y", "yes":
-			msg := fmt.Sprintf("Gate %q approved\n", phase.Name)
+		if phase.RequirePhrase == "" && strings.EqualFold(input, "ya") {
+			msg := fmt.Sprintf("Gate %q approved — remaining gates will be auto-approved\n", phase.Name)
 			fmt.Print(msg)
 			logMsg(logFile, msg)
-			return &Result{ExitCode: 0, Output: msg}, nil
-		default:
-			msg := fmt.Sprintf("Gate %q — revision requested\n", phase.Name)
+			return &Result{ExitCode: 0, Output: msg, ApproveAllGates: true}, nil
+		}
+		if gateApproved(phase, input) {
+			msg := fmt.Sprintf("Gate %q approved\n", phase.Name)
 			fmt.Print(msg)
 			logMsg(logFile, msg)
-			logMsg(logFile, fmt.Sprintf("Feedback: %s\n", input))
-			return &Result{ExitCode: 1, Output: input}, nil
+			return &Result{ExitCode: 0, Output: msg}, nil
 		}
+		msg := fmt.Sprintf("Gate %q — revision requested\n", phase.Name)
+		fmt.Print(msg)
+		logMsg(logFile, msg)
+		logMsg(logFile, fmt.Sprintf("Feedback: %s\n", input))
+		return &Result{ExitCode: 1, Output: input, Rejected: true}, nil
+	}
+}
+
+// gateApproved reports whether the operator's input approves the gate.
+// With require-phrase set, only the exact phrase approves; otherwise y/yes.
+func gateApproved(phase config.Phase, input string) bool {
+	if phase.RequirePhrase != "" {
+		return input == phase.RequirePhrase
+	}
+	switch strings.ToLower(input) {
+	case "y", "yes":
+		return true
 	}
+	return false
 }
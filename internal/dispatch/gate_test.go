@@ -50,6 +50,52 @@ func TestRunGate_Approve(t *testing.T) {
 	}
 }
 
+func TestRunGate_ApproveAll(t *testing.T) {
+	env := scriptEnv(t)
+	phase := config.Phase{Name: "test", Type: "gate"}
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WriteString("YA\n")
+	w.Close()
+	result, err := runGate(context.Background(), phase, env, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExitCode != 0 || !result.ApproveAllGates {
+		t.Fatalf("result = %+v, want approved with ApproveAllGates", result)
+	}
+}
+
+func TestRunGate_AutoApproveGates(t *testing.T) {
+	env := scriptEnv(t)
+	env.AutoApproveGates = true
+	phase := config.Phase{Name: "test", Type: "gate"}
+	result, err := runGate(context.Background(), phase, env, strings.NewReader(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExitCode != 0 || !strings.Contains(result.Output, "auto-approved") {
+		t.Fatalf("result = %+v, want auto-approved", result)
+	}
+
+	// require-phrase gates still ask for their phrase.
+	phrase := config.Phase{Name: "deploy", Type: "gate", RequirePhrase: "DEPLOY"}
+	out := captureGateStdout(t, func() {
+		result, err = runGate(context.Background(), phrase, env, strings.NewReader("ya\n"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExitCode != 1 || result.ApproveAllGates {
+		t.Fatalf("require-phrase gate result = %+v, want revision requested", result)
+	}
+	if !strings.Contains(out, "type DEPLOY to continue") {
+		t.Fatalf("expected the phrase prompt, got %q", out)
+	}
+}
+
 func TestRunGate_Feedback(t *testing.T) {
 	env := scriptEnv(t)
 	phase := config.Phase{Name: "test", Type: "gate"}
@@ -69,6 +115,93 @@ func TestRunGate_Feedback(t *testing.T) {
 	if !strings.Contains(result.Output, "please fix the title") {
 		t.Fatalf("output = %q, want feedback text", result.Output)
 	}
+	if !result.Rejected {
+		t.Fatal("Rejected = false, want true for revision feedback")
+	}
+}
+
+// captureGateStdout runs fn with os.Stdout redirected and returns what it printed.
+func captureGateStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	old := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	fn()
+	w.Close()
+	os.Stdout = old
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	return buf.String()
+}
+
+func TestRunGate_CustomPrompt(t *testing.T) {
+	env := scriptEnv(t)
+	phase := config.Phase{Name: "deploy", Type: "gate", Prompt: "Approve deployment of $TICKET to prod?"}
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WriteString("y\n")
+	w.Close()
+	var result *Result
+	out := captureGateStdout(t, func() {
+		result, err = runGate(context.Background(), phase, env, r)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExitCode != 0 {
+		t.Fatalf("ExitCode = %d, want 0", result.ExitCode)
+	}
+	want := "Approve deployment of " + env.Ticket + " to prod? [y to continue, ya to approve all gates / feedback to revise]: "
+	if !strings.Contains(out, want) {
+		t.Fatalf("output = %q, want prompt %q", out, want)
+	}
+}
+
+func TestRunGate_RequirePhraseRejectsY(t *testing.T) {
+	env := scriptEnv(t)
+	phase := config.Phase{Name: "deploy", Type: "gate", RequirePhrase: "DEPLOY"}
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WriteString("y\n")
+	w.Close()
+	var result *Result
+	out := captureGateStdout(t, func() {
+		result, err = runGate(context.Background(), phase, env, r)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExitCode != 1 {
+		t.Fatalf("ExitCode = %d, want 1 (plain y must not satisfy require-phrase)", result.ExitCode)
+	}
+	if !strings.Contains(out, "[type DEPLOY to continue / feedback to revise]") {
+		t.Fatalf("output = %q, want require-phrase hint", out)
+	}
+}
+
+func TestRunGate_RequirePhraseApproves(t *testing.T) {
+	env := scriptEnv(t)
+	phase := config.Phase{Name: "deploy", Type: "gate", RequirePhrase: "DEPLOY"}
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WriteString("DEPLOY\n")
+	w.Close()
+	result, err := runGate(context.Background(), phase, env, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExitCode != 0 {
+		t.Fatalf("ExitCode = %d, want 0", result.ExitCode)
+	}
 }
 
 func TestRunGate_ContextCancellation(t *testing.T) {
@@ -11,21 +11,23 @@ import (
 
 	"github.com/jorge-barreto/orc/internal/config"
 	"github.com/jorge-barreto/orc/internal/state"
+	"github.com/jorge-barreto/orc/internal/ux"
 )
 
-// RunHook executes a hook command (pre-run or post-run) via bash.
+// RunHook executes a hook command (pre-run, post-run, or on-success) via
+// bash, with ORC_PHASE_NAME set to the phase's name.
 // The caller is responsible for providing logWriter; RunHook does not open any files.
 func RunHook(ctx context.Context, command string, phase config.Phase, env *Environment, logWriter io.Writer) (int, error) {
 	cmd := exec.CommandContext(ctx, "bash", "-c", command)
 	cmd.Dir = PhaseWorkDir(phase, env)
-	cmd.Env = BuildEnv(env)
+	cmd.Env = append(BuildEnv(env), "ORC_PHASE_NAME="+phase.Name)
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 	cmd.Cancel = func() error {
 		return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
 	}
 	cmd.WaitDelay = 5 * time.Second
 
-	mw := io.MultiWriter(os.Stdout, logWriter)
+	mw := io.MultiWriter(ux.PhaseOutput(phase.Name), logWriter)
 	cmd.Stdout = mw
 	cmd.Stderr = mw
 
@@ -50,12 +52,22 @@ func RunHookWithLog(ctx context.Context, hookCmd, label string, phase config.Pha
 
 // DispatchWithHooks runs pre-run hook, dispatches the phase, then runs post-run hook.
 // Pre-run failure skips dispatch. Post-run always runs (cleanup semantics).
-// Post-run failure overrides a successful dispatch result.
+// Post-run failure overrides a successful dispatch result. on-success runs
+// last, only if the phase still succeeded; its failure is a warning unless
+// on-success-fail is "fail". With create-cwd set, the phase's cwd is created
+// before anything runs.
 func DispatchWithHooks(ctx context.Context, phase config.Phase, env *Environment, dispatchFn DispatchFunc) (*Result, error) {
 	var preRunFailed bool
 	var preRunCode int
 	var preRunErr error
 
+	// Create the cwd first so pre-run hooks can run in it too.
+	if phase.CreateCwd {
+		if err := os.MkdirAll(PhaseWorkDir(phase, env), 0755); err != nil {
+			return nil, fmt.Errorf("phase %q: creating cwd: %w", phase.Name, err)
+		}
+	}
+
 	if phase.PreRun != "" {
 		code, err := RunHookWithLog(ctx, phase.PreRun, "pre-run", phase, env)
 		if err != nil {
@@ -79,16 +91,31 @@ func DispatchWithHooks(ctx context.Context, phase config.Phase, env *Environment
 			if !preRunFailed && dispatchErr == nil {
 				return result, fmt.Errorf("post-run hook: %w", err)
 			}
-			fmt.Fprintf(os.Stderr, "warning: post-run hook error: %v\n", err)
+			ux.Warnf("warning: post-run hook error: %v\n", err)
 		} else if code != 0 {
-			if !preRunFailed && dispatchErr == nil && result != nil && result.ExitCode == 0 {
+			if !preRunFailed && dispatchErr == nil && result != nil && phase.SucceedsWith(result.ExitCode) {
 				result.ExitCode = code
 			} else {
-				fmt.Fprintf(os.Stderr, "warning: post-run hook failed (exit %d) but phase already failed\n", code)
+				ux.Warnf("warning: post-run hook failed (exit %d) but phase already failed\n", code)
 			}
 		}
 	}
 
+	if phase.OnSuccess != "" && !preRunFailed && dispatchErr == nil && result != nil &&
+		!result.TimedOut && !result.Stalled && phase.SucceedsWith(result.ExitCode) {
+		code, err := RunHookWithLog(ctx, phase.OnSuccess, "on-success", phase, env)
+		switch {
+		case err != nil && phase.OnSuccessFail == "fail":
+			return result, fmt.Errorf("on-success: %w", err)
+		case err != nil:
+			ux.Warnf("warning: phase %q: on-success error: %v\n", phase.Name, err)
+		case code != 0 && phase.OnSuccessFail == "fail":
+			return result, fmt.Errorf("on-success failed (exit %d)", code)
+		case code != 0:
+			ux.Warnf("warning: phase %q: on-success failed (exit %d)\n", phase.Name, code)
+		}
+	}
+
 	if preRunErr != nil {
 		return nil, preRunErr
 	}
@@ -258,6 +258,24 @@ func TestDispatchWithHooks_PostRunFail_OverridesSuccess(t *testing.T) {
 	}
 }
 
+func TestDispatchWithHooks_PostRunFail_OverridesWhitelistedExit(t *testing.T) {
+	env := scriptEnv(t)
+	if err := os.MkdirAll(filepath.Join(env.ArtifactsDir, "logs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	phase := config.Phase{Name: "test", Type: "script", PostRun: "exit 7", SuccessExitCodes: []int{3}}
+	fn := func(ctx context.Context, p config.Phase, e *Environment) (*Result, error) {
+		return &Result{ExitCode: 3}, nil
+	}
+	result, err := DispatchWithHooks(context.Background(), phase, env, fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExitCode != 7 {
+		t.Fatalf("ExitCode = %d, want 7 (post-run override of a success exit code)", result.ExitCode)
+	}
+}
+
 func TestDispatchWithHooks_PreRunFail_PostRunStillRuns(t *testing.T) {
 	env := scriptEnv(t)
 	if err := os.MkdirAll(filepath.Join(env.ArtifactsDir, "logs"), 0755); err != nil {
@@ -378,3 +396,39 @@ func TestDispatchWithHooks_PreRunInfraError_PostRunStillAttempted(t *testing.T)
 		t.Fatalf("stderr = %q, want post-run hook warning (proves post-run was attempted)", stderrBuf.String())
 	}
 }
+
+func TestDispatchWithHooks_CreateCwd(t *testing.T) {
+	env := scriptEnv(t)
+	worktree := filepath.Join(env.ProjectRoot, "worktrees", "TEST-1")
+	phase := config.Phase{Name: "build", Type: "script", Cwd: "$PROJECT_ROOT/worktrees/$TICKET", CreateCwd: true,
+		Run: "pwd > \"$ARTIFACTS_DIR/pwd.txt\""}
+
+	result, err := DispatchWithHooks(context.Background(), phase, env, Dispatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExitCode != 0 {
+		t.Fatalf("ExitCode = %d, output %q", result.ExitCode, result.Output)
+	}
+	got, err := os.ReadFile(filepath.Join(env.ArtifactsDir, "pwd.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(got)) != worktree {
+		t.Fatalf("phase ran in %q, want %q", strings.TrimSpace(string(got)), worktree)
+	}
+}
+
+func TestDispatchWithHooks_MissingCwdWithoutCreate(t *testing.T) {
+	env := scriptEnv(t)
+	missing := filepath.Join(env.ProjectRoot, "not-yet")
+	phase := config.Phase{Name: "build", Type: "script", Cwd: missing, Run: "true"}
+
+	result, err := DispatchWithHooks(context.Background(), phase, env, Dispatch)
+	if err == nil && result.ExitCode == 0 {
+		t.Fatal("expected the phase to fail when cwd is missing and create-cwd is off")
+	}
+	if _, statErr := os.Stat(missing); !os.IsNotExist(statErr) {
+		t.Fatalf("cwd must not be created without create-cwd, stat err = %v", statErr)
+	}
+}
@@ -0,0 +1,73 @@
+package dispatch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/state"
+)
+
+// RunManual executes a manual phase: it prints the operator's instructions
+// and waits until they confirm the step is done.
+func RunManual(ctx context.Context, phase config.Phase, env *Environment) (*Result, error) {
+	return runManual(ctx, phase, env, os.Stdin)
+}
+
+func runManual(ctx context.Context, phase config.Phase, env *Environment, stdin io.Reader) (*Result, error) {
+	logFile, err := os.OpenFile(state.LogPath(env.ArtifactsDir, env.PhaseIndex), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer logFile.Close()
+
+	instructions := ExpandVars(phase.Description, env.Vars())
+
+	// Nobody is there to do the step in --auto mode; record it and move on.
+	if env.AutoMode {
+		msg := fmt.Sprintf("Manual step %q skipped (--auto mode) — not done: %s\n", phase.Name, instructions)
+		fmt.Print(msg)
+		logMsg(logFile, msg)
+		return &Result{ExitCode: 0, Output: msg}, nil
+	}
+
+	fmt.Printf("\n  Manual step: %s\n\n", instructions)
+
+	reader := NewStdinReader(stdin)
+	defer reader.Stop()
+
+	type lineResult struct {
+		text string
+		ok   bool
+	}
+	for {
+		fmt.Print("  [y when done]: ")
+		lineCh := make(chan lineResult, 1)
+		go func() {
+			text, ok := reader.ReadLineBlocking()
+			lineCh <- lineResult{text, ok}
+		}()
+
+		select {
+		case <-ctx.Done():
+			msg := "Manual step cancelled\n"
+			logMsg(logFile, msg)
+			return &Result{ExitCode: 1, Output: msg}, nil
+		case lr := <-lineCh:
+			if !lr.ok {
+				return nil, io.EOF
+			}
+			switch strings.ToLower(strings.TrimSpace(lr.text)) {
+			case "y", "yes":
+				msg := fmt.Sprintf("Manual step %q done\n", phase.Name)
+				fmt.Print(msg)
+				logMsg(logFile, msg)
+				return &Result{ExitCode: 0, Output: msg}, nil
+			}
+			fmt.Println("  Type y once the step is done, or press Ctrl-C to stop.")
+		}
+	}
+}
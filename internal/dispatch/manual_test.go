@@ -0,0 +1,78 @@
+package dispatch
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/state"
+)
+
+func TestRunManual_Confirm(t *testing.T) {
+	env := scriptEnv(t)
+	phase := config.Phase{Name: "bump", Type: "manual", Description: "Bump the version for $TICKET in the release system"}
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Anything other than y re-prompts; the step completes on y.
+	w.WriteString("not yet\ny\n")
+	w.Close()
+
+	var result *Result
+	out := captureGateStdout(t, func() {
+		result, err = runManual(context.Background(), phase, env, r)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExitCode != 0 {
+		t.Fatalf("ExitCode = %d, want 0", result.ExitCode)
+	}
+	if !strings.Contains(out, "Manual step: Bump the version for "+env.Ticket) {
+		t.Fatalf("instructions not shown with vars expanded; got:\n%s", out)
+	}
+	if strings.Count(out, "[y when done]") != 2 {
+		t.Fatalf("expected a re-prompt after non-y input; got:\n%s", out)
+	}
+	log, _ := os.ReadFile(state.LogPath(env.ArtifactsDir, env.PhaseIndex))
+	if !strings.Contains(string(log), `Manual step "bump" done`) {
+		t.Fatalf("log = %q, want completion line", log)
+	}
+}
+
+func TestRunManual_AutoSkip(t *testing.T) {
+	env := scriptEnv(t)
+	env.AutoMode = true
+	phase := config.Phase{Name: "bump", Type: "manual", Description: "Bump the version"}
+
+	// No stdin is read in --auto mode.
+	result, err := runManual(context.Background(), phase, env, strings.NewReader(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExitCode != 0 {
+		t.Fatalf("ExitCode = %d, want 0", result.ExitCode)
+	}
+	if !strings.Contains(result.Output, "skipped (--auto mode)") {
+		t.Fatalf("output = %q, want auto-skip notice", result.Output)
+	}
+	log, _ := os.ReadFile(state.LogPath(env.ArtifactsDir, env.PhaseIndex))
+	if !strings.Contains(string(log), "skipped (--auto mode)") {
+		t.Fatalf("log = %q, want auto-skip notice", log)
+	}
+}
+
+func TestDispatch_RoutesManual(t *testing.T) {
+	env := scriptEnv(t)
+	env.AutoMode = true
+	result, err := Dispatch(context.Background(), config.Phase{Name: "m", Type: "manual", Description: "do it"}, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result.Output, "Manual step") {
+		t.Fatalf("output = %q, want manual phase output", result.Output)
+	}
+}
@@ -2,43 +2,87 @@ package dispatch
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"sort"
 	"strings"
 
 	"github.com/jorge-barreto/orc/internal/config"
 )
 
-// Preflight checks that all binaries required by the workflow phases are available on PATH.
-func Preflight(phases []config.Phase) error {
+// RequiredBinaries returns the sorted names of the binaries the workflow
+// phases need on PATH.
+func RequiredBinaries(phases []config.Phase) []string {
 	needed := make(map[string]bool)
 	for _, p := range phases {
 		switch p.Type {
-		case "script":
-			needed["bash"] = true
+		case "script", "publish":
+			if p.Container != nil {
+				needed[p.Container.RuntimeOrDefault()] = true
+			} else {
+				needed["bash"] = true
+			}
 		case "agent":
 			needed["claude"] = true
 		case "branch":
 			needed["bash"] = true // check script runs via bash
 		}
-		if p.PreRun != "" || p.PostRun != "" {
+		if p.PreRun != "" || p.PostRun != "" || p.OnSuccess != "" || p.Verify != "" {
 			needed["bash"] = true
 		}
 	}
+	bins := make([]string, 0, len(needed))
+	for bin := range needed {
+		bins = append(bins, bin)
+	}
+	sort.Strings(bins)
+	return bins
+}
 
+// InstallHint returns the install instructions for a missing binary, or ""
+// when there are none.
+func InstallHint(bin string) string {
+	if bin == "claude" {
+		return "install: npm install -g @anthropic-ai/claude-code"
+	}
+	return ""
+}
+
+// Preflight checks that all binaries required by the workflow phases are
+// available on PATH and that every secret-env source variable is set.
+func Preflight(phases []config.Phase) error {
 	var hints []string
-	for bin := range needed {
+	for _, bin := range RequiredBinaries(phases) {
 		if _, err := exec.LookPath(bin); err != nil {
-			switch bin {
-			case "claude":
-				hints = append(hints, "claude (install: npm install -g @anthropic-ai/claude-code)")
-			default:
-				hints = append(hints, bin)
+			if hint := InstallHint(bin); hint != "" {
+				bin += " (" + hint + ")"
 			}
+			hints = append(hints, bin)
 		}
 	}
 
 	if len(hints) > 0 {
 		return fmt.Errorf("required binaries not found in PATH: %s", strings.Join(hints, ", "))
 	}
+
+	var unset []string
+	for _, p := range phases {
+		if p.Disabled {
+			continue
+		}
+		keys := make([]string, 0, len(p.SecretEnv))
+		for k := range p.SecretEnv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if _, ok := os.LookupEnv(p.SecretEnv[k]); !ok {
+				unset = append(unset, fmt.Sprintf("$%s (secret-env %s of phase %q)", p.SecretEnv[k], k, p.Name))
+			}
+		}
+	}
+	if len(unset) > 0 {
+		return fmt.Errorf("secret-env source variables not set: %s", strings.Join(unset, ", "))
+	}
 	return nil
 }
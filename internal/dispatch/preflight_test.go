@@ -76,3 +76,36 @@ func TestPreflight_PostRunHookNeedsBash(t *testing.T) {
 		t.Fatalf("gate with post-run hook should check for bash, got: %v", err)
 	}
 }
+
+func TestPreflight_SecretEnvSourceUnset(t *testing.T) {
+	t.Setenv("ORC_TEST_SET_SECRET", "x")
+	phases := []config.Phase{
+		{Name: "push", Type: "script", Run: "true", SecretEnv: map[string]string{
+			"A_TOKEN": "ORC_TEST_SET_SECRET",
+			"B_TOKEN": "ORC_TEST_DEFINITELY_UNSET",
+		}},
+		{Name: "off", Type: "script", Run: "true", Disabled: true,
+			SecretEnv: map[string]string{"C_TOKEN": "ORC_TEST_ALSO_UNSET"}},
+	}
+	err := Preflight(phases)
+	if err == nil || !strings.Contains(err.Error(), `$ORC_TEST_DEFINITELY_UNSET (secret-env B_TOKEN of phase "push")`) {
+		t.Fatalf("got %v", err)
+	}
+	if strings.Contains(err.Error(), "ORC_TEST_ALSO_UNSET") {
+		t.Fatalf("disabled phase should not be checked: %v", err)
+	}
+}
+
+func TestPreflight_ContainerNeedsRuntime(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	phases := []config.Phase{
+		{Name: "a", Type: "script", Run: "make", Container: &config.Container{Image: "golang:1.22", Runtime: "podman"}},
+	}
+	if got := RequiredBinaries(phases); len(got) != 1 || got[0] != "podman" {
+		t.Fatalf("RequiredBinaries = %v, want [podman]", got)
+	}
+	err := Preflight(phases)
+	if err == nil || !strings.Contains(err.Error(), "podman") {
+		t.Fatalf("expected missing podman error, got: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package dispatch
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jorge-barreto/orc/internal/config"
+)
+
+// PrintAgentCommand shows exactly how orc would invoke claude for an agent
+// phase, without running it: the rendered prompt is written to a temp file
+// and the full argv, working directory, and environment changes are printed
+// so the call can be reproduced by hand. Returns the prompt file path.
+func PrintAgentCommand(w io.Writer, phase config.Phase, env *Environment) (string, error) {
+	prompt, err := renderPrompt(phase, env)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp("", "orc-prompt-*.md")
+	if err != nil {
+		return "", fmt.Errorf("writing prompt file: %w", err)
+	}
+	if _, err := f.WriteString(prompt); err != nil {
+		f.Close()
+		return "", fmt.Errorf("writing prompt file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("writing prompt file: %w", err)
+	}
+
+	argv := append([]string{"claude"}, buildAgentArgs(phase, env, "", false, nil)...)
+	quoted := make([]string, len(argv))
+	for i, a := range argv {
+		quoted[i] = shellQuote(a)
+	}
+
+	fmt.Fprintf(w, "# Phase %d: %s\n", env.PhaseIndex+1, phase.Name)
+	fmt.Fprintf(w, "# Working directory\n")
+	fmt.Fprintf(w, "cd %s\n\n", shellQuote(PhaseWorkDir(phase, env)))
+	set, unset := envDelta(os.Environ(), BuildEnv(env))
+	fmt.Fprintf(w, "# Environment changes\n")
+	for _, k := range unset {
+		fmt.Fprintf(w, "unset %s\n", k)
+	}
+	for _, kv := range set {
+		k, v, _ := strings.Cut(kv, "=")
+		if src, ok := env.SecretEnv[k]; ok {
+			// Never print a secret-env value; show where it comes from.
+			fmt.Fprintf(w, "export %s=\"$%s\"\n", k, src)
+			continue
+		}
+		fmt.Fprintf(w, "export %s=%s\n", k, shellQuote(v))
+	}
+	fmt.Fprintf(w, "\n# Command (prompt is fed on stdin)\n")
+	fmt.Fprintf(w, "%s < %s\n", strings.Join(quoted, " "), shellQuote(f.Name()))
+	return f.Name(), nil
+}
+
+// DefaultMaxPromptBytes is the rendered prompt size above which --dry-run
+// warns when the config doesn't set max-prompt-bytes (~50k tokens).
+const DefaultMaxPromptBytes = 200 * 1000
+
+// EstimatePrompt renders an agent phase's prompt as it would be sent (vars
+// expanded, pending loop feedback included) and returns its size in bytes
+// with its estimated input tokens and cost. See EstimatePromptCost.
+func EstimatePrompt(phase config.Phase, env *Environment) (size, tokens int, costUSD float64, err error) {
+	prompt, err := renderPrompt(phase, env)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	tokens, costUSD = EstimatePromptCost(phase.Model, prompt)
+	return len(prompt), tokens, costUSD, nil
+}
+
+// envDelta compares the inherited environment with the one orc builds for a
+// child process. set holds KEY=VALUE entries that are new or changed; unset
+// holds keys orc strips. Both are sorted.
+func envDelta(base, child []string) (set, unset []string) {
+	baseVals := make(map[string]string, len(base))
+	for _, kv := range base {
+		k, v, _ := strings.Cut(kv, "=")
+		baseVals[k] = v
+	}
+	childKeys := make(map[string]bool, len(child))
+	for _, kv := range child {
+		k, v, _ := strings.Cut(kv, "=")
+		childKeys[k] = true
+		if old, ok := baseVals[k]; !ok || old != v {
+			set = append(set, kv)
+		}
+	}
+	for k := range baseVals {
+		if !childKeys[k] {
+			unset = append(unset, k)
+		}
+	}
+	sort.Strings(set)
+	sort.Strings(unset)
+	return set, unset
+}
+
+// shellQuote quotes s for a POSIX shell when it contains anything beyond
+// a conservative set of safe characters.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:,+@%", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,108 @@
+package dispatch
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jorge-barreto/orc/internal/config"
+)
+
+func TestPrintAgentCommand(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "plan.md"), []byte("Plan $TICKET"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	phase := config.Phase{Name: "plan", Type: "agent", Prompt: "plan.md", Model: "sonnet", Effort: "high",
+		AllowTools: []string{"Bash(make test)"}}
+	env := &Environment{ProjectRoot: root, WorkDir: root, ArtifactsDir: filepath.Join(root, "art"), Ticket: "T-1", PhaseIndex: 1}
+
+	var buf bytes.Buffer
+	promptFile, err := PrintAgentCommand(&buf, phase, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(promptFile)
+	out := buf.String()
+
+	for _, want := range []string{
+		"claude -p ",
+		"--model sonnet",
+		"--allowedTools Read Edit",
+		"'Bash(make test)'",
+		"< " + promptFile,
+		"cd " + root,
+		"export ORC_TICKET=T-1",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	data, err := os.ReadFile(promptFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "Plan T-1" {
+		t.Errorf("prompt file = %q, want rendered prompt", data)
+	}
+	// Previewing must not touch the artifacts directory.
+	if _, err := os.Stat(env.ArtifactsDir); !os.IsNotExist(err) {
+		t.Errorf("artifacts dir should not be created, stat err = %v", err)
+	}
+}
+
+func TestEnvDelta(t *testing.T) {
+	set, unset := envDelta(
+		[]string{"HOME=/h", "CLAUDECODE=1", "TICKET=old"},
+		[]string{"HOME=/h", "TICKET=new", "ORC_TICKET=new"},
+	)
+	if strings.Join(set, ",") != "ORC_TICKET=new,TICKET=new" {
+		t.Errorf("set = %v", set)
+	}
+	if strings.Join(unset, ",") != "CLAUDECODE" {
+		t.Errorf("unset = %v", unset)
+	}
+}
+
+func TestEstimatePromptCost(t *testing.T) {
+	tokens, cost := EstimatePromptCost("opus", strings.Repeat("a", 4001))
+	if tokens != 1001 {
+		t.Errorf("tokens = %d, want 1001 (rounded up)", tokens)
+	}
+	if want := float64(tokens) * lookupRates("opus").inputPerToken; cost != want {
+		t.Errorf("cost = %v, want %v", cost, want)
+	}
+	_, haiku := EstimatePromptCost("haiku", strings.Repeat("a", 4000))
+	_, opus := EstimatePromptCost("opus", strings.Repeat("a", 4000))
+	if !(haiku < opus) {
+		t.Errorf("haiku estimate %v should be cheaper than opus %v", haiku, opus)
+	}
+}
+
+func TestPrintAgentCommand_RedactsSecretEnv(t *testing.T) {
+	t.Setenv("ORC_TEST_GH_PAT", "s3cret-value")
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "push.md"), []byte("push"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	phase := config.Phase{Name: "push", Type: "agent", Prompt: "push.md", Model: "sonnet",
+		SecretEnv: map[string]string{"GITHUB_TOKEN": "ORC_TEST_GH_PAT"}}
+	env := &Environment{ProjectRoot: root, WorkDir: root, ArtifactsDir: filepath.Join(root, "art"), Ticket: "T-1",
+		SecretEnv: phase.SecretEnv}
+
+	var buf bytes.Buffer
+	promptFile, err := PrintAgentCommand(&buf, phase, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(promptFile)
+	out := buf.String()
+	if strings.Contains(out, "s3cret-value") {
+		t.Fatalf("secret value printed:\n%s", out)
+	}
+	if !strings.Contains(out, `export GITHUB_TOKEN="$ORC_TEST_GH_PAT"`) {
+		t.Fatalf("secret-env source not shown:\n%s", out)
+	}
+}
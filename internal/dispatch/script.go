@@ -2,33 +2,70 @@ package dispatch
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/jorge-barreto/orc/internal/config"
 	"github.com/jorge-barreto/orc/internal/state"
+	"github.com/jorge-barreto/orc/internal/ux"
 )
 
-// RunScript executes a script phase via bash.
+// RunScript executes a script phase via bash, inside the phase's container
+// when one is configured.
 func RunScript(ctx context.Context, phase config.Phase, env *Environment) (*Result, error) {
-	if phase.Timeout > 0 {
+	if timeout := PhaseTimeout(phase, env); timeout > 0 {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, time.Duration(phase.Timeout)*time.Minute)
+		ctx, cancel = context.WithTimeout(ctx, timeout)
 		defer cancel()
 	}
+	cmdCtx, wd, stopWatchdog := newWatchdog(ctx, InactivityTimeout(phase))
+	defer stopWatchdog()
 
-	cmd := exec.CommandContext(ctx, "bash", "-c", phase.Run)
-	cmd.Dir = PhaseWorkDir(phase, env)
-	cmd.Env = BuildEnv(env)
+	workDir := PhaseWorkDir(phase, env)
+	childEnv := BuildEnv(env)
+	name, args := "bash", []string{"-c", phase.Run}
+	var container string
+	if phase.Container != nil {
+		interactive := phase.Stdin != "" || phase.StdinFile != ""
+		name = phase.Container.RuntimeOrDefault()
+		container = containerName()
+		args = containerArgs(phase.Container, container, env.ProjectRoot, workDir, env.ArtifactsDir, phase.Run, containerEnvKeys(childEnv, env), interactive, env.Vars())
+	}
+	cmd := exec.CommandContext(cmdCtx, name, args...)
+	cmd.Dir = workDir
+	cmd.Env = childEnv
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 	cmd.Cancel = func() error {
+		if container != "" {
+			killContainer(name, container)
+		}
 		return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
 	}
 	cmd.WaitDelay = 5 * time.Second
 
+	// A nil Stdin reads from /dev/null, so scripts never block on the terminal.
+	switch {
+	case phase.StdinFile != "":
+		path := ExpandVars(phase.StdinFile, env.Vars())
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(env.ArtifactsDir, path)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("opening stdin-file: %w", err)
+		}
+		defer f.Close()
+		cmd.Stdin = f
+	case phase.Stdin != "":
+		cmd.Stdin = strings.NewReader(ExpandVars(phase.Stdin, env.Vars()))
+	}
+
 	logFile, err := os.OpenFile(state.LogPath(env.ArtifactsDir, env.PhaseIndex), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		return nil, err
@@ -36,15 +73,15 @@ func RunScript(ctx context.Context, phase config.Phase, env *Environment) (*Resu
 	defer logFile.Close()
 
 	captured := newTailWriter(1 << 20) // 1 MB tail buffer
-	cmd.Stdout = io.MultiWriter(os.Stdout, logFile, captured)
-	cmd.Stderr = io.MultiWriter(os.Stderr, logFile, captured)
+	cmd.Stdout = io.MultiWriter(ux.PhaseOutput(phase.Name), logFile, captured, wd)
+	cmd.Stderr = io.MultiWriter(ux.PhaseErrOutput(phase.Name), logFile, captured, wd)
 
 	code, err := exitCode(cmd.Run())
 	if err != nil {
 		return nil, err
 	}
 
-	res := &Result{ExitCode: code, Output: captured.String()}
+	res := &Result{ExitCode: code, Output: captured.String(), Stalled: wd.Fired()}
 	if ctx.Err() == context.DeadlineExceeded {
 		res.TimedOut = true
 	}
@@ -2,13 +2,16 @@ package dispatch
 
 import (
 	"context"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jorge-barreto/orc/internal/config"
 	"github.com/jorge-barreto/orc/internal/state"
+	"github.com/jorge-barreto/orc/internal/ux"
 )
 
 func scriptEnv(t *testing.T) *Environment {
@@ -42,6 +45,37 @@ func TestRunScript_Success(t *testing.T) {
 	}
 }
 
+func TestRunScript_PrefixOutput(t *testing.T) {
+	defer func(prev bool) { ux.PrefixOutput = prev }(ux.PrefixOutput)
+	ux.PrefixOutput = true
+
+	env := scriptEnv(t)
+	phase := config.Phase{Name: "build", Type: "script", Run: "echo first; printf 'second\\nthird'"}
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	result, err := RunScript(context.Background(), phase, env)
+	w.Close()
+	os.Stdout = oldStdout
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := io.ReadAll(r)
+
+	want := "[build] first\n[build] second\n[build] third"
+	if string(out) != want {
+		t.Fatalf("stdout = %q, want %q", out, want)
+	}
+	if strings.Contains(result.Output, "[build]") {
+		t.Errorf("captured output should not be prefixed: %q", result.Output)
+	}
+	logData, _ := os.ReadFile(state.LogPath(env.ArtifactsDir, env.PhaseIndex))
+	if strings.Contains(string(logData), "[build]") {
+		t.Errorf("phase log should not be prefixed: %q", logData)
+	}
+}
+
 func TestRunScript_Failure(t *testing.T) {
 	env := scriptEnv(t)
 	phase := config.Phase{Name: "test", Type: "script", Run: "exit 1"}
@@ -134,3 +168,70 @@ func TestRunScript_CwdDefaultWhenUnset(t *testing.T) {
 		t.Fatalf("output = %q, expected WorkDir %q", result.Output, env.WorkDir)
 	}
 }
+
+func TestRunScript_Stdin(t *testing.T) {
+	env := scriptEnv(t)
+	phase := config.Phase{Name: "test", Type: "script", Run: "cat", Stdin: "ticket=$TICKET\n"}
+	result, err := RunScript(context.Background(), phase, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExitCode != 0 {
+		t.Fatalf("ExitCode = %d", result.ExitCode)
+	}
+	if !strings.Contains(result.Output, "ticket=TEST-1") {
+		t.Fatalf("output = %q, want expanded stdin", result.Output)
+	}
+}
+
+func TestRunScript_StdinFile(t *testing.T) {
+	env := scriptEnv(t)
+	os.WriteFile(filepath.Join(env.ArtifactsDir, "manifest.yaml"), []byte("kind: Pod\n"), 0644)
+	phase := config.Phase{Name: "test", Type: "script", Run: "cat", StdinFile: "manifest.yaml"}
+	result, err := RunScript(context.Background(), phase, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result.Output, "kind: Pod") {
+		t.Fatalf("output = %q, want stdin-file content", result.Output)
+	}
+}
+
+func TestRunScript_StdinFileMissing(t *testing.T) {
+	env := scriptEnv(t)
+	phase := config.Phase{Name: "test", Type: "script", Run: "cat", StdinFile: "missing.yaml"}
+	if _, err := RunScript(context.Background(), phase, env); err == nil || !strings.Contains(err.Error(), "stdin-file") {
+		t.Fatalf("expected stdin-file error, got %v", err)
+	}
+}
+
+func TestRunScript_NoStdinIsEmpty(t *testing.T) {
+	env := scriptEnv(t)
+	// Without stdin configured, reads must see EOF immediately rather than block.
+	phase := config.Phase{Name: "test", Type: "script", Run: `if read -r line; then echo "got:$line"; else echo "eof"; fi`, Timeout: 1}
+	result, err := RunScript(context.Background(), phase, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result.Output, "eof") {
+		t.Fatalf("output = %q, want eof", result.Output)
+	}
+}
+
+func TestRunScript_ScriptTimeoutOverride(t *testing.T) {
+	env := scriptEnv(t)
+	env.ScriptTimeout = 200 * time.Millisecond
+	env.AgentTimeout = time.Hour // must not affect script phases
+	phase := config.Phase{Name: "test", Type: "script", Run: "sleep 10", Timeout: 10}
+	start := time.Now()
+	result, err := RunScript(context.Background(), phase, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.TimedOut {
+		t.Fatalf("expected TimedOut, got exit %d", result.ExitCode)
+	}
+	if elapsed := time.Since(start); elapsed > 8*time.Second {
+		t.Fatalf("script ran %v; --script-timeout was not applied", elapsed)
+	}
+}
@@ -8,16 +8,26 @@ import (
 // StdinReader monitors stdin in a goroutine and buffers any input.
 // The caller checks for buffered input between agent turns.
 type StdinReader struct {
-	lines chan string
-	done  chan struct{}
+	lines     chan string
+	done      chan struct{}
+	intercept func(line string) bool // consumes a line immediately, as it is typed; nil for none
 }
 
 // NewStdinReader starts a background goroutine reading lines from r.
 // The goroutine exits when r returns an error (EOF) or Stop() is called.
 func NewStdinReader(r io.Reader) *StdinReader {
+	return newInterceptingStdinReader(r, nil)
+}
+
+// newInterceptingStdinReader is NewStdinReader with a hook that sees each
+// line as soon as it is read — while an agent turn is still running. Lines
+// for which intercept returns true are consumed; the rest are buffered for
+// ReadLine as usual.
+func newInterceptingStdinReader(r io.Reader, intercept func(line string) bool) *StdinReader {
 	sr := &StdinReader{
-		lines: make(chan string, 16),
-		done:  make(chan struct{}),
+		lines:     make(chan string, 16),
+		done:      make(chan struct{}),
+		intercept: intercept,
 	}
 	go sr.readLoop(r)
 	return sr
@@ -39,6 +49,9 @@ func (sr *StdinReader) readLoop(r io.Reader) {
 		if line == "" {
 			continue
 		}
+		if sr.intercept != nil && sr.intercept(line) {
+			continue
+		}
 		select {
 		case sr.lines <- line:
 		case <-sr.done:
@@ -2,12 +2,14 @@ package dispatch
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
+	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/jorge-barreto/orc/internal/ux"
 )
@@ -46,9 +48,80 @@ type StreamResult struct {
 	CacheReadInputTokens     int
 	RateLimited              bool
 	RateLimitResetAt         int64 // Unix timestamp from rate_limit_event
+	Overloaded               bool  // true if the result event reported an API overload error
 	CostOverrun              bool  // true if in-flight cost monitor tripped
 }
 
+// LogEvent is a normalized stream event, written one per line by
+// --log-format json and to the live event sink (--events-fd/--events-pipe).
+type LogEvent struct {
+	Type         string  `json:"type"`               // "text", "tool_use", "result", "phase_start", "phase_end", "phase_skip", or "run_end"
+	Workflow     string  `json:"workflow,omitempty"` // runner events only; set for named and sub-workflows
+	Phase        string  `json:"phase,omitempty"`
+	Index        int     `json:"index,omitempty"` // 1-indexed phase number (phase_start, phase_end)
+	Tool         string  `json:"tool,omitempty"`
+	Input        string  `json:"input,omitempty"`
+	Text         string  `json:"text,omitempty"`
+	Status       string  `json:"status,omitempty"` // phase_end: "ok" or "failed"; run_end: final run status
+	ExitCode     int     `json:"exit_code,omitempty"`
+	DurationSecs float64 `json:"duration_secs,omitempty"`
+	CostUSD      float64 `json:"cost_usd,omitempty"`
+	SessionID    string  `json:"session_id,omitempty"`
+	Reason       string  `json:"reason,omitempty"` // phase_skip: why the phase was skipped
+}
+
+// EventSink is the live event stream for external consumers. Writes are
+// serialized so events from parallel phases never interleave mid-line.
+// A nil *EventSink discards events.
+type EventSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewEventSink returns an EventSink writing JSON lines to w.
+func NewEventSink(w io.Writer) *EventSink {
+	return &EventSink{w: w}
+}
+
+// Write implements io.Writer. Each call is written atomically.
+func (s *EventSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// Emit writes ev as one JSON line.
+func (s *EventSink) Emit(ev LogEvent) {
+	if s == nil {
+		return
+	}
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	s.Write(append(data, '\n'))
+}
+
+// EventLog writes LogEvents as JSON lines, stamping each with the phase name.
+// A nil *EventLog discards events.
+type EventLog struct {
+	phase string
+	enc   *json.Encoder
+}
+
+// NewEventLog returns an EventLog writing to w.
+func NewEventLog(w io.Writer, phase string) *EventLog {
+	return &EventLog{phase: phase, enc: json.NewEncoder(w)}
+}
+
+func (l *EventLog) emit(ev LogEvent) {
+	if l == nil {
+		return
+	}
+	ev.Phase = l.phase
+	l.enc.Encode(ev)
+}
+
 // streamState tracks tool use accumulation across stream events.
 type streamState struct {
 	toolName      string
@@ -57,6 +130,17 @@ type streamState struct {
 	userQuestions []UserQuestion
 	toolsUsed     []string
 	toolsSeen     map[string]bool
+	events        *EventLog
+	textChunk     strings.Builder // text since the last emitted event
+}
+
+// flushText emits accumulated text as a single "text" event.
+func (ss *streamState) flushText() {
+	if ss.textChunk.Len() == 0 {
+		return
+	}
+	ss.events.emit(LogEvent{Type: "text", Text: ss.textChunk.String()})
+	ss.textChunk.Reset()
 }
 
 // warnWriter wraps an io.Writer and logs the first write error to stderr.
@@ -73,31 +157,42 @@ func (ww *warnWriter) Write(p []byte) (int, error) {
 	n, err := ww.w.Write(p)
 	if err != nil {
 		ww.failed = true
-		fmt.Fprintf(os.Stderr, "warning: raw log write failed: %v\n", err)
+		ux.Warnf("warning: raw log write failed: %v\n", err)
 		return len(p), nil
 	}
 	return n, nil
 }
 
+// DefaultMaxStreamLineBytes caps a single stream-json line when the config
+// doesn't set max-stream-line-bytes. Large tool results (file reads, diffs)
+// arrive as one line, so this is deliberately generous.
+const DefaultMaxStreamLineBytes = 16 * 1024 * 1024
+
 // ProcessStream reads stream-json lines from stdout, routes text to display+log,
 // tracks tool use for inline display, and extracts the final result.
 func ProcessStream(ctx context.Context, stdout io.Reader, display io.Writer, logFile io.Writer, rawLog io.Writer) (*StreamResult, error) {
-	return ProcessStreamWithMonitor(ctx, stdout, display, logFile, rawLog, nil, nil)
+	return ProcessStreamWithMonitor(ctx, stdout, display, logFile, rawLog, nil, nil, nil, 0)
 }
 
-// ProcessStreamWithMonitor is ProcessStream with an optional cost monitor
-// and an optional cancel callback. When the monitor's running cost
-// estimate exceeds its cap, cancel() is invoked (typically cancels the
-// subprocess context, which SIGTERMs claude) and the stream loop exits
-// with ErrCostOverrun.
-func ProcessStreamWithMonitor(ctx context.Context, stdout io.Reader, display io.Writer, logFile io.Writer, rawLog io.Writer, monitor *costMonitor, cancel func()) (*StreamResult, error) {
-	scanner := bufio.NewScanner(stdout)
-	scanner.Buffer(make([]byte, 0, 256*1024), 1024*1024)
+// ProcessStreamWithMonitor is ProcessStream with an optional event log, an
+// optional cost monitor, and an optional cancel callback. When the monitor's
+// running cost estimate exceeds its cap, cancel() is invoked (typically
+// cancels the subprocess context, which SIGTERMs claude) and the stream loop
+// exits with ErrCostOverrun. Lines longer than maxLineBytes (0 means
+// DefaultMaxStreamLineBytes) are skipped with a warning instead of aborting.
+func ProcessStreamWithMonitor(ctx context.Context, stdout io.Reader, display io.Writer, logFile io.Writer, rawLog io.Writer, events *EventLog, monitor *costMonitor, cancel func(), maxLineBytes int) (*StreamResult, error) {
+	if maxLineBytes <= 0 {
+		maxLineBytes = DefaultMaxStreamLineBytes
+	}
+	reader := bufio.NewReaderSize(stdout, 256*1024)
 
 	var result StreamResult
 	var textBuf strings.Builder
 	var ss streamState
 	ss.toolsSeen = make(map[string]bool)
+	ss.events = events
+	defer ss.flushText()
+	defer flushDisplay(display)
 
 	var safeRawLog io.Writer
 	if rawLog != nil {
@@ -106,12 +201,22 @@ func ProcessStreamWithMonitor(ctx context.Context, stdout io.Reader, display io.
 
 	var overBudget bool
 
-	for scanner.Scan() {
+	for {
+		line, tooLong, err := readStreamLine(reader, maxLineBytes)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return &result, fmt.Errorf("reading agent output stream: %w", err)
+		}
 		if ctx.Err() != nil {
 			return &result, ctx.Err()
 		}
 
-		line := scanner.Bytes()
+		if tooLong {
+			ux.Warnf("warning: skipped stream line longer than %d bytes (raise max-stream-line-bytes to keep it)\n", maxLineBytes)
+			continue
+		}
 		if safeRawLog != nil {
 			safeRawLog.Write(line)
 			safeRawLog.Write([]byte{'\n'})
@@ -132,6 +237,8 @@ func ProcessStreamWithMonitor(ctx context.Context, stdout io.Reader, display io.
 
 		case "result":
 			handleResultEvent(&event, &result)
+			ss.flushText()
+			events.emit(LogEvent{Type: "result", CostUSD: event.TotalCostUSD, SessionID: event.SessionID})
 
 		case "rate_limit_event":
 			handleRateLimitEvent(&event, &result)
@@ -146,10 +253,6 @@ func ProcessStreamWithMonitor(ctx context.Context, stdout io.Reader, display io.
 		}
 	}
 
-	if err := scanner.Err(); err != nil && !overBudget {
-		return &result, fmt.Errorf("reading agent output stream: %w", err)
-	}
-
 	result.Text = textBuf.String()
 	result.UserQuestions = ss.userQuestions
 	result.ToolsUsed = ss.toolsUsed
@@ -159,6 +262,31 @@ func ProcessStreamWithMonitor(ctx context.Context, stdout io.Reader, display io.
 	return &result, nil
 }
 
+// readStreamLine reads one newline-terminated line, without the newline. A
+// line longer than limit is consumed and discarded, and tooLong is set, so
+// the caller can keep reading the lines after it. io.EOF is returned only
+// once no data remains.
+func readStreamLine(r *bufio.Reader, limit int) (line []byte, tooLong bool, err error) {
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if !tooLong {
+			if len(line)+len(bytes.TrimSuffix(chunk, []byte{'\n'})) > limit {
+				tooLong = true
+				line = nil
+			} else {
+				line = append(line, chunk...)
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err == io.EOF && (len(line) > 0 || tooLong) {
+			err = nil
+		}
+		return bytes.TrimSuffix(line, []byte{'\n'}), tooLong, err
+	}
+}
+
 // streamEvent is the top-level JSON structure from stream-json output.
 type streamEvent struct {
 	Type      string          `json:"type"`
@@ -274,6 +402,7 @@ func handleStreamEventWithMonitor(event *streamEvent, textBuf *strings.Builder,
 		case "text_delta":
 			text := nested.Delta.Text
 			textBuf.WriteString(text)
+			ss.textChunk.WriteString(text)
 			if display != nil {
 				fmt.Fprint(display, text)
 			}
@@ -289,6 +418,8 @@ func handleStreamEventWithMonitor(event *streamEvent, textBuf *strings.Builder,
 		}
 
 	case "content_block_stop":
+		ss.flushText()
+		flushDisplay(display)
 		if ss.toolName != "" {
 			if ss.toolName == "AskUserQuestion" {
 				var input struct {
@@ -314,6 +445,7 @@ func handleStreamEventWithMonitor(event *streamEvent, textBuf *strings.Builder,
 				fmt.Fprint(logFile, "\n")
 			}
 			ux.ToolUse(ss.toolName, summary)
+			ss.events.emit(LogEvent{Type: "tool_use", Tool: ss.toolName, Input: summary})
 			if logFile != nil {
 				fmt.Fprintf(logFile, "⚡ %s %s\n", ss.toolName, summary)
 			}
@@ -324,6 +456,15 @@ func handleStreamEventWithMonitor(event *streamEvent, textBuf *strings.Builder,
 	}
 }
 
+// flushDisplay writes out a partial line held by a line-buffered display
+// (ux.StreamOutput), so a finished text block or stream is shown in full
+// before anything else prints.
+func flushDisplay(display io.Writer) {
+	if f, ok := display.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+}
+
 // toolUseSummary extracts the most informative field from accumulated tool input JSON.
 func toolUseSummary(toolName, rawJSON string) string {
 	if rawJSON == "" {
@@ -378,6 +519,9 @@ func toolUseSummary(toolName, rawJSON string) string {
 // If the CLI ever changes to emit incremental deltas across multiple result
 // events, this function must switch to accumulation (+=).
 func handleResultEvent(event *streamEvent, result *StreamResult) {
+	if event.IsError && isOverloadMessage(string(event.Result)) {
+		result.Overloaded = true
+	}
 	if event.TotalCostUSD > 0 {
 		result.CostUSD = event.TotalCostUSD
 	}
@@ -433,3 +577,15 @@ func handleRateLimitEvent(event *streamEvent, result *StreamResult) {
 		}
 	}
 }
+
+// overloadRe matches an API overload: the overloaded_error type or
+// "Overloaded" message, or a 529 that is reported as a status code ("API
+// Error: 529", "status 529", "HTTP 529") — not any 529 in the text, which
+// could be a line number, PR, or byte count.
+var overloadRe = regexp.MustCompile(`(?i)\boverloaded(?:_error)?\b|\b(?:api error|status(?: code)?|http)\s*[: ]\s*529\b`)
+
+// isOverloadMessage reports whether an error message from claude describes
+// an API overload (HTTP 529 / overloaded_error) rather than a task failure.
+func isOverloadMessage(msg string) bool {
+	return overloadRe.MatchString(msg)
+}
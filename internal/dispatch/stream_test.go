@@ -1,10 +1,16 @@
 package dispatch
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
+	"io"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/jorge-barreto/orc/internal/ux"
 )
 
 func streamLines(lines ...string) *bytes.Reader {
@@ -42,6 +48,41 @@ func TestProcessStream_TextDeltas(t *testing.T) {
 	}
 }
 
+// writeRecorder records each Write the display receives.
+type writeRecorder struct{ writes []string }
+
+func (w *writeRecorder) Write(b []byte) (int, error) {
+	w.writes = append(w.writes, string(b))
+	return len(b), nil
+}
+
+func TestProcessStream_LineBufferedDisplayFlushesAtNewlines(t *testing.T) {
+	input := streamLines(
+		`{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"Hel"}}}`,
+		`{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"lo\nwor"}}}`,
+		`{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"ld\nmore\npart"}}}`,
+		`{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"ial"}}}`,
+		`{"type":"stream_event","event":{"type":"content_block_stop"}}`,
+		`{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"tail"}}}`,
+	)
+
+	rec := &writeRecorder{}
+	var log bytes.Buffer
+	if _, err := ProcessStream(context.Background(), input, ux.NewLineWriter(rec), &log, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Whole lines as they complete; a partial line when its block stops,
+	// and at the end of the stream.
+	want := []string{"Hello\n", "world\nmore\n", "partial", "tail"}
+	if !reflect.DeepEqual(rec.writes, want) {
+		t.Fatalf("display writes = %q, want %q", rec.writes, want)
+	}
+	if log.String() != "Hello\nworld\nmore\npartialtail" {
+		t.Fatalf("log = %q", log.String())
+	}
+}
+
 func TestProcessStream_ToolUseEvent(t *testing.T) {
 	input := streamLines(
 		// Tool use: content_block_start -> input_json_deltas -> content_block_stop
@@ -65,6 +106,46 @@ func TestProcessStream_ToolUseEvent(t *testing.T) {
 	}
 }
 
+func TestProcessStream_EventLog(t *testing.T) {
+	input := streamLines(
+		`{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"Reading "}}}`,
+		`{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"the file"}}}`,
+		`{"type":"stream_event","event":{"type":"content_block_stop"}}`,
+		`{"type":"stream_event","event":{"type":"content_block_start","content_block":{"type":"tool_use","name":"Read","input":{}}}}`,
+		`{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"input_json_delta","partial_json":"{\"file_path\":\"main.go\"}"}}}`,
+		`{"type":"stream_event","event":{"type":"content_block_stop"}}`,
+		`{"type":"result","total_cost_usd":0.02,"session_id":"s1","usage":{"input_tokens":10,"output_tokens":5},"permission_denials":[]}`,
+	)
+
+	var sink bytes.Buffer
+	_, err := ProcessStreamWithMonitor(context.Background(), input, nil, nil, nil, NewEventLog(&sink, "implement"), nil, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events []LogEvent
+	for _, line := range strings.Split(strings.TrimSpace(sink.String()), "\n") {
+		var ev LogEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("invalid event line %q: %v", line, err)
+		}
+		events = append(events, ev)
+	}
+	want := []LogEvent{
+		{Type: "text", Phase: "implement", Text: "Reading the file"},
+		{Type: "tool_use", Phase: "implement", Tool: "Read", Input: "main.go"},
+		{Type: "result", Phase: "implement", CostUSD: 0.02, SessionID: "s1"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Fatalf("events[%d] = %+v, want %+v", i, events[i], want[i])
+		}
+	}
+}
+
 func TestProcessStream_PermissionDenials(t *testing.T) {
 	input := streamLines(
 		`{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"trying..."}}}`,
@@ -471,6 +552,62 @@ func TestProcessStream_MultipleResultEventsUpdatesHigherValues(t *testing.T) {
 	}
 }
 
+func TestProcessStream_LineLongerThanOldBuffer(t *testing.T) {
+	// A 2MB tool result used to exceed the scanner's 1MB cap and abort the stream.
+	big := strings.Repeat("x", 2*1024*1024)
+	input := streamLines(
+		`{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"`+big+`"}}}`,
+		`{"type":"result","total_cost_usd":0.01,"session_id":"s1","usage":{"input_tokens":1,"output_tokens":1},"permission_denials":[]}`,
+	)
+
+	result, err := ProcessStream(context.Background(), input, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Text) != len(big) {
+		t.Fatalf("Text length = %d, want %d", len(result.Text), len(big))
+	}
+	if result.SessionID != "s1" {
+		t.Fatalf("SessionID = %q, want s1", result.SessionID)
+	}
+}
+
+func TestProcessStream_SkipsLineOverLimit(t *testing.T) {
+	input := streamLines(
+		`{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"kept"}}}`,
+		`{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"`+strings.Repeat("y", 4096)+`"}}}`,
+		`{"type":"result","total_cost_usd":0.03,"session_id":"s2","usage":{"input_tokens":1,"output_tokens":1},"permission_denials":[]}`,
+	)
+
+	var rawLog bytes.Buffer
+	result, err := ProcessStreamWithMonitor(context.Background(), input, nil, nil, &rawLog, nil, nil, nil, 1024)
+	if err != nil {
+		t.Fatalf("over-long line should be skipped, got error: %v", err)
+	}
+	if result.Text != "kept" {
+		t.Fatalf("Text = %q, want %q", result.Text, "kept")
+	}
+	if result.SessionID != "s2" || result.CostUSD != 0.03 {
+		t.Fatalf("result event after the long line was not parsed: %+v", result)
+	}
+	if strings.Contains(rawLog.String(), "yyyy") {
+		t.Fatal("skipped line should not reach the raw log")
+	}
+}
+
+func TestReadStreamLine_NoTrailingNewline(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("first\nlast"))
+	for _, want := range []string{"first", "last"} {
+		line, tooLong, err := readStreamLine(r, 64)
+		if err != nil || tooLong || string(line) != want {
+			t.Fatalf("readStreamLine = %q, %v, %v; want %q", line, tooLong, err, want)
+		}
+	}
+	if _, _, err := readStreamLine(r, 64); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
 func TestProcessStream_RawLogReceivesAllLines(t *testing.T) {
 	lines := []string{
 		`{"type":"stream_event","event":{"type":"content_block_delta","delta":{"type":"text_delta","text":"Hello"}}}`,
@@ -726,3 +863,22 @@ func TestProcessStream_RawLogWriteError(t *testing.T) {
 		t.Fatalf("CostUSD = %f, want 0.01", result.CostUSD)
 	}
 }
+
+func TestIsOverloadMessage(t *testing.T) {
+	for msg, want := range map[string]bool{
+		`API Error: 529 {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`: true,
+		"API Error: 529":                          true,
+		"request failed with status code 529":     true,
+		"HTTP 529 from upstream":                  true,
+		"Overloaded":                              true,
+		"fixed the bug at handler.go:529":         false,
+		"see PR #529 for context":                 false,
+		"wrote 15291 bytes":                       false,
+		"exit status 1: test failed at line 529":  false,
+		"the queue was not overloadedness-tested": false,
+	} {
+		if got := isOverloadMessage(msg); got != want {
+			t.Errorf("isOverloadMessage(%q) = %v, want %v", msg, got, want)
+		}
+	}
+}
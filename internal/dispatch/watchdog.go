@@ -0,0 +1,56 @@
+package dispatch
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/jorge-barreto/orc/internal/config"
+)
+
+// InactivityTimeout returns how long phase may go without producing any
+// output before the watchdog cancels it. Zero disables the watchdog.
+func InactivityTimeout(phase config.Phase) time.Duration {
+	return time.Duration(phase.InactivityTimeout) * time.Minute
+}
+
+// watchdog cancels a context when nothing has been written to it for idle.
+// It is an io.Writer so it can sit alongside the log and terminal writers
+// that a phase's output already fans out to; every write resets the timer.
+type watchdog struct {
+	idle  time.Duration
+	timer *time.Timer
+	fired atomic.Bool
+}
+
+// newWatchdog derives a context from ctx that is cancelled once idle passes
+// without a write to the returned watchdog. The returned stop func releases
+// the timer and the context. A zero idle disables the watchdog: the context
+// is only cancelled by stop or ctx, and writes are no-ops.
+func newWatchdog(ctx context.Context, idle time.Duration) (context.Context, *watchdog, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	w := &watchdog{idle: idle}
+	if idle <= 0 {
+		return ctx, w, cancel
+	}
+	w.timer = time.AfterFunc(idle, func() {
+		w.fired.Store(true)
+		cancel()
+	})
+	return ctx, w, func() {
+		w.timer.Stop()
+		cancel()
+	}
+}
+
+func (w *watchdog) Write(p []byte) (int, error) {
+	if w.timer != nil && len(p) > 0 && !w.fired.Load() {
+		w.timer.Reset(w.idle)
+	}
+	return len(p), nil
+}
+
+// Fired reports whether the watchdog cancelled its context for inactivity.
+func (w *watchdog) Fired() bool {
+	return w.fired.Load()
+}
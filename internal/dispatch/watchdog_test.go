@@ -0,0 +1,52 @@
+package dispatch
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWatchdog_FiresWhenWriterGoesSilent(t *testing.T) {
+	ctx, wd, stop := newWatchdog(context.Background(), 50*time.Millisecond)
+	defer stop()
+
+	// Keep writing for well past the idle window: the watchdog must not fire.
+	for i := 0; i < 6; i++ {
+		time.Sleep(20 * time.Millisecond)
+		wd.Write([]byte("tick\n"))
+	}
+	if ctx.Err() != nil || wd.Fired() {
+		t.Fatal("watchdog fired while output was still flowing")
+	}
+
+	// Go silent.
+	select {
+	case <-ctx.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("watchdog did not fire after the writer went silent")
+	}
+	if !wd.Fired() {
+		t.Fatal("Fired() = false after inactivity cancellation")
+	}
+}
+
+func TestWatchdog_StopIsNotInactivity(t *testing.T) {
+	ctx, wd, stop := newWatchdog(context.Background(), time.Hour)
+	stop()
+	<-ctx.Done()
+	if wd.Fired() {
+		t.Fatal("Fired() = true after stop")
+	}
+}
+
+func TestWatchdog_ZeroDisables(t *testing.T) {
+	ctx, wd, stop := newWatchdog(context.Background(), 0)
+	defer stop()
+	if n, err := wd.Write([]byte("x")); n != 1 || err != nil {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if ctx.Err() != nil || wd.Fired() {
+		t.Fatal("disabled watchdog cancelled its context")
+	}
+}
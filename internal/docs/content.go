@@ -16,7 +16,7 @@ var topics = []Topic{
 	{
 		Name:    "phases",
 		Title:   "Phase Types",
-		Summary: "Script, agent, and gate phase details",
+		Summary: "Script, agent, gate, manual, and publish phase details",
 		Content: topicPhases,
 	},
 	{
@@ -79,7 +79,7 @@ const topicQuickstart = `Quick Start
    template is used instead.
 
 2. Edit .orc/config.yaml to define your workflow. A workflow is a list
-   of phases — each phase is a script, agent, gate, workflow, or branch.
+   of phases — each phase is a script, agent, gate, manual, workflow, or branch.
 
 3. Preview the plan without executing:
 
@@ -98,13 +98,55 @@ CLI Flags
 
   orc run <ticket>              Run the workflow
   orc run <ticket> --auto       Skip human gate phases
-  orc run <ticket> --dry-run    Preview phase plan
+  orc run <ticket> --dry-run    Preview phase plan, with an estimated prompt
+                                cost per agent phase (~4 chars/token, input
+                                tokens only — real runs cost more); warns
+                                about prompts over max-prompt-bytes
+  orc run <ticket> --dry-run --eval-conditions
+                                Also run each phase's condition and mark
+                                it "would run" or "would skip"
+  orc run <ticket> --confirm    Show phases, estimated cost, and gates, then
+                                ask y/n before running (--yes answers yes)
+  orc run <ticket> --prompt-only <phase>  Print the claude command for an agent phase
+                                          (prompt saved to a temp file), its working
+                                          directory, and env changes, without running it
   orc run <ticket> --retry <phase>    Retry from phase (number or name)
   orc run <ticket> --from <phase>     Start from phase (number or name)
+  orc run <ticket> --from-feedback    Restart at the loop.goto target of the phase
+                                      whose feedback is on disk
+  orc run <ticket> --bisect <good>..<bad>  Find which phase after good introduces
+                                      the failure at bad, re-running from saved outputs
+  orc run <ticket> --bisect-run <run-id>  Seed --bisect trials from this history run
   orc run <ticket> --resume        Resume interrupted agent phase session
+  orc run <ticket> --resume-gate   Re-answer the gate that stopped the run
   orc run <ticket> --step          Step through phases interactively
   orc run <ticket> --headless     Non-interactive mode — JSONL output, implies --auto, --no-color
+  orc run <ticket> --compact      One line per phase; streamed output only goes to the logs
+  orc run <ticket> --prefix-output  Prefix streamed output lines with [phase-name]
+  orc run <ticket> --raw-stream   Show agent text delta by delta, not line by line
+  orc run <ticket> --interactive-run  Full-screen phase board, output pane, and
+                                      keys for gates and steering
+  orc run <ticket> --replay <file>  Replay a recorded stream-json file instead of invoking claude
+  orc run <ticket> --record <dir>   Save raw claude stdout per agent phase for later replay
+  orc run <ticket> --no-stream    Use plain text claude output instead of stream-json
+  orc run <ticket> --log-format json  Also write parsed agent events as JSON lines
+  orc run <ticket> --events-pipe <path>  Stream live JSONL events to a named pipe (or --events-fd <n>)
+  orc run <ticket> --metrics-addr :9090  Serve Prometheus-style metrics at /metrics during the run
+  orc run <ticket> --max-phases <n>  Cap total phase dispatches in one run (default 1000)
+  orc run <ticket> --workflow-retries <k>  Start a failed run over from phase 1, up to k times
+  orc run <ticket> --keep-going   Let parallel branches finish and report every failure
+  orc run <ticket> --auto-parallel  Run adjacent independent phases as parallel pairs
+  orc run <ticket> --labels slow,db  Run only phases carrying one of these labels
+  orc run <ticket> --phase-output-dir  Put each phase's outputs in outputs/<phase>/
+  orc run <ticket> --phase-prefix build-  Prefix phase logs/prompts, feedback and state files (global flag)
+  orc run <ticket> --prompt plan=alt.md  Use a different prompt file for one agent phase
+  orc run <ticket> --var ENV=staging  Set or override a custom variable for this run
+  orc run <ticket> --agent-timeout 5m  Override every agent phase's timeout for this run
+  orc run <ticket> --script-timeout 90s  Override every script phase's timeout for this run
   orc flow                        Visualize workflow as a flow diagram
+  orc list                        Table of phases with resolved model, timeout, outputs
+  orc config --resolved           Print the config with all defaults applied
+  orc upgrade-config              Migrate the config to the current config-version
   orc run -w bugfix <ticket>    Run a named workflow (multi-workflow projects)
   orc flow -w bugfix            Flow diagram for a specific workflow
   orc --no-color flow             Flow diagram without color (flag works on any command)
@@ -114,13 +156,30 @@ CLI Flags
   orc history                   List past runs for most recent ticket
   orc history <ticket>          List past runs for a specific ticket
   orc history --prune           Remove history beyond the configured limit
+  orc timing <ticket> --format csv  Export per-phase timing (csv or json)
+  orc get <ticket>              Print the paths of the run's deliverables
+  orc get <ticket> --copy <dir>  Copy the deliverables into a directory
+  orc open [ticket] <artifact>  Open an output or phase log in the default viewer
   orc status <ticket>           Show workflow status for a ticket
   orc report                    Generate a run report (most recent ticket)
   orc report <ticket>           Report for a specific ticket
   orc report --json             Structured JSON output
   orc doctor <ticket>           Diagnose a failed run using AI
+  orc doctor <ticket> --diff    Include config changes since the last successful run
+  orc doctor <ticket> --no-ai   Print failure context without calling AI (alias --explain-failure)
+  orc doctor <ticket> --json    Structured diagnosis as JSON for scripts and CI
+  orc doctor <ticket> --brief   Few-line diagnosis for quick triage (alias --summary-only)
+  orc doctor --env              Runtime environment report (versions, PATH, config)
   orc init                      Initialize .orc/ directory (AI-powered)
   orc init "description"        Guide AI generation with a description
+  orc init --interactive        Confirm the detected project type, test command,
+                                and ticket pattern before generating
+  orc init --dump-context       Print the gathered project context and full init
+                                prompt without calling claude (redirect to save)
+  orc init --skip-dir <dir>     Leave a directory out of the gathered project tree
+                                (name or project-relative path; repeatable)
+  orc init --include-file <glob>  Also send this file or glob's content as project
+                                  context (project-relative; repeatable)
   orc init --recipe <name>      Scaffold from a recipe (simple, standard, full-pipeline, review-loop)
   orc init --list-recipes       Show available recipes with descriptions
   orc docs                      List documentation topics
@@ -137,6 +196,12 @@ They are mutually exclusive. Both reset loop counts.
 phase. Mutually exclusive with --retry and --from. If the session has expired,
 falls back to a fresh start automatically.
 
+--resume-gate re-runs only the gate at the saved phase index — use it after
+rejecting a gate by mistake. Approving continues the workflow from the next
+phase without re-running earlier phases or resetting loop counts. Fails with
+exit code 6 if the run is not stopped at a gate. Mutually exclusive with
+--resume, --retry, and --from; incompatible with --auto and --headless.
+
 --step pauses after each phase with an interactive prompt (continue,
 rewind, abort, or inspect artifacts). Incompatible with --auto.
 
@@ -156,6 +221,156 @@ primary status signal. Incompatible with --step.
 
 Activate via flag (--headless) or env var (ORC_HEADLESS=1).
 
+--compact replaces the phase headers with a single line per phase:
+
+  [3/8] test ... ok (0m 04s)
+  [4/8] lint ... skipped (condition not met)
+  [5/8] review ... failed: exit code 1
+
+Streamed agent text, tool calls, and script/hook stdout are kept off the
+terminal but are still written to logs/phase-N.log. Stderr and prompts
+(gates, permission approvals) still appear. On a terminal the line for the
+running phase is shown as "[3/8] test ..." and completed in place.
+--headless takes precedence.
+
+--prefix-output prefixes every line of streamed agent, script, and hook
+output (stdout and stderr) with "[phase-name] ", so a log captured from an
+--auto run in CI shows which phase printed what — including the two
+branches of a parallel group. logs/phase-N.log and feedback are unchanged.
+
+Streamed agent text is line-buffered on the terminal: each line is written
+once it is complete, and a trailing partial line is flushed when its text
+block ends, so output doesn't arrive in uneven bursts of tiny writes.
+--raw-stream writes every text delta as it arrives instead. Logs are
+written the same either way.
+
+--interactive-run takes over the terminal for the run. At the top is a
+board of every phase — ▶ running, ✓ passed, ✗ failed, – skipped,
+○ pending — with run and per-phase timing, cost, and how many times a
+looped phase has run. Below it, a pane scrolls the output of the running
+phase (colors removed), and at the bottom is an input line with a hint for
+the current phase. A line typed there and sent with Enter goes to the
+running phase as if typed at its prompt: steering for an agent, feedback
+for a gate, the answer to a manual step. At a gate, y approves and a
+approves it and every later gate (a require-phrase gate needs the phrase
+typed). Ctrl-C interrupts as usual. When the run ends the screen is
+restored and the last output, the final board, and the run summary are
+printed. When stdout is not a terminal orc prints a note and uses plain
+output; when stdin isn't one, the board is printed inline as each phase
+starts instead. Mutually exclusive with --headless and --compact.
+
+--replay <file> feeds a previously captured stream-json file (such as a
+logs/phase-N.stream.jsonl saved by --verbose) to every agent phase instead
+of spawning claude. The recording goes through the same stream parser and
+display as a live run, so parser bugs are reproducible and demos work
+offline. Replayed turns always exit 0; claude need not be installed.
+
+--record <dir> tees the exact bytes claude writes to stdout into
+<dir>/phase-N.jsonl before any parsing — attach these when filing parser
+bugs, or pass one to --replay. Every turn of a phase appends to the same
+file. Independent of the parsed logs/phase-N.log. Mutually exclusive
+with --replay.
+
+--no-stream is a fallback for claude CLI versions or sandboxes where
+stream-json or --include-partial-messages misbehave. claude is invoked
+with --output-format text, and each turn's output is shown and written
+to logs/phase-N.log in one piece when the turn ends. Plain text carries
+no cost, tool-use, or permission-denial events, so cost tracking and
+tool approval prompts do not apply, and a config that sets max-cost (for
+the run or an agent phase) is rejected. A turn is silent until it ends,
+so agent phases' inactivity-timeout is not applied. Mutually exclusive
+with --replay.
+
+--log-format json writes the parsed agent stream to
+logs/phase-N.events.jsonl alongside the plain-text phase-N.log, one
+normalized event per line:
+
+  {"type":"text","phase":"implement","text":"Reading the handler..."}
+  {"type":"tool_use","phase":"implement","tool":"Read","input":"main.go"}
+  {"type":"result","phase":"implement","cost_usd":0.42,"session_id":"..."}
+
+Consecutive text chunks are merged into one text event. Event logs are
+archived to the audit directory with the other phase logs. The default
+is --log-format text.
+
+--events-pipe <path> and --events-fd <n> stream events live to a named
+pipe (or file) or an already-open file descriptor, for TUIs and
+dashboards. They are mutually exclusive. Opening a FIFO waits until a
+reader attaches. The stream carries runner lifecycle events plus every
+agent event above, one JSON object per line:
+
+  {"type":"phase_start","phase":"test","index":3}
+  {"type":"phase_end","phase":"test","index":3,"status":"failed","exit_code":1,"duration_secs":12.4}
+  {"type":"phase_skip","phase":"docs","index":4,"reason":"condition not met"}
+  {"type":"run_end","status":"failed","exit_code":1}
+
+Fields: type (text, tool_use, result, phase_start, phase_end, phase_skip,
+run_end), phase, index (1-indexed), tool, input, text, status (phase_end:
+ok or failed; run_end: completed, failed, or interrupted), exit_code,
+duration_secs, cost_usd, session_id, reason (phase_skip: "condition not
+met", "condition timed out after Ns", "disabled", "not selected by --labels",
+"jumped over by <gate>'s on-reject goto", or "between parallel phases <a> and <b>"), and workflow
+(set for named workflows and sub-workflows). Zero-valued fields are omitted. Parallel phases share
+the stream; lines never interleave. This works with either --log-format.
+
+--metrics-addr <addr> serves Prometheus text-format metrics at
+http://<addr>/metrics while the run is active, for teams running orc as
+part of a service. The counters are fed by the same runner events:
+orc_phases_run_total, orc_phase_failures_total, orc_phases_skipped_total,
+and orc_loop_iterations_total (each labelled by phase), orc_cost_usd_total,
+orc_run_duration_seconds, and orc_run_active (1 until the run ends). The
+server stops when orc exits; an address that can't be bound is a config
+error.
+
+--labels <a,b> runs only the phases whose labels list contains any of the
+given labels, plus the parallel-with partners of those phases so a
+parallel pair always runs together. Every other phase is skipped with the
+reason "not selected by --labels". A label that matches no phase is a
+config error.
+
+Phases that share a group are shown together in the end-of-run summary.
+A group whose phases all passed collapses to a single line ("Setup (3
+phases)") with the summed runs and duration; a group containing the failed
+phase is expanded under a group heading so the failure stays visible.
+
+--confirm prints a short preview before anything executes — workflow
+name, ticket, enabled phase count, the dry-run prompt-cost estimate, and
+the gates that will stop for input — then asks "Proceed? [y/N]". Anything
+but y or yes cancels the run without touching the artifacts directory and
+exits 5 (interrupted), so a script that pipes in an answer can tell a
+declined run from a finished one. --yes, --auto, and --headless skip the
+prompt.
+
+--max-phases <n> is a safety cap on the total number of phase dispatches
+in one invocation, counting every loop iteration and both branches of a
+parallel group. It is a guard against pathological jump cycles, separate
+from per-phase loop.max. When the cap is reached the run stops with
+"dispatch cap exceeded — possible loop" and exit code 1. Default: 1000.
+
+--workflow-retries <k> (or top-level workflow-retries) re-runs the whole
+workflow when a run ends failed: the failed attempt is archived to
+history, state and loop counts start fresh, and the run begins again at
+phase 1 — up to k more times. Only phase failures are retried (script or
+agent failure, loop exhaustion, missing or invalid outputs, failed verify);
+gate rejections, timeouts, cost overruns, rate limits and interrupts end
+the run. Unlike a phase's on-fail, nothing is targeted; use it for flaky
+end-to-end workflows. Costs and timing in the audit dir accumulate across
+attempts, so max-cost caps the run as a whole.
+
+--prompt <phase>=<path> swaps in a different prompt file for one agent
+phase (by name or number) for this run only — handy for A/B testing a
+prompt without editing the config. Repeat it to override several phases.
+The path is relative to the current directory and must exist. The
+override is rendered like any prompt (variables, feedback) and saved to
+prompts/phase-N.md as usual.
+
+--agent-timeout <dur> and --script-timeout <dur> replace the configured
+timeout of every phase of that type for one run, without editing the
+config — e.g. cap agents at 5m while chasing a hang, leaving scripts alone.
+Durations use Go syntax (90s, 5m, 1h30m). Sub-workflows inherit the
+overrides. A timeout caused by an override names the flag in the failure
+message.
+
 Color Control
 -------------
 
@@ -169,6 +384,37 @@ orc respects the standard NO_COLOR convention (https://no-color.org/):
 --headless disables color and switches to JSONL output. The --no-color
 flag is a global flag — it works on any command (run, flow, status, etc.).
 
+Compact Artifacts
+-----------------
+
+State and audit JSON files (state.json, loop-counts.json, timing.json,
+costs.json, run-result.json, ...) are written indented by default so they
+are easy to read. For high-frequency runs where smaller, faster writes
+matter more, switch to compact JSON:
+
+  --compact-artifacts flag      orc --compact-artifacts run KS-42
+  ORC_COMPACT_ARTIFACTS env var ORC_COMPACT_ARTIFACTS=1 orc run KS-42
+
+Both forms load identically, so compact and indented runs can be mixed.
+
+File Prefix
+-----------
+
+Every command reads and writes the file names prefixed by the selected
+workflow's artifacts-prefix, so status, cancel, timing and the rest find a
+prefixed run's files. --phase-prefix is a global flag: a run started with it
+needs it again on later commands:
+
+  orc run KS-42 --phase-prefix build-
+  orc --phase-prefix build- status KS-42
+
+run-result.json, ORC-README.md and config.snapshot.yaml are prefixed too.
+Declared outputs, history/ and the audit directory are not. When a run
+completes or is archived, only its own prefixed files move into history/;
+another workflow's run in the same directory stays in place. Unprefixed
+outputs go with the unprefixed workflow's run, or with the last run to be
+archived when every workflow is prefixed.
+
 `
 
 const topicConfig = `Configuration Reference
@@ -181,9 +427,18 @@ Top-level fields
 ----------------
 
   name                string    Required. Project name.
+  config-version      int       Config schema version. Written by
+                                'orc upgrade-config'; a version newer than this
+                                orc understands is rejected.
   ticket-pattern      string    Regex for ticket IDs (anchored automatically).
   default-allow-tools list      Tools auto-approved for all agent phases.
                                 Merged with built-in defaults (see 'orc docs phases').
+  tools-file          string    YAML list of tools (relative to project root)
+                                merged into default-allow-tools, for a shared
+                                org-wide tool policy.
+  claude-settings     string    Default claude settings file for all agent phases.
+                                Passed as --settings to claude. Per-phase
+                                claude-settings overrides this.
   model               string    Default model for all agent phases. "opus", "sonnet",
                                 or "haiku". Per-phase model overrides this.
   cwd                 string    Default working directory for script and agent phases.
@@ -193,9 +448,47 @@ Top-level fields
                                 or "high". Per-phase effort overrides this.
   max-cost            float     Per-run cost budget in USD. Workflow stops with
                                 exit code 4 if cumulative cost exceeds this.
+  workflow-retries    int       Start a failed run over from phase 1 up to this
+                                many times (see --workflow-retries). Default 0.
   history-limit       int       Maximum archived runs per ticket. Default 10.
                                 Set to prevent unbounded disk usage.
+  artifacts-gitignore bool      Keep a .gitignore containing "*" in the ticket's
+                                artifacts directory so run outputs are never
+                                committed. Default true.
+  max-stream-line-bytes int     Longest single line of agent stream output kept.
+                                Longer lines (huge tool results) are skipped with
+                                a warning. Default 16777216 (16MB).
+  max-prompt-bytes    int       --dry-run warns about any agent phase whose
+                                rendered prompt is larger. Default 200000.
+  outputs-dir         string    Subdirectory of the artifacts dir that declared
+                                outputs resolve under (e.g. reports). Must be
+                                relative and stay inside the artifacts dir.
+  condition-timeout   int       Default seconds a phase condition may run
+                                before it is killed. Default 60.
+  phase-output-dirs   bool      Resolve each phase's outputs under
+                                outputs/<phase>/ (or <outputs-dir>/<phase>/) so
+                                phases can declare the same filename. Default false.
+  artifacts-prefix    string    Prepended to phase-N.log/phase-N.md, feedback
+                                files and the run files (state.json,
+                                run-result.json, ORC-README.md, ...) so
+                                workflows sharing an artifacts dir don't
+                                clobber each other. See "File Prefix".
+                                Letters, digits, '.', '_' and '-' only.
+  deliverables        []string  The files that are the run's results (final report,
+                                PR link), relative to the artifacts dir. A name that
+                                matches a phase output resolves where that output
+                                does. Retrieve them with 'orc get'.
+  doctor-prompt       string    Markdown file (relative to project root) with
+                                project-specific instructions appended to the
+                                'orc doctor' diagnosis prompt.
   vars                map       Custom variables expanded at startup (declaration order).
+  export-vars         map       Which vars are exported to child processes:
+                                only: [A, B] or except: [C]. Unexported vars still
+                                expand in prompts and other templates. Default:
+                                all are exported.
+  expected-env        list      Environment variable names that phases may reference
+                                without a vars entry (e.g. CI_TOKEN). Used by the
+                                undefined-variable audit (see 'orc docs variables').
   phases              list      Required. Ordered list of phases.
 
 Phase fields
@@ -203,31 +496,88 @@ Phase fields
 
   name             string    Required. Unique phase name. Must be a simple
                              name (no path separators or '.' / '..').
-  type             string    Required. "script", "agent", "gate", "workflow",
-                             or "branch".
+  type             string    Required. "script", "agent", "gate", "manual",
+                             "workflow", "branch", or "publish".
   description      string    Human-readable description.
+  disabled         bool      Skip this phase entirely without deleting it.
+                             See 'orc docs runner'.
+  labels           []string  Free-form tags for run --labels, e.g. [slow, db].
+                             Non-empty, no commas.
+  group            string    Run summary section, e.g. "Setup". A group that
+                             passes collapses to one line; a failing group
+                             is expanded. A group's phases must be listed
+                             together.
   run              string    Shell command (required for script phases).
   prompt           string    Path to prompt template, relative to project root
-                             (required for agent phases).
+                             and not escaping it (required for agent
+                             phases). On gate phases,
+                             the operator message shown at the prompt.
   model            string    "opus" (default), "sonnet", or "haiku" (agent only).
+  fallback-model   string    Model to retry a turn with, once, when the primary
+                             model reports an API overload (agent only). Must
+                             differ from model.
+  on-denial        string    Unattended (--auto) handling of permission
+                             denials (agent only): "ignore" (default; log
+                             them), "fail", or "approve-and-retry" (approve
+                             the denied tools and resume the session once).
+  inject-all-feedback bool   Also prepend feedback already archived to the
+                             audit dir, from phases that later passed (agent
+                             only) — for a triage phase that should see every
+                             failure so far.
   timeout          int       Minutes. Default: 30 (agent), 10 (script).
+                             --agent-timeout/--script-timeout override it per run.
+  inactivity-timeout int     Minutes without any output (stdout, stderr, or
+                             agent stream) before the phase is killed with
+                             "no output for Xm — possible hang". Fails like a
+                             timeout (exit code 2). Agent/script only.
+                             Default 0 (off).
+  secret-env       map       Env vars injected into this phase only, each read
+                             by name from orc's own environment at spawn time:
+                             secret-env: { GITHUB_TOKEN: GH_PAT } gives the
+                             phase $GITHUB_TOKEN = orc's $GH_PAT. Never
+                             inlined, persisted, or shown (--prompt-only prints
+                             the source name). A run fails preflight if a
+                             source variable is unset.
   max-cost         float     Per-phase cost budget in USD (agent only). Workflow
                              stops with exit code 4 if phase cost exceeds this.
-  outputs          list      Expected output filenames in artifacts dir.
+  outputs          list      Expected output filenames in artifacts dir. Append
+                             :json, :yaml, or :markdown (report.json:json) to
+                             also validate the file's content.
+  require-fresh-outputs
+                   bool      Treat outputs last written before the phase
+                             started as missing. Requires outputs.
+  outputs-optional bool      Missing outputs are reported as a warning but
+                             don't re-prompt or fail the phase (e.g. "fix if
+                             needed" phases). Requires outputs.
   condition        string    Shell command; phase skipped if exit code non-zero.
+  condition-timeout int      Seconds the condition may run before it is killed.
+                             Default: top-level condition-timeout, else 60.
+  on-condition-timeout
+                   string    "skip" (default) or "fail" when the condition
+                             times out.
   parallel-with    string    Name of another phase to run concurrently.
+  keep-going       bool      With parallel-with: a failing branch doesn't cancel
+                             its partner; all failures are reported together.
   loop             object    Convergent loop: goto (phase name), min (default 1),
                              max (required), optional check (shell command — if exit
-                             non-zero, treated as failure), and optional on-exhaust
-                             for recovery.
+                             non-zero, treated as failure), optional on-exhaust
+                             for recovery, and optional feedback-history (keep the
+                             last N failures in the feedback file).
   allow-tools      list      Additional tools to approve for this agent phase.
                              Merged with defaults. Only valid on agent phases.
   mcp-config       string    Path to MCP server config file (agent only). Supports
                              variable expansion. Passed as --mcp-config to claude.
                              File need not exist at config validation time (may be
                              produced by a prior phase).
+  claude-settings  string    Path to a claude settings file (agent only), relative
+                             to the project root. Supports variable expansion.
+                             Passed as --settings to claude.
   cwd              string    Working directory for this phase (expanded with vars).
                              Not supported on gate phases.
+  create-cwd       bool      Create the resolved cwd (mkdir -p) before the phase
+                             and its pre-run hook run. Off by default so a typo'd
+                             path still fails loudly. Requires cwd; not valid on
+                             gate or manual phases.
   pre-run          string    Shell command to run before dispatch. Non-zero exit
                              skips dispatch and fails the phase. Post-run still
                              runs. Supports variable expansion.
@@ -235,6 +585,29 @@ Phase fields
                              Runs regardless of dispatch outcome. If post-run fails
                              and dispatch succeeded, phase is marked failed.
                              Supports variable expansion.
+  on-success       string    Shell command to run after post-run, only when the
+                             phase succeeded. Failure only warns by default.
+  on-success-fail  string    "warn" (default) or "fail": whether a failing
+                             on-success command fails the phase.
+  verify           string    Shell command run after a successful dispatch and
+                             output checks. Non-zero exit fails the phase
+                             (category verify_failure) and its output becomes
+                             feedback; with loop, it loops like a failure.
+  require-phrase   string    Exact text the operator must type to approve
+                             (gate only). Replaces "y".
+  on-reject        string    What a rejected gate does (gate only): stop
+                             (default), continue, or {goto: <phase>}.
+  stdin            string    Literal stdin for the script (script only).
+                             Expanded with vars.
+  stdin-file       string    File fed to the script's stdin (script only).
+                             Relative to the artifacts directory.
+  container        string    Image to run the script in (script only), or an
+                             object {image, runtime: docker|podman, volumes}.
+                             See "Containers" under script.
+  success-exit-codes []int   Extra exit codes that count as success (script
+                             only; 0 always does). E.g. [0, 1] for a
+                             "git commit" that may find nothing to commit.
+                             A timeout is a failure whatever its code.
 
 Custom Variables (vars)
 -----------------------
@@ -242,25 +615,47 @@ Custom Variables (vars)
 The vars field is an ordered key-value map at the top level of config.yaml.
 Variables are expanded at startup in declaration order, so later vars can
 reference earlier ones. Custom vars cannot override built-in variables
-(TICKET, WORKFLOW, ARTIFACTS_DIR, WORK_DIR, PROJECT_ROOT). Duplicate names are not
-allowed.
+(TICKET, WORKFLOW, WORKFLOW_NAME, PHASE_DESCRIPTION, PHASE_OUTPUT_DIR,
+ARTIFACTS_DIR, WORK_DIR, PROJECT_ROOT, DELIVERABLES). Duplicate names are
+not allowed. DELIVERABLES became reserved with the publish phase type, so a
+config that declares its own DELIVERABLES var fails validation and must
+rename it.
 
 Validation Rules
 ----------------
 
-- Phase names must be unique.
+- Phase names must be unique, ignoring case ("Test" and "test" collide).
 - Phase names must not contain path separators or be '.' / '..'.
+- Phase names must not have leading or trailing whitespace, and must not
+  contain '+', ':', or control characters (orc uses '+' and ':' in names
+  it derives from phase names).
 - loop.goto must reference an earlier phase (no forward jumps).
 - loop.max is required and means total iterations (not retries).
 - loop.on-exhaust.goto must reference an earlier phase.
 - parallel-with must reference an existing phase.
-- parallel-with and loop cannot be combined on the same phase.
-- Agent phases require a prompt file that exists on disk.
+- Only one phase of a parallel group may declare loop. A group loop
+  supports goto, max, and on-exhaust only (no check or min), and its goto
+  must reference a phase before both partners.
+- Agent phases require a prompt file that exists on disk. The prompt path
+  must stay inside the project root (../ escapes are rejected).
 - Model must be opus, sonnet, haiku, or empty.
 - Output filenames must be simple filenames (no path separators, . or ..).
+  A ":type" suffix must be json, yaml, or markdown.
 - mcp-config is only valid on agent phases.
+- inject-all-feedback is only valid on agent phases.
+- labels must be non-empty strings without commas.
+- Phases sharing a group must be consecutive.
+- claude-settings is only valid on agent phases. The file must exist at
+  config load time unless the path references a variable.
 - Gate phases cannot have a cwd field.
+- on-success must be a non-empty command; on-success-fail must be warn or
+  fail and requires on-success.
+- verify must be a non-empty command.
 - history-limit must not be negative. Defaults to 10 if unset.
+- max-stream-line-bytes must not be negative.
+- max-prompt-bytes must not be negative.
+- workflow-retries must not be negative.
+- outputs-dir must be a relative path inside the artifacts directory.
 
 Example Config
 --------------
@@ -333,6 +728,46 @@ Example:
     condition: test -f Makefile
     cwd: $WORKTREE
 
+Scripts read stdin from /dev/null by default, so a command that waits
+for input sees end-of-file instead of blocking on the terminal. To feed
+input, set stdin (literal text, expanded with vars) or stdin-file (a
+file path, expanded with vars; relative paths resolve against the
+artifacts directory). The two are mutually exclusive.
+
+  - name: apply
+    type: script
+    run: kubectl apply -f -
+    stdin-file: manifest.yaml
+
+Containers: set container to run the script inside an image instead of
+on the host. orc runs
+
+  docker run --rm --name orc-<id> -v <project>:<project> -w <workdir> \
+    [-v <volume>...] -e ORC_TICKET ... <image> bash -c <run>
+
+The project root, the phase's working directory and the artifacts
+directory are mounted at their host paths (one mount when one contains
+the others), and the working directory is the container's, so
+$PROJECT_ROOT, $WORK_DIR, $ARTIFACTS_DIR and declared outputs all resolve
+unchanged inside it. Each container gets a unique name; when the phase is
+cancelled or times out, orc kills the container by that name so it doesn't
+keep running in the background. orc's
+variables — ORC_*, their unprefixed aliases, custom vars, and secret-env
+— are passed through by name; the rest of the host environment is not.
+The image must provide bash, and the runtime binary must be on PATH
+(checked at preflight). Extra volumes are expanded with vars.
+
+  - name: test
+    type: script
+    run: go test ./...
+    container:
+      image: golang:1.22
+      runtime: podman          # default: docker
+      volumes:
+        - $HOME/go/pkg/mod:/go/pkg/mod
+
+The shorthand container: golang:1.22 uses docker with no extra volumes.
+
 agent
 -----
 
@@ -341,7 +776,9 @@ Reads a prompt template file, expands variables, and invokes:
   claude -p <prompt> --model <model> --allowedTools <tools...>
 
 Output is streamed to the terminal and saved to .orc/artifacts/<ticket>/logs/phase-N.log.
-The rendered prompt is saved to .orc/artifacts/<ticket>/prompts/phase-N.md.
+The rendered prompt is saved to .orc/artifacts/<ticket>/prompts/phase-N.md,
+with a per-attempt copy at prompts/phase-N-attempt-M.md so each loop-back's
+prompt (including injected feedback) can be compared.
 
 Tool Permissions
 ~~~~~~~~~~~~~~~~
@@ -357,10 +794,68 @@ Additional tools can be approved at two levels:
   allow-tools            Per-phase config. Applied to a single phase.
                          Use for phase-specific tools like Bash.
 
+To share one tool policy across repos, keep it in a YAML list and point
+the top-level tools-file at it (a symlink works too):
+
+    tools-file: .orc/allowed-tools.yaml
+
+    # .orc/allowed-tools.yaml
+    - mcp__github__*
+    - "Bash(git *)"
+
+Its entries are appended to default-allow-tools at load time (skipping
+ones already listed) and validated the same way.
+
+Entries can be scoped to specific tool inputs with claude's Tool(pattern)
+syntax, e.g. approve Bash only for git commands:
+
+    allow-tools:
+      - "Bash(git *)"
+      - "Bash(make test)"
+
+Scoped entries are passed to claude verbatim. The pattern must be
+non-empty and nothing may follow the closing parenthesis.
+
 All lists are merged and deduplicated. In attended mode (without --auto),
 if the agent attempts a tool that wasn't pre-approved, orc prompts you
 to approve it for the remainder of that phase.
 
+In unattended mode (--auto) there is no one to ask, so the phase's
+on-denial policy decides:
+
+  ignore              Default. Log the denials and finish the phase with
+                      whatever the agent produced.
+  fail                Log the denials and fail the phase (its loop or
+                      on-fail handling applies as for any failure).
+  approve-and-retry   Approve the denied tools and resume the session
+                      once, as if you had answered "y". Denials on the
+                      retry are logged and not retried again.
+
+approve-and-retry lets an agent use any tool it asks for, once denied.
+Prefer listing known tools in allow-tools.
+
+Turn Separators
+~~~~~~~~~~~~~~~
+
+In attended mode every agent turn starts with a separator line in
+logs/phase-N.log giving the turn number, what started it, and a UTC
+timestamp:
+
+  --- turn 1 · initial prompt: phase-3.md · 2026-10-16T09:10:41Z ---
+  --- turn 2 · user steering: also update the docs · 2026-10-16T09:12:03Z ---
+  --- turn 3 · tools approved: Bash · 2026-10-16T09:12:40Z ---
+
+Other labels are "resumed session: <id>" and "user answer: <text>".
+
+Extending the Timeout
+~~~~~~~~~~~~~~~~~~~~~
+
+If an agent is making good progress as its timeout approaches, type
+"extend <minutes>" (e.g. extend 15) in attended mode. The phase's deadline
+moves back by that many minutes, taking effect immediately — even in the
+middle of a turn. The command is not sent to the agent as steering, and the
+extension is noted in the phase log.
+
 Agent Questions
 ~~~~~~~~~~~~~~~
 
@@ -420,6 +915,18 @@ Example:
       goto: plan
       max: 3
 
+Claude Settings
+~~~~~~~~~~~~~~~
+
+To reuse an existing claude settings file or permissions profile, point
+agent phases at it with claude-settings (top-level or per-phase):
+
+  claude-settings: .claude/orc-settings.json
+
+orc passes --settings <path> to claude -p. Relative paths resolve against
+the project root. Paths without variables are checked at config load
+time; paths that reference variables are expanded at dispatch time.
+
 Hooks (pre-run / post-run)
 --------------------------
 
@@ -439,9 +946,36 @@ post-run runs after dispatch regardless of outcome. If post-run fails and
 dispatch succeeded, the phase is marked failed. If dispatch already failed,
 post-run failure is logged as a warning.
 
-Both hooks:
+on-success runs last, and only when the phase succeeded (post-run
+included) — e.g. tag the commit once tests pass:
+
+  - name: test
+    type: script
+    run: make test
+    on-success: git tag "passed-$TICKET"
+
+If on-success exits non-zero, orc prints a warning and the phase still
+passes. Set on-success-fail: fail to fail the phase instead.
+
+verify asserts a postcondition richer than "the outputs exist" — it runs
+after a successful dispatch and the output checks, and a non-zero exit
+fails the phase. Its output is written to feedback/from-<phase>.md, so with
+a loop the retried phase sees why verification failed. In a parallel
+group with a loop, a failed verify on either branch retries the group and
+its output joins the group's feedback, like a failed branch:
+
+  - name: report
+    type: agent
+    prompt: .orc/phases/report.md
+    outputs: [report.json]
+    verify: jq empty "$ARTIFACTS_DIR/report.json"
+
+condition runs before a phase; verify runs after it.
+
+All hooks:
 - Variables available as environment variables ($TICKET, $ARTIFACTS_DIR, custom vars, etc.)
 - Run in the phase's cwd (or project root if unset)
+- Get $ORC_PHASE_NAME, the phase's name
 - Do NOT run when condition causes a phase skip
 - In loops, run every iteration
 - In parallel-with, wrap each goroutine's dispatch
@@ -474,18 +1008,66 @@ gate
 
 Prompts the operator for approval at the terminal. The operator can type
 "y" to continue, or any other text to request a revision — the text is
-captured as feedback in the phase log and the workflow stops.
+captured as feedback in the phase log and the workflow stops (see on-reject
+below to continue instead).
+
+Typing "ya" ("yes to all") approves the current gate and auto-approves every
+later gate in the run, including gates in sub-workflows. Agent phases stay
+attended — only gates change. Gates with require-phrase still ask for their
+phrase.
 
 When --auto or --headless is passed, gate phases are automatically approved and skipped.
 
 Gate phases do not support the cwd field.
 
+On a gate, prompt is the operator message itself (not a file) and is
+expanded with variables. require-phrase replaces "y" with an exact,
+case-sensitive confirmation string for high-stakes gates — anything else,
+including "y", is treated as revision feedback.
+
+on-reject decides what happens after a rejection. stop (the default) fails
+the run. continue saves the revision text as feedback and advances to the
+next phase. {goto: <phase>} saves the feedback and jumps to the named phase
+— backward to rework (e.g. goto: implement), or forward, in which case the
+phases in between are marked skipped. The feedback is written to
+feedback/from-<gate>.md and prepended to later agent prompts, as with loops.
+Not valid together with loop or parallel-with.
+
 Example:
 
   - name: review
     type: gate
     description: Review implementation before merging
 
+  - name: deploy-approval
+    type: gate
+    prompt: Approve deployment of $TICKET to prod?
+    require-phrase: DEPLOY
+
+  - name: plan-review
+    type: gate
+    on-reject:
+      goto: plan
+
+manual
+------
+
+A checklist item for work a human must do outside orc. Prints the phase's
+description (expanded with variables) as instructions and waits for the
+operator to type "y" once the step is done. Unlike a gate there is no
+approve/reject: any other input re-prompts, and Ctrl-C stops the run.
+
+When --auto or --headless is passed, manual phases are skipped with a
+notice in the phase log.
+
+description is required. run, prompt, cwd, and parallel-with are not valid.
+
+Example:
+
+  - name: bump-version
+    type: manual
+    description: Bump the version for $TICKET in the release system
+
 workflow
 --------
 
@@ -538,6 +1120,34 @@ Example:
       simple: quick-fix
       complex: full-pipeline
     default: full-pipeline
+
+publish
+-------
+
+Pushes the run's results somewhere else — S3, GCS, an artifact store —
+without orc depending on any cloud SDK. run is a shell command, executed
+like a script phase, that receives the absolute paths of the config's
+deliverables that exist as $DELIVERABLES, one path per line (missing ones
+are left out). Only the publish phase gets $DELIVERABLES. "for f in
+$DELIVERABLES" splits on spaces as well as newlines; for paths that may
+contain spaces, read the list line by line:
+
+  printf '%s\n' "$DELIVERABLES" | while IFS= read -r f; do ...; done
+
+A workflow may have at most one publish phase, and it must be the last
+phase, so it runs once the rest of the workflow has finished. run is
+required; parallel-with is not valid.
+
+Example:
+
+  deliverables:
+    - report.md
+    - pr-url.txt
+  phases:
+    ...
+    - name: upload
+      type: publish
+      run: for f in $DELIVERABLES; do aws s3 cp "$f" s3://reports/$TICKET/; done
 `
 
 const topicVariables = `Template Variables
@@ -547,8 +1157,8 @@ Variables are available in agent prompt templates, script run commands,
 conditions, loop checks, phase cwd fields, and pre-run/post-run hooks
 using $VAR or ${VAR} syntax.
 
-For agent prompt templates, cwd, and mcp-config fields, variables are
-expanded via Go string substitution before use.
+For agent prompt templates, cwd, mcp-config, and claude-settings fields,
+variables are expanded via Go string substitution before use.
 
 For bash-executed fields (run, condition, loop.check, pre-run, post-run),
 variables are set as environment variables in the child process. This means
@@ -563,6 +1173,11 @@ Built-in Variables
   $WORK_DIR        Absolute path to the working directory (project root).
   $PROJECT_ROOT    Absolute path to the project root (where .orc/ lives).
   $WORKFLOW        Current workflow name (empty for single-config projects).
+  $WORKFLOW_NAME   The config's name: field — the overall goal of the workflow.
+  $PHASE_DESCRIPTION  The current phase's description (empty if it has none).
+  $PHASE_OUTPUT_DIR   Absolute directory the current phase's declared outputs
+                      go in: $ARTIFACTS_DIR, its outputs-dir, or its
+                      outputs/<phase>/ subdir with phase-output-dirs.
 
 For Go-expanded fields, if a variable is not in the built-in set or custom
 vars, os.Expand falls back to environment variables. For bash-executed
@@ -583,10 +1198,21 @@ Key behaviors:
   earlier ones (e.g., SRC references WORKTREE above).
 - Available everywhere built-ins are: prompt templates, run commands,
   condition, loop.check, cwd fields, and pre-run/post-run hooks.
-- Cannot override built-in variables (TICKET, WORKFLOW, ARTIFACTS_DIR, WORK_DIR,
-  PROJECT_ROOT). Config validation rejects attempts to do so.
+- Cannot override built-in variables (TICKET, WORKFLOW, WORKFLOW_NAME,
+  PHASE_DESCRIPTION, PHASE_OUTPUT_DIR, ARTIFACTS_DIR, WORK_DIR, PROJECT_ROOT,
+  DELIVERABLES). Config validation rejects attempts to do so.
 - No duplicate variable names allowed.
 
+Pass one-off values at run time with --var KEY=VALUE (repeatable):
+
+  orc run PROJ-123 --var ENV=staging --var REGION=us-east-1
+
+A --var replaces a config var of the same name in place, so vars that
+reference it see the new value; other keys are added after the config
+vars. The same naming rules apply, and the value is exported as ORC_<KEY>
+and KEY like any custom var. The flag splits on commas, so keep list
+values in vars.
+
 Environment Variables (ORC_* prefix)
 ------------------------------------
 
@@ -598,14 +1224,62 @@ these additional ORC_-prefixed variables:
   ORC_ARTIFACTS_DIR    Absolute path to .orc/artifacts/<ticket>/.
   ORC_WORK_DIR         Working directory.
   ORC_PROJECT_ROOT     Project root directory.
+  ORC_WORKFLOW_NAME    The config's name: field.
+  ORC_PHASE_DESCRIPTION  The current phase's description.
+  ORC_PHASE_OUTPUT_DIR   Where the current phase's outputs go.
   ORC_PHASE_INDEX      Current phase index (0-based).
   ORC_PHASE_COUNT      Total number of phases.
+  ORC_DELIVERABLES     Absolute paths of the deliverables that exist, one
+                       per line (also as $DELIVERABLES). Publish phases
+                       only.
+  ORC_PHASE_NAME       The phase's name (pre-run, post-run, and on-success
+                       hooks only).
 
 Custom vars are also exported with an ORC_ prefix. For example, a var
 named WORKTREE becomes ORC_WORKTREE in child processes.
 
+To keep vars out of every subprocess's environment, list them under
+export-vars — either the ones to export or the ones to hold back:
+
+  export-vars:
+    except: [API_HOST]      # or: only: [WORKTREE, SRC]
+
+An unexported var is still expanded wherever orc substitutes variables
+itself (prompt templates, cwd, stdin, mcp-config), but it is absent —
+with and without the ORC_ prefix — from the environment of scripts,
+hooks, conditions, and claude, so a bash-executed $API_HOST is empty.
+Names must be declared under vars. Sub-workflows keep hidden any var
+hidden by either config.
+
 The CLAUDECODE environment variable is stripped from child processes so
 that claude -p can run without nesting conflicts.
+
+Undefined Variable Audit
+------------------------
+
+orc run and orc validate scan run, cwd, condition, pre-run, post-run,
+check, verify, on-success, and loop.check fields plus agent prompt files
+for $NAME and ${NAME} references, and warn about any that are not a
+built-in, an ORC_-prefixed built-in, a custom var, a secret-env name of
+the same phase, or listed under expected-env:
+
+  expected-env: [CI_TOKEN, GITHUB_SHA]
+
+Only upper-case names are audited. Common environment variables (HOME,
+PATH, USER, SHELL, PWD, TMPDIR, LANG, TERM), variables assigned inside
+the same command (FOO=..., for FOO in ...), and ${NAME:-default} forms
+are never reported. Use orc validate --strict to turn warnings into
+errors (exit code 3).
+
+Unreachable Phases
+------------------
+
+orc validate also warns about phases no run can reach from the first
+phase. It follows fall-throughs, loop and on-exhaust gotos, and gate
+on-reject gotos, treating every condition as possibly true. A parallel
+group continues after its later partner, so a phase placed between two
+non-adjacent parallel-with partners is reachable only if some goto
+targets it. These stay warnings under --strict.
 `
 
 const topicRunner = `Execution Model
@@ -626,6 +1300,42 @@ command exits non-zero, the phase is skipped.
     run: make test
     condition: test -f Makefile
 
+A condition that hangs (e.g. curl against a dead host) is killed after
+condition-timeout seconds — 60 unless the phase or the top-level config
+sets it. By default a timed-out condition counts as false: orc prints a
+warning and skips the phase. Set on-condition-timeout: fail to stop the
+run instead (exit code 2, failure category timeout).
+
+  - name: smoke
+    type: script
+    run: ./smoke.sh
+    condition: curl -fsS https://staging.example.com/health
+    condition-timeout: 10
+    on-condition-timeout: fail
+
+orc run <ticket> --dry-run --eval-conditions previews which phases would
+actually run: it evaluates every enabled phase's condition now (with the
+same environment and timeout as a real run) and marks each phase "would
+run" or "would skip", without executing any phase. Conditions should be
+read-only checks, since they really run.
+
+Disabled Phases
+---------------
+
+Set disabled: true to turn a phase off without deleting or commenting it
+out. The runner skips it before evaluating its condition, dry-run and
+orc flow mark it "(disabled)", and orc status lists it as [disabled].
+
+  - name: e2e
+    type: script
+    run: make e2e
+    disabled: true
+
+A disabled phase keeps its number, so phase-N artifact paths and
+$PHASE_COUNT do not shift. It is still fully validated — required fields
+must be present. Loop jumps that target it land on the next enabled
+phase, and a parallel-with partner of a disabled phase runs on its own.
+
 Parallel Execution
 ------------------
 
@@ -643,8 +1353,38 @@ Two phases can run concurrently using parallel-with:
 Both phases start at the same time. If either fails, the other is
 cancelled. After both complete, the runner advances past both phases.
 
-Constraints: parallel-with and loop cannot be combined on the same
-phase.
+To learn about every failure in one run, set keep-going: true on the
+phase that declares parallel-with, or pass --keep-going to orc run to
+apply it to every parallel group. Both branches then run to completion
+and the run fails with all of their errors, e.g.
+"2 parallel phases failed: phase "lint" failed: ...; phase "test" failed: ...".
+
+orc run --auto-parallel infers pairs without parallel-with. Two adjacent
+phases run together when both are enabled script phases that declare
+outputs, neither has loop, condition, or parallel-with, and the second is
+not a goto target. Their outputs must not overlap, and neither phase's
+commands may mention the other's output filenames. Agent phases are never
+paired: an agent edits the work tree, and its partner could run against
+the tree before the edits land. Pairs are chosen from the top and noted on
+stderr when the run starts. Gates and other phase types always stay
+sequential.
+
+A loop on either partner applies to the whole group:
+
+  - name: lint
+    type: script
+    run: make lint
+    parallel-with: test
+    loop:
+      goto: implement
+      max: 3
+
+With a group loop, a failing branch does not cancel its sibling. Once
+both finish, the outputs of every failed branch are combined into one
+feedback file, .orc/artifacts/<ticket>/feedback/from-lint+test.md, and
+the runner jumps to loop.goto. A group loop only retries on failure —
+check and min are not supported, and goto must reference a phase before
+both partners.
 
 Session resume (--resume) is not supported for parallel agent phases;
 if interrupted, they must be re-run from scratch.
@@ -661,6 +1401,7 @@ simple error retry and deliberate quality iteration.
     max: 3                # total iterations before exhaustion (required)
     check: test -f ...    # quality gate command (optional)
     on-exhaust: plan      # outer recovery (optional, string or object)
+    feedback-history: 3   # keep the last 3 failures in feedback (optional)
 
 Failure path: When a phase with loop fails, the failure output is
 written to .orc/artifacts/<ticket>/feedback/from-<phase>.md, the loop
@@ -669,6 +1410,12 @@ next iteration, all feedback files are automatically prepended to
 agent prompts so agents see prior failure context. If the counter
 reaches loop.max, the loop is exhausted.
 
+By default each failure overwrites the feedback file, so the retried
+agent only sees the latest one. Set loop.feedback-history: K to keep the
+failure output of the last K attempts instead, oldest first, each under
+an "## Attempt N" heading — the agent can see what was already tried and
+how it failed.
+
 Success path: When a phase with loop succeeds but the iteration count
 is less than loop.min, the runner forces another iteration (writing
 the output as feedback). Once iteration >= min, the loop breaks and
@@ -771,6 +1518,33 @@ resume point:
 
 Both flags reset loop counts.
 
+  orc run TICKET --from-feedback     Restart where the failed loop points
+
+--from-feedback reads feedback/from-<phase>.md left by the failed run and
+restarts at that phase's loop.goto target (for a parallel group, the
+partner that carries the loop). Only that loop's count is reset; feedback
+stays in place so the restarted phases see it. When several phases left
+feedback, the one the run stopped at wins; otherwise use --retry.
+
+  orc run TICKET --bisect 1..test    Which phase after 1 breaks "test"?
+
+--bisect localizes a regression in a long pipeline. The newest run in
+history/ that completed is taken as known-good saved outputs; name another
+with --bisect-run <run-id> (see orc history). The live artifacts directory
+is not used, since after a failed run it holds that run's outputs. With no
+completed run, --bisect refuses to start. Each trial copies the seed run
+to a scratch directory and runs from some phase through <bad>, like
+--from, so earlier phases are not re-run. Starting right after <good> must
+fail; orc then binary-searches for the latest start that still fails —
+that phase introduces the failure, since starting one phase later passes.
+Trials reset loop counts and use a scratch audit directory, so they never
+touch the live artifacts, history, audit records or state. Phases do
+change the project, so in a git repo --bisect refuses a work tree with
+uncommitted or untracked changes (outside .orc/) and resets it to the
+starting commit after every trial, discarding the trial's edits and
+commits. Trials dispatch their phases for real: each agent phase in a
+trial is paid for again.
+
 Agent Session Resume
 ~~~~~~~~~~~~~~~~~~~~
 
@@ -790,6 +1564,17 @@ using --retry/--from.
 Note: parallel agent phases do not persist session IDs — if a parallel
 agent is interrupted, --resume cannot recover its session.
 
+Re-answering a Gate
+~~~~~~~~~~~~~~~~~~~
+
+A rejected gate stops the run with the phase index still pointing at the
+gate. To answer it again without re-running earlier phases:
+
+  orc run TICKET --resume-gate
+
+Only the gate is re-prompted; approving it continues the workflow. Loop
+counts are preserved and the previous gate-rejection failure is cleared.
+
 Step-Through Mode
 ~~~~~~~~~~~~~~~~~
 
@@ -853,14 +1638,17 @@ Directory Structure
 -------------------
 
   .orc/artifacts/<ticket>/
+  ├── ORC-README.md           What this run is: ticket, workflow, phases, layout
   ├── state.json              Current run state
   ├── timing.json             Start/end timestamps per phase
   ├── costs.json              Per-phase cost and token counts
   ├── loop-counts.json        Loop iteration counters per phase
   ├── run-result.json         Machine-readable run summary
+  ├── config.snapshot.yaml    Copy of the config used for this run
   ├── prompts/
   │   ├── phase-1.md          Rendered prompt for phase 1
   │   ├── phase-2.md          Rendered prompt for phase 2
+  │   ├── phase-2-attempt-1.md  Rendered prompt for each attempt of phase 2
   │   └── ...
   ├── logs/
   │   ├── phase-1.log           Agent output for phase 1
@@ -881,7 +1669,7 @@ Tracks the current phase index, ticket identifier, workflow status
 (running, completed, failed, interrupted), the Claude session ID
 for interrupted agent phases (used by --resume), and for failed/interrupted
 runs, a failure_category (loop_exhaustion, cost_overrun, gate_rejection,
-script_failure, output_missing, interrupted, agent_error, timeout,
+script_failure, output_missing, output_invalid, verify_failure, interrupted, agent_error, timeout,
 state_save, rate_limit) and optional
 failure_detail with a human-readable description. Written atomically
 after every phase.
@@ -924,13 +1712,19 @@ Phase object fields:
   status                  string     "completed", "skipped", "failed", or "pending"
   duration_seconds        float      Wall-clock seconds for this phase (0 if skipped/pending)
   cost_usd                float      Cost in USD (0 for non-agent phases)
+  skip_reason             string     Why a skipped or disabled phase didn't run
+                                     (same values as the phase_skip event)
+
+'orc status <ticket>' lists skipped phases with these reasons.
 
 prompts/
 --------
 
 Rendered prompts for agent phases (after variable expansion). Saved as
 phase-N.md where N is the 1-indexed phase number. Useful for debugging
-what the agent actually received.
+what the agent actually received. Each attempt is also kept as
+phase-N-attempt-M.md (M is 1-indexed), so prompts from earlier loop
+iterations are not lost when the phase is re-run.
 
 logs/
 -----
@@ -948,7 +1742,7 @@ orc report for richer output.
 
 Fields:
   phase_name         string     Phase name from config
-  phase_type         string     "agent", "script", "gate", "workflow", or "branch"
+  phase_type         string     "agent", "script", "gate", "manual", "workflow", or "branch"
   phase_index        int        0-indexed phase number
   model              string     Model used (agent phases only)
   effort             string     Effort level (agent phases only)
@@ -974,6 +1768,16 @@ agents receive prior failure context without needing to manually read
 feedback files. Multiple feedback files are concatenated with headers
 (e.g., "--- Feedback from review ---").
 
+When a phase later passes, its own from-<phase-name>.md (or a parallel
+group's from-<first>+<second>.md) is archived to the audit directory and
+removed, so the directory only holds feedback for failures that are
+still relevant. Feedback from other phases is left in place.
+
+An agent phase with inject-all-feedback: true (e.g. a triage phase) also
+gets the feedback already archived to the ticket's audit directory, under
+"## Earlier feedback in this ticket" at the top of its prompt, oldest
+first. Still-live feedback is appended as usual.
+
 Audit Directory
 ---------------
 
@@ -986,6 +1790,18 @@ When you run orc cancel, the audit directory is preserved by rotating
 to a timestamped name. orc status reads from the audit directory for
 cost and timing data.
 
+For unfinished runs, orc status and orc run also show "est. N min
+remaining": each remaining phase's average duration across completed runs
+archived in history/ for any ticket. No estimate is shown without history.
+From the same averages, a remaining phase that takes at least a minute and
+at least twice as long as the other phases' average is marked
+"[expected long: ~N min]" in orc status, and orc run --dry-run lists such
+phases under "Expected long phases".
+
+The artifacts section of orc status also reports the total size of the
+artifacts directory (including history/) and the largest log file, so a
+runaway log is noticed before it fills the disk.
+
 History Directory
 -----------------
 
@@ -995,6 +1811,10 @@ timestamp (e.g., 2026-03-22T14-30-05.123). Completed runs are archived
 immediately. Failed or interrupted runs stay in place for --resume/--retry,
 and are archived automatically when the next fresh orc run starts.
 
+After a successful run, orc lists every declared output that was produced,
+with its path in the archived run, followed by the next commands to try
+(orc status, orc history).
+
   .orc/artifacts/<ticket>/
   ├── history/
   │   ├── 2026-03-22T14-30-05.123/
@@ -1012,7 +1832,7 @@ history-limit config field (default 10).
 
 If a previous run left stale artifacts (completed, failed, interrupted,
 or killed mid-execution), the next orc run auto-archives them before
-starting. Recovery flags (--resume, --retry, --from) skip auto-archiving
+starting. Recovery flags (--resume, --resume-gate, --retry, --from) skip auto-archiving
 so the prior run's state is preserved.
 
 Declared Outputs
@@ -1021,6 +1841,52 @@ Declared Outputs
 Phases can declare expected output files via the outputs field. These
 files are expected to appear directly in the .orc/artifacts/<ticket>/ directory (not
 in subdirectories). Output filenames must be simple filenames (no path separators, . or ..).
+
+When an agent phase finishes with some outputs missing, orc resumes its
+session once with a single re-prompt. The re-prompt lists only the files
+still missing and names the outputs already present, asking the agent to
+leave those as they are.
+
+An output can carry a content type, so a file that exists but is empty or
+truncated still fails the phase:
+
+  outputs: [report.json:json, config.yaml:yaml, plan.md:markdown]
+
+json and yaml must parse; markdown must contain at least one heading. An
+empty file is never valid. The type is not part of the filename — the
+phase writes report.json. A malformed output fails the phase with
+failure category output_invalid and a message such as
+output "report.json" is not valid JSON: unexpected end of JSON input.
+
+Set the top-level outputs-dir to collect outputs in a conventional
+subdirectory instead. With outputs-dir: reports, a phase declaring
+summary.md must produce $ARTIFACTS_DIR/reports/summary.md. orc creates the
+directory at the start of each run and applies it to the existence check,
+the missing-output re-prompt, loop feedback, and audit archiving. It must be
+a relative path inside the artifacts directory.
+
+When several phases produce a file with the same name (e.g. a summary.md per
+service), set phase-output-dirs: true (or pass --phase-output-dir to orc
+run). Each phase's outputs then resolve under outputs/<phase>/ — or
+<outputs-dir>/<phase>/ when outputs-dir is set — and orc creates those
+directories at the start of the run. Prompts and scripts should write to
+$PHASE_OUTPUT_DIR, which always points at the current phase's directory.
+
+Each phase's .meta.json records the name, modification time, and SHA-256 of
+every declared output present when the phase finished.
+
+An output left over from an earlier run satisfies the existence check. Set
+require-fresh-outputs: true on a phase to also require that each output was
+written after the phase started. Stale outputs are handled like missing ones:
+agent phases are re-prompted once to rewrite them, and the phase fails if they
+are still stale afterwards. Modification times are compared with 2-second
+slack, so filesystems with coarse timestamps don't flag a fresh write; a file
+written in the couple of seconds before the phase started counts as fresh.
+
+Some phases legitimately produce nothing — a "fix if needed" agent that found
+nothing to fix. Set outputs-optional: true to treat the declared outputs as
+expected rather than required: anything missing is reported on stderr and in
+the phase log, but the agent is not re-prompted and the phase still succeeds.
 `
 
 const topicQualityLoops = `Adversarial Quality Loops
@@ -1435,6 +2301,58 @@ when the next fresh orc run starts. Use orc cancel to archive manually.
 Configure the maximum number of archived runs with the history-limit
 config field (default 10).
 
+orc timing — Export Timing Data
+---------------------------------
+
+Exports per-phase timing for a ticket's archived runs (oldest first) and its
+live run, for loading into a spreadsheet or dashboard.
+
+  orc timing                       Most recent ticket, CSV
+  orc timing KS-42 --format csv    One row per timing entry, with header
+  orc timing KS-42 --format json   The same rows as a JSON array
+
+Columns: run (history run ID, or "current" for the live run), phase,
+attempt (1 for a phase's first entry in the run, 2 for its second, ...),
+start, end, duration_secs. A phase still running has an empty end and
+duration.
+
+orc get — Retrieve Deliverables
+---------------------------------
+
+Most phases' outputs are intermediate. List the ones that matter under a
+top-level deliverables key:
+
+  deliverables:
+    - report.md        # a phase output: found under outputs-dir / outputs/<phase>/
+    - pr-url.txt
+
+orc get finds them in the ticket's latest run — the live artifacts
+directory, or the newest archived run once a completed run has been moved
+to history — so you don't need to know the artifact layout.
+
+  orc get                          Most recent ticket; print absolute paths
+  orc get KS-42                    One path per line, for scripting
+  orc get KS-42 --copy ./results   Copy them, keeping their relative paths
+
+Missing deliverables are reported as warnings; orc get fails only when
+none are found.
+
+orc open — Open an Artifact
+-----------------------------
+
+orc open [ticket] <artifact> finds <artifact> in the ticket's latest run
+the same way and launches it with the OS default opener (xdg-open on
+Linux, open on macOS, start on Windows). <artifact> is a declared output,
+a path relative to the artifacts directory, or a phase name or number,
+which opens that phase's log.
+
+  orc open report.md               Most recent ticket's report
+  orc open KS-42 logs/phase-3.log  A path in the artifacts directory
+  orc open KS-42 test              The "test" phase's log
+
+If the artifact doesn't exist, orc open fails. If no opener is installed,
+it prints the path and exits non-zero.
+
 orc stats — Aggregate Metrics
 -------------------------------
 
@@ -1473,7 +2391,7 @@ Top-level fields:
 Each phases[] entry:
   number        int      1-indexed phase number
   name          string   Phase name
-  type          string   "agent", "script", "gate", "workflow", or "branch"
+  type          string   "agent", "script", "gate", "manual", "workflow", "branch", or "publish"
   duration      string   Formatted duration or "—"
   cost          string   Formatted cost or "—"
   cost_usd      float    Raw cost in USD
@@ -1519,6 +2437,48 @@ iteration history, then sends everything to Claude for analysis.
 Recommends whether to --retry, --from, or fix-first.
 
   orc doctor KS-42
+  orc doctor KS-42 --diff
+  orc doctor KS-42 --no-ai
+  orc doctor KS-42 --json
+  orc doctor KS-42 --brief
+  orc doctor --env
+
+--diff compares the current config file against config.snapshot.yaml from
+the ticket's most recent successful run in history/ and adds a line-based
+diff to the diagnosis context, so Claude can attribute a failure to a
+recent config edit. If no successful run is on record, or nothing changed,
+the diagnosis runs without it.
+
+--no-ai (alias --explain-failure) skips Claude entirely and prints the
+failed phase's config, the tail of its log, all feedback files, and loop
+counts to the terminal. It is instant and costs nothing.
+
+--json asks Claude for a structured answer and prints a single JSON object
+on stdout with ticket, phase, root_cause, classification ("workflow" or
+"code"), suggested_fixes (a list), and recommended_command. Progress notes
+go to stderr so stdout stays parseable. If Claude's answer can't be parsed
+into that shape, the raw response is printed instead with a warning on
+stderr. --json cannot be combined with --no-ai.
+
+--brief (alias --summary-only) sends the same context but asks Claude to
+answer in at most four lines: root cause, classification, one fix, and
+the next command — for quick triage or pasting into a CI comment. It
+cannot be combined with --json or --no-ai.
+
+To teach the diagnosis your team's conventions, point the top-level
+doctor-prompt field at a markdown file:
+
+  doctor-prompt: .orc/doctor.md
+
+Its content is appended to the built-in instructions under "Project-Specific
+Guidance" (e.g. "always recommend running make fmt before a retry"); orc
+still injects the gathered logs, config, and feedback.
+
+--env needs no ticket or failed run. It prints the environment orc sees —
+orc and Go versions, OS/arch, the resolved config path (or why it could not
+be loaded), whether CLAUDECODE is set, where bash, claude, and git resolve
+on PATH with their --version, which of them the workflow requires, and the
+PATH entries. Paste it into bug reports.
 
 orc improve — Workflow Refinement
 ----------------------------------
@@ -1554,6 +2514,43 @@ names, and more.
   orc validate                      Validate all workflows
   orc validate -w bugfix            Validate one workflow
   orc validate --config path.yaml   Validate a specific file
+  orc validate --strict             Fail on undefined variable references
+  orc validate --format github      Also print errors as GitHub Actions
+                                    annotations (::error file=...,line=...::msg)
+
+With --format github, each error is printed on stdout as a workflow
+command so the Actions UI marks the offending line of the config. Phase
+errors point at the phase's entry in the YAML; YAML syntax errors use the
+parser's line. Other errors are annotated against the file alone.
+
+orc config — Effective Config
+-----------------------------
+
+Prints the workflow config. With --resolved, the config is validated and
+printed back as canonical YAML with every default filled in — model,
+effort, and timeout on each phase, loop.min, history-limit, and inherited
+cwd — so you can see exactly what orc will run. Fields left at their zero
+value are omitted; vars keep their declaration order.
+
+  orc config                   The config file as written
+  orc config --resolved        Effective config after defaults
+  orc config -w bugfix --resolved
+
+orc upgrade-config — Config Migrations
+--------------------------------------
+
+When config fields are renamed or change units, orc bumps the config
+schema version and ships a migration. upgrade-config reads the config's
+config-version (0 when absent), applies each migration up to the current
+version, saves the original to <config>.bak, and writes the upgraded file in
+place — comments and key order are kept. It prints the migrations applied,
+or that the config is already current.
+
+  orc upgrade-config              Upgrade .orc/config.yaml
+  orc upgrade-config -w bugfix    Upgrade a named workflow
+  orc upgrade-config --dry-run    Print the upgraded config; write nothing
+
+The current version is 1; upgrading from 0 only records config-version.
 
 orc update — Self-Update
 ------------------------
@@ -1,6 +1,9 @@
 package docs
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Topic holds a single documentation article.
 type Topic struct {
@@ -24,3 +27,11 @@ func Get(name string) (Topic, error) {
 	}
 	return Topic{}, fmt.Errorf("unknown topic %q — run 'orc docs' to list available topics", name)
 }
+
+// ArtifactsLayout returns the annotated directory tree from the artifacts
+// topic, for the README the runner writes into each artifacts directory.
+func ArtifactsLayout() string {
+	_, rest, _ := strings.Cut(topicArtifacts, "Directory Structure\n-------------------\n\n")
+	layout, _, _ := strings.Cut(rest, "\n\n")
+	return layout
+}
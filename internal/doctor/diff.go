@@ -0,0 +1,113 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jorge-barreto/orc/internal/state"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 2
+
+// gatherConfigDiff compares the current config file against the config
+// snapshot of the most recent successful run in the ticket's history.
+// Returns "" if there is no successful run, no snapshot, or no change.
+func gatherConfigDiff(ticketDir, configPath string) string {
+	current, err := os.ReadFile(configPath)
+	if err != nil {
+		return ""
+	}
+	entries, err := state.ListHistory(ticketDir)
+	if err != nil {
+		return ""
+	}
+	for _, e := range entries {
+		if e.Status != state.StatusCompleted {
+			continue
+		}
+		previous, err := os.ReadFile(state.ConfigSnapshotPath(e.Dir))
+		if err != nil {
+			continue
+		}
+		diff := diffLines(string(previous), string(current))
+		if diff == "" {
+			return ""
+		}
+		return fmt.Sprintf("Compared against successful run %s:\n%s", e.RunID, diff)
+	}
+	return ""
+}
+
+// diffLines returns a line-based diff of a and b: removed lines are prefixed
+// with "- ", added lines with "+ ", and unchanged context lines with "  ".
+// Hunks are separated by "...". Returns "" if the inputs are identical.
+func diffLines(a, b string) string {
+	if a == b {
+		return ""
+	}
+	x := strings.Split(strings.TrimRight(a, "\n"), "\n")
+	y := strings.Split(strings.TrimRight(b, "\n"), "\n")
+
+	// lcs[i][j] holds the LCS length of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte // ' ', '-', '+'
+		text string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			lines = append(lines, line{' ', x[i]})
+			i++
+			j++
+		case j < len(y) && (i == len(x) || lcs[i][j+1] >= lcs[i+1][j]):
+			lines = append(lines, line{'+', y[j]})
+			j++
+		default:
+			lines = append(lines, line{'-', x[i]})
+			i++
+		}
+	}
+
+	// Keep only changed lines and their surrounding context.
+	keep := make([]bool, len(lines))
+	for k, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for c := max(0, k-diffContext); c <= min(len(lines)-1, k+diffContext); c++ {
+			keep[c] = true
+		}
+	}
+
+	var sb strings.Builder
+	last := -1
+	for k, l := range lines {
+		if !keep[k] {
+			continue
+		}
+		if last >= 0 && k != last+1 {
+			sb.WriteString("...\n")
+		}
+		fmt.Fprintf(&sb, "%c %s\n", l.op, l.text)
+		last = k
+	}
+	return sb.String()
+}
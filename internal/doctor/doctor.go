@@ -3,6 +3,7 @@ package doctor
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -28,11 +29,12 @@ const diagPrompt = `You are diagnosing a failed orc workflow phase. Analyze the
 
 ## Failed Phase Log Output (last %d lines)
 %s
-%s%s%s%s%s
+%s%s%s%s%s%s
 Instructions:
 1. Identify what went wrong from the log output. Cross-reference with other phase logs and previous iterations if available.
    - If a phase log mentions "timed out", it was killed by orc's phase timeout. This usually means the agent ran out of time — possibly due to network issues, slow API responses, or the task being too large for the configured timeout.
    - Check the Execution Context timing: if a phase's duration closely matches its configured timeout, it likely timed out even if upstream of the current failed phase.
+   - If a Config Changes section is present, consider whether a recent config edit caused the failure.
 2. Classify this as a WORKFLOW problem (config, phase ordering, missing outputs) or a CODE problem (the task the agent was working on).
 3. Suggest specific fixes.
 4. Recommend the next command to run:
@@ -42,10 +44,48 @@ Instructions:
 
 Be direct and concise. Focus on actionable advice.`
 
+const briefInstructions = `
+
+Brief mode: reply in at most four short lines and nothing else —
+Root cause: <one sentence>
+Classification: WORKFLOW or CODE
+Fix: <one sentence>
+Next: <the single orc command to run>`
+
+// Options controls optional diagnosis context.
+type Options struct {
+	// Diff includes a diff of ConfigPath against the config snapshot of the
+	// last successful run found in TicketDir's history.
+	Diff       bool
+	ConfigPath string
+	TicketDir  string
+
+	// NoAI prints the gathered failure context instead of sending it to
+	// claude (--no-ai / --explain-failure).
+	NoAI bool
+
+	// JSON asks claude for a structured diagnosis and prints it as a JSON
+	// object (see Diagnosis) instead of streaming free-form text.
+	JSON bool
+
+	// Brief asks claude for a few-line diagnosis — root cause,
+	// classification, one fix, and the next command (--brief).
+	Brief bool
+
+	// ProjectRoot resolves a relative doctor-prompt path.
+	ProjectRoot string
+}
+
 // Run gathers failure context from artifacts and sends it to claude for diagnosis.
-func Run(ctx context.Context, auditDir, artifactsDir string, cfg *config.Config, st *state.State) error {
+func Run(ctx context.Context, auditDir, artifactsDir string, cfg *config.Config, st *state.State, opts Options) error {
+	// Keep stdout pure JSON in --json mode; notices go to stderr.
+	notices := io.Writer(os.Stdout)
+	if opts.JSON {
+		notices = os.Stderr
+	}
+
 	if st.GetStatus() != state.StatusFailed && st.GetStatus() != state.StatusInterrupted {
-		fmt.Println("No failed run to diagnose.")
+		fmt.Fprintln(notices, "No failed run to diagnose.")
 		return nil
 	}
 
@@ -55,7 +95,13 @@ func Run(ctx context.Context, auditDir, artifactsDir string, cfg *config.Config,
 
 	phase := cfg.Phases[st.GetPhaseIndex()]
 
-	phaseConfig := gatherPhaseConfig(phase)
+	if opts.NoAI {
+		explain(os.Stdout, artifactsDir, cfg, st)
+		ux.ResumeHint(st.GetTicket(), st.GetSessionID() != "")
+		return nil
+	}
+
+	phaseConfig := gatherPhaseConfig(cfg, phase)
 	log := gatherLog(artifactsDir, st.GetPhaseIndex())
 	prompt := gatherPrompt(artifactsDir, st.GetPhaseIndex(), phase)
 	feedback := gatherFeedback(artifactsDir)
@@ -63,19 +109,35 @@ func Run(ctx context.Context, auditDir, artifactsDir string, cfg *config.Config,
 	loops := gatherLoopCounts(artifactsDir)
 	otherLogs := gatherAllLogs(artifactsDir, cfg.Phases, st.GetPhaseIndex())
 	iterLogs := gatherIterationLogs(auditDir, st.GetPhaseIndex())
+	var configDiff string
+	if opts.Diff {
+		configDiff = gatherConfigDiff(opts.TicketDir, opts.ConfigPath)
+		if configDiff == "" {
+			fmt.Fprintln(notices, "No config changes since the last successful run (or no successful run on record).")
+		}
+	}
 
-	diagText := buildPrompt(phaseConfig, log, prompt, feedback, timing, loops, otherLogs, iterLogs)
+	guidance, err := gatherGuidance(cfg.DoctorPrompt, opts.ProjectRoot)
+	if err != nil {
+		return err
+	}
+
+	diagText := withOutputInstructions(buildPrompt(phaseConfig, log, prompt, feedback, timing, loops, otherLogs, iterLogs, configDiff, guidance), opts)
 
 	model := cfg.Model
 	if model == "" {
 		model = "opus"
 	}
 
+	if opts.JSON {
+		return diagnoseJSON(ctx, os.Stdout, diagText, model, st.GetTicket(), phase.Name)
+	}
+
 	// Print header
 	fmt.Printf("\n%s%s══ Doctor: diagnosing phase %d/%d (%s) ══%s\n\n",
 		ux.Bold, ux.Cyan, st.GetPhaseIndex()+1, len(cfg.Phases), phase.Name, ux.Reset)
 
-	if err := runClaude(ctx, diagText, model); err != nil {
+	if err := runClaude(ctx, os.Stdout, diagText, model); err != nil {
 		return fmt.Errorf("failed to run claude: %w", err)
 	}
 
@@ -84,8 +146,34 @@ func Run(ctx context.Context, auditDir, artifactsDir string, cfg *config.Config,
 	return nil
 }
 
-func buildPrompt(phaseConfig, log, prompt, feedback, timing, loops, otherLogs, iterLogs string) string {
-	var promptSection, feedbackSection, timingSection, otherLogsSection, iterLogsSection string
+// explain renders the failed phase's config, log tail, and feedback files to w
+// without calling claude.
+func explain(w io.Writer, artifactsDir string, cfg *config.Config, st *state.State) {
+	idx := st.GetPhaseIndex()
+	phase := cfg.Phases[idx]
+
+	fmt.Fprintf(w, "\n%s%s══ Phase %d/%d (%s) %s ══%s\n",
+		ux.Bold, ux.Cyan, idx+1, len(cfg.Phases), phase.Name, st.GetStatus(), ux.Reset)
+
+	section := func(title, body string) {
+		fmt.Fprintf(w, "\n%s%s%s\n%s\n", ux.Bold, title, ux.Reset, strings.TrimRight(body, "\n"))
+	}
+	section("Phase config", gatherPhaseConfig(cfg, phase))
+	section(fmt.Sprintf("Log (last %d lines)", maxLogLines), gatherLog(artifactsDir, idx))
+	if feedback := gatherFeedback(artifactsDir); feedback != "" {
+		section("Feedback files", feedback)
+	} else {
+		section("Feedback files", "(none)")
+	}
+	if loops := gatherLoopCounts(artifactsDir); loops != "" {
+		section("Loop counts", loops)
+	}
+}
+
+// buildPrompt composes the diagnosis prompt. guidance — the project's
+// doctor-prompt file, if any — is appended after the built-in instructions.
+func buildPrompt(phaseConfig, log, prompt, feedback, timing, loops, otherLogs, iterLogs, configDiff, guidance string) string {
+	var promptSection, feedbackSection, timingSection, otherLogsSection, iterLogsSection, configDiffSection string
 	if prompt != "" {
 		promptSection = fmt.Sprintf("\n## Agent Prompt\n%s\n", prompt)
 	}
@@ -110,10 +198,47 @@ func buildPrompt(phaseConfig, log, prompt, feedback, timing, loops, otherLogs, i
 		iterLogsSection = fmt.Sprintf("\n## Previous Iterations of Failed Phase\n%s\n", iterLogs)
 	}
 
-	return fmt.Sprintf(diagPrompt, phaseConfig, maxLogLines, log, promptSection, feedbackSection, timingSection, otherLogsSection, iterLogsSection)
+	if configDiff != "" {
+		configDiffSection = fmt.Sprintf("\n## Config Changes Since Last Successful Run\n%s\n", configDiff)
+	}
+
+	text := fmt.Sprintf(diagPrompt, phaseConfig, maxLogLines, log, promptSection, feedbackSection, timingSection, otherLogsSection, iterLogsSection, configDiffSection)
+	if guidance = strings.TrimSpace(guidance); guidance != "" {
+		text += "\n\n## Project-Specific Guidance\nFollow these project conventions when diagnosing and recommending fixes:\n" + guidance
+	}
+	return text
+}
+
+// withOutputInstructions appends the response-format instructions for
+// --json or --brief to the diagnosis prompt.
+func withOutputInstructions(diagText string, opts Options) string {
+	switch {
+	case opts.JSON:
+		return diagText + jsonInstructions
+	case opts.Brief:
+		return diagText + briefInstructions
+	}
+	return diagText
+}
+
+// gatherGuidance reads the config's doctor-prompt file. An unset field
+// yields "".
+func gatherGuidance(doctorPrompt, projectRoot string) (string, error) {
+	if doctorPrompt == "" {
+		return "", nil
+	}
+	path := doctorPrompt
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectRoot, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading doctor-prompt: %w", err)
+	}
+	return string(data), nil
 }
 
-func gatherPhaseConfig(phase config.Phase) string {
+func gatherPhaseConfig(cfg *config.Config, phase config.Phase) string {
 	var parts []string
 	parts = append(parts, fmt.Sprintf("Name: %s", phase.Name))
 	parts = append(parts, fmt.Sprintf("Type: %s", phase.Type))
@@ -133,7 +258,7 @@ func gatherPhaseConfig(phase config.Phase) string {
 		parts = append(parts, fmt.Sprintf("Timeout: %ds", phase.Timeout))
 	}
 	if len(phase.Outputs) > 0 {
-		parts = append(parts, fmt.Sprintf("Expected outputs: %s", strings.Join(phase.Outputs, ", ")))
+		parts = append(parts, fmt.Sprintf("Expected outputs: %s", strings.Join(cfg.OutputPaths(phase), ", ")))
 	}
 	if phase.Condition != "" {
 		parts = append(parts, fmt.Sprintf("Condition: %s", phase.Condition))
@@ -298,7 +423,9 @@ func truncateLines(content string, maxLines int) string {
 	return fmt.Sprintf("... (truncated to last %d lines)\n%s", maxLines, strings.Join(lines, "\n"))
 }
 
-func runClaude(ctx context.Context, prompt, model string) error {
+// runClaude runs the diagnosis prompt through claude and streams the
+// response text to w.
+func runClaude(ctx context.Context, w io.Writer, prompt, model string) error {
 	cmd := exec.CommandContext(ctx, "claude", "-p", prompt,
 		"--model", model, "--effort", "high",
 		"--output-format", "stream-json",
@@ -316,7 +443,7 @@ func runClaude(ctx context.Context, prompt, model string) error {
 		return fmt.Errorf("starting claude: %w", err)
 	}
 
-	_, streamErr := dispatch.ProcessStream(ctx, stdout, os.Stdout, nil, nil)
+	_, streamErr := dispatch.ProcessStream(ctx, stdout, w, nil, nil)
 
 	if err := cmd.Wait(); err != nil && streamErr == nil {
 		return fmt.Errorf("claude: %w", err)
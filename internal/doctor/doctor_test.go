@@ -1,6 +1,7 @@
 package doctor
 
 import (
+	"bytes"
 	"context"
 	"os"
 	"path/filepath"
@@ -64,7 +65,7 @@ func TestGatherPhaseConfig_Agent(t *testing.T) {
 		Prompt: ".orc/prompts/implement.md",
 		Model:  "opus",
 	}
-	result := gatherPhaseConfig(phase)
+	result := gatherPhaseConfig(&config.Config{}, phase)
 	if !strings.Contains(result, "Name: implement") {
 		t.Error("missing name")
 	}
@@ -85,7 +86,7 @@ func TestGatherPhaseConfig_Script(t *testing.T) {
 		Type: "script",
 		Run:  "make build",
 	}
-	result := gatherPhaseConfig(phase)
+	result := gatherPhaseConfig(&config.Config{}, phase)
 	if !strings.Contains(result, "Run: make build") {
 		t.Error("missing run command")
 	}
@@ -105,7 +106,7 @@ func TestGatherPhaseConfig_WithLoop(t *testing.T) {
 			Max:  3,
 		},
 	}
-	result := gatherPhaseConfig(phase)
+	result := gatherPhaseConfig(&config.Config{}, phase)
 	if !strings.Contains(result, "Loop: goto implement (min 1, max 3)") {
 		t.Error("missing loop info")
 	}
@@ -126,7 +127,7 @@ func TestGatherPhaseConfig_WithLoopOnExhaust(t *testing.T) {
 			},
 		},
 	}
-	result := gatherPhaseConfig(phase)
+	result := gatherPhaseConfig(&config.Config{}, phase)
 	if !strings.Contains(result, "Loop: goto implement (min 1, max 3)") {
 		t.Error("missing loop info")
 	}
@@ -135,6 +136,14 @@ func TestGatherPhaseConfig_WithLoopOnExhaust(t *testing.T) {
 	}
 }
 
+func TestGatherPhaseConfig_OutputsWithoutTypeAnnotations(t *testing.T) {
+	phase := config.Phase{Name: "report", Type: "script", Run: "true", Outputs: []string{"report.json:json", "notes.md"}}
+	result := gatherPhaseConfig(&config.Config{}, phase)
+	if !strings.Contains(result, "Expected outputs: report.json, notes.md") {
+		t.Errorf("outputs not shown as paths:\n%s", result)
+	}
+}
+
 func TestGatherFeedback_MultipleFiles(t *testing.T) {
 	dir := t.TempDir()
 	fbDir := filepath.Join(dir, "feedback")
@@ -216,7 +225,7 @@ func TestGatherTiming_NoData(t *testing.T) {
 func TestRun_NotFailed(t *testing.T) {
 	st := &state.State{Status: state.StatusCompleted}
 	cfg := &config.Config{Phases: []config.Phase{{Name: "test"}}}
-	err := Run(context.Background(), t.TempDir(), t.TempDir(), cfg, st)
+	err := Run(context.Background(), t.TempDir(), t.TempDir(), cfg, st, Options{})
 	if err != nil {
 		t.Errorf("expected nil error, got %v", err)
 	}
@@ -225,7 +234,7 @@ func TestRun_NotFailed(t *testing.T) {
 func TestRun_PhaseIndexOutOfRange(t *testing.T) {
 	st := &state.State{Status: state.StatusFailed, PhaseIndex: 5}
 	cfg := &config.Config{Phases: []config.Phase{{Name: "test"}}}
-	err := Run(context.Background(), t.TempDir(), t.TempDir(), cfg, st)
+	err := Run(context.Background(), t.TempDir(), t.TempDir(), cfg, st, Options{})
 	if err == nil {
 		t.Error("expected error for out of range phase index")
 	}
@@ -322,7 +331,7 @@ func TestRun_WorkflowNamespaced_GathersData(t *testing.T) {
 	// t.Setenv automatically restores the original PATH when the test completes.
 	t.Setenv("PATH", t.TempDir())
 
-	err := Run(context.Background(), auditDir, artifactsDir, cfg, st)
+	err := Run(context.Background(), auditDir, artifactsDir, cfg, st, Options{})
 	if err == nil {
 		t.Fatal("expected error from runClaude (no claude binary), got nil")
 	}
@@ -332,6 +341,57 @@ func TestRun_WorkflowNamespaced_GathersData(t *testing.T) {
 	}
 }
 
+func TestExplain_PrintsLogTailAndFeedback(t *testing.T) {
+	artifactsDir := t.TempDir()
+	state.EnsureDir(artifactsDir)
+	os.MkdirAll(filepath.Join(artifactsDir, "feedback"), 0755)
+
+	var lines []string
+	for i := 0; i < 250; i++ {
+		lines = append(lines, "noise")
+	}
+	lines = append(lines, "FAIL: TestParse (0.01s)")
+	os.WriteFile(state.LogPath(artifactsDir, 1), []byte(strings.Join(lines, "\n")), 0644)
+	os.WriteFile(filepath.Join(artifactsDir, "feedback", "from-test.md"), []byte("parser rejects empty input"), 0644)
+
+	cfg := &config.Config{
+		Phases: []config.Phase{
+			{Name: "build", Type: "script", Run: "make build"},
+			{Name: "test", Type: "script", Run: "make test"},
+		},
+	}
+	st := &state.State{Status: state.StatusFailed, PhaseIndex: 1, Ticket: "T-1"}
+
+	var buf bytes.Buffer
+	explain(&buf, artifactsDir, cfg, st)
+	out := buf.String()
+
+	for _, want := range []string{
+		"Phase 2/2 (test)",
+		"Run: make test",
+		"truncated to last 200 lines",
+		"FAIL: TestParse",
+		"--- from-test.md ---",
+		"parser rejects empty input",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRun_NoAI_SkipsClaude(t *testing.T) {
+	artifactsDir := t.TempDir()
+	cfg := &config.Config{Phases: []config.Phase{{Name: "build", Type: "script", Run: "make"}}}
+	st := &state.State{Status: state.StatusFailed, Ticket: "T-1"}
+
+	// No claude on PATH: with NoAI set, Run must not try to invoke it.
+	t.Setenv("PATH", t.TempDir())
+	if err := Run(context.Background(), t.TempDir(), artifactsDir, cfg, st, Options{NoAI: true}); err != nil {
+		t.Fatalf("Run with NoAI: %v", err)
+	}
+}
+
 func TestGatherAllLogs_MultiplePhases(t *testing.T) {
 	dir := t.TempDir()
 	artifactsDir := filepath.Join(dir, ".orc", "artifacts")
@@ -449,3 +509,120 @@ func TestTruncateLines_AboveThreshold(t *testing.T) {
 		t.Errorf("expected 11 lines, got %d", len(outLines))
 	}
 }
+
+func TestDiffLines_Identical(t *testing.T) {
+	if got := diffLines("a\nb\n", "a\nb\n"); got != "" {
+		t.Fatalf("expected empty diff, got %q", got)
+	}
+}
+
+func TestDiffLines_Change(t *testing.T) {
+	old := "name: x\nmodel: opus\nphases:\n  - name: a\n"
+	cur := "name: x\nmodel: sonnet\nphases:\n  - name: a\n"
+	got := diffLines(old, cur)
+	if !strings.Contains(got, "- model: opus") || !strings.Contains(got, "+ model: sonnet") {
+		t.Fatalf("diff missing change lines:\n%s", got)
+	}
+	if !strings.Contains(got, "  name: x") {
+		t.Fatalf("diff missing context line:\n%s", got)
+	}
+}
+
+func TestDiffLines_SeparatesHunks(t *testing.T) {
+	old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+	cur := "one\n2\n3\n4\n5\n6\n7\n8\nnine\n"
+	got := diffLines(old, cur)
+	if !strings.Contains(got, "...\n") {
+		t.Fatalf("expected hunk separator:\n%s", got)
+	}
+	if strings.Contains(got, "  5\n") {
+		t.Fatalf("line far from any change should be omitted:\n%s", got)
+	}
+}
+
+func TestGatherConfigDiff_DetectsChange(t *testing.T) {
+	ticketDir := t.TempDir()
+	okRun := filepath.Join(state.HistoryDir(ticketDir), "2026-01-01T00-00-00.000")
+	os.MkdirAll(okRun, 0755)
+	okState := &state.State{Ticket: "T-1", Status: state.StatusCompleted}
+	okState.Save(okRun)
+	os.WriteFile(state.ConfigSnapshotPath(okRun), []byte("name: x\nmodel: opus\n"), 0644)
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(configPath, []byte("name: x\nmodel: haiku\n"), 0644)
+
+	diff := gatherConfigDiff(ticketDir, configPath)
+	if !strings.Contains(diff, "2026-01-01T00-00-00.000") || !strings.Contains(diff, "+ model: haiku") {
+		t.Fatalf("unexpected diff:\n%s", diff)
+	}
+
+	prompt := buildPrompt("Name: a", "log", "", "", "", "", "", "", diff, "")
+	if !strings.Contains(prompt, "## Config Changes Since Last Successful Run") || !strings.Contains(prompt, "- model: opus") {
+		t.Fatalf("prompt missing config diff section:\n%s", prompt)
+	}
+}
+
+func TestGatherConfigDiff_SkipsFailedRuns(t *testing.T) {
+	ticketDir := t.TempDir()
+	failedRun := filepath.Join(state.HistoryDir(ticketDir), "2026-01-02T00-00-00.000")
+	os.MkdirAll(failedRun, 0755)
+	(&state.State{Ticket: "T-1", Status: state.StatusFailed}).Save(failedRun)
+	os.WriteFile(state.ConfigSnapshotPath(failedRun), []byte("name: x\n"), 0644)
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(configPath, []byte("name: y\n"), 0644)
+
+	if diff := gatherConfigDiff(ticketDir, configPath); diff != "" {
+		t.Fatalf("expected no diff without a successful run, got:\n%s", diff)
+	}
+}
+
+func TestBuildPrompt_NoConfigDiffSection(t *testing.T) {
+	prompt := buildPrompt("Name: a", "log", "", "", "", "", "", "", "", "")
+	if strings.Contains(prompt, "## Config Changes") {
+		t.Fatal("prompt should not include config diff section when diff is empty")
+	}
+}
+
+func TestBuildPrompt_AppendsProjectGuidance(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "doctor.md"), []byte("Always recommend running `make fmt` before retry.\n"), 0644)
+
+	guidance, err := gatherGuidance("doctor.md", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prompt := buildPrompt("Name: a", "log", "", "", "", "", "", "", "", guidance)
+	if !strings.Contains(prompt, "## Project-Specific Guidance") || !strings.Contains(prompt, "make fmt") {
+		t.Fatalf("prompt missing project guidance:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "Be direct and concise.") {
+		t.Fatal("project guidance should extend, not replace, the built-in instructions")
+	}
+}
+
+func TestBuildPrompt_NoGuidanceSection(t *testing.T) {
+	guidance, err := gatherGuidance("", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prompt := buildPrompt("Name: a", "log", "", "", "", "", "", "", "", guidance); strings.Contains(prompt, "Project-Specific Guidance") {
+		t.Fatal("prompt should not include a guidance section when doctor-prompt is unset")
+	}
+}
+
+func TestWithOutputInstructions_Brief(t *testing.T) {
+	base := buildPrompt("Name: a", "log", "", "", "", "", "", "", "", "")
+	prompt := withOutputInstructions(base, Options{Brief: true})
+	if !strings.HasPrefix(prompt, base) {
+		t.Fatal("brief prompt should keep the full diagnosis context")
+	}
+	for _, want := range []string{"at most four short lines", "Root cause:", "Classification:", "Next:"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("brief prompt missing %q:\n%s", want, prompt[len(base):])
+		}
+	}
+	if plain := withOutputInstructions(base, Options{}); plain != base {
+		t.Errorf("default prompt should carry no extra instructions, got %q", plain[len(base):])
+	}
+}
@@ -0,0 +1,106 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/dispatch"
+)
+
+// envBinaries are always reported by 'orc doctor --env', whether or not the
+// current workflow needs them.
+var envBinaries = []string{"bash", "claude", "git"}
+
+// EnvOptions describes what 'orc doctor --env' reports on.
+type EnvOptions struct {
+	OrcVersion string
+	ConfigPath string
+	// ConfigErr is why the config could not be resolved or loaded; the
+	// report still prints, without the workflow's required binaries.
+	ConfigErr error
+	Config    *config.Config
+}
+
+// WriteEnv writes a diagnostic report of the runtime environment orc sees:
+// versions, platform, the resolved config, the CLAUDECODE guard, and where
+// the binaries it shells out to resolve on PATH.
+func WriteEnv(ctx context.Context, w io.Writer, opts EnvOptions) {
+	fmt.Fprintln(w, "orc environment")
+	fmt.Fprintf(w, "  orc:         %s\n", opts.OrcVersion)
+	fmt.Fprintf(w, "  go:          %s\n", runtime.Version())
+	fmt.Fprintf(w, "  os/arch:     %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	if opts.ConfigErr != nil {
+		fmt.Fprintf(w, "  config:      %s (error: %v)\n", orNone(opts.ConfigPath), opts.ConfigErr)
+	} else {
+		fmt.Fprintf(w, "  config:      %s\n", orNone(opts.ConfigPath))
+	}
+	if os.Getenv("CLAUDECODE") != "" {
+		fmt.Fprintln(w, "  CLAUDECODE:  set — orc run will refuse to start; use a regular terminal")
+	} else {
+		fmt.Fprintln(w, "  CLAUDECODE:  not set")
+	}
+
+	required := make(map[string]bool)
+	if opts.Config != nil {
+		for _, bin := range dispatch.RequiredBinaries(opts.Config.Phases) {
+			required[bin] = true
+		}
+	}
+	fmt.Fprintln(w, "\nBinaries")
+	for _, bin := range envBinaries {
+		need := ""
+		if required[bin] {
+			need = " (required by workflow)"
+		}
+		path, err := exec.LookPath(bin)
+		if err != nil {
+			hint := dispatch.InstallHint(bin)
+			if hint != "" {
+				hint = " — " + hint
+			}
+			fmt.Fprintf(w, "  %-8s not found%s%s\n", bin, need, hint)
+			continue
+		}
+		fmt.Fprintf(w, "  %-8s %s%s\n", bin, path, need)
+		if v := binaryVersion(ctx, path); v != "" {
+			fmt.Fprintf(w, "  %-8s %s\n", "", v)
+		}
+	}
+
+	fmt.Fprintln(w, "\nPATH")
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		fmt.Fprintf(w, "  %s\n", dir)
+	}
+}
+
+// binaryVersion returns the first line of `<path> --version`, or "" if the
+// binary doesn't answer within a few seconds.
+func binaryVersion(ctx context.Context, path string) string {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path, "--version")
+	cmd.Env = dispatch.FilteredEnv()
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return ""
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(out.String()), "\n")
+	return strings.TrimSpace(line)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
@@ -0,0 +1,50 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/jorge-barreto/orc/internal/config"
+)
+
+func TestWriteEnv_ReportsBashAndConfigPath(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not on PATH")
+	}
+	cfg := &config.Config{Phases: []config.Phase{{Name: "build", Type: "script", Run: "make"}}}
+
+	var buf bytes.Buffer
+	WriteEnv(context.Background(), &buf, EnvOptions{
+		OrcVersion: "v1.2.3",
+		ConfigPath: "/proj/.orc/config.yaml",
+		Config:     cfg,
+	})
+	out := buf.String()
+
+	for _, want := range []string{
+		"orc:         v1.2.3",
+		"config:      /proj/.orc/config.yaml",
+		"bash     " + bash + " (required by workflow)",
+		"CLAUDECODE:",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteEnv_ConfigError(t *testing.T) {
+	var buf bytes.Buffer
+	WriteEnv(context.Background(), &buf, EnvOptions{
+		ConfigPath: "/proj/.orc/config.yaml",
+		ConfigErr:  errors.New("phase 1: missing name"),
+	})
+	if !strings.Contains(buf.String(), "/proj/.orc/config.yaml (error: phase 1: missing name)") {
+		t.Fatalf("report should show the config error:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,88 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// jsonInstructions replaces the free-form answer format when --json is set.
+const jsonInstructions = `
+
+Output format: respond with ONLY a JSON object — no prose, no markdown fences — with exactly these fields:
+{
+  "root_cause": "one or two sentences on what went wrong",
+  "classification": "workflow" or "code",
+  "suggested_fixes": ["specific fix", "..."],
+  "recommended_command": "the next orc command to run, e.g. orc run --retry implement <ticket>"
+}`
+
+// Diagnosis is the structured result of 'orc doctor --json'.
+type Diagnosis struct {
+	Ticket             string   `json:"ticket"`
+	Phase              string   `json:"phase"`
+	RootCause          string   `json:"root_cause"`
+	Classification     string   `json:"classification"` // "workflow" or "code"
+	SuggestedFixes     []string `json:"suggested_fixes"`
+	RecommendedCommand string   `json:"recommended_command"`
+}
+
+// captureClaude runs the diagnosis prompt and returns claude's text
+// response. A var so tests can stub it.
+var captureClaude = func(ctx context.Context, prompt, model string) (string, error) {
+	var text bytes.Buffer
+	err := runClaude(ctx, &text, prompt, model)
+	return text.String(), err
+}
+
+// diagnoseJSON runs the JSON-format diagnosis and writes it to w as an
+// indented JSON object. If claude's answer can't be parsed into a Diagnosis,
+// the raw text is written instead, with a warning on stderr.
+func diagnoseJSON(ctx context.Context, w io.Writer, prompt, model, ticket, phase string) error {
+	text, err := captureClaude(ctx, prompt, model)
+	if err != nil {
+		return fmt.Errorf("failed to run claude: %w", err)
+	}
+	d, err := parseDiagnosis(text)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not parse structured diagnosis (%v); printing raw response\n", err)
+		fmt.Fprintln(w, strings.TrimSpace(text))
+		return nil
+	}
+	d.Ticket = ticket
+	d.Phase = phase
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// parseDiagnosis extracts the JSON object from claude's response — tolerating
+// surrounding prose or code fences — and checks it has the expected shape.
+func parseDiagnosis(text string) (*Diagnosis, error) {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no JSON object in response")
+	}
+	var d Diagnosis
+	if err := json.Unmarshal([]byte(text[start:end+1]), &d); err != nil {
+		return nil, fmt.Errorf("decoding diagnosis: %w", err)
+	}
+	d.Classification = strings.ToLower(strings.TrimSpace(d.Classification))
+	switch {
+	case strings.TrimSpace(d.RootCause) == "":
+		return nil, fmt.Errorf("diagnosis is missing root_cause")
+	case d.Classification != "workflow" && d.Classification != "code":
+		return nil, fmt.Errorf("diagnosis classification %q must be \"workflow\" or \"code\"", d.Classification)
+	case strings.TrimSpace(d.RecommendedCommand) == "":
+		return nil, fmt.Errorf("diagnosis is missing recommended_command")
+	}
+	if d.SuggestedFixes == nil {
+		d.SuggestedFixes = []string{}
+	}
+	return &d, nil
+}
@@ -0,0 +1,108 @@
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/state"
+)
+
+func stubCaptureClaude(t *testing.T, response string) *string {
+	t.Helper()
+	var prompt string
+	orig := captureClaude
+	t.Cleanup(func() { captureClaude = orig })
+	captureClaude = func(_ context.Context, p, _ string) (string, error) {
+		prompt = p
+		return response, nil
+	}
+	return &prompt
+}
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	fn()
+	w.Close()
+	os.Stdout = old
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
+func TestRun_JSON(t *testing.T) {
+	artifactsDir := t.TempDir()
+	os.MkdirAll(filepath.Join(artifactsDir, "logs"), 0755)
+	os.WriteFile(state.LogPath(artifactsDir, 0), []byte("FAIL: TestParse"), 0644)
+	cfg := &config.Config{Phases: []config.Phase{{Name: "test", Type: "script", Run: "go test ./..."}}}
+	st := &state.State{Status: state.StatusFailed, Ticket: "T-1"}
+
+	prompt := stubCaptureClaude(t, "```json\n"+`{
+  "root_cause": "TestParse fails on empty input",
+  "classification": "Code",
+  "suggested_fixes": ["handle empty input in Parse"],
+  "recommended_command": "orc run --retry test T-1"
+}`+"\n```")
+
+	var err error
+	out := captureStdout(t, func() {
+		err = Run(context.Background(), t.TempDir(), artifactsDir, cfg, st, Options{JSON: true})
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !strings.Contains(*prompt, `"classification": "workflow" or "code"`) {
+		t.Error("prompt should ask for the JSON format")
+	}
+
+	var d Diagnosis
+	if err := json.Unmarshal([]byte(out), &d); err != nil {
+		t.Fatalf("stdout is not a JSON diagnosis: %v\n%s", err, out)
+	}
+	if d.Ticket != "T-1" || d.Phase != "test" {
+		t.Errorf("ticket/phase = %q/%q, want T-1/test", d.Ticket, d.Phase)
+	}
+	if d.RootCause != "TestParse fails on empty input" || d.Classification != "code" {
+		t.Errorf("unexpected diagnosis: %+v", d)
+	}
+	if len(d.SuggestedFixes) != 1 || d.RecommendedCommand != "orc run --retry test T-1" {
+		t.Errorf("unexpected fixes/command: %+v", d)
+	}
+}
+
+func TestRun_JSONFallsBackToRawText(t *testing.T) {
+	cfg := &config.Config{Phases: []config.Phase{{Name: "test", Type: "script", Run: "go test ./..."}}}
+	st := &state.State{Status: state.StatusFailed, Ticket: "T-1"}
+	stubCaptureClaude(t, "The tests failed because Parse panics.")
+
+	var err error
+	out := captureStdout(t, func() {
+		err = Run(context.Background(), t.TempDir(), t.TempDir(), cfg, st, Options{JSON: true})
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if strings.TrimSpace(out) != "The tests failed because Parse panics." {
+		t.Fatalf("expected raw text fallback, got %q", out)
+	}
+}
+
+func TestParseDiagnosis_RejectsBadShape(t *testing.T) {
+	for _, text := range []string{
+		`{"root_cause": "x", "classification": "infra", "recommended_command": "orc run"}`,
+		`{"classification": "code", "recommended_command": "orc run"}`,
+		`{"root_cause": "x", "classification": "code"}`,
+		`not json`,
+	} {
+		if _, err := parseDiagnosis(text); err == nil {
+			t.Errorf("parseDiagnosis(%q) should fail", text)
+		}
+	}
+}
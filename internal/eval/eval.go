@@ -105,7 +105,8 @@ func LoadFixture(caseDir string) (*Fixture, error) {
 		"TICKET": true, "ARTIFACTS_DIR": true,
 		"WORK_DIR": true, "PROJECT_ROOT": true,
 		"PHASE_INDEX": true, "PHASE_COUNT": true,
-		"WORKFLOW": true,
+		"WORKFLOW": true, "WORKFLOW_NAME": true,
+		"PHASE_DESCRIPTION": true, "PHASE_OUTPUT_DIR": true,
 	}
 	// Fixture vars are injected BARE (unprefixed) into the run env AND the
 	// grader env, last-wins, so a var named PATH/HOME/etc. would shadow the
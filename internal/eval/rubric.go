@@ -48,6 +48,8 @@ func filteredEnv(extras ...string) []string {
 	overridden := map[string]bool{
 		"TICKET": true, "ARTIFACTS_DIR": true, "WORK_DIR": true,
 		"PROJECT_ROOT": true, "WORKFLOW": true,
+		"WORKFLOW_NAME": true, "PHASE_DESCRIPTION": true,
+		"PHASE_OUTPUT_DIR": true,
 	}
 	base := dispatch.FilteredEnv()
 	var env []string
@@ -0,0 +1,112 @@
+// Package metrics keeps live counters for a run and serves them in the
+// Prometheus text exposition format (run --metrics-addr).
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jorge-barreto/orc/internal/dispatch"
+)
+
+// Registry accumulates run metrics from runner lifecycle events. A nil
+// *Registry discards observations, so the runner can call it unconditionally.
+type Registry struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	start    time.Time
+	end      time.Time // zero while the run is active
+	phases   map[string]int
+	failures map[string]int
+	skipped  map[string]int
+	loops    map[string]int
+	costUSD  float64
+}
+
+// New returns a registry whose run clock starts now.
+func New() *Registry {
+	return &Registry{
+		now:      time.Now,
+		start:    time.Now(),
+		phases:   make(map[string]int),
+		failures: make(map[string]int),
+		skipped:  make(map[string]int),
+		loops:    make(map[string]int),
+	}
+}
+
+// Observe updates the counters from a runner event: phase_end counts a
+// dispatch (and a failure when its status is failed) and adds its cost,
+// phase_skip counts a skip, and run_end stops the run clock. A later
+// phase_start (a --workflow-retries attempt) restarts it.
+func (m *Registry) Observe(ev dispatch.LogEvent) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch ev.Type {
+	case "phase_start":
+		m.end = time.Time{}
+	case "phase_end":
+		m.phases[ev.Phase]++
+		if ev.Status == "failed" {
+			m.failures[ev.Phase]++
+		}
+		m.costUSD += ev.CostUSD
+	case "phase_skip":
+		m.skipped[ev.Phase]++
+	case "run_end":
+		m.end = m.now()
+	}
+}
+
+// LoopIteration counts one loop iteration of phase.
+func (m *Registry) LoopIteration(phase string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.loops[phase]++
+}
+
+// ServeHTTP writes the current metrics in the Prometheus text format.
+func (m *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	writeCounter(w, "orc_phases_run_total", "Phase dispatches that finished, by phase.", m.phases)
+	writeCounter(w, "orc_phase_failures_total", "Phase dispatches that failed, by phase.", m.failures)
+	writeCounter(w, "orc_phases_skipped_total", "Phases skipped, by phase.", m.skipped)
+	writeCounter(w, "orc_loop_iterations_total", "Loop iterations, by phase.", m.loops)
+
+	fmt.Fprintf(w, "# HELP orc_cost_usd_total Reported agent cost in USD.\n# TYPE orc_cost_usd_total counter\norc_cost_usd_total %g\n", m.costUSD)
+
+	end := m.end
+	active := 0
+	if end.IsZero() {
+		end = m.now()
+		active = 1
+	}
+	fmt.Fprintf(w, "# HELP orc_run_duration_seconds Time since the run started, frozen when it ends.\n# TYPE orc_run_duration_seconds gauge\norc_run_duration_seconds %g\n", end.Sub(m.start).Seconds())
+	fmt.Fprintf(w, "# HELP orc_run_active Whether the run is still in progress.\n# TYPE orc_run_active gauge\norc_run_active %d\n", active)
+}
+
+// writeCounter writes one labelled counter family, sorted by phase.
+func writeCounter(w io.Writer, name, help string, values map[string]int) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	phases := make([]string, 0, len(values))
+	for p := range values {
+		phases = append(phases, p)
+	}
+	sort.Strings(phases)
+	for _, p := range phases {
+		fmt.Fprintf(w, "%s{phase=%q} %d\n", name, p, values[p])
+	}
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jorge-barreto/orc/internal/dispatch"
+)
+
+func scrape(t *testing.T, m *Registry) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	return rec.Body.String()
+}
+
+func TestRegistry_Counters(t *testing.T) {
+	m := New()
+	now := m.start
+	m.now = func() time.Time { return now }
+
+	m.Observe(dispatch.LogEvent{Type: "phase_start", Phase: "build"})
+	m.Observe(dispatch.LogEvent{Type: "phase_end", Phase: "build", Status: "ok", CostUSD: 0.25})
+	m.Observe(dispatch.LogEvent{Type: "phase_end", Phase: "test", Status: "failed"})
+	m.Observe(dispatch.LogEvent{Type: "phase_skip", Phase: "docs"})
+	m.LoopIteration("test")
+	now = now.Add(90 * time.Second)
+
+	out := scrape(t, m)
+	for _, want := range []string{
+		`orc_phases_run_total{phase="build"} 1`,
+		`orc_phases_run_total{phase="test"} 1`,
+		`orc_phase_failures_total{phase="test"} 1`,
+		`orc_phases_skipped_total{phase="docs"} 1`,
+		`orc_loop_iterations_total{phase="test"} 1`,
+		"orc_cost_usd_total 0.25",
+		"orc_run_duration_seconds 90",
+		"orc_run_active 1",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `orc_phase_failures_total{phase="build"}`) {
+		t.Errorf("build did not fail:\n%s", out)
+	}
+
+	m.Observe(dispatch.LogEvent{Type: "run_end", Status: "failed"})
+	now = now.Add(time.Hour)
+	out = scrape(t, m)
+	if !strings.Contains(out, "orc_run_active 0") || !strings.Contains(out, "orc_run_duration_seconds 90") {
+		t.Errorf("run_end should stop the clock:\n%s", out)
+	}
+}
+
+func TestRegistry_NilDiscards(t *testing.T) {
+	var m *Registry
+	m.Observe(dispatch.LogEvent{Type: "phase_end", Phase: "a"})
+	m.LoopIteration("a")
+}
@@ -232,6 +232,7 @@ func Build(artifactsDir, auditDir string, st *state.State, phases []config.Phase
 	metadataFiles := map[string]bool{
 		"state.json": true, "timing.json": true,
 		"costs.json": true, "loop-counts.json": true,
+		".gitignore": true,
 	}
 	entries, _ := os.ReadDir(artifactsDir)
 	artifacts := []ArtifactFile{}
@@ -0,0 +1,210 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jorge-barreto/orc/internal/state"
+	"github.com/jorge-barreto/orc/internal/ux"
+)
+
+// BisectSeed returns the history run directory whose saved outputs seed
+// --bisect trials: runID when given, otherwise the newest run that
+// completed. The live artifacts directory is never a seed, since after a
+// failed run it holds that run's outputs, corrupt ones included, and every
+// trial would inherit them. It errors when there is no completed run.
+func BisectSeed(artifactsDir, runID string) (string, error) {
+	entries, err := state.ListHistory(artifactsDir)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range entries {
+		if runID != "" && e.RunID != runID {
+			continue
+		}
+		if e.Status != state.StatusCompleted {
+			if runID != "" {
+				return "", fmt.Errorf("history run %s did not complete (status %s); its outputs are not known-good", runID, e.Status)
+			}
+			continue
+		}
+		return e.Dir, nil
+	}
+	if runID != "" {
+		return "", fmt.Errorf("no history run %s (see orc history)", runID)
+	}
+	return "", fmt.Errorf("no completed run in history to seed trials from — complete a run first, or name one with --bisect-run")
+}
+
+// Bisect localizes which phase between good and bad (0-based indices)
+// introduces the failure seen at bad. seed is a run directory holding
+// known-good saved outputs (see BisectSeed). Each trial copies it to a
+// scratch directory and runs the workflow from a start phase through bad,
+// like --from: earlier phases are not re-run and their saved outputs are used.
+// Starting at good+1 must fail; the culprit is the latest start that still
+// fails, since starting one phase later passes. It returns -1 when the
+// failure does not reproduce from good+1.
+//
+// The live artifacts and audit directories are never modified. In a git
+// work tree the tree must be clean, and it is reset to the starting commit
+// after every trial so one trial's edits and commits can't leak into the
+// next. Trials dispatch their phases for real: agent phases are paid for
+// again on every trial.
+func (r *Runner) Bisect(ctx context.Context, good, bad int, seed string) (int, error) {
+	if good < 0 || bad >= len(r.Config.Phases) || good >= bad {
+		return -1, fmt.Errorf("bisect range must name a good phase before the bad phase")
+	}
+	tree, err := snapshotWorktree(r.Env.WorkDir)
+	if err != nil {
+		return -1, &ExitError{Code: ExitConfigError, Err: err}
+	}
+	names := func(i int) string { return fmt.Sprintf("phase %d (%s)", i+1, r.Config.Phases[i].Name) }
+	fails := func(start int) (bool, error) {
+		fmt.Printf("\n%sbisect:%s running from %s through %s\n", ux.Bold, ux.Reset, names(start), names(bad))
+		failed, err := r.bisectTrial(ctx, seed, start, bad)
+		if restoreErr := tree.restore(); restoreErr != nil && err == nil {
+			err = fmt.Errorf("restoring work tree after bisect trial: %w", restoreErr)
+		}
+		if err != nil {
+			return false, err
+		}
+		verdict := ux.Green + "passes" + ux.Reset
+		if failed {
+			verdict = ux.Red + "fails" + ux.Reset
+		}
+		fmt.Printf("%sbisect:%s starting at %s %s\n", ux.Bold, ux.Reset, names(start), verdict)
+		return failed, nil
+	}
+
+	lo, hi := good+1, bad
+	failed, err := fails(lo)
+	if err != nil || !failed {
+		return -1, err
+	}
+	// Invariant: starting at lo fails; starting after hi passes.
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		failed, err := fails(mid)
+		if err != nil {
+			return -1, err
+		}
+		if failed {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo, nil
+}
+
+// bisectTrial runs phases start through bad against a scratch copy of the
+// seed run directory, with a scratch audit directory, and reports whether
+// the run failed. Loop counts are reset as with --from. An interrupt or a
+// setup error aborts the bisect.
+func (r *Runner) bisectTrial(ctx context.Context, seed string, start, bad int) (bool, error) {
+	scratch, err := os.MkdirTemp("", "orc-bisect-")
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(scratch)
+	dir := filepath.Join(scratch, "artifacts")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, err
+	}
+	if err := state.CopyRun(seed, dir); err != nil {
+		return false, fmt.Errorf("copying artifacts for bisect: %w", err)
+	}
+	if err := state.SaveLoopCounts(dir, make(map[string]int)); err != nil {
+		return false, fmt.Errorf("resetting loop counts: %w", err)
+	}
+
+	cfg := *r.Config
+	cfg.Phases = slices.Clone(r.Config.Phases[:bad+1])
+	env := r.Env.Clone()
+	env.ArtifactsDir = dir
+	env.PhaseCount = len(cfg.Phases)
+	st := &state.State{Ticket: env.Ticket, Workflow: env.Workflow, Status: state.StatusRunning}
+	st.SetPhase(start)
+
+	trial := &Runner{
+		Config:        &cfg,
+		State:         st,
+		Env:           env,
+		Dispatcher:    r.Dispatcher,
+		HistoryLimit:  r.HistoryLimit,
+		MaxDispatches: r.MaxDispatches,
+		KeepGoing:     r.KeepGoing,
+		StepPromptFn:  r.StepPromptFn,
+		RePromptFn:    r.RePromptFn,
+		auditDir:      filepath.Join(scratch, "audit"),
+	}
+	err = trial.Run(ctx)
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	var exitErr *ExitError
+	if err != nil && errors.As(err, &exitErr) && exitErr.Code == ExitConfigError {
+		return false, err
+	}
+	return err != nil, nil
+}
+
+// worktree is the git commit a bisect started from, so each trial's changes
+// to the project can be undone. head is "" outside a git work tree, where
+// nothing can be restored.
+type worktree struct {
+	dir, head string
+}
+
+// snapshotWorktree records dir's HEAD, refusing a work tree with
+// uncommitted or untracked changes (outside .orc/) since restoring after a
+// trial would discard them.
+func snapshotWorktree(dir string) (*worktree, error) {
+	head, err := gitOutput(dir, "rev-parse", "HEAD")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %s is not a git work tree — changes bisect trials make to it are not undone\n", dir)
+		return &worktree{dir: dir}, nil
+	}
+	status, err := gitOutput(dir, "status", "--porcelain", "--", ".", ":(exclude).orc")
+	if err != nil {
+		return nil, err
+	}
+	if status != "" {
+		return nil, fmt.Errorf("--bisect needs a clean work tree: trials reset it between runs — commit or stash your changes first")
+	}
+	return &worktree{dir: dir, head: head}, nil
+}
+
+// restore resets the work tree to the recorded commit and removes untracked
+// files a trial left behind, keeping .orc/.
+func (w *worktree) restore() error {
+	if w.head == "" {
+		return nil
+	}
+	if _, err := gitOutput(w.dir, "reset", "--hard", "--quiet", w.head); err != nil {
+		return err
+	}
+	_, err := gitOutput(w.dir, "clean", "-fd", "--quiet", "--exclude=.orc")
+	return err
+}
+
+// gitOutput runs git in dir and returns its trimmed stdout.
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
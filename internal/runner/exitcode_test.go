@@ -13,7 +13,7 @@ func TestExitCodeFrom_Nil(t *testing.T) {
 }
 
 func TestExitCodeFrom_ExitError(t *testing.T) {
-	for _, code := range []int{ExitPhaseFailure, ExitTimeout, ExitCostLimit, ExitInterrupted, ExitResumeFailure, ExitInfraError} {
+	for _, code := range []int{ExitPhaseFailure, ExitTimeout, ExitConfigError, ExitCostLimit, ExitInterrupted, ExitResumeFailure, ExitInfraError, ExitRateLimit} {
 		err := &ExitError{Code: code, Err: fmt.Errorf("test")}
 		if got := ExitCodeFrom(err); got != code {
 			t.Fatalf("ExitCodeFrom(ExitError{%d}) = %d", code, got)
@@ -21,6 +21,17 @@ func TestExitCodeFrom_ExitError(t *testing.T) {
 	}
 }
 
+func TestExitCodes_Distinct(t *testing.T) {
+	codes := []int{ExitSuccess, ExitPhaseFailure, ExitTimeout, ExitConfigError, ExitCostLimit, ExitInterrupted, ExitResumeFailure, ExitInfraError, ExitRateLimit}
+	seen := make(map[int]bool)
+	for _, code := range codes {
+		if seen[code] {
+			t.Fatalf("exit code %d is used for more than one outcome", code)
+		}
+		seen[code] = true
+	}
+}
+
 func TestExitCodeFrom_WrappedExitError(t *testing.T) {
 	inner := &ExitError{Code: ExitCostLimit, Err: fmt.Errorf("gate denied")}
 	wrapped := fmt.Errorf("run failed: %w", inner)
@@ -9,12 +9,17 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/jorge-barreto/orc/internal/config"
 	"github.com/jorge-barreto/orc/internal/dispatch"
+	"github.com/jorge-barreto/orc/internal/docs"
+	"github.com/jorge-barreto/orc/internal/metrics"
 	"github.com/jorge-barreto/orc/internal/state"
 	"github.com/jorge-barreto/orc/internal/ux"
 )
@@ -26,20 +31,188 @@ var errStepRewind = errors.New("step rewind")
 
 // Runner drives the workflow state machine.
 type Runner struct {
-	Config       *config.Config
-	State        *state.State
-	Env          *dispatch.Environment
-	Dispatcher   dispatch.Dispatcher
-	Timing       *state.Timing
-	Costs        *state.CostData
-	StepMode     bool
-	HistoryLimit int
-	StepPromptFn func(artifactsDir string, phaseIdx int, phaseName string) ux.StepAction
-	RePromptFn   func(ctx context.Context, phase config.Phase, env *dispatch.Environment, prompt, sessionID string) (*dispatch.Result, error)
-	skipped      map[string]bool
-	auditDir     string
-	baseCommit   string
-	attemptCount map[int]int // tracks phase attempts (includes pre-run hook failures where dispatch was skipped)
+	Config         *config.Config
+	State          *state.State
+	Env            *dispatch.Environment
+	Dispatcher     dispatch.Dispatcher
+	Timing         *state.Timing
+	Costs          *state.CostData
+	StepMode       bool
+	ResumeGate     bool
+	HistoryLimit   int
+	MaxDispatches  int                     // cap on total phase dispatches per run; 0 uses DefaultMaxDispatches
+	KeepGoing      bool                    // --keep-going: a failing parallel branch doesn't cancel its sibling
+	Labels         []string                // --labels: run only phases carrying one of these labels
+	EvalConditions bool                    // --eval-conditions: DryRunPrint evaluates phase conditions
+	AutoParallel   bool                    // --auto-parallel: run independent adjacent phases as parallel pairs
+	Metrics        *metrics.Registry       // --metrics-addr: live run counters; nil disables
+	OnEvent        func(dispatch.LogEvent) // --interactive-run: receives each lifecycle event; nil disables
+	StepPromptFn   func(artifactsDir string, phaseIdx int, phaseName string) ux.StepAction
+	RePromptFn     func(ctx context.Context, phase config.Phase, env *dispatch.Environment, prompt, sessionID string) (*dispatch.Result, error)
+	skipped        map[string]string // skipped phase name → reason
+	selected       map[string]bool   // phases chosen by Labels; nil runs every phase
+	auditDir       string
+	baseCommit     string
+	attemptCount   map[int]int     // tracks phase attempts (includes pre-run hook failures where dispatch was skipped)
+	dispatches     int             // phase dispatches so far in this invocation
+	history        []*state.Timing // timings of past completed runs, for remaining-time estimates
+}
+
+// DefaultMaxDispatches is the dispatch cap used when Runner.MaxDispatches is
+// unset. It is deliberately generous — per-phase loop.max is the real limit;
+// this only catches pathological jump cycles.
+const DefaultMaxDispatches = 1000
+
+// Skip reasons recorded for phases that never ran.
+const (
+	skipReasonCondition = "condition not met"
+	skipReasonDisabled  = "disabled"
+	skipReasonLabels    = "not selected by --labels"
+)
+
+// labelSelection returns the names of phases carrying any of labels, plus
+// the parallel-with partners of those phases so a parallel pair always runs
+// together.
+func labelSelection(phases []config.Phase, labels []string) map[string]bool {
+	selected := make(map[string]bool)
+	for _, p := range phases {
+		for _, l := range p.Labels {
+			if slices.Contains(labels, l) {
+				selected[p.Name] = true
+				break
+			}
+		}
+	}
+	for _, p := range phases {
+		if p.ParallelWith == "" {
+			continue
+		}
+		if selected[p.Name] || selected[p.ParallelWith] {
+			selected[p.Name] = true
+			selected[p.ParallelWith] = true
+		}
+	}
+	return selected
+}
+
+// autoParallelPairs finds adjacent phase pairs that --auto-parallel can run
+// together. Both phases must be enabled script phases that declare outputs,
+// with no loop, condition, or parallel-with of their own, and the second must
+// not be a jump target (it would be entered mid-pair). Agent phases are never
+// paired: an agent edits the work tree, which declared outputs say nothing
+// about, so a partner could run before or during its edits. The two are
+// independent when their outputs don't overlap and neither mentions the
+// other's outputs in its commands. Pairs are chosen greedily from the top and
+// only among selected phases (nil selects all).
+func autoParallelPairs(cfg *config.Config, selected map[string]bool) [][2]int {
+	targets := make(map[string]bool)
+	for _, p := range cfg.Phases {
+		if p.ParallelWith != "" {
+			targets[p.ParallelWith] = true
+		}
+		if p.Loop != nil {
+			targets[p.Loop.Goto] = true
+			if p.Loop.OnExhaust != nil {
+				targets[p.Loop.OnExhaust.Goto] = true
+			}
+		}
+		if p.OnReject != nil {
+			targets[p.OnReject.Goto] = true
+		}
+	}
+	eligible := func(p config.Phase) bool {
+		return p.Type == "script" && !p.Disabled && len(p.Outputs) > 0 &&
+			p.Loop == nil && p.Condition == "" && p.ParallelWith == "" && !targets[p.Name] &&
+			(selected == nil || selected[p.Name])
+	}
+	var pairs [][2]int
+	for i := 0; i+1 < len(cfg.Phases); i++ {
+		a, b := cfg.Phases[i], cfg.Phases[i+1]
+		if !eligible(a) || !eligible(b) || !independentPhases(a, b) {
+			continue
+		}
+		pairs = append(pairs, [2]int{i, i + 1})
+		i++
+	}
+	return pairs
+}
+
+// independentPhases reports whether neither phase produces an output the
+// other also produces or refers to.
+func independentPhases(a, b config.Phase) bool {
+	text := func(p config.Phase) string {
+		return strings.Join([]string{p.Run, p.Cwd, p.PreRun, p.PostRun, p.Verify, p.Stdin, p.StdinFile}, "\n")
+	}
+	names := func(p config.Phase) []string {
+		var out []string
+		for _, o := range p.Outputs {
+			name, _ := config.SplitOutput(o)
+			out = append(out, name)
+		}
+		return out
+	}
+	aText, bText := text(a), text(b)
+	for _, n := range names(a) {
+		if slices.Contains(names(b), n) || strings.Contains(bText, n) {
+			return false
+		}
+	}
+	for _, n := range names(b) {
+		if strings.Contains(aText, n) {
+			return false
+		}
+	}
+	return true
+}
+
+// runReadme renders the ORC-README.md written into the artifacts directory at
+// run start, so a shared or archived run explains itself: the ticket, the
+// workflow, its phases, and what each file and subdirectory holds.
+func (r *Runner) runReadme() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s — %s\n\n", r.Env.Ticket, r.Config.Name)
+	fmt.Fprintf(&b, "Artifacts of an orc run of the %q workflow", r.Config.Name)
+	if r.Env.Workflow != "" {
+		fmt.Fprintf(&b, " (.orc/workflows/%s)", r.Env.Workflow)
+	}
+	fmt.Fprintf(&b, " for ticket %s.\n\n## Phases\n\n", r.Env.Ticket)
+	for i, p := range r.Config.Phases {
+		fmt.Fprintf(&b, "%d. **%s** (%s)", i+1, p.Name, p.Type)
+		if p.Description != "" {
+			fmt.Fprintf(&b, " — %s", p.Description)
+		}
+		if p.Disabled {
+			b.WriteString(" — disabled")
+		}
+		b.WriteString("\n")
+		if len(p.Outputs) > 0 {
+			fmt.Fprintf(&b, "   - outputs: %s\n", strings.Join(r.Config.OutputPaths(p), ", "))
+		}
+	}
+	fmt.Fprintf(&b, "\n## Layout\n\nPhase N's files are named after its position in the list above.\n\n```\n%s\n```\n\n", docs.ArtifactsLayout())
+	fmt.Fprintf(&b, "Run `orc status %s` for results and `orc docs artifacts` for the full reference.\n", r.Env.Ticket)
+	return b.String()
+}
+
+// dispatchLimit returns the effective cap on phase dispatches per run.
+func (r *Runner) dispatchLimit() int {
+	if r.MaxDispatches > 0 {
+		return r.MaxDispatches
+	}
+	return DefaultMaxDispatches
+}
+
+// dispatchCapExceeded reports whether starting n more dispatches would exceed
+// the run's dispatch cap.
+func (r *Runner) dispatchCapExceeded(n int) bool {
+	return r.dispatches+n > r.dispatchLimit()
+}
+
+// failDispatchCap stops the run because the dispatch cap was reached.
+func (r *Runner) failDispatchCap(i int) error {
+	r.printRunSummary(i)
+	msg := fmt.Sprintf("dispatch cap exceeded — possible loop (%d phase dispatches, limit %d)", r.dispatches, r.dispatchLimit())
+	return r.failWithCategory(state.StatusFailed, ExitPhaseFailure, state.FailCategoryLoopExhaustion, msg, errors.New(msg))
 }
 
 // appendPhaseLog appends a message to the phase log file.
@@ -55,9 +228,10 @@ func appendPhaseLog(artifactsDir string, phaseIdx int, msg string) {
 
 // writePhaseMetadata writes structured metadata for a completed phase.
 // Errors are logged as warnings — metadata should not break the run.
-func writePhaseMetadata(artifactsDir string, phaseIdx int, meta *state.PhaseMetadata) {
+func writePhaseMetadata(artifactsDir string, phaseIdx int, meta *state.PhaseMetadata, outputs []string) {
+	meta.Outputs = state.RecordOutputs(artifactsDir, outputs)
 	if err := state.SaveMetadata(state.MetaPath(artifactsDir, phaseIdx), meta); err != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to write phase metadata: %v\n", err)
+		ux.Warnf("warning: failed to write phase metadata: %v\n", err)
 	}
 }
 
@@ -88,32 +262,152 @@ func buildPhaseMetadata(phase config.Phase, phaseIdx int, result *dispatch.Resul
 	return meta
 }
 
+// unsatisfiedOutputs returns the phase's declared outputs that are missing
+// or, with require-fresh-outputs, were last written before start (left over
+// from an earlier run). stale reports whether any entry is in the latter group.
+func (r *Runner) unsatisfiedOutputs(phase config.Phase, start time.Time) (missing []string, stale bool) {
+	outputs := r.Config.OutputPaths(phase)
+	missing = state.CheckOutputs(r.Env.ArtifactsDir, outputs)
+	if phase.RequireFreshOutputs {
+		old := state.StaleOutputs(r.Env.ArtifactsDir, outputs, start)
+		missing = append(missing, old...)
+		stale = len(old) > 0
+	}
+	return missing, stale
+}
+
+// outputsErrMsg describes unsatisfied outputs for logs and failure details.
+func outputsErrMsg(missing []string, stale bool) string {
+	if stale {
+		return fmt.Sprintf("missing or stale outputs: %v", missing)
+	}
+	return fmt.Sprintf("missing outputs: %v", missing)
+}
+
+// missingOutputsPrompt builds the single re-prompt sent when an agent phase
+// leaves outputs unsatisfied. It asks only for the missing files and names
+// the ones already present so the agent doesn't regenerate them.
+func missingOutputsPrompt(artifactsDir string, outputs, missing []string, stale bool) string {
+	var want, have []string
+	for _, o := range outputs {
+		path := filepath.Join(artifactsDir, o)
+		if slices.Contains(missing, o) {
+			want = append(want, path)
+		} else {
+			have = append(have, path)
+		}
+	}
+	what := "are missing"
+	if stale {
+		what = "are missing or were not written in this run"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "The following expected output files %s:\n%s\n", what, strings.Join(want, "\n"))
+	if len(have) > 0 {
+		fmt.Fprintf(&b, "\nThese outputs are already present — leave them as they are:\n%s\n", strings.Join(have, "\n"))
+	}
+	b.WriteString("\nPlease produce only the missing files now.")
+	return b.String()
+}
+
+// noteOptionalOutputs reports the unsatisfied outputs of an outputs-optional
+// phase on stderr and in its log. The phase is not re-prompted or failed.
+func noteOptionalOutputs(artifactsDir string, i int, phase config.Phase, missing []string, stale bool) {
+	msg := outputsErrMsg(missing, stale) + " (outputs-optional, continuing)"
+	ux.Warnf("  warning: phase %q: %s\n", phase.Name, msg)
+	appendPhaseLog(artifactsDir, i, fmt.Sprintf("\n[orc] %s\n", msg))
+}
+
+// emit sends a runner lifecycle event to the live event sink, if any.
+func (r *Runner) emit(ev dispatch.LogEvent) {
+	ev.Workflow = r.Env.Workflow
+	r.Env.Events.Emit(ev)
+	r.Metrics.Observe(ev)
+	if r.OnEvent != nil {
+		r.OnEvent(ev)
+	}
+}
+
+// emitPhaseEnd reports a finished dispatch of phase i to the live event sink.
+func (r *Runner) emitPhaseEnd(i int, result *dispatch.Result, err error, start, end time.Time) {
+	ev := dispatch.LogEvent{
+		Type:         "phase_end",
+		Phase:        r.Config.Phases[i].Name,
+		Index:        i + 1,
+		Status:       "ok",
+		DurationSecs: end.Sub(start).Seconds(),
+	}
+	if result != nil {
+		ev.ExitCode = result.ExitCode
+		ev.CostUSD = result.CostUSD
+	}
+	if phaseFailed(r.Config.Phases[i], result, err) {
+		ev.Status = "failed"
+	}
+	r.emit(ev)
+}
+
+// phaseFailed reports whether a dispatch failed: it errored, timed out, or
+// exited with a code the phase doesn't accept via success-exit-codes.
+func phaseFailed(phase config.Phase, result *dispatch.Result, err error) bool {
+	if err != nil {
+		return true
+	}
+	if result == nil {
+		return false
+	}
+	return result.TimedOut || result.Stalled || !phase.SucceedsWith(result.ExitCode)
+}
+
+// timeoutMessage describes a phase timeout, naming the run flag when an
+// --agent-timeout or --script-timeout override was in effect.
+func timeoutMessage(phase config.Phase, env *dispatch.Environment) string {
+	timeout := dispatch.PhaseTimeout(phase, env)
+	if timeout != time.Duration(phase.Timeout)*time.Minute {
+		return fmt.Sprintf("timed out after %s — set by --%s-timeout (config timeout: %dm)", timeout, phase.Type, phase.Timeout)
+	}
+	return fmt.Sprintf("timed out after %dm — consider increasing 'timeout' in config (current: %d)", phase.Timeout, phase.Timeout)
+}
+
+// inactivityMessage describes a phase killed by its inactivity-timeout watchdog.
+func inactivityMessage(phase config.Phase) string {
+	return fmt.Sprintf("no output for %dm — possible hang (inactivity-timeout: %d)", phase.InactivityTimeout, phase.InactivityTimeout)
+}
+
+// noteAcceptedExit records in the phase log that a non-zero exit was taken
+// as success because success-exit-codes lists it.
+func noteAcceptedExit(artifactsDir string, i int, phase config.Phase, result *dispatch.Result) {
+	if result != nil && result.ExitCode != 0 && phase.SucceedsWith(result.ExitCode) {
+		appendPhaseLog(artifactsDir, i, fmt.Sprintf("\n[orc] exit code %d treated as success (success-exit-codes)\n", result.ExitCode))
+	}
+}
+
 // failAndHint sets the failure status, saves state (warning on error),
 // flushes timing, prints a resume hint, and returns the given error.
 func (r *Runner) failAndHint(status string, exitCode int, err error) error {
 	r.State.SetStatus(status)
 	if saveErr := r.State.Save(r.Env.ArtifactsDir); saveErr != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to save state: %v\n", saveErr)
+		ux.Warnf("warning: failed to save state: %v\n", saveErr)
 	}
 	if r.auditDir != "" {
 		if saveErr := r.State.Save(r.auditDir); saveErr != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to save audit state: %v\n", saveErr)
+			ux.Warnf("warning: failed to save audit state: %v\n", saveErr)
 		}
 	}
 	if r.Timing != nil {
 		if flushErr := r.Timing.Flush(r.auditDir); flushErr != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to flush timing: %v\n", flushErr)
+			ux.Warnf("warning: failed to flush timing: %v\n", flushErr)
 		}
 		if flushErr := r.Timing.Flush(r.Env.ArtifactsDir); flushErr != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to flush timing to artifacts: %v\n", flushErr)
+			ux.Warnf("warning: failed to flush timing to artifacts: %v\n", flushErr)
 		}
 	}
 	if r.Costs != nil {
 		if flushErr := r.Costs.Flush(r.auditDir); flushErr != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to flush costs: %v\n", flushErr)
+			ux.Warnf("warning: failed to flush costs: %v\n", flushErr)
 		}
 		if flushErr := r.Costs.Flush(r.Env.ArtifactsDir); flushErr != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to flush costs to artifacts: %v\n", flushErr)
+			ux.Warnf("warning: failed to flush costs to artifacts: %v\n", flushErr)
 		}
 	}
 	ux.ResumeHint(r.State.GetTicket(), r.State.GetSessionID() != "")
@@ -129,6 +423,7 @@ func (r *Runner) failAndHint(status string, exitCode int, err error) error {
 		}
 	}
 	r.writeRunResult(exitCode, failedPhase)
+	r.emit(dispatch.LogEvent{Type: "run_end", Status: status, ExitCode: exitCode})
 	return &ExitError{Code: exitCode, Err: err}
 }
 
@@ -146,6 +441,45 @@ func (r *Runner) printRunSummary(failedPhase int) {
 	ux.RunSummary(r.Config.Phases, r.Timing, failedPhase, r.skipped)
 }
 
+// producedOutputs returns the declared outputs of the phases that ran, as
+// ux.ProducedArtifacts whose Path is relative to the artifacts directory.
+// Outputs that don't exist (e.g. of a skipped phase) are left out.
+func (r *Runner) producedOutputs() []ux.ProducedArtifact {
+	var produced []ux.ProducedArtifact
+	for _, p := range r.Config.Phases {
+		if _, skipped := r.skipped[p.Name]; p.Disabled || skipped {
+			continue
+		}
+		paths := r.Config.OutputPaths(p)
+		missing := state.CheckOutputs(r.Env.ArtifactsDir, paths)
+		for _, path := range paths {
+			if !slices.Contains(missing, path) {
+				produced = append(produced, ux.ProducedArtifact{Phase: p.Name, Path: path})
+			}
+		}
+	}
+	return produced
+}
+
+// printCompletionBanner lists the produced outputs under dir — the archived
+// run once the artifacts have moved to history — relative to the project
+// root when possible.
+func (r *Runner) printCompletionBanner(produced []ux.ProducedArtifact, dir string) {
+	for i := range produced {
+		path := filepath.Join(dir, produced[i].Path)
+		if _, err := os.Stat(path); err != nil {
+			// Unprefixed outputs stay in the artifacts dir while another
+			// workflow's run shares it (see state.ArchiveRun).
+			path = filepath.Join(r.Env.ArtifactsDir, produced[i].Path)
+		}
+		if rel, err := filepath.Rel(r.Env.ProjectRoot, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+		produced[i].Path = path
+	}
+	ux.CompletionBanner(r.Env.Ticket, produced, ux.Warnings() > 0)
+}
+
 // captureBaseCommit returns the current HEAD short hash, or empty string on failure.
 func captureBaseCommit(projectRoot string) string {
 	cmd := exec.Command("git", "rev-parse", "--short", "HEAD")
@@ -197,11 +531,11 @@ func (r *Runner) writeRunResult(exitCode int, failedPhase string) *state.RunResu
 	result := r.buildRunResult(exitCode, failedPhase)
 	if r.auditDir != "" {
 		if err := state.WriteRunResult(r.auditDir, result); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to write run-result.json to audit: %v\n", err)
+			ux.Warnf("warning: failed to write run-result.json to audit: %v\n", err)
 		}
 	}
 	if err := state.WriteRunResult(r.Env.ArtifactsDir, result); err != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to write run-result.json: %v\n", err)
+		ux.Warnf("warning: failed to write run-result.json: %v\n", err)
 	}
 	return result
 }
@@ -227,9 +561,15 @@ func (r *Runner) buildPhaseResults(failedPhase string) []state.PhaseResult {
 	}
 
 	for i, phase := range r.Config.Phases {
+		skipReason := r.skipped[phase.Name]
+		if phase.Disabled {
+			skipReason = skipReasonDisabled
+		}
 		var status string
 		switch {
-		case r.skipped != nil && r.skipped[phase.Name]:
+		case phase.Disabled:
+			status = state.PhaseStatusDisabled
+		case skipReason != "":
 			status = state.PhaseStatusSkipped
 		case failedPhase == phase.Name:
 			status = state.PhaseStatusFailed
@@ -260,6 +600,7 @@ func (r *Runner) buildPhaseResults(failedPhase string) []state.PhaseResult {
 			Status:          status,
 			DurationSeconds: durations[phase.Name],
 			CostUSD:         costUSD,
+			SkipReason:      skipReason,
 		})
 	}
 	return results
@@ -274,9 +615,29 @@ func (r *Runner) Run(ctx context.Context) error {
 	if err := state.EnsureDir(r.Env.ArtifactsDir); err != nil {
 		return setupErr(err)
 	}
+	if r.Config.GitignoreArtifacts() {
+		if err := state.EnsureGitignore(r.Env.ArtifactsDir); err != nil {
+			return setupErr(err)
+		}
+	}
+	for _, p := range r.Config.Phases {
+		if dir := r.Config.OutputDir(p); dir != "" {
+			if err := os.MkdirAll(filepath.Join(r.Env.ArtifactsDir, dir), 0755); err != nil {
+				return setupErr(fmt.Errorf("creating output directory: %w", err))
+			}
+		}
+	}
 
-	// Initialize audit dir for costs, timing, and log archives
-	r.auditDir = state.AuditDirForWorkflow(r.Env.ProjectRoot, r.Env.Workflow, r.Env.Ticket)
+	r.Env.WorkflowName = r.Config.Name
+	if err := state.WriteFileAtomic(state.ReadmePath(r.Env.ArtifactsDir), []byte(r.runReadme()), 0644); err != nil {
+		ux.Warnf("warning: failed to write artifacts README: %v\n", err)
+	}
+
+	// Initialize audit dir for costs, timing, and log archives. A bisect
+	// trial arrives with a scratch one already set.
+	if r.auditDir == "" {
+		r.auditDir = state.AuditDirForWorkflow(r.Env.ProjectRoot, r.Env.Workflow, r.Env.Ticket)
+	}
 	if err := os.MkdirAll(r.auditDir, 0755); err != nil {
 		return setupErr(fmt.Errorf("creating audit dir: %w", err))
 	}
@@ -297,7 +658,28 @@ func (r *Runner) Run(ctx context.Context) error {
 		return setupErr(fmt.Errorf("loading costs: %w", err))
 	}
 	r.Costs = costs
-	r.skipped = make(map[string]bool)
+	r.skipped = make(map[string]string)
+	if len(r.Labels) > 0 {
+		r.selected = labelSelection(r.Config.Phases, r.Labels)
+		if len(r.selected) == 0 {
+			return setupErr(fmt.Errorf("--labels %s matches no phase", strings.Join(r.Labels, ",")))
+		}
+	}
+	if r.AutoParallel {
+		pairs := autoParallelPairs(r.Config, r.selected)
+		if len(pairs) > 0 {
+			// Pair up a copy: the caller's config stays as declared for
+			// anything else sharing it (a retry attempt, the live board).
+			cfg := *r.Config
+			cfg.Phases = slices.Clone(r.Config.Phases)
+			r.Config = &cfg
+		}
+		for _, pair := range pairs {
+			first, second := r.Config.Phases[pair[0]].Name, r.Config.Phases[pair[1]].Name
+			r.Config.Phases[pair[0]].ParallelWith = second
+			fmt.Fprintf(os.Stderr, "note: --auto-parallel: running %q and %q together (no shared outputs)\n", first, second)
+		}
+	}
 
 	attemptCounts, err := state.LoadAttemptCounts(r.auditDir)
 	if err != nil {
@@ -305,13 +687,26 @@ func (r *Runner) Run(ctx context.Context) error {
 	}
 	r.attemptCount = attemptCounts
 	r.baseCommit = captureBaseCommit(r.Env.ProjectRoot)
+	r.history = ux.HistoricalTimings(r.Env.ArtifactsDir)
 
 	total := len(r.Config.Phases)
 
+	if r.ResumeGate {
+		idx := r.State.GetPhaseIndex()
+		if idx >= total || r.Config.Phases[idx].Type != "gate" {
+			return &ExitError{Code: ExitResumeFailure, Err: fmt.Errorf("no stopped gate to re-answer (use --retry to restart a phase)")}
+		}
+		// The gate is about to be answered again — the prior rejection no
+		// longer describes this run.
+		r.State.SetFailure("", "")
+	}
+
 mainLoop:
 	for r.State.GetPhaseIndex() < total {
 		i := r.State.GetPhaseIndex()
 		phase := r.Config.Phases[i]
+		r.Env.SecretEnv = nil // set only around this phase's own dispatch below
+		r.Env.Deliverables = nil
 
 		// Check for context cancellation
 		if ctx.Err() != nil {
@@ -329,11 +724,47 @@ mainLoop:
 				fmt.Errorf("run exceeded cost limit: $%.2f > $%.2f", r.Costs.TotalCost(), r.Config.MaxCost))
 		}
 
+		// Disabled phases are skipped without evaluating their condition
+		if phase.Disabled {
+			ux.PhaseDisabled(i, len(r.Config.Phases), phase.Name)
+			r.emit(dispatch.LogEvent{Type: "phase_skip", Phase: phase.Name, Index: i + 1, Reason: skipReasonDisabled})
+			r.State.Advance()
+			if err := r.State.Save(r.Env.ArtifactsDir); err != nil {
+				return fmt.Errorf("saving state after skip: %w", err)
+			}
+			continue
+		}
+
+		// Phases outside the --labels selection are skipped the same way
+		if r.selected != nil && !r.selected[phase.Name] {
+			ux.PhaseSkip(i, len(r.Config.Phases), phase.Name, skipReasonLabels)
+			r.skip(i, skipReasonLabels)
+			r.State.Advance()
+			if err := r.State.Save(r.Env.ArtifactsDir); err != nil {
+				return fmt.Errorf("saving state after skip: %w", err)
+			}
+			continue
+		}
+
 		// Evaluate condition
 		if phase.Condition != "" {
-			if !evalCondition(ctx, phase, r.Env) {
-				ux.PhaseSkip(i, phase.Name)
-				r.skipped[phase.Name] = true
+			pass, timedOut := evalCondition(ctx, phase, r.Env)
+			reason := skipReasonCondition
+			if timedOut {
+				msg := fmt.Sprintf("condition timed out after %ds", phase.ConditionTimeout)
+				if phase.OnConditionTimeout == "fail" {
+					ux.PhaseFail(i, len(r.Config.Phases), phase.Name, msg)
+					r.printRunSummary(i)
+					return r.failWithCategory(state.StatusFailed, ExitTimeout, state.FailCategoryTimeout,
+						fmt.Sprintf("phase %q: %s", phase.Name, msg),
+						fmt.Errorf("phase %q: %s", phase.Name, msg))
+				}
+				ux.Warnf("warning: phase %q: %s — skipping the phase\n", phase.Name, msg)
+				reason = msg
+			}
+			if !pass {
+				ux.PhaseSkip(i, len(r.Config.Phases), phase.Name, reason)
+				r.skip(i, reason)
 				r.State.Advance()
 				if err := r.State.Save(r.Env.ArtifactsDir); err != nil {
 					return fmt.Errorf("saving state after skip: %w", err)
@@ -342,13 +773,17 @@ mainLoop:
 			}
 		}
 
-		// Handle parallel-with
+		// Handle parallel-with (a disabled partner leaves this phase to run alone)
 		if phase.ParallelWith != "" {
-			partnerIdx := r.Config.PhaseIndex(phase.ParallelWith)
-			if partnerIdx < 0 {
+			partner, partnerIdx, ok := r.Config.PhaseByName(phase.ParallelWith)
+			if !ok {
 				return r.failAndHint(state.StatusFailed, ExitConfigError, fmt.Errorf("phase %q: parallel-with %q not found", phase.Name, phase.ParallelWith))
 			}
-			if partnerIdx > i {
+			if partnerIdx > i && !partner.Disabled {
+				if r.dispatchCapExceeded(2) {
+					return r.failDispatchCap(i)
+				}
+				r.dispatches += 2
 				err := r.runParallel(ctx, i, partnerIdx, total, loopCounts)
 				if err == errStepRewind {
 					continue
@@ -361,11 +796,23 @@ mainLoop:
 		}
 
 		// Normal dispatch
-		ux.PhaseHeader(i, total, phase)
+		if r.dispatchCapExceeded(1) {
+			return r.failDispatchCap(i)
+		}
+		r.dispatches++
+		ux.PhaseHeader(i, r.Config.Phases, r.history)
+		r.emit(dispatch.LogEvent{Type: "phase_start", Phase: phase.Name, Index: i + 1})
 		start := time.Now()
 		r.Timing.AddStartAt(phase.Name, start)
 
 		r.Env.PhaseIndex = i
+		r.Env.PhaseDescription = phase.Description
+		r.Env.PhaseOutputDir = r.phaseOutputDir(phase)
+		r.Env.SecretEnv = phase.SecretEnv
+		if phase.Type == "publish" {
+			r.Env.Deliverables = r.Config.DeliverablePaths()
+		}
+		r.Env.Attempt = r.attemptCount[i] + 1
 		var result *dispatch.Result
 		var err error
 		switch phase.Type {
@@ -376,6 +823,9 @@ mainLoop:
 		default:
 			result, err = r.dispatchWithHooks(ctx, phase, r.Env)
 		}
+		if result != nil && result.ApproveAllGates {
+			r.Env.AutoApproveGates = true
+		}
 
 		// Persist session ID immediately so it survives interruption.
 		// Must happen before any error handling — if the process dies
@@ -383,7 +833,7 @@ mainLoop:
 		if phase.Type == "agent" && result != nil && result.SessionID != "" {
 			r.State.SetSessionID(result.SessionID)
 			if saveErr := r.State.Save(r.Env.ArtifactsDir); saveErr != nil {
-				fmt.Fprintf(os.Stderr, "warning: failed to save session ID: %v\n", saveErr)
+				ux.Warnf("warning: failed to save session ID: %v\n", saveErr)
 			}
 		}
 
@@ -397,7 +847,7 @@ mainLoop:
 			if phase.Type == "agent" && result != nil {
 				r.Costs.Record(phase.Name, i, result.CostUSD, result.InputTokens, result.OutputTokens, result.CacheCreationInputTokens, result.CacheReadInputTokens, result.Turns)
 				if flushErr := r.Costs.Flush(r.auditDir); flushErr != nil {
-					fmt.Fprintf(os.Stderr, "warning: failed to flush costs: %v\n", flushErr)
+					ux.Warnf("warning: failed to flush costs: %v\n", flushErr)
 				}
 			}
 
@@ -466,13 +916,14 @@ mainLoop:
 
 		// Write structured metadata before archiving
 		end := time.Now()
-		writePhaseMetadata(r.Env.ArtifactsDir, i, buildPhaseMetadata(phase, i, result, start, end))
+		writePhaseMetadata(r.Env.ArtifactsDir, i, buildPhaseMetadata(phase, i, result, start, end), r.Config.OutputPaths(phase))
+		r.emitPhaseEnd(i, result, err, start, end)
 
 		// Archive every attempt to audit (before any error/interrupt handling)
 		r.attemptCount[i]++
-		archivePhaseFiles(r.Env.ArtifactsDir, r.auditDir, i, r.attemptCount[i], phase.Outputs)
+		archivePhaseFiles(r.Env.ArtifactsDir, r.auditDir, i, r.attemptCount[i], r.Config.OutputPaths(phase))
 		if saveErr := state.SaveAttemptCounts(r.auditDir, r.attemptCount); saveErr != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to save attempt counts: %v\n", saveErr)
+			ux.Warnf("warning: failed to save attempt counts: %v\n", saveErr)
 		}
 
 		if ctx.Err() != nil {
@@ -492,7 +943,7 @@ mainLoop:
 				fmt.Fprintf(os.Stderr, "  note: no token counts in stream output for phase %q (token tracking is best-effort)\n", phase.Name)
 			}
 			if flushErr := r.Costs.Flush(r.auditDir); flushErr != nil {
-				fmt.Fprintf(os.Stderr, "warning: failed to flush costs: %v\n", flushErr)
+				ux.Warnf("warning: failed to flush costs: %v\n", flushErr)
 			}
 			// In-flight cost monitor tripped: subprocess was killed mid-stream.
 			// Fail fast — after a kill the result event may never arrive, so
@@ -518,23 +969,37 @@ mainLoop:
 			}
 		}
 
-		if err != nil || (result != nil && result.ExitCode != 0) {
+		if phaseFailed(phase, result, err) {
 			r.Timing.AddEnd(phase.Name)
+
+			// Rejected gate with on-reject continue/goto: keep going. This
+			// is not a failure, so it is reported by handleGateReject alone.
+			if phase.Type == "gate" && result != nil && result.Rejected &&
+				phase.OnReject != nil && phase.OnReject.Action != "stop" {
+				appendPhaseLog(r.Env.ArtifactsDir, i, fmt.Sprintf("\n[orc] gate %q rejected (on-reject %s)\n", phase.Name, phase.OnReject.Action))
+				if err := r.handleGateReject(i, phase, result.Output, loopCounts); err != nil {
+					return err
+				}
+				continue
+			}
+
 			errMsg := fmt.Sprintf("%s exited with non-zero status", phase.Type)
 			if result != nil && result.TimedOut {
-				errMsg = fmt.Sprintf("timed out after %dm — consider increasing 'timeout' in config (current: %d)", phase.Timeout, phase.Timeout)
+				errMsg = timeoutMessage(phase, r.Env)
+			} else if result != nil && result.Stalled {
+				errMsg = inactivityMessage(phase)
 			} else if err != nil {
 				errMsg = err.Error()
 			}
 			appendPhaseLog(r.Env.ArtifactsDir, i, fmt.Sprintf("\n[orc] phase %q failed: %s\n", phase.Name, errMsg))
-			ux.PhaseFail(i, phase.Name, errMsg)
+			ux.PhaseFail(i, len(r.Config.Phases), phase.Name, errMsg)
 			if phase.Type == "agent" {
 				fmt.Fprintf(os.Stderr, "  hint: if the agent couldn't perform actions, check your .claude/settings.local.json permissions\n")
 			}
 
 			// Handle loop
 			if phase.Loop != nil {
-				output := state.ReadDeclaredOutputs(r.Env.ArtifactsDir, phase.Outputs)
+				output := state.ReadDeclaredOutputs(r.Env.ArtifactsDir, r.Config.OutputPaths(phase))
 				if output == "" && result != nil {
 					output = result.Output
 				}
@@ -553,7 +1018,7 @@ mainLoop:
 			// No loop: stop
 			exitCode := ExitPhaseFailure
 			category := state.FailCategoryScriptFailure
-			if result != nil && result.TimedOut {
+			if result != nil && (result.TimedOut || result.Stalled) {
 				exitCode = ExitTimeout
 				category = state.FailCategoryTimeout
 			} else if phase.Type == "agent" {
@@ -566,19 +1031,18 @@ mainLoop:
 			r.printRunSummary(i)
 			return r.failWithCategory(state.StatusFailed, exitCode, category, errMsg, fmt.Errorf("phase %q failed", phase.Name))
 		}
+		noteAcceptedExit(r.Env.ArtifactsDir, i, phase, result)
 
 		// Check declared outputs
 		if len(phase.Outputs) > 0 {
-			missing := state.CheckOutputs(r.Env.ArtifactsDir, phase.Outputs)
+			missing, stale := r.unsatisfiedOutputs(phase, start)
+			if len(missing) > 0 && phase.OutputsOptional {
+				noteOptionalOutputs(r.Env.ArtifactsDir, i, phase, missing, stale)
+				missing = nil
+			}
 			if len(missing) > 0 && phase.Type == "agent" {
 				// Resume the agent session once for missing outputs
-				var paths []string
-				for _, m := range missing {
-					paths = append(paths, filepath.Join(r.Env.ArtifactsDir, m))
-				}
-				prompt := fmt.Sprintf(
-					"The following expected output files are missing:\n%s\nPlease produce them now.",
-					strings.Join(paths, "\n"))
+				prompt := missingOutputsPrompt(r.Env.ArtifactsDir, r.Config.OutputPaths(phase), missing, stale)
 				sessionID := ""
 				if result != nil {
 					sessionID = result.SessionID
@@ -591,28 +1055,28 @@ mainLoop:
 				reResult, reErr := rePromptFn(ctx, phase, r.Env, prompt, sessionID)
 				reEnd := time.Now()
 				if reErr != nil {
-					fmt.Fprintf(os.Stderr, "warning: re-prompt for missing outputs failed: %v\n", reErr)
+					ux.Warnf("warning: re-prompt for missing outputs failed: %v\n", reErr)
 				}
 				if reResult != nil && r.Costs != nil {
 					r.Costs.Record(phase.Name, i, reResult.CostUSD, reResult.InputTokens, reResult.OutputTokens, reResult.CacheCreationInputTokens, reResult.CacheReadInputTokens, reResult.Turns)
 					if flushErr := r.Costs.Flush(r.auditDir); flushErr != nil {
-						fmt.Fprintf(os.Stderr, "warning: failed to flush costs: %v\n", flushErr)
+						ux.Warnf("warning: failed to flush costs: %v\n", flushErr)
 					}
 				}
 				// Write metadata for re-prompt dispatch
 				if reResult != nil {
-					writePhaseMetadata(r.Env.ArtifactsDir, i, buildPhaseMetadata(phase, i, reResult, reStart, reEnd))
+					writePhaseMetadata(r.Env.ArtifactsDir, i, buildPhaseMetadata(phase, i, reResult, reStart, reEnd), r.Config.OutputPaths(phase))
 				}
 				r.attemptCount[i]++
-				archivePhaseFiles(r.Env.ArtifactsDir, r.auditDir, i, r.attemptCount[i], phase.Outputs)
+				archivePhaseFiles(r.Env.ArtifactsDir, r.auditDir, i, r.attemptCount[i], r.Config.OutputPaths(phase))
 				if saveErr := state.SaveAttemptCounts(r.auditDir, r.attemptCount); saveErr != nil {
-					fmt.Fprintf(os.Stderr, "warning: failed to save attempt counts: %v\n", saveErr)
+					ux.Warnf("warning: failed to save attempt counts: %v\n", saveErr)
 				}
-				missing = state.CheckOutputs(r.Env.ArtifactsDir, phase.Outputs)
+				missing, stale = r.unsatisfiedOutputs(phase, start)
 			}
 			if len(missing) > 0 {
-				errMsg := fmt.Sprintf("missing outputs: %v", missing)
-				ux.PhaseFail(i, phase.Name, errMsg)
+				errMsg := outputsErrMsg(missing, stale)
+				ux.PhaseFail(i, len(r.Config.Phases), phase.Name, errMsg)
 				if phase.Type == "agent" {
 					fmt.Fprintf(os.Stderr, "  hint: if the agent couldn't perform actions, check your .claude/settings.local.json permissions\n")
 				}
@@ -621,6 +1085,34 @@ mainLoop:
 				return r.failWithCategory(state.StatusFailed, ExitPhaseFailure, state.FailCategoryOutputMissing, errMsg,
 					fmt.Errorf("phase %q: %s", phase.Name, errMsg))
 			}
+			if errMsg := r.invalidOutputs(i, phase); errMsg != "" {
+				r.Timing.AddEnd(phase.Name)
+				r.printRunSummary(i)
+				return r.failWithCategory(state.StatusFailed, ExitPhaseFailure, state.FailCategoryOutputInvalid, errMsg,
+					fmt.Errorf("phase %q: %s", phase.Name, errMsg))
+			}
+		}
+
+		// Run verify if present (after output checks): a non-zero exit fails the phase
+		if phase.Verify != "" {
+			if verifyMsg, feedback := r.runVerify(ctx, i, phase); verifyMsg != "" {
+				r.Timing.AddEnd(phase.Name)
+				if phase.Loop != nil {
+					shouldContinue, loopErr := r.handleLoopFailure(i, phase, loopCounts, feedback)
+					if loopErr != nil {
+						return loopErr
+					}
+					if shouldContinue {
+						continue
+					}
+				}
+				if err := state.WriteFeedback(r.Env.ArtifactsDir, phase.Name, feedback); err != nil {
+					ux.Warnf("warning: failed to write feedback: %v\n", err)
+				}
+				r.printRunSummary(i)
+				return r.failWithCategory(state.StatusFailed, ExitPhaseFailure, state.FailCategoryVerifyFailure, verifyMsg,
+					fmt.Errorf("phase %q: %s", phase.Name, verifyMsg))
+			}
 		}
 
 		// Run loop.check if present (after phase success, before loop min enforcement)
@@ -631,9 +1123,9 @@ mainLoop:
 				r.Timing.AddEnd(phase.Name)
 				checkMsg := fmt.Sprintf("loop.check failed (exit %d)", checkCode)
 				appendPhaseLog(r.Env.ArtifactsDir, i, fmt.Sprintf("\n[orc] %s: %s\n%s", phase.Name, checkMsg, checkOutput))
-				ux.PhaseFail(i, phase.Name, checkMsg)
+				ux.PhaseFail(i, len(r.Config.Phases), phase.Name, checkMsg)
 
-				feedback := state.ReadDeclaredOutputs(r.Env.ArtifactsDir, phase.Outputs)
+				feedback := state.ReadDeclaredOutputs(r.Env.ArtifactsDir, r.Config.OutputPaths(phase))
 				shouldContinue, loopErr := r.handleLoopFailure(i, phase, loopCounts, feedback)
 				if loopErr != nil {
 					return loopErr
@@ -649,6 +1141,7 @@ mainLoop:
 		if phase.Loop != nil {
 			iteration := loopCounts[phase.Name] + 1
 			loopCounts[phase.Name] = iteration
+			r.Metrics.LoopIteration(phase.Name)
 
 			if iteration < phase.Loop.Min {
 				// min not reached — forced loop-back with success output as feedback
@@ -691,17 +1184,21 @@ mainLoop:
 			archiveAndClearFeedback(r.Env.ArtifactsDir, r.auditDir, i, r.attemptCount[i])
 		}
 
+		// The phase passed, so any feedback it wrote on an earlier failure no
+		// longer describes a live problem.
+		archiveFeedbackFrom(r.Env.ArtifactsDir, r.auditDir, i, r.attemptCount[i], phase.Name)
+
 		duration := time.Since(start)
 		r.Timing.AddEnd(phase.Name)
 		if err := r.Timing.Flush(r.auditDir); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to flush timing: %v\n", err)
+			ux.Warnf("warning: failed to flush timing: %v\n", err)
 		}
 		r.State.Advance()
 		r.State.SetStatus(state.StatusRunning)
 		if err := r.State.Save(r.Env.ArtifactsDir); err != nil {
 			return fmt.Errorf("saving state after phase advance: %w", err)
 		}
-		ux.PhaseComplete(i, phase.Name, duration)
+		ux.PhaseComplete(i, len(r.Config.Phases), phase.Name, duration)
 
 		// Step-through pause
 		if r.StepMode {
@@ -751,40 +1248,53 @@ mainLoop:
 		return r.failWithCategory(state.StatusFailed, ExitInfraError, state.FailCategoryStateSave, err.Error(), fmt.Errorf("saving final state: %w", err))
 	}
 	if saveErr := r.State.Save(r.auditDir); saveErr != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to save audit state: %v\n", saveErr)
+		ux.Warnf("warning: failed to save audit state: %v\n", saveErr)
 	}
 	if flushErr := r.Timing.Flush(r.auditDir); flushErr != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to flush timing to audit: %v\n", flushErr)
+		ux.Warnf("warning: failed to flush timing to audit: %v\n", flushErr)
 	}
 	if flushErr := r.Costs.Flush(r.auditDir); flushErr != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to flush costs to audit: %v\n", flushErr)
+		ux.Warnf("warning: failed to flush costs to audit: %v\n", flushErr)
 	}
 	// Flush timing and costs to artifacts dir so they are included in the archive
 	if flushErr := r.Timing.Flush(r.Env.ArtifactsDir); flushErr != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to flush timing to artifacts: %v\n", flushErr)
+		ux.Warnf("warning: failed to flush timing to artifacts: %v\n", flushErr)
 	}
 	if flushErr := r.Costs.Flush(r.Env.ArtifactsDir); flushErr != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to flush costs to artifacts: %v\n", flushErr)
+		ux.Warnf("warning: failed to flush costs to artifacts: %v\n", flushErr)
 	}
 	runResult := r.writeRunResult(ExitSuccess, "")
+	r.emit(dispatch.LogEvent{Type: "run_end", Status: state.StatusCompleted})
 	r.printRunSummary(-1)
+	produced := r.producedOutputs()
 	// Archive run to history
+	outputsDir := r.Env.ArtifactsDir
 	if runID, archiveErr := state.ArchiveRun(r.Env.ArtifactsDir); archiveErr != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to archive run: %v\n", archiveErr)
-	} else if !ux.QuietMode {
-		fmt.Printf("  %sRun archived:%s %s\n", ux.Dim, ux.Reset, runID)
+		ux.Warnf("warning: failed to archive run: %v\n", archiveErr)
+	} else {
+		outputsDir = filepath.Join(state.HistoryDir(r.Env.ArtifactsDir), runID)
+		if !ux.QuietMode {
+			fmt.Printf("  %sRun archived:%s %s\n\n", ux.Dim, ux.Reset, runID)
+		}
 	}
+	r.printCompletionBanner(produced, outputsDir)
 	// Restore run-result.json after archive so it remains accessible in the current artifacts dir.
 	// Reuse the cached result — no need to re-collect commits or write to audit dir again.
 	if err := state.WriteRunResult(r.Env.ArtifactsDir, runResult); err != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to restore run-result.json: %v\n", err)
+		ux.Warnf("warning: failed to restore run-result.json: %v\n", err)
 	}
 	if pruneErr := state.PruneHistory(r.Env.ArtifactsDir, r.HistoryLimit); pruneErr != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to prune history: %v\n", pruneErr)
+		ux.Warnf("warning: failed to prune history: %v\n", pruneErr)
 	}
 	return nil
 }
 
+// phaseOutputDir returns the absolute directory phase's outputs resolve
+// under, exposed to the phase as $PHASE_OUTPUT_DIR.
+func (r *Runner) phaseOutputDir(phase config.Phase) string {
+	return filepath.Join(r.Env.ArtifactsDir, r.Config.OutputDir(phase))
+}
+
 // prepareBackwardJump resets state for phases that will be re-executed after a backward jump.
 // It clears loop counters for phases in [gotoIdx, currentIdx) and removes stale feedback.
 // The jumping phase's own counter (at currentIdx) is NOT touched — the caller manages it.
@@ -800,11 +1310,66 @@ func (r *Runner) prepareBackwardJump(gotoIdx, currentIdx int, loopCounts map[str
 	return state.ClearFeedback(r.Env.ArtifactsDir)
 }
 
+// skip records phases[i] as skipped for reason and emits a phase_skip event.
+func (r *Runner) skip(i int, reason string) {
+	name := r.Config.Phases[i].Name
+	r.skipped[name] = reason
+	r.emit(dispatch.LogEvent{Type: "phase_skip", Phase: name, Index: i + 1, Reason: reason})
+}
+
+// handleGateReject applies a gate's on-reject continue/goto after the
+// operator rejected it: the rejection feedback is saved for later phases and
+// the run moves on to the next phase or the goto target. Phases jumped over
+// by a forward goto are recorded as skipped.
+func (r *Runner) handleGateReject(i int, phase config.Phase, feedback string, loopCounts map[string]int) error {
+	nextIdx := i + 1
+	if phase.OnReject.Action == "goto" {
+		nextIdx = r.Config.PhaseIndex(phase.OnReject.Goto)
+		if nextIdx < 0 {
+			return r.failAndHint(state.StatusFailed, ExitConfigError,
+				fmt.Errorf("phase %q: on-reject.goto %q not found", phase.Name, phase.OnReject.Goto))
+		}
+	}
+
+	if nextIdx < i {
+		if err := r.prepareBackwardJump(nextIdx, i, loopCounts); err != nil {
+			return r.failAndHint(state.StatusFailed, ExitPhaseFailure, err)
+		}
+		if err := state.SaveLoopCounts(r.Env.ArtifactsDir, loopCounts); err != nil {
+			return r.failAndHint(state.StatusFailed, ExitPhaseFailure, fmt.Errorf("saving loop counts: %w", err))
+		}
+	} else {
+		for mid := i + 1; mid < nextIdx; mid++ {
+			r.skip(mid, fmt.Sprintf("jumped over by %s's on-reject goto", phase.Name))
+		}
+	}
+	if err := state.WriteFeedback(r.Env.ArtifactsDir, phase.Name, feedback); err != nil {
+		return r.failAndHint(state.StatusFailed, ExitPhaseFailure, fmt.Errorf("writing feedback: %w", err))
+	}
+
+	next := "end of workflow"
+	if nextIdx < len(r.Config.Phases) {
+		next = r.Config.Phases[nextIdx].Name
+	}
+	ux.GateRejected(phase.Name, next)
+
+	if err := r.Timing.Flush(r.auditDir); err != nil {
+		ux.Warnf("warning: failed to flush timing: %v\n", err)
+	}
+	r.State.SetPhase(nextIdx)
+	r.State.SetStatus(state.StatusRunning)
+	if err := r.State.Save(r.Env.ArtifactsDir); err != nil {
+		return r.failAndHint(state.StatusFailed, ExitPhaseFailure, fmt.Errorf("saving state after gate rejection: %w", err))
+	}
+	return nil
+}
+
 // handleLoopFailure processes a loop iteration failure (from phase failure or check failure).
 // output is the content to write as feedback. Returns true if the main loop should continue.
 func (r *Runner) handleLoopFailure(i int, phase config.Phase, loopCounts map[string]int, output string) (bool, error) {
 	iteration := loopCounts[phase.Name] + 1
 	loopCounts[phase.Name] = iteration
+	r.Metrics.LoopIteration(phase.Name)
 
 	if iteration >= phase.Loop.Max {
 		// Loop exhausted
@@ -813,7 +1378,7 @@ func (r *Runner) handleLoopFailure(i int, phase config.Phase, loopCounts map[str
 			exhaustCount := loopCounts[exhaustKey] + 1
 			if exhaustCount > phase.Loop.OnExhaust.Max {
 				if err := state.SaveLoopCounts(r.Env.ArtifactsDir, loopCounts); err != nil {
-					fmt.Fprintf(os.Stderr, "warning: failed to save loop counts: %v\n", err)
+					ux.Warnf("warning: failed to save loop counts: %v\n", err)
 				}
 				if !ux.QuietMode {
 					fmt.Printf("\n  Phase %q: loop exhausted after %d iterations, recovery exhausted after %d attempts. Manual intervention needed.\n",
@@ -859,7 +1424,7 @@ func (r *Runner) handleLoopFailure(i int, phase config.Phase, loopCounts map[str
 
 		// No on-exhaust: hard fail
 		if err := state.SaveLoopCounts(r.Env.ArtifactsDir, loopCounts); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to save loop counts: %v\n", err)
+			ux.Warnf("warning: failed to save loop counts: %v\n", err)
 		}
 		if !ux.QuietMode {
 			fmt.Printf("\n  Phase %q failed after %d iterations. Manual intervention needed.\n",
@@ -878,6 +1443,13 @@ func (r *Runner) handleLoopFailure(i int, phase config.Phase, loopCounts map[str
 			fmt.Errorf("phase %q: loop.goto %q not found", phase.Name, phase.Loop.Goto))
 	}
 
+	// With feedback-history, carry earlier attempts forward — read them
+	// before prepareBackwardJump clears the feedback directory.
+	if phase.Loop.FeedbackHistory > 1 {
+		previous := state.ReadFeedback(r.Env.ArtifactsDir, phase.Name)
+		output = state.FeedbackWithHistory(previous, output, iteration, phase.Loop.FeedbackHistory)
+	}
+
 	if err := r.prepareBackwardJump(gotoIdx, i, loopCounts); err != nil {
 		return false, r.failAndHint(state.StatusFailed, ExitPhaseFailure, err)
 	}
@@ -911,7 +1483,7 @@ func (r *Runner) waitForRateLimit(ctx context.Context, phaseName string, resetAt
 	// entry at line 344 when it re-enters on continue.
 	r.Timing.AddEnd(phaseName)
 	if flushErr := r.Timing.Flush(r.auditDir); flushErr != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to flush timing: %v\n", flushErr)
+		ux.Warnf("warning: failed to flush timing: %v\n", flushErr)
 	}
 
 	const heartbeatInterval = 60 * time.Second
@@ -959,12 +1531,159 @@ func runLoopCheck(ctx context.Context, check string, phase config.Phase, env *di
 	return 0, buf.String()
 }
 
-// DryRunPrint prints the phase plan without executing.
-func (r *Runner) DryRunPrint() {
+// invalidOutputs validates the content of the phase's type-annotated outputs
+// ("report.json:json"). On failure it logs and reports the problems and
+// returns them joined into one message; it returns "" when all are valid.
+func (r *Runner) invalidOutputs(i int, phase config.Phase) string {
+	problems := state.ValidateOutputs(r.Env.ArtifactsDir, r.Config.OutputContentTypes(phase))
+	if len(problems) == 0 {
+		return ""
+	}
+	msg := strings.Join(problems, "; ")
+	appendPhaseLog(r.Env.ArtifactsDir, i, fmt.Sprintf("\n[orc] phase %q failed: %s\n", phase.Name, msg))
+	ux.PhaseFail(i, len(r.Config.Phases), phase.Name, msg)
+	return msg
+}
+
+// runVerify runs phase.Verify after a successful dispatch. On a non-zero
+// exit it logs and reports the failure and returns the failure message and
+// the feedback to record (the command's output); both are empty on success.
+func (r *Runner) runVerify(ctx context.Context, i int, phase config.Phase) (msg, feedback string) {
+	code, output := runLoopCheck(ctx, phase.Verify, phase, r.Env)
+	if code == 0 {
+		return "", ""
+	}
+	msg = fmt.Sprintf("verify failed (exit %d)", code)
+	appendPhaseLog(r.Env.ArtifactsDir, i, fmt.Sprintf("\n[orc] %s: %s\n%s", phase.Name, msg, output))
+	ux.PhaseFail(i, len(r.Config.Phases), phase.Name, msg)
+	feedback = output
+	if strings.TrimSpace(feedback) == "" {
+		feedback = msg
+	}
+	return msg, feedback
+}
+
+// DryRunPrint prints the phase plan without executing. With EvalConditions,
+// phase conditions are run under ctx, so an interrupt cancels them.
+func (r *Runner) DryRunPrint(ctx context.Context) {
 	expandFn := func(s string) string {
 		return dispatch.ExpandVars(s, r.Env.DryRunVars())
 	}
 	ux.FlowDiagram(r.Config, r.Env.CustomVars, expandFn)
+	ux.LongPhaseNote(r.Config.Phases, ux.HistoricalTimings(r.Env.ArtifactsDir))
+	if r.EvalConditions {
+		ux.ConditionPlan(r.conditionPlan(ctx))
+	}
+
+	estimates := r.promptEstimates()
+	ux.PromptCostEstimate(estimates)
+
+	limit := r.Config.MaxPromptBytes
+	if limit == 0 {
+		limit = dispatch.DefaultMaxPromptBytes
+	}
+	for _, e := range estimates {
+		if e.Err == nil && e.Bytes > limit {
+			ux.Warnf("warning: phase %d (%s): rendered prompt is %d bytes (~%d tokens), over the %d-byte max-prompt-bytes limit\n",
+				e.Index+1, e.Name, e.Bytes, e.Tokens, limit)
+		}
+	}
+}
+
+// promptEstimates renders every enabled agent phase's prompt to estimate
+// its size and input cost.
+func (r *Runner) promptEstimates() []ux.PromptEstimate {
+	var estimates []ux.PromptEstimate
+	for i, phase := range r.Config.Phases {
+		if phase.Type != "agent" || phase.Disabled {
+			continue
+		}
+		env := *r.Env
+		env.PhaseIndex = i
+		env.PhaseDescription = phase.Description
+		env.PhaseOutputDir = r.phaseOutputDir(phase)
+		size, tokens, cost, err := dispatch.EstimatePrompt(phase, &env)
+		estimates = append(estimates, ux.PromptEstimate{
+			Index: i, Name: phase.Name, Model: phase.Model, Bytes: size, Tokens: tokens, CostUSD: cost, Err: err,
+		})
+	}
+	return estimates
+}
+
+// ConfirmPreview summarizes the run for the --confirm prompt: what would
+// execute, its estimated prompt cost, and which gates will stop for input.
+func (r *Runner) ConfirmPreview() ux.RunPreview {
+	p := ux.RunPreview{Workflow: r.Config.Name, Ticket: r.Env.Ticket}
+	for _, phase := range r.Config.Phases {
+		if phase.Disabled {
+			continue
+		}
+		p.Phases++
+		if phase.Type == "gate" && !r.Env.AutoMode {
+			p.Gates = append(p.Gates, phase.Name)
+		}
+	}
+	for _, e := range r.promptEstimates() {
+		if e.Err != nil {
+			p.EstimateErrors++
+			continue
+		}
+		p.CostUSD += e.CostUSD
+	}
+	return p
+}
+
+// conditionPlan evaluates every enabled phase's condition without running
+// the phase, for the dry-run condition preview.
+func (r *Runner) conditionPlan(ctx context.Context) []ux.ConditionPreview {
+	rows := make([]ux.ConditionPreview, 0, len(r.Config.Phases))
+	for i, phase := range r.Config.Phases {
+		row := ux.ConditionPreview{Index: i, Name: phase.Name, Condition: phase.Condition, Disabled: phase.Disabled, Run: true}
+		if phase.Condition != "" && !phase.Disabled {
+			env := *r.Env
+			env.PhaseIndex = i
+			env.PhaseDescription = phase.Description
+			env.PhaseOutputDir = r.phaseOutputDir(phase)
+			row.Run, row.TimedOut = evalCondition(ctx, phase, &env)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// parallelGroupName returns the name used for a parallel pair's shared loop
+// counter and aggregated feedback file (from-<first>+<second>.md).
+func parallelGroupName(first, second config.Phase) string {
+	return first.Name + "+" + second.Name
+}
+
+// aggregateFailures combines the failure output of every failed branch of a
+// parallel group, in phase order, into a single feedback document.
+func aggregateFailures(phases []config.Phase, outputs map[int]string) string {
+	idxs := make([]int, 0, len(outputs))
+	for idx := range outputs {
+		idxs = append(idxs, idx)
+	}
+	sort.Ints(idxs)
+	var parts []string
+	for _, idx := range idxs {
+		parts = append(parts, fmt.Sprintf("## Parallel phase %q failed\n\n%s", phases[idx].Name, outputs[idx]))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// joinFailures joins per-branch failure messages in phase order.
+func joinFailures(msgs map[int]string) string {
+	idxs := make([]int, 0, len(msgs))
+	for idx := range msgs {
+		idxs = append(idxs, idx)
+	}
+	sort.Ints(idxs)
+	parts := make([]string, len(idxs))
+	for i, idx := range idxs {
+		parts[i] = msgs[idx]
+	}
+	return strings.Join(parts, "; ")
 }
 
 // runParallel runs two phases concurrently.
@@ -972,8 +1691,8 @@ func (r *Runner) runParallel(parentCtx context.Context, idx1, idx2, total int, l
 	phase1 := r.Config.Phases[idx1]
 	phase2 := r.Config.Phases[idx2]
 
-	ux.PhaseHeader(idx1, total, phase1)
-	ux.PhaseHeader(idx2, total, phase2)
+	ux.PhaseHeader(idx1, r.Config.Phases, r.history)
+	ux.PhaseHeader(idx2, r.Config.Phases, r.history)
 
 	// Check run-level cost limit before starting parallel phases
 	if r.Config.MaxCost > 0 && r.Costs.TotalCost() > r.Config.MaxCost {
@@ -986,6 +1705,22 @@ func (r *Runner) runParallel(parentCtx context.Context, idx1, idx2, total int, l
 	ctx, cancel := context.WithCancel(parentCtx)
 	defer cancel()
 
+	// A loop on either partner applies to the group as a whole. With a group
+	// loop, a failing branch does not cancel its sibling so that every
+	// branch's failure output can be aggregated into one feedback file.
+	var groupLoop *config.Loop
+	if phase1.Loop != nil {
+		groupLoop = phase1.Loop
+	} else if phase2.Loop != nil {
+		groupLoop = phase2.Loop
+	}
+	failureOutputs := make(map[int]string)
+
+	// With keep-going (run flag or on either partner), both branches always
+	// run to completion and every failure is reported together.
+	keepGoing := r.KeepGoing || phase1.KeepGoing || phase2.KeepGoing
+	failureMsgs := make(map[int]string)
+
 	type phaseResult struct {
 		idx       int
 		result    *dispatch.Result
@@ -998,12 +1733,21 @@ func (r *Runner) runParallel(parentCtx context.Context, idx1, idx2, total int, l
 	var wg sync.WaitGroup
 	wg.Add(2)
 
+	// Read attempt numbers before starting the goroutines — attemptCount
+	// is only written after both branches finish.
+	attempt1, attempt2 := r.attemptCount[idx1]+1, r.attemptCount[idx2]+1
+
 	go func() {
 		defer wg.Done()
 		env1 := r.Env.Clone()
 		env1.PhaseIndex = idx1
+		env1.PhaseDescription = phase1.Description
+		env1.PhaseOutputDir = r.phaseOutputDir(phase1)
+		env1.SecretEnv = phase1.SecretEnv
+		env1.Attempt = attempt1
 		phaseStart := time.Now()
 		r.Timing.AddStartAt(phase1.Name, phaseStart)
+		r.emit(dispatch.LogEvent{Type: "phase_start", Phase: phase1.Name, Index: idx1 + 1})
 		res, err := r.dispatchWithHooks(ctx, phase1, env1)
 		phaseEnd := time.Now()
 		results <- phaseResult{idx: idx1, result: res, err: err, startTime: phaseStart, endTime: phaseEnd}
@@ -1013,8 +1757,13 @@ func (r *Runner) runParallel(parentCtx context.Context, idx1, idx2, total int, l
 		defer wg.Done()
 		env2 := r.Env.Clone()
 		env2.PhaseIndex = idx2
+		env2.PhaseDescription = phase2.Description
+		env2.PhaseOutputDir = r.phaseOutputDir(phase2)
+		env2.SecretEnv = phase2.SecretEnv
+		env2.Attempt = attempt2
 		phaseStart := time.Now()
 		r.Timing.AddStartAt(phase2.Name, phaseStart)
+		r.emit(dispatch.LogEvent{Type: "phase_start", Phase: phase2.Name, Index: idx2 + 1})
 		res, err := r.dispatchWithHooks(ctx, phase2, env2)
 		phaseEnd := time.Now()
 		results <- phaseResult{idx: idx2, result: res, err: err, startTime: phaseStart, endTime: phaseEnd}
@@ -1026,6 +1775,7 @@ func (r *Runner) runParallel(parentCtx context.Context, idx1, idx2, total int, l
 		close(results)
 	}()
 
+	starts := make(map[int]time.Time) // dispatch start per phase, for require-fresh-outputs
 	var firstErr error
 	var failedIdx int = -1
 	var firstTimedOut bool
@@ -1037,10 +1787,12 @@ func (r *Runner) runParallel(parentCtx context.Context, idx1, idx2, total int, l
 	for pr := range results {
 		phase := r.Config.Phases[pr.idx]
 		// Write metadata before archiving
-		writePhaseMetadata(r.Env.ArtifactsDir, pr.idx, buildPhaseMetadata(phase, pr.idx, pr.result, pr.startTime, pr.endTime))
+		writePhaseMetadata(r.Env.ArtifactsDir, pr.idx, buildPhaseMetadata(phase, pr.idx, pr.result, pr.startTime, pr.endTime), r.Config.OutputPaths(phase))
+		starts[pr.idx] = pr.startTime
+		r.emitPhaseEnd(pr.idx, pr.result, pr.err, pr.startTime, pr.endTime)
 		// Archive every parallel attempt to audit
 		r.attemptCount[pr.idx]++
-		archivePhaseFiles(r.Env.ArtifactsDir, r.auditDir, pr.idx, r.attemptCount[pr.idx], phase.Outputs)
+		archivePhaseFiles(r.Env.ArtifactsDir, r.auditDir, pr.idx, r.attemptCount[pr.idx], r.Config.OutputPaths(phase))
 		// Record cost data for agent phases (cost is incurred regardless of success/failure)
 		if phase.Type == "agent" && pr.result != nil {
 			r.Costs.Record(phase.Name, pr.idx, pr.result.CostUSD, pr.result.InputTokens, pr.result.OutputTokens, pr.result.CacheCreationInputTokens, pr.result.CacheReadInputTokens, pr.result.Turns)
@@ -1048,35 +1800,49 @@ func (r *Runner) runParallel(parentCtx context.Context, idx1, idx2, total int, l
 				fmt.Fprintf(os.Stderr, "  note: no token counts in stream output for phase %q (token tracking is best-effort)\n", phase.Name)
 			}
 		}
-		if pr.err != nil || (pr.result != nil && pr.result.ExitCode != 0) {
-			cancel() // cancel the other goroutine
+		if phaseFailed(phase, pr.result, pr.err) {
+			if groupLoop == nil && !keepGoing {
+				cancel() // cancel the other goroutine
+			}
 			r.Timing.AddEndAt(phase.Name, pr.endTime)
 			errMsg := fmt.Sprintf("%s exited with non-zero status", phase.Type)
 			if pr.result != nil && pr.result.TimedOut {
-				errMsg = fmt.Sprintf("timed out after %dm — consider increasing 'timeout' in config (current: %d)", phase.Timeout, phase.Timeout)
+				errMsg = timeoutMessage(phase, r.Env)
+			} else if pr.result != nil && pr.result.Stalled {
+				errMsg = inactivityMessage(phase)
 			} else if pr.err != nil {
 				errMsg = pr.err.Error()
 			}
+			output := state.ReadDeclaredOutputs(r.Env.ArtifactsDir, r.Config.OutputPaths(phase))
+			if output == "" && pr.result != nil {
+				output = pr.result.Output
+			}
+			if output == "" {
+				output = errMsg
+			}
+			failureOutputs[pr.idx] = output
 			appendPhaseLog(r.Env.ArtifactsDir, pr.idx, fmt.Sprintf("\n[orc] phase %q failed: %s\n", phase.Name, errMsg))
-			ux.PhaseFail(pr.idx, phase.Name, errMsg)
+			ux.PhaseFail(pr.idx, len(r.Config.Phases), phase.Name, errMsg)
 			if phase.Type == "agent" && pr.result != nil && pr.result.SessionID != "" {
-				fmt.Fprintf(os.Stderr, "warning: session ID from parallel phase %q not persisted — resume is not supported for parallel agents\n", phase.Name)
+				ux.Warnf("warning: session ID from parallel phase %q not persisted — resume is not supported for parallel agents\n", phase.Name)
 			}
+			failureMsgs[pr.idx] = fmt.Sprintf("phase %q failed: %s", phase.Name, errMsg)
 			if firstErr == nil {
 				firstErr = fmt.Errorf("phase %q failed: %s", phase.Name, errMsg)
 				failedIdx = pr.idx
-				firstTimedOut = pr.result != nil && pr.result.TimedOut
+				firstTimedOut = pr.result != nil && (pr.result.TimedOut || pr.result.Stalled)
 			}
 		} else {
 			r.Timing.AddEndAt(phase.Name, pr.endTime)
+			noteAcceptedExit(r.Env.ArtifactsDir, pr.idx, phase, pr.result)
 			if phase.Type == "agent" && pr.result != nil && pr.result.SessionID != "" {
-				fmt.Fprintf(os.Stderr, "warning: session ID from parallel phase %q not persisted — resume is not supported for parallel agents\n", phase.Name)
+				ux.Warnf("warning: session ID from parallel phase %q not persisted — resume is not supported for parallel agents\n", phase.Name)
 			}
-			ux.PhaseComplete(pr.idx, phase.Name, pr.endTime.Sub(pr.startTime))
+			ux.PhaseComplete(pr.idx, len(r.Config.Phases), phase.Name, pr.endTime.Sub(pr.startTime))
 		}
 	}
 	if saveErr := state.SaveAttemptCounts(r.auditDir, r.attemptCount); saveErr != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to save attempt counts: %v\n", saveErr)
+		ux.Warnf("warning: failed to save attempt counts: %v\n", saveErr)
 	}
 
 	if firstErr != nil {
@@ -1084,6 +1850,20 @@ func (r *Runner) runParallel(parentCtx context.Context, idx1, idx2, total int, l
 			r.printRunSummary(failedIdx)
 			return r.failWithCategory(state.StatusInterrupted, ExitInterrupted, state.FailCategoryInterrupted, parentCtx.Err().Error(), parentCtx.Err())
 		}
+		if groupLoop != nil {
+			lo := min(idx1, idx2)
+			group := config.Phase{Name: parallelGroupName(r.Config.Phases[lo], r.Config.Phases[max(idx1, idx2)]), Loop: groupLoop}
+			shouldContinue, loopErr := r.handleLoopFailure(lo, group, loopCounts, aggregateFailures(r.Config.Phases, failureOutputs))
+			if loopErr != nil {
+				return loopErr
+			}
+			if shouldContinue {
+				return nil
+			}
+		}
+		if keepGoing && len(failureMsgs) > 1 {
+			firstErr = fmt.Errorf("%d parallel phases failed: %s", len(failureMsgs), joinFailures(failureMsgs))
+		}
 		r.printRunSummary(failedIdx)
 		failedPhase := r.Config.Phases[failedIdx]
 		category := state.FailCategoryScriptFailure
@@ -1102,7 +1882,7 @@ func (r *Runner) runParallel(parentCtx context.Context, idx1, idx2, total int, l
 
 	// Flush costs and check cost limits after parallel phases complete
 	if err := r.Costs.Flush(r.auditDir); err != nil {
-		fmt.Fprintf(os.Stderr, "warning: failed to flush costs: %v\n", err)
+		ux.Warnf("warning: failed to flush costs: %v\n", err)
 	}
 	for _, pi := range []struct {
 		idx   int
@@ -1125,31 +1905,87 @@ func (r *Runner) runParallel(parentCtx context.Context, idx1, idx2, total int, l
 			fmt.Errorf("run exceeded cost limit: $%.2f > $%.2f", r.Costs.TotalCost(), r.Config.MaxCost))
 	}
 
-	// Check declared outputs for both phases
+	// Check declared outputs for both phases. With a group loop, a failed
+	// verify on either branch retries the group like a failed branch does.
+	verifyFailures := make(map[int]string)
+	var firstVerify string
+	verifyIdx := -1
 	for _, pi := range []struct {
 		idx   int
 		phase config.Phase
 	}{{idx1, phase1}, {idx2, phase2}} {
 		if len(pi.phase.Outputs) > 0 {
-			missing := state.CheckOutputs(r.Env.ArtifactsDir, pi.phase.Outputs)
+			missing, stale := r.unsatisfiedOutputs(pi.phase, starts[pi.idx])
+			if len(missing) > 0 && pi.phase.OutputsOptional {
+				noteOptionalOutputs(r.Env.ArtifactsDir, pi.idx, pi.phase, missing, stale)
+				missing = nil
+			}
 			if len(missing) > 0 {
-				errMsg := fmt.Sprintf("missing outputs: %v", missing)
-				ux.PhaseFail(pi.idx, pi.phase.Name, errMsg)
+				errMsg := outputsErrMsg(missing, stale)
+				ux.PhaseFail(pi.idx, len(r.Config.Phases), pi.phase.Name, errMsg)
 				return r.failWithCategory(state.StatusFailed, ExitPhaseFailure, state.FailCategoryOutputMissing, errMsg,
 					fmt.Errorf("phase %q: %s", pi.phase.Name, errMsg))
 			}
+			if errMsg := r.invalidOutputs(pi.idx, pi.phase); errMsg != "" {
+				r.printRunSummary(pi.idx)
+				return r.failWithCategory(state.StatusFailed, ExitPhaseFailure, state.FailCategoryOutputInvalid, errMsg,
+					fmt.Errorf("phase %q: %s", pi.phase.Name, errMsg))
+			}
+		}
+		if pi.phase.Verify != "" {
+			if verifyMsg, feedback := r.runVerify(parentCtx, pi.idx, pi.phase); verifyMsg != "" {
+				if groupLoop == nil {
+					if err := state.WriteFeedback(r.Env.ArtifactsDir, pi.phase.Name, feedback); err != nil {
+						ux.Warnf("warning: failed to write feedback: %v\n", err)
+					}
+					r.printRunSummary(pi.idx)
+					return r.failWithCategory(state.StatusFailed, ExitPhaseFailure, state.FailCategoryVerifyFailure, verifyMsg,
+						fmt.Errorf("phase %q: %s", pi.phase.Name, verifyMsg))
+				}
+				verifyFailures[pi.idx] = feedback
+				if verifyIdx < 0 {
+					firstVerify, verifyIdx = verifyMsg, pi.idx
+				}
+			}
 		}
 	}
+	if verifyIdx >= 0 {
+		lo := min(idx1, idx2)
+		group := config.Phase{Name: parallelGroupName(r.Config.Phases[lo], r.Config.Phases[max(idx1, idx2)]), Loop: groupLoop}
+		feedback := aggregateFailures(r.Config.Phases, verifyFailures)
+		shouldContinue, loopErr := r.handleLoopFailure(lo, group, loopCounts, feedback)
+		if loopErr != nil {
+			return loopErr
+		}
+		if shouldContinue {
+			return nil
+		}
+		if err := state.WriteFeedback(r.Env.ArtifactsDir, group.Name, feedback); err != nil {
+			ux.Warnf("warning: failed to write feedback: %v\n", err)
+		}
+		r.printRunSummary(verifyIdx)
+		failed := r.Config.Phases[verifyIdx]
+		return r.failWithCategory(state.StatusFailed, ExitPhaseFailure, state.FailCategoryVerifyFailure, firstVerify,
+			fmt.Errorf("phase %q: %s", failed.Name, firstVerify))
+	}
 
-	// Mark intermediate phases (between the two parallel partners) as skipped.
-	// These phases are never dispatched — jumping past them without marking
-	// them would cause buildPhaseResults to report them as "completed".
 	lo, hi := idx1, idx2
 	if lo > hi {
 		lo, hi = hi, lo
 	}
+
+	// Both branches passed: drop feedback left by earlier failures of either
+	// branch or of the group as a whole.
+	group := parallelGroupName(r.Config.Phases[lo], r.Config.Phases[hi])
+	for _, name := range []string{phase1.Name, phase2.Name, group} {
+		archiveFeedbackFrom(r.Env.ArtifactsDir, r.auditDir, lo, r.attemptCount[lo], name)
+	}
+
+	// Mark intermediate phases (between the two parallel partners) as skipped.
+	// These phases are never dispatched — jumping past them without marking
+	// them would cause buildPhaseResults to report them as "completed".
 	for mid := lo + 1; mid < hi; mid++ {
-		r.skipped[r.Config.Phases[mid].Name] = true
+		r.skip(mid, fmt.Sprintf("between parallel phases %s and %s", r.Config.Phases[lo].Name, r.Config.Phases[hi].Name))
 	}
 
 	// Advance past both phases — set to the one after the later index
@@ -1217,6 +2053,7 @@ func archivePhaseFiles(artifactsDir, auditDir string, phaseIdx, iteration int, o
 	copyFile(state.LogPath(artifactsDir, phaseIdx), state.AuditLogPath(auditDir, phaseIdx, iteration))
 	copyFile(state.PromptPath(artifactsDir, phaseIdx), state.AuditPromptPath(auditDir, phaseIdx, iteration))
 	copyFile(state.StreamLogPath(artifactsDir, phaseIdx), state.AuditStreamLogPath(auditDir, phaseIdx, iteration))
+	copyFile(state.EventLogPath(artifactsDir, phaseIdx), state.AuditEventLogPath(auditDir, phaseIdx, iteration))
 	copyFile(state.MetaPath(artifactsDir, phaseIdx), state.AuditMetaPath(auditDir, phaseIdx, iteration))
 	for _, o := range outputs {
 		copyFile(filepath.Join(artifactsDir, o), state.AuditOutputPath(auditDir, phaseIdx, iteration, o))
@@ -1234,11 +2071,11 @@ func archiveAndClearFeedback(artifactsDir, auditDir string, phaseIdx, iteration
 		return
 	}
 	for _, e := range entries {
-		if e.IsDir() {
+		name := e.Name()
+		fromPhase, ok := state.FeedbackSource(name)
+		if e.IsDir() || !ok {
 			continue
 		}
-		name := e.Name()
-		fromPhase := strings.TrimSuffix(strings.TrimPrefix(name, "from-"), ".md")
 		src := filepath.Join(feedbackDir, name)
 		dst := state.AuditFeedbackPath(auditDir, phaseIdx, iteration, fromPhase)
 		copyFile(src, dst)
@@ -1246,6 +2083,18 @@ func archiveAndClearFeedback(artifactsDir, auditDir string, phaseIdx, iteration
 	}
 }
 
+// archiveFeedbackFrom archives and removes the feedback file written by
+// fromPhase, if there is one, leaving other phases' feedback in place.
+// Errors are silently ignored — archiving should not break the run.
+func archiveFeedbackFrom(artifactsDir, auditDir string, phaseIdx, iteration int, fromPhase string) {
+	src := state.FeedbackPath(artifactsDir, fromPhase)
+	if _, err := os.Stat(src); err != nil {
+		return
+	}
+	copyFile(src, state.AuditFeedbackPath(auditDir, phaseIdx, iteration, fromPhase))
+	os.Remove(src)
+}
+
 // copyFile copies src to dst, creating parent directories as needed.
 // Errors are silently ignored — archiving should not break the run.
 func copyFile(src, dst string) {
@@ -1267,11 +2116,28 @@ func copyFile(src, dst string) {
 }
 
 // evalCondition runs a shell command and returns true if it exits 0.
-func evalCondition(ctx context.Context, phase config.Phase, env *dispatch.Environment) bool {
-	cmd := exec.CommandContext(ctx, "bash", "-c", phase.Condition)
+// evalCondition runs the phase's condition and reports whether it passed.
+// A condition still running after phase.ConditionTimeout seconds is killed
+// and counts as not passing, with timedOut set.
+func evalCondition(ctx context.Context, phase config.Phase, env *dispatch.Environment) (pass, timedOut bool) {
+	condCtx := ctx
+	if phase.ConditionTimeout > 0 {
+		var cancel context.CancelFunc
+		condCtx, cancel = context.WithTimeout(ctx, time.Duration(phase.ConditionTimeout)*time.Second)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(condCtx, "bash", "-c", phase.Condition)
 	cmd.Dir = dispatch.PhaseWorkDir(phase, env)
 	cmd.Env = dispatch.BuildEnv(env)
-	return cmd.Run() == nil
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = 5 * time.Second
+	if err := cmd.Run(); err != nil {
+		return false, ctx.Err() == nil && errors.Is(condCtx.Err(), context.DeadlineExceeded)
+	}
+	return true, false
 }
 
 func (r *Runner) dispatchWithHooks(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
@@ -1328,12 +2194,20 @@ func (r *Runner) runSubWorkflow(ctx context.Context, workflowName string) (*disp
 	childEnv := r.Env.Clone()
 	childEnv.ArtifactsDir = childArtifacts
 	childEnv.Workflow = workflowName
+	childEnv.WorkflowName = childCfg.Name
 	childEnv.PhaseCount = len(childCfg.Phases)
 	childEnv.ResumeSessionID = ""
 	// Merge parent custom vars with child config vars (child vars win on conflict).
 	if len(childCfg.Vars) > 0 {
 		builtins := childEnv.Vars()
 		childEnv.CustomVars = dispatch.ExpandConfigVars(childCfg.Vars, builtins)
+		// A var hidden by either config's export-vars stays hidden.
+		for k := range childCfg.UnexportedVars() {
+			if childEnv.UnexportedVars == nil {
+				childEnv.UnexportedVars = make(map[string]bool)
+			}
+			childEnv.UnexportedVars[k] = true
+		}
 	}
 
 	ux.SubWorkflowStart(workflowName)
@@ -1344,6 +2218,7 @@ func (r *Runner) runSubWorkflow(ctx context.Context, workflowName string) (*disp
 		Env:          childEnv,
 		Dispatcher:   r.Dispatcher,
 		StepMode:     r.StepMode,
+		KeepGoing:    r.KeepGoing,
 		HistoryLimit: childCfg.HistoryLimit,
 	}
 
@@ -1356,8 +2231,9 @@ func (r *Runner) runSubWorkflow(ctx context.Context, workflowName string) (*disp
 
 	ux.SubWorkflowEnd(workflowName)
 
-	// Synthesize a Result for the parent's phase handling.
-	result := &dispatch.Result{ExitCode: 0}
+	// Synthesize a Result for the parent's phase handling. A "ya" answered
+	// inside the child carries on to the parent's remaining gates.
+	result := &dispatch.Result{ExitCode: 0, ApproveAllGates: childEnv.AutoApproveGates}
 	if child.Costs != nil {
 		result.CostUSD = child.Costs.TotalCost()
 	}
@@ -7,8 +7,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -17,7 +24,9 @@ import (
 
 	"github.com/jorge-barreto/orc/internal/config"
 	"github.com/jorge-barreto/orc/internal/dispatch"
+	"github.com/jorge-barreto/orc/internal/metrics"
 	"github.com/jorge-barreto/orc/internal/state"
+	"github.com/jorge-barreto/orc/internal/tui"
 	"github.com/jorge-barreto/orc/internal/ux"
 )
 
@@ -494,6 +503,477 @@ func TestRun_LoopOnExhaustExhausted(t *testing.T) {
 	assertExitCode(t, err, ExitPhaseFailure)
 }
 
+func TestRun_LoopFeedbackHistory(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "implement", Type: "script", Run: "echo"},
+			{Name: "test", Type: "script", Run: "echo", Loop: &config.Loop{Goto: "implement", Min: 1, Max: 4, FeedbackHistory: 2}},
+		},
+	}
+	var testCount int
+	var seen []string
+	mock := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		switch phase.Name {
+		case "implement":
+			seen = append(seen, state.ReadFeedback(env.ArtifactsDir, "test"))
+		case "test":
+			testCount++
+			if testCount <= 3 {
+				return &dispatch.Result{ExitCode: 1, Output: fmt.Sprintf("failure %d", testCount)}, nil
+			}
+		}
+		return &dispatch.Result{ExitCode: 0}, nil
+	}}
+	r := newTestRunner(t, cfg, mock)
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 4 {
+		t.Fatalf("implement ran %d times, want 4", len(seen))
+	}
+	if !strings.Contains(seen[2], "failure 1") || !strings.Contains(seen[2], "failure 2") {
+		t.Errorf("third implement should see attempts 1 and 2, got:\n%s", seen[2])
+	}
+	if strings.Contains(seen[3], "failure 1") || !strings.Contains(seen[3], "## Attempt 2") || !strings.Contains(seen[3], "## Attempt 3") {
+		t.Errorf("fourth implement should see only the last two attempts, got:\n%s", seen[3])
+	}
+}
+
+func TestRun_OnEventDrivesBoard(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "implement", Type: "script", Run: "echo"},
+			{Name: "test", Type: "script", Run: "echo", Loop: &config.Loop{Goto: "implement", Min: 1, Max: 3}},
+			{Name: "docs", Type: "script", Run: "echo", Condition: "false"},
+		},
+	}
+	var testCount int
+	var midRun []tui.PhaseView
+	board := tui.NewBoard(cfg, "T-1")
+	mock := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		if phase.Name == "test" {
+			testCount++
+			midRun = board.Phases()
+			if testCount == 1 {
+				return &dispatch.Result{ExitCode: 1, Output: "fail"}, nil
+			}
+		}
+		return &dispatch.Result{ExitCode: 0, CostUSD: 0.5}, nil
+	}}
+	r := newTestRunner(t, cfg, mock)
+	r.OnEvent = board.Observe
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if midRun[0].Status != tui.StatusOK || midRun[1].Status != tui.StatusRunning {
+		t.Errorf("board while test runs = %+v", midRun)
+	}
+	got := board.Phases()
+	if got[0].Status != tui.StatusOK || got[0].Runs != 2 || got[0].CostUSD != 1 {
+		t.Errorf("implement = %+v, want ok after 2 runs costing $1", got[0])
+	}
+	if got[1].Status != tui.StatusOK || got[2].Status != tui.StatusSkipped {
+		t.Errorf("final board = %+v", got)
+	}
+}
+
+func TestRun_MetricsScrapedMidRun(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "implement", Type: "script", Run: "echo"},
+			{Name: "test", Type: "script", Run: "echo", Loop: &config.Loop{Goto: "implement", Min: 1, Max: 3}},
+			{Name: "report", Type: "script", Run: "echo"},
+		},
+	}
+	reg := metrics.New()
+	srv := httptest.NewServer(reg)
+	defer srv.Close()
+	scrape := func() string {
+		resp, err := http.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	var testCount int
+	var midRun string
+	mock := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		switch phase.Name {
+		case "test":
+			testCount++
+			if testCount == 1 {
+				return &dispatch.Result{ExitCode: 1, Output: "fail"}, nil
+			}
+		case "report":
+			midRun = scrape()
+		}
+		return &dispatch.Result{ExitCode: 0, CostUSD: 0.5}, nil
+	}}
+	r := newTestRunner(t, cfg, mock)
+	r.Metrics = reg
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`orc_phases_run_total{phase="implement"} 2`,
+		`orc_phases_run_total{phase="test"} 2`,
+		`orc_phase_failures_total{phase="test"} 1`,
+		`orc_loop_iterations_total{phase="test"} 2`,
+		"orc_cost_usd_total 1.5",
+		"orc_run_active 1",
+	} {
+		if !strings.Contains(midRun, want) {
+			t.Errorf("mid-run scrape missing %q:\n%s", want, midRun)
+		}
+	}
+	if strings.Contains(midRun, `phase="report"`) {
+		t.Errorf("report had not finished at the mid-run scrape:\n%s", midRun)
+	}
+	final := scrape()
+	if !strings.Contains(final, `orc_phases_run_total{phase="report"} 1`) || !strings.Contains(final, "orc_run_active 0") {
+		t.Errorf("final scrape should include report and a finished run:\n%s", final)
+	}
+}
+
+func TestRun_AutoParallel(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "lint", Type: "script", Run: "make lint > lint.txt", Outputs: []string{"lint.txt"}},
+			{Name: "unit", Type: "script", Run: "make test > unit.txt", Outputs: []string{"unit.txt"}},
+			{Name: "build", Type: "script", Run: "make > bin.txt", Outputs: []string{"bin.txt"}},
+			{Name: "package", Type: "script", Run: "tar czf pkg.tgz bin.txt", Outputs: []string{"pkg.tgz"}},
+		},
+	}
+	var mu sync.Mutex
+	running := make(map[string]bool)
+	overlaps := make(map[string][]string) // phase → phases running when it started
+	unitStarted := make(chan struct{})
+	mock := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		mu.Lock()
+		for name := range running {
+			overlaps[phase.Name] = append(overlaps[phase.Name], name)
+		}
+		running[phase.Name] = true
+		mu.Unlock()
+		switch phase.Name {
+		case "unit":
+			close(unitStarted)
+		case "lint":
+			// Hold lint open until unit has started alongside it.
+			select {
+			case <-unitStarted:
+			case <-time.After(2 * time.Second):
+			}
+		}
+		for _, o := range phase.Outputs {
+			os.WriteFile(filepath.Join(env.ArtifactsDir, o), []byte("ok"), 0644)
+		}
+		mu.Lock()
+		delete(running, phase.Name)
+		mu.Unlock()
+		return &dispatch.Result{ExitCode: 0}, nil
+	}}
+	r := newTestRunner(t, cfg, mock)
+	r.AutoParallel = true
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(overlaps["unit"], "lint") && !slices.Contains(overlaps["lint"], "unit") {
+		t.Errorf("independent lint and unit should run concurrently, overlaps: %v", overlaps)
+	}
+	if len(overlaps["build"]) > 0 || len(overlaps["package"]) > 0 {
+		t.Errorf("package reads build's output and must stay sequential, overlaps: %v", overlaps)
+	}
+	if cfg.Phases[0].ParallelWith != "" {
+		t.Errorf("inferred pair leaked into the caller's config: lint.parallel-with = %q", cfg.Phases[0].ParallelWith)
+	}
+}
+
+func TestAutoParallelPairs_SkipsAgentPhases(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "plan-api", Type: "agent", Prompt: "api.md", Outputs: []string{"api.md"}},
+			{Name: "plan-ui", Type: "agent", Prompt: "ui.md", Outputs: []string{"ui.md"}},
+			{Name: "implement", Type: "agent", Prompt: "implement.md", Outputs: []string{"summary.md"}},
+			{Name: "test", Type: "script", Run: "make test > test.txt", Outputs: []string{"test.txt"}},
+			{Name: "lint", Type: "script", Run: "make lint > lint.txt", Outputs: []string{"lint.txt"}},
+		},
+	}
+	got := autoParallelPairs(cfg, nil)
+	if len(got) != 1 || got[0] != [2]int{3, 4} {
+		t.Errorf("pairs = %v, want only [test lint]; an agent must not run alongside a phase that reads the tree it edits", got)
+	}
+}
+
+func TestRun_PrefixedCompletionLeavesOtherPrefixRun(t *testing.T) {
+	orig := state.FilePrefix
+	t.Cleanup(func() { state.FilePrefix = orig })
+	cfg := &config.Config{
+		Name:   "test",
+		Phases: []config.Phase{{Name: "build", Type: "script", Run: "true"}},
+	}
+	r := newTestRunner(t, cfg, &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		return &dispatch.Result{ExitCode: 0}, nil
+	}})
+
+	// Workflow b- is mid-run in the shared artifacts directory.
+	state.FilePrefix = "b-"
+	if err := state.EnsureDir(r.Env.ArtifactsDir); err != nil {
+		t.Fatal(err)
+	}
+	stB := &state.State{PhaseIndex: 2, Ticket: "TEST-1", Status: state.StatusFailed}
+	if err := stB.Save(r.Env.ArtifactsDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.WriteRunResult(r.Env.ArtifactsDir, &state.RunResult{Ticket: "TEST-1", Workflow: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(state.LogPath(r.Env.ArtifactsDir, 0), []byte("workflow b"), 0644)
+
+	state.FilePrefix = "a-"
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(r.Env.ArtifactsDir, "a-run-result.json")); err != nil {
+		t.Errorf("a-'s run-result.json should be restored to the artifacts dir: %v", err)
+	}
+
+	state.FilePrefix = "b-"
+	st, err := state.Load(r.Env.ArtifactsDir)
+	if err != nil || st.PhaseIndex != 2 || st.Status != state.StatusFailed {
+		t.Errorf("b-'s state should survive a-'s completion, got %+v, %v", st, err)
+	}
+	res, err := state.LoadRunResult(r.Env.ArtifactsDir)
+	if err != nil || res.Workflow != "b" {
+		t.Errorf("b-'s run-result.json should survive, got %+v, %v", res, err)
+	}
+	if data, _ := os.ReadFile(state.LogPath(r.Env.ArtifactsDir, 0)); string(data) != "workflow b" {
+		t.Errorf("b-'s phase log should survive, got %q", data)
+	}
+}
+
+func TestRun_WritesArtifactsReadme(t *testing.T) {
+	cfg := &config.Config{
+		Name: "bugfix",
+		Phases: []config.Phase{
+			{Name: "plan", Type: "script", Run: "true", Description: "Write the plan", Outputs: []string{"plan.md"}},
+			{Name: "implement", Type: "script", Run: "true"},
+		},
+	}
+	var readme, ours string
+	mock := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		if phase.Name == "plan" {
+			data, _ := os.ReadFile(state.ReadmePath(env.ArtifactsDir))
+			readme = string(data)
+			data, _ = os.ReadFile(filepath.Join(env.ArtifactsDir, "README.md"))
+			ours = string(data)
+			os.WriteFile(filepath.Join(env.ArtifactsDir, "plan.md"), []byte("plan"), 0644)
+		}
+		return &dispatch.Result{ExitCode: 0}, nil
+	}}
+	r := newTestRunner(t, cfg, mock)
+	// A phase output named README.md, left by an earlier attempt, is the
+	// workflow's own and must survive the run-start write.
+	os.MkdirAll(r.Env.ArtifactsDir, 0755)
+	os.WriteFile(filepath.Join(r.Env.ArtifactsDir, "README.md"), []byte("# ours"), 0644)
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if ours != "# ours" {
+		t.Errorf("README.md output clobbered: %q", ours)
+	}
+	for _, want := range []string{r.Env.Ticket, "bugfix", "1. **plan** (script) — Write the plan", "outputs: plan.md", "2. **implement** (script)", "logs/", "feedback/"} {
+		if !strings.Contains(readme, want) {
+			t.Errorf("README missing %q:\n%s", want, readme)
+		}
+	}
+}
+
+func TestBisect_LocalizesCorruptingPhase(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "gen", Type: "script", Run: "true"},
+			{Name: "transform", Type: "script", Run: "true"},
+			{Name: "format", Type: "script", Run: "true"},
+			{Name: "compress", Type: "script", Run: "true"},
+			{Name: "test", Type: "script", Run: "true"},
+		},
+	}
+	regressed := false
+	mock := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		data := filepath.Join(env.ArtifactsDir, "data.txt")
+		switch phase.Name {
+		case "gen":
+			os.WriteFile(data, []byte("ok"), 0644)
+		case "transform":
+			if regressed {
+				os.WriteFile(data, []byte("CORRUPT"), 0644)
+			}
+		case "test":
+			if content, _ := os.ReadFile(data); string(content) != "ok" {
+				return &dispatch.Result{ExitCode: 1, Output: "bad data"}, nil
+			}
+		}
+		return &dispatch.Result{ExitCode: 0}, nil
+	}}
+	r := newTestRunner(t, cfg, mock)
+	if _, err := BisectSeed(r.Env.ArtifactsDir, ""); err == nil {
+		t.Fatal("expected an error with no completed run to seed from")
+	}
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The regression lands and a real run fails, leaving its corrupt output
+	// in the live artifacts directory.
+	regressed = true
+	r.State = &state.State{Status: state.StatusRunning}
+	if err := r.Run(context.Background()); err == nil {
+		t.Fatal("expected the regressed run to fail")
+	}
+	saved := filepath.Join(r.Env.ArtifactsDir, "data.txt")
+	if content, _ := os.ReadFile(saved); string(content) != "CORRUPT" {
+		t.Fatalf("failed run should leave data.txt corrupt, got %q", content)
+	}
+
+	seed, err := BisectSeed(r.Env.ArtifactsDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	culprit, err := r.Bisect(context.Background(), 0, 4, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if culprit != 1 {
+		t.Fatalf("culprit = %d, want 1 (transform)", culprit)
+	}
+	if content, _ := os.ReadFile(saved); string(content) != "CORRUPT" {
+		t.Errorf("bisect modified the live artifacts: data.txt = %q", content)
+	}
+
+	if culprit, err := r.Bisect(context.Background(), 2, 4, seed); err != nil || culprit != -1 {
+		t.Errorf("range after the culprit should not reproduce, got %d, %v", culprit, err)
+	}
+}
+
+func TestBisectSeed_SkipsRunsThatDidNotComplete(t *testing.T) {
+	artDir := t.TempDir()
+	for id, status := range map[string]string{
+		"2026-01-01T00-00-00.000": state.StatusCompleted,
+		"2026-01-02T00-00-00.000": state.StatusFailed,
+	} {
+		dir := filepath.Join(state.HistoryDir(artDir), id)
+		os.MkdirAll(dir, 0755)
+		if err := (&state.State{Ticket: "T", Status: status}).Save(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	seed, err := BisectSeed(artDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(seed) != "2026-01-01T00-00-00.000" {
+		t.Errorf("seed = %s, want the newest completed run", seed)
+	}
+	if _, err := BisectSeed(artDir, "2026-01-02T00-00-00.000"); err == nil {
+		t.Error("expected an error naming a failed run as the seed")
+	}
+	if _, err := BisectSeed(artDir, "2026-01-03T00-00-00.000"); err == nil {
+		t.Error("expected an error naming a missing run")
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestBisect_IsolatesWorkTreeAndAudit(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "gen", Type: "script", Run: "true"},
+			{Name: "transform", Type: "script", Run: "true"},
+			{Name: "format", Type: "script", Run: "true"},
+			{Name: "test", Type: "script", Run: "true"},
+		},
+	}
+	mock := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		src := filepath.Join(env.WorkDir, "src.txt")
+		switch phase.Name {
+		case "transform":
+			os.WriteFile(src, []byte("broken"), 0644)
+			os.WriteFile(filepath.Join(env.WorkDir, "scratch.txt"), []byte("x"), 0644)
+		case "test":
+			if content, _ := os.ReadFile(src); string(content) != "ok" {
+				return &dispatch.Result{ExitCode: 1, Output: "broken source"}, nil
+			}
+		}
+		return &dispatch.Result{ExitCode: 0}, nil
+	}}
+	r := newTestRunner(t, cfg, mock)
+	os.MkdirAll(r.Env.ArtifactsDir, 0755)
+	work := r.Env.WorkDir
+	src := filepath.Join(work, "src.txt")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+		{"config", "commit.gpgsign", "false"},
+	} {
+		runGit(t, work, args...)
+	}
+	if err := os.WriteFile(src, []byte("ok"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, work, "add", "-A")
+	runGit(t, work, "commit", "-q", "-m", "base")
+	seed := t.TempDir()
+
+	// Without a reset between trials, transform's edit would make every
+	// later start fail too and bisect would blame "test".
+	culprit, err := r.Bisect(context.Background(), 0, 3, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if culprit != 1 {
+		t.Fatalf("culprit = %d, want 1 (transform)", culprit)
+	}
+	if content, _ := os.ReadFile(src); string(content) != "ok" {
+		t.Errorf("work tree not restored: src.txt = %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(work, "scratch.txt")); !os.IsNotExist(err) {
+		t.Errorf("untracked file from a trial left behind: %v", err)
+	}
+	if _, err := os.Stat(state.AuditDirForWorkflow(r.Env.ProjectRoot, r.Env.Workflow, r.Env.Ticket)); !os.IsNotExist(err) {
+		t.Errorf("bisect trials wrote to the live audit dir: %v", err)
+	}
+
+	os.WriteFile(src, []byte("uncommitted"), 0644)
+	_, err = r.Bisect(context.Background(), 0, 3, seed)
+	assertExitCode(t, err, ExitConfigError)
+	if content, _ := os.ReadFile(src); string(content) != "uncommitted" {
+		t.Errorf("dirty work tree was touched: src.txt = %q", content)
+	}
+}
+
 func TestRun_LoopExhaustFeedbackHeader(t *testing.T) {
 	cfg := &config.Config{
 		Name: "test",
@@ -657,6 +1137,82 @@ func TestRun_FeedbackClearedOnLoopExit(t *testing.T) {
 	}
 }
 
+func TestRun_LoopSavesPromptPerAttempt(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "impl", Type: "agent", Prompt: "impl.md"},
+			{Name: "check", Type: "script", Run: "echo", Loop: &config.Loop{Goto: "impl", Max: 3}},
+		},
+	}
+
+	checkCount := 0
+	mock := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		if phase.Type == "agent" {
+			if _, err := dispatch.RenderAndSavePrompt(phase, env); err != nil {
+				return nil, err
+			}
+			return &dispatch.Result{ExitCode: 0}, nil
+		}
+		checkCount++
+		if checkCount == 1 {
+			return &dispatch.Result{ExitCode: 1, Output: "tests failed"}, nil
+		}
+		return &dispatch.Result{ExitCode: 0}, nil
+	}}
+
+	r := newTestRunner(t, cfg, mock)
+	os.WriteFile(filepath.Join(r.Env.ProjectRoot, "impl.md"), []byte("Implement $TICKET"), 0644)
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Completed runs are archived to history.
+	histEntries, err := os.ReadDir(filepath.Join(r.Env.ArtifactsDir, "history"))
+	if err != nil || len(histEntries) == 0 {
+		t.Fatalf("expected archived run in history: %v", err)
+	}
+	runDir := filepath.Join(r.Env.ArtifactsDir, "history", histEntries[len(histEntries)-1].Name())
+	first, err := os.ReadFile(state.AttemptPromptPath(runDir, 0, 1))
+	if err != nil {
+		t.Fatalf("attempt 1 prompt not saved: %v", err)
+	}
+	second, err := os.ReadFile(state.AttemptPromptPath(runDir, 0, 2))
+	if err != nil {
+		t.Fatalf("attempt 2 prompt not saved: %v", err)
+	}
+	if strings.Contains(string(first), "tests failed") {
+		t.Fatalf("attempt 1 prompt should not contain feedback: %q", first)
+	}
+	if !strings.Contains(string(second), "tests failed") {
+		t.Fatalf("attempt 2 prompt should contain loop feedback: %q", second)
+	}
+}
+
+func TestRun_DispatchCapStopsRunawayLoop(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "a", Type: "script", Run: "echo"},
+			{Name: "b", Type: "script", Run: "echo", Loop: &config.Loop{Goto: "a", Max: 1000000}},
+		},
+	}
+	mock := newMock()
+	mock.results["b"] = &dispatch.Result{ExitCode: 1, Output: "always fails"}
+	r := newTestRunner(t, cfg, mock)
+	r.MaxDispatches = 10
+
+	err := r.Run(context.Background())
+	assertExitCode(t, err, ExitPhaseFailure)
+	if err == nil || !strings.Contains(err.Error(), "dispatch cap exceeded") {
+		t.Fatalf("expected dispatch cap error, got %v", err)
+	}
+	if n := mock.callCount(); n != 10 {
+		t.Fatalf("dispatched %d phases, want 10", n)
+	}
+}
+
 func TestRun_ConditionFalse(t *testing.T) {
 	cfg := &config.Config{
 		Name: "test",
@@ -698,97 +1254,307 @@ func TestRun_ConditionTrue(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if mock.callCount() != 1 {
-		t.Fatalf("expected 1 call, got %d", mock.callCount())
+	if mock.callCount() != 1 {
+		t.Fatalf("expected 1 call, got %d", mock.callCount())
+	}
+}
+
+func TestRun_ResumeFromState(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "a", Type: "script", Run: "echo"},
+			{Name: "b", Type: "script", Run: "echo"},
+			{Name: "c", Type: "script", Run: "echo"},
+			{Name: "d", Type: "script", Run: "echo"},
+		},
+	}
+	mock := newMock()
+	r := newTestRunner(t, cfg, mock)
+	r.State.SetPhase(2) // resume from phase c
+
+	err := r.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := mock.callNames()
+	if len(calls) != 2 || calls[0] != "c" || calls[1] != "d" {
+		t.Fatalf("expected [c, d], got %v", calls)
+	}
+}
+
+func TestRun_ContextCancelled(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "a", Type: "script", Run: "echo"},
+		},
+	}
+	mock := newMock()
+	r := newTestRunner(t, cfg, mock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // cancel immediately
+
+	err := r.Run(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected error wrapping context.Canceled, got %v", err)
+	}
+	assertExitCode(t, err, ExitInterrupted)
+	if r.State.GetStatus() != state.StatusInterrupted {
+		t.Fatalf("status = %q", r.State.GetStatus())
+	}
+}
+
+func TestRun_OutputCheckPass(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "a", Type: "script", Run: "echo", Outputs: []string{"result.md"}},
+		},
+	}
+	mock := newMock()
+	r := newTestRunner(t, cfg, mock)
+
+	// Create the expected output file before running
+	state.EnsureDir(r.Env.ArtifactsDir)
+	os.WriteFile(filepath.Join(r.Env.ArtifactsDir, "result.md"), []byte("done"), 0644)
+
+	err := r.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.State.GetStatus() != state.StatusCompleted {
+		t.Fatalf("status = %q", r.State.GetStatus())
+	}
+}
+
+func TestRun_OutputCheckFail(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "a", Type: "script", Run: "echo", Outputs: []string{"missing.md"}},
+		},
+	}
+	mock := newMock()
+	r := newTestRunner(t, cfg, mock)
+
+	err := r.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "missing outputs") {
+		t.Fatalf("expected missing outputs error, got %v", err)
+	}
+	if r.State.GetStatus() != state.StatusFailed {
+		t.Fatalf("status = %q", r.State.GetStatus())
+	}
+}
+
+func TestRun_OutputsDir(t *testing.T) {
+	cfg := &config.Config{
+		Name:       "test",
+		OutputsDir: "reports",
+		Phases: []config.Phase{
+			{Name: "a", Type: "script", Run: "echo", Outputs: []string{"summary.md"}},
+		},
+	}
+	r := newTestRunner(t, cfg, &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		// The runner creates outputs-dir up front; write the output there only.
+		return &dispatch.Result{}, os.WriteFile(filepath.Join(env.ArtifactsDir, "reports", "summary.md"), []byte("ok"), 0644)
+	}})
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("expected summary.md to be found under reports/, got %v", err)
+	}
+
+	// A bare summary.md at the artifacts root does not satisfy the check.
+	r2 := newTestRunner(t, cfg, &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		return &dispatch.Result{}, os.WriteFile(filepath.Join(env.ArtifactsDir, "summary.md"), []byte("ok"), 0644)
+	}})
+	err := r2.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), filepath.Join("reports", "summary.md")) {
+		t.Fatalf("expected missing reports/summary.md, got %v", err)
+	}
+}
+
+func TestRun_PhaseOutputDirs(t *testing.T) {
+	cfg := &config.Config{
+		Name:            "test",
+		PhaseOutputDirs: true,
+		Phases: []config.Phase{
+			{Name: "api", Type: "script", Run: "echo", Outputs: []string{"summary.md"}},
+			{Name: "web", Type: "script", Run: "echo", Outputs: []string{"summary.md"}},
+		},
+	}
+	dirs := make(map[string]string)
+	var apiSummary string
+	r := newTestRunner(t, cfg, &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		dir := env.Vars()["PHASE_OUTPUT_DIR"]
+		dirs[phase.Name] = dir
+		if err := os.WriteFile(filepath.Join(dir, "summary.md"), []byte(phase.Name), 0644); err != nil {
+			return nil, err
+		}
+		if phase.Name == "web" {
+			data, _ := os.ReadFile(filepath.Join(dirs["api"], "summary.md"))
+			apiSummary = string(data)
+		}
+		return &dispatch.Result{}, nil
+	}})
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, name := range []string{"api", "web"} {
+		if want := filepath.Join(r.Env.ArtifactsDir, "outputs", name); dirs[name] != want {
+			t.Fatalf("%s: PHASE_OUTPUT_DIR = %q, want %q", name, dirs[name], want)
+		}
+	}
+	if apiSummary != "api" {
+		t.Fatalf("api's summary.md = %q after web ran, want it untouched", apiSummary)
 	}
 }
 
-func TestRun_ResumeFromState(t *testing.T) {
+func TestRun_CompletionBannerNamesProducedArtifacts(t *testing.T) {
 	cfg := &config.Config{
 		Name: "test",
 		Phases: []config.Phase{
-			{Name: "a", Type: "script", Run: "echo"},
-			{Name: "b", Type: "script", Run: "echo"},
-			{Name: "c", Type: "script", Run: "echo"},
-			{Name: "d", Type: "script", Run: "echo"},
+			{Name: "plan", Type: "script", Run: "echo", Outputs: []string{"plan.md"}},
+			{Name: "notes", Type: "script", Run: "echo", Outputs: []string{"notes.md"}, Condition: "false"},
 		},
 	}
-	mock := newMock()
-	r := newTestRunner(t, cfg, mock)
-	r.State.SetPhase(2) // resume from phase c
+	r := newTestRunner(t, cfg, &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		return &dispatch.Result{}, os.WriteFile(filepath.Join(env.ArtifactsDir, "plan.md"), []byte("plan"), 0644)
+	}})
 
-	err := r.Run(context.Background())
-	if err != nil {
+	origStdout := os.Stdout
+	rp, wp, _ := os.Pipe()
+	os.Stdout = wp
+	defer wp.Close()
+	t.Cleanup(func() { os.Stdout = origStdout })
+
+	if err := r.Run(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	calls := mock.callNames()
-	if len(calls) != 2 || calls[0] != "c" || calls[1] != "d" {
-		t.Fatalf("expected [c, d], got %v", calls)
+	wp.Close()
+	var buf bytes.Buffer
+	io.Copy(&buf, rp)
+	out := buf.String()
+
+	if !strings.Contains(out, "Produced artifacts:") {
+		t.Fatalf("completion banner missing:\n%s", out)
+	}
+	// The run was archived, so the listed path points into history/.
+	if !regexp.MustCompile(`history/[^/\s]+/plan\.md`).MatchString(out) {
+		t.Fatalf("banner should name the archived plan.md:\n%s", out)
+	}
+	if strings.Contains(out, "notes.md") {
+		t.Fatalf("skipped phase's output should not be listed:\n%s", out)
+	}
+	if !strings.Contains(out, "orc status "+r.Env.Ticket) {
+		t.Fatalf("next-step hint missing:\n%s", out)
 	}
 }
 
-func TestRun_ContextCancelled(t *testing.T) {
+func TestRun_RequireFreshOutputs_StaleOutputRePrompts(t *testing.T) {
 	cfg := &config.Config{
 		Name: "test",
 		Phases: []config.Phase{
-			{Name: "a", Type: "script", Run: "echo"},
+			{Name: "plan", Type: "agent", Prompt: "unused.md", Model: "sonnet",
+				Outputs: []string{"plan.md"}, RequireFreshOutputs: true},
 		},
 	}
-	mock := newMock()
-	r := newTestRunner(t, cfg, mock)
+	// The agent "succeeds" without touching plan.md, leaving a copy from an
+	// earlier run in place.
+	r := newTestRunner(t, cfg, &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		return &dispatch.Result{ExitCode: 0}, nil
+	}})
+	planPath := filepath.Join(r.Env.ArtifactsDir, "plan.md")
+	os.MkdirAll(r.Env.ArtifactsDir, 0755)
+	if err := os.WriteFile(planPath, []byte("old plan"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(planPath, old, old); err != nil {
+		t.Fatal(err)
+	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	cancel() // cancel immediately
+	var rePrompt string
+	r.RePromptFn = func(ctx context.Context, phase config.Phase, env *dispatch.Environment, prompt, sessionID string) (*dispatch.Result, error) {
+		rePrompt = prompt
+		return &dispatch.Result{ExitCode: 0}, os.WriteFile(planPath, []byte("new plan"), 0644)
+	}
 
-	err := r.Run(ctx)
-	if !errors.Is(err, context.Canceled) {
-		t.Fatalf("expected error wrapping context.Canceled, got %v", err)
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
 	}
-	assertExitCode(t, err, ExitInterrupted)
-	if r.State.GetStatus() != state.StatusInterrupted {
-		t.Fatalf("status = %q", r.State.GetStatus())
+	if !strings.Contains(rePrompt, "not written in this run") || !strings.Contains(rePrompt, planPath) {
+		t.Fatalf("expected re-prompt for stale plan.md, got %q", rePrompt)
+	}
+	meta := readTestMeta(t, r.Env.ArtifactsDir, 0)
+	if len(meta.Outputs) != 1 || meta.Outputs[0].Name != "plan.md" || meta.Outputs[0].SHA256 == "" {
+		t.Fatalf("expected plan.md fingerprint in metadata, got %+v", meta.Outputs)
 	}
 }
 
-func TestRun_OutputCheckPass(t *testing.T) {
+func TestRun_RePromptListsOnlyMissingOutputs(t *testing.T) {
 	cfg := &config.Config{
 		Name: "test",
 		Phases: []config.Phase{
-			{Name: "a", Type: "script", Run: "echo", Outputs: []string{"result.md"}},
+			{Name: "plan", Type: "agent", Prompt: "unused.md", Model: "sonnet",
+				Outputs: []string{"plan.md", "risks.md"}},
 		},
 	}
-	mock := newMock()
-	r := newTestRunner(t, cfg, mock)
+	r := newTestRunner(t, cfg, &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		return &dispatch.Result{ExitCode: 0}, os.WriteFile(filepath.Join(env.ArtifactsDir, "plan.md"), []byte("plan"), 0644)
+	}})
+	planPath := filepath.Join(r.Env.ArtifactsDir, "plan.md")
+	risksPath := filepath.Join(r.Env.ArtifactsDir, "risks.md")
 
-	// Create the expected output file before running
-	state.EnsureDir(r.Env.ArtifactsDir)
-	os.WriteFile(filepath.Join(r.Env.ArtifactsDir, "result.md"), []byte("done"), 0644)
+	var rePrompts []string
+	r.RePromptFn = func(ctx context.Context, phase config.Phase, env *dispatch.Environment, prompt, sessionID string) (*dispatch.Result, error) {
+		rePrompts = append(rePrompts, prompt)
+		return &dispatch.Result{ExitCode: 0}, os.WriteFile(risksPath, []byte("risks"), 0644)
+	}
 
-	err := r.Run(context.Background())
-	if err != nil {
+	if err := r.Run(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	if r.State.GetStatus() != state.StatusCompleted {
-		t.Fatalf("status = %q", r.State.GetStatus())
+	if len(rePrompts) != 1 {
+		t.Fatalf("expected one consolidated re-prompt, got %d", len(rePrompts))
+	}
+	missingPart, presentPart, ok := strings.Cut(rePrompts[0], "already present")
+	if !ok {
+		t.Fatalf("re-prompt should acknowledge present outputs, got %q", rePrompts[0])
+	}
+	if !strings.Contains(missingPart, risksPath) || strings.Contains(missingPart, planPath) {
+		t.Errorf("re-prompt should request only risks.md, got %q", rePrompts[0])
+	}
+	if !strings.Contains(presentPart, planPath) {
+		t.Errorf("re-prompt should name plan.md as present, got %q", rePrompts[0])
 	}
 }
 
-func TestRun_OutputCheckFail(t *testing.T) {
+func TestRun_RequireFreshOutputs_StaleScriptOutputFails(t *testing.T) {
 	cfg := &config.Config{
 		Name: "test",
 		Phases: []config.Phase{
-			{Name: "a", Type: "script", Run: "echo", Outputs: []string{"missing.md"}},
+			{Name: "a", Type: "script", Run: "true", Outputs: []string{"report.md"}, RequireFreshOutputs: true},
 		},
 	}
-	mock := newMock()
-	r := newTestRunner(t, cfg, mock)
+	r := newTestRunner(t, cfg, &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		return &dispatch.Result{ExitCode: 0}, nil
+	}})
+	reportPath := filepath.Join(r.Env.ArtifactsDir, "report.md")
+	os.MkdirAll(r.Env.ArtifactsDir, 0755)
+	if err := os.WriteFile(reportPath, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(reportPath, old, old); err != nil {
+		t.Fatal(err)
+	}
 
 	err := r.Run(context.Background())
-	if err == nil || !strings.Contains(err.Error(), "missing outputs") {
-		t.Fatalf("expected missing outputs error, got %v", err)
-	}
-	if r.State.GetStatus() != state.StatusFailed {
-		t.Fatalf("status = %q", r.State.GetStatus())
+	if err == nil || !strings.Contains(err.Error(), "stale outputs") {
+		t.Fatalf("expected stale output failure, got %v", err)
 	}
 }
 
@@ -1015,6 +1781,257 @@ func TestRun_ParallelOneFails(t *testing.T) {
 	}
 }
 
+func TestRun_ParallelKeepGoing_ReportsEveryFailure(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "a", Type: "script", Run: "echo", ParallelWith: "b"},
+			{Name: "b", Type: "script", Run: "echo"},
+			{Name: "c", Type: "script", Run: "echo"},
+		},
+	}
+	var bCancelled bool
+	mock := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		switch phase.Name {
+		case "a":
+			return &dispatch.Result{ExitCode: 1, Output: "a broke"}, nil
+		case "b":
+			// Fail late, so a cancel from a's failure would land first.
+			select {
+			case <-ctx.Done():
+				bCancelled = true
+				return &dispatch.Result{ExitCode: 1}, ctx.Err()
+			case <-time.After(200 * time.Millisecond):
+			}
+			return &dispatch.Result{ExitCode: 2, Output: "b broke"}, nil
+		}
+		return &dispatch.Result{ExitCode: 0}, nil
+	}}
+	r := newTestRunner(t, cfg, mock)
+	r.KeepGoing = true
+
+	err := r.Run(context.Background())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if bCancelled {
+		t.Fatal("--keep-going should let b finish instead of cancelling it")
+	}
+	for _, want := range []string{"2 parallel phases failed", `phase "a" failed`, `phase "b" failed`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+	if r.State.GetStatus() != state.StatusFailed {
+		t.Fatalf("status = %q", r.State.GetStatus())
+	}
+}
+
+func TestRun_ParallelKeepGoing_PhaseLevel(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "a", Type: "script", Run: "echo", ParallelWith: "b", KeepGoing: true},
+			{Name: "b", Type: "script", Run: "echo"},
+		},
+	}
+	mock := newMock()
+	mock.results["a"] = &dispatch.Result{ExitCode: 1}
+	mock.results["b"] = &dispatch.Result{ExitCode: 1}
+	r := newTestRunner(t, cfg, mock)
+
+	err := r.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), `phase "a" failed`) || !strings.Contains(err.Error(), `phase "b" failed`) {
+		t.Fatalf("expected both failures in the error, got %v", err)
+	}
+}
+
+func TestRun_ParallelGroupLoop_AggregatesFeedback(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "prep", Type: "script", Run: "echo"},
+			{Name: "lint", Type: "script", Run: "echo", ParallelWith: "test", Loop: &config.Loop{Goto: "prep", Max: 2}},
+			{Name: "test", Type: "script", Run: "echo"},
+		},
+	}
+
+	counts := make(map[string]int)
+	mu := sync.Mutex{}
+	var prepSawFeedback string
+	mock := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		mu.Lock()
+		counts[phase.Name]++
+		n := counts[phase.Name]
+		mu.Unlock()
+		switch {
+		case phase.Name == "prep" && n == 2:
+			prepSawFeedback, _ = state.ReadAllFeedback(env.ArtifactsDir)
+		case phase.Name != "prep" && n == 1:
+			return &dispatch.Result{ExitCode: 1, Output: phase.Name + " failed"}, nil
+		}
+		return &dispatch.Result{ExitCode: 0}, nil
+	}}
+
+	r := newTestRunner(t, cfg, mock)
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if counts["prep"] != 2 || counts["lint"] != 2 || counts["test"] != 2 {
+		t.Fatalf("expected every phase to run twice, got %v", counts)
+	}
+	for _, want := range []string{"lint failed", "test failed", "Feedback from lint+test"} {
+		if !strings.Contains(prepSawFeedback, want) {
+			t.Fatalf("feedback missing %q: %q", want, prepSawFeedback)
+		}
+	}
+}
+
+func TestRun_ParallelGroupLoop_RemovesFeedbackOnSuccess(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "prep", Type: "script", Run: "echo"},
+			{Name: "lint", Type: "script", Run: "echo", ParallelWith: "test", Loop: &config.Loop{Goto: "prep", Max: 2}},
+			{Name: "test", Type: "script", Run: "echo"},
+			{Name: "report", Type: "script", Run: "echo"},
+		},
+	}
+
+	counts := make(map[string]int)
+	mu := sync.Mutex{}
+	reportSawFeedback := "unset"
+	mock := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		mu.Lock()
+		counts[phase.Name]++
+		n := counts[phase.Name]
+		mu.Unlock()
+		switch {
+		case phase.Name == "report":
+			reportSawFeedback, _ = state.ReadAllFeedback(env.ArtifactsDir)
+		case phase.Name == "lint" && n == 1:
+			return &dispatch.Result{ExitCode: 1, Output: "lint failed"}, nil
+		}
+		return &dispatch.Result{ExitCode: 0}, nil
+	}}
+
+	r := newTestRunner(t, cfg, mock)
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if reportSawFeedback != "" {
+		t.Fatalf("phase after the recovered group saw stale feedback: %q", reportSawFeedback)
+	}
+	if _, err := os.Stat(state.FeedbackPath(r.Env.ArtifactsDir, "lint+test")); !os.IsNotExist(err) {
+		t.Fatalf("expected group feedback to be removed, stat err = %v", err)
+	}
+	auditDir := state.AuditDir(r.Env.ProjectRoot, r.Env.Ticket)
+	if _, err := os.Stat(state.AuditFeedbackPath(auditDir, 1, 2, "lint+test")); err != nil {
+		t.Fatalf("expected removed feedback to be archived: %v", err)
+	}
+}
+
+func TestRun_RemovesOwnFeedbackOnSuccess(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "setup", Type: "script", Run: "echo"},
+			{Name: "check", Type: "script", Run: "echo"},
+			{Name: "after", Type: "script", Run: "echo"},
+		},
+	}
+	var afterSawFeedback string
+	mock := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		switch phase.Name {
+		case "setup":
+			// Stand-ins for feedback from earlier check and review failures.
+			if err := state.WriteFeedback(env.ArtifactsDir, "check", "check failed"); err != nil {
+				return nil, err
+			}
+			if err := state.WriteFeedback(env.ArtifactsDir, "review", "review failed"); err != nil {
+				return nil, err
+			}
+		case "after":
+			afterSawFeedback, _ = state.ReadAllFeedback(env.ArtifactsDir)
+		}
+		return &dispatch.Result{ExitCode: 0}, nil
+	}}
+	r := newTestRunner(t, cfg, mock)
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(afterSawFeedback, "check failed") {
+		t.Fatalf("check's feedback should be removed once check passes: %q", afterSawFeedback)
+	}
+	if !strings.Contains(afterSawFeedback, "review failed") {
+		t.Fatalf("feedback from other phases should be kept: %q", afterSawFeedback)
+	}
+}
+
+func TestRun_ParallelGroupLoop_Exhausted(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "prep", Type: "script", Run: "echo"},
+			{Name: "lint", Type: "script", Run: "echo", ParallelWith: "test"},
+			{Name: "test", Type: "script", Run: "echo", Loop: &config.Loop{Goto: "prep", Max: 2}},
+		},
+	}
+	mock := newMock()
+	mock.results["test"] = &dispatch.Result{ExitCode: 1, Output: "still failing"}
+	r := newTestRunner(t, cfg, mock)
+
+	err := r.Run(context.Background())
+	assertExitCode(t, err, ExitPhaseFailure)
+	prepCalls := 0
+	for _, c := range mock.callNames() {
+		if c == "prep" {
+			prepCalls++
+		}
+	}
+	if prepCalls != 2 {
+		t.Fatalf("prep called %d times, want 2", prepCalls)
+	}
+}
+
+func TestRun_WritesArtifactsGitignore(t *testing.T) {
+	cfg := &config.Config{
+		Name:   "test",
+		Phases: []config.Phase{{Name: "a", Type: "script", Run: "echo"}},
+	}
+	r := newTestRunner(t, cfg, newMock())
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(r.Env.ArtifactsDir, ".gitignore"))
+	if err != nil {
+		t.Fatalf("artifacts .gitignore not created: %v", err)
+	}
+	if string(data) != "*\n" {
+		t.Fatalf(".gitignore = %q", data)
+	}
+}
+
+func TestRun_ArtifactsGitignoreDisabled(t *testing.T) {
+	off := false
+	cfg := &config.Config{
+		Name:               "test",
+		ArtifactsGitignore: &off,
+		Phases:             []config.Phase{{Name: "a", Type: "script", Run: "echo"}},
+	}
+	r := newTestRunner(t, cfg, newMock())
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(r.Env.ArtifactsDir, ".gitignore")); !os.IsNotExist(err) {
+		t.Fatalf("expected no .gitignore when disabled, got err=%v", err)
+	}
+}
+
 func TestRun_SavesStatePersistently(t *testing.T) {
 	cfg := &config.Config{
 		Name: "test",
@@ -1188,14 +2205,49 @@ func TestRun_ConditionVarExpandedFalse(t *testing.T) {
 	}
 	mock := newMock()
 	r := newTestRunner(t, cfg, mock)
-	r.Env.CustomVars = map[string]string{"MY_DIR": "/nonexistent/path/that/does/not/exist"}
+	r.Env.CustomVars = map[string]string{"MY_DIR": "/nonexistent/path/that/does/not/exist"}
+
+	err := r.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mock.callCount() != 0 {
+		t.Fatalf("expected 0 calls (condition false after expansion), got %d", mock.callCount())
+	}
+}
+
+func TestRun_ApproveAllGatesAutoApprovesLaterGates(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "review-plan", Type: "gate"},
+			{Name: "implement", Type: "script", Run: "echo"},
+			{Name: "review-code", Type: "gate"},
+		},
+	}
+	var laterGate *dispatch.Result
+	mock := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		switch phase.Name {
+		case "review-plan":
+			// The operator answered "ya".
+			return &dispatch.Result{ExitCode: 0, ApproveAllGates: true}, nil
+		case "review-code":
+			res, err := dispatch.RunGate(ctx, phase, env)
+			laterGate = res
+			return res, err
+		}
+		return &dispatch.Result{ExitCode: 0}, nil
+	}}
+	r := newTestRunner(t, cfg, mock)
 
-	err := r.Run(context.Background())
-	if err != nil {
+	if err := r.Run(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	if mock.callCount() != 0 {
-		t.Fatalf("expected 0 calls (condition false after expansion), got %d", mock.callCount())
+	if laterGate == nil || !strings.Contains(laterGate.Output, "auto-approved") {
+		t.Fatalf("expected later gate to be auto-approved, got %+v", laterGate)
+	}
+	if r.Env.AutoMode {
+		t.Fatal("approving all gates must not switch the run into --auto")
 	}
 }
 
@@ -1221,7 +2273,7 @@ func TestDryRunPrint_VarsAreSorted(t *testing.T) {
 	pr, pw, _ := os.Pipe()
 	os.Stdout = pw
 
-	r.DryRunPrint()
+	r.DryRunPrint(context.Background())
 
 	pw.Close()
 	os.Stdout = oldStdout
@@ -1264,7 +2316,7 @@ func TestDryRunPrint_ExpandsORCPrefixedVars(t *testing.T) {
 	pr, pw, _ := os.Pipe()
 	os.Stdout = pw
 
-	r.DryRunPrint()
+	r.DryRunPrint(context.Background())
 
 	pw.Close()
 	os.Stdout = oldStdout
@@ -1279,6 +2331,85 @@ func TestDryRunPrint_ExpandsORCPrefixedVars(t *testing.T) {
 	}
 }
 
+func TestDryRunPrint_EstimatesPromptCost(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "setup", Type: "script", Run: "echo"},
+			{Name: "implement", Type: "agent", Prompt: "implement.md", Model: "opus"},
+		},
+	}
+	mock := newMock()
+	r := newTestRunner(t, cfg, mock)
+	if err := os.WriteFile(filepath.Join(r.Env.ProjectRoot, "implement.md"), []byte(strings.Repeat("x", 4000)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldStdout := os.Stdout
+	pr, pw, _ := os.Pipe()
+	os.Stdout = pw
+
+	r.DryRunPrint(context.Background())
+
+	pw.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, pr)
+	output := buf.String()
+
+	if !strings.Contains(output, "Estimated prompt cost") {
+		t.Fatalf("expected cost estimate section, got:\n%s", output)
+	}
+	if !strings.Contains(output, "implement") || !strings.Contains(output, "~1000 tok") {
+		t.Errorf("expected ~1000 token estimate for agent phase, got:\n%s", output)
+	}
+	if !strings.Contains(output, "total") || !strings.Contains(output, "~$") {
+		t.Errorf("expected total cost line, got:\n%s", output)
+	}
+	est := output[strings.Index(output, "Estimated prompt cost"):]
+	if strings.Contains(est, "setup") {
+		t.Errorf("script phase should not be estimated:\n%s", est)
+	}
+}
+
+func TestDryRunPrint_WarnsOnOversizedPrompt(t *testing.T) {
+	cfg := &config.Config{
+		Name:           "test",
+		MaxPromptBytes: 1000,
+		Phases: []config.Phase{
+			{Name: "plan", Type: "agent", Prompt: "plan.md", Model: "opus"},
+			{Name: "implement", Type: "agent", Prompt: "implement.md", Model: "opus"},
+		},
+	}
+	mock := newMock()
+	r := newTestRunner(t, cfg, mock)
+	os.WriteFile(filepath.Join(r.Env.ProjectRoot, "plan.md"), []byte("short"), 0644)
+	os.WriteFile(filepath.Join(r.Env.ProjectRoot, "implement.md"), []byte(strings.Repeat("x", 2000)), 0644)
+
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	_, outW, _ := os.Pipe()
+	errR, errW, _ := os.Pipe()
+	os.Stdout, os.Stderr = outW, errW
+
+	r.DryRunPrint(context.Background())
+
+	outW.Close()
+	errW.Close()
+	os.Stdout, os.Stderr = oldStdout, oldStderr
+
+	var buf bytes.Buffer
+	io.Copy(&buf, errR)
+	stderr := buf.String()
+
+	if !strings.Contains(stderr, "phase 2 (implement): rendered prompt is") || !strings.Contains(stderr, "max-prompt-bytes") {
+		t.Fatalf("expected oversized prompt warning for implement, got:\n%s", stderr)
+	}
+	if strings.Contains(stderr, "plan") {
+		t.Errorf("small prompt should not be flagged:\n%s", stderr)
+	}
+}
+
 func TestRun_CostsTrackedForAgentPhases(t *testing.T) {
 	cfg := &config.Config{
 		Name: "test",
@@ -1428,6 +2559,63 @@ func TestRun_TimeoutExitCode(t *testing.T) {
 	}
 }
 
+func TestRun_SecretEnvReachesOnlyItsPhase(t *testing.T) {
+	const secret = "s3cret-token-value"
+	t.Setenv("ORC_TEST_GH_PAT", secret)
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "before", Type: "script", Run: `test -z "$GITHUB_TOKEN"`, Timeout: 1},
+			{Name: "push", Type: "script", Run: `test "$GITHUB_TOKEN" = "$EXPECTED"`, Timeout: 1,
+				SecretEnv: map[string]string{"GITHUB_TOKEN": "ORC_TEST_GH_PAT"}},
+			// The next phase's condition must not see the secret either.
+			{Name: "after", Type: "script", Run: `test -z "$GITHUB_TOKEN" && touch "$PROJECT_ROOT/after-ran"`, Timeout: 1,
+				Condition: `test -z "$GITHUB_TOKEN"`},
+		},
+	}
+	t.Setenv("EXPECTED", secret)
+	r := newTestRunner(t, cfg, &dispatch.DefaultDispatcher{})
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("run failed (secret leaked to another phase or missing from its own): %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(r.Env.ProjectRoot, "after-ran")); err != nil {
+		t.Fatal("phase after the secret-env phase was skipped — its condition saw the secret")
+	}
+
+	for _, root := range []string{r.Env.ProjectRoot, filepath.Dir(r.Env.ArtifactsDir)} {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err == nil && bytes.Contains(data, []byte(secret)) {
+				t.Errorf("secret written to %s", path)
+			}
+			return nil
+		})
+	}
+}
+
+func TestRun_StalledPhaseFailsAsTimeout(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "a", Type: "script", Run: "echo", Timeout: 10, InactivityTimeout: 2},
+		},
+	}
+	mock := newMock()
+	mock.results["a"] = &dispatch.Result{ExitCode: 143, Stalled: true}
+	r := newTestRunner(t, cfg, mock)
+	err := r.Run(context.Background())
+	assertExitCode(t, err, ExitTimeout)
+	if r.State.GetFailureCategory() != state.FailCategoryTimeout {
+		t.Fatalf("failure_category = %q, want %q", r.State.GetFailureCategory(), state.FailCategoryTimeout)
+	}
+	if detail := r.State.GetFailureDetail(); !strings.Contains(detail, "no output for 2m") {
+		t.Fatalf("failure detail = %q, want the inactivity message", detail)
+	}
+}
+
 func TestRun_ParallelTimeoutExitCode(t *testing.T) {
 	cfg := &config.Config{
 		Name: "test",
@@ -1478,6 +2666,54 @@ func TestRun_SuccessExitCode(t *testing.T) {
 	assertExitCode(t, err, ExitSuccess)
 }
 
+func TestRun_SuccessExitCodesTreatsListedCodeAsSuccess(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "commit", Type: "script", Run: "git commit -am wip", SuccessExitCodes: []int{0, 1}},
+			{Name: "push", Type: "script", Run: "echo"},
+		},
+	}
+	mock := newMock()
+	mock.results["commit"] = &dispatch.Result{ExitCode: 1, Output: "nothing to commit, working tree clean"}
+	r := newTestRunner(t, cfg, mock)
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("exit code 1 is listed in success-exit-codes, run should succeed: %v", err)
+	}
+	if calls := mock.callNames(); len(calls) != 2 || calls[1] != "push" {
+		t.Fatalf("expected the next phase to run, calls = %v", calls)
+	}
+}
+
+func TestRun_SuccessExitCodesUnlistedCodeFails(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "commit", Type: "script", Run: "git commit -am wip", SuccessExitCodes: []int{0, 1}},
+		},
+	}
+	mock := newMock()
+	mock.results["commit"] = &dispatch.Result{ExitCode: 128, Output: "fatal: not a git repository"}
+	r := newTestRunner(t, cfg, mock)
+
+	assertExitCode(t, r.Run(context.Background()), ExitPhaseFailure)
+}
+
+func TestRun_SuccessExitCodesDoNotMaskTimeout(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "slow", Type: "script", Run: "sleep 999", SuccessExitCodes: []int{0, 1}},
+		},
+	}
+	mock := newMock()
+	mock.results["slow"] = &dispatch.Result{ExitCode: 1, TimedOut: true}
+	r := newTestRunner(t, cfg, mock)
+
+	assertExitCode(t, r.Run(context.Background()), ExitTimeout)
+}
+
 // Cost limit tests — sequential path
 
 func TestRun_RunCostLimitExceeded(t *testing.T) {
@@ -2721,6 +3957,148 @@ func TestRun_PostRunWarning_OnDispatchFailure(t *testing.T) {
 	// Just verify it fails — the warning goes to stderr, which is tested by the behavior
 }
 
+func TestRun_OnSuccess_RunsOnlyAfterSuccess(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "on-success.log")
+	onSuccess := `echo "$ORC_PHASE_NAME" >> ` + log
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "build", Type: "script", Run: "echo", PostRun: "echo post >> " + log, OnSuccess: onSuccess},
+			{Name: "test", Type: "script", Run: "echo", OnSuccess: onSuccess},
+		},
+	}
+	mock := newMock()
+	mock.results["test"] = &dispatch.Result{ExitCode: 1}
+	r := newTestRunner(t, cfg, mock)
+	assertExitCode(t, r.Run(context.Background()), ExitPhaseFailure)
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "post\nbuild\n"; got != want {
+		t.Fatalf("hook log = %q, want %q (on-success after post-run, not after the failing phase)", got, want)
+	}
+}
+
+func TestRun_OnSuccessFailure(t *testing.T) {
+	for _, tc := range []struct {
+		policy  string
+		wantErr bool
+	}{
+		{"", false},
+		{"fail", true},
+	} {
+		t.Run("policy="+tc.policy, func(t *testing.T) {
+			cfg := &config.Config{
+				Name:   "test",
+				Phases: []config.Phase{{Name: "a", Type: "script", Run: "echo", OnSuccess: "exit 3", OnSuccessFail: tc.policy}},
+			}
+			r := newTestRunner(t, cfg, newMock())
+			err := r.Run(context.Background())
+			if tc.wantErr {
+				assertExitCode(t, err, ExitPhaseFailure)
+			} else if err != nil {
+				t.Fatalf("on-success failure should only warn, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRun_VerifyPasses(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "test", Type: "script", Run: "echo", Outputs: []string{"result.txt"}, Verify: "grep -q PASS $ARTIFACTS_DIR/result.txt"},
+			{Name: "ship", Type: "script", Run: "echo"},
+		},
+	}
+	mock := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		if phase.Name == "test" {
+			os.WriteFile(filepath.Join(env.ArtifactsDir, "result.txt"), []byte("ok: PASS\n"), 0644)
+		}
+		return &dispatch.Result{ExitCode: 0}, nil
+	}}
+	r := newTestRunner(t, cfg, mock)
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("passing verify should not fail the phase: %v", err)
+	}
+	if r.State.GetStatus() != state.StatusCompleted {
+		t.Fatalf("status = %q, want completed", r.State.GetStatus())
+	}
+}
+
+func TestRun_VerifyFails(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "test", Type: "script", Run: "echo", Verify: "echo 'expected PASS in output'; exit 4"},
+			{Name: "ship", Type: "script", Run: "echo"},
+		},
+	}
+	mock := newMock()
+	r := newTestRunner(t, cfg, mock)
+
+	err := r.Run(context.Background())
+	assertExitCode(t, err, ExitPhaseFailure)
+	if !strings.Contains(err.Error(), "verify failed (exit 4)") {
+		t.Errorf("error = %v, want verify failure", err)
+	}
+	if slices.Contains(mock.calls, "ship") {
+		t.Error("phase after a failed verify should not run")
+	}
+	if got := r.State.GetFailureCategory(); got != state.FailCategoryVerifyFailure {
+		t.Errorf("failure category = %q, want %q", got, state.FailCategoryVerifyFailure)
+	}
+	if fb := state.ReadFeedback(r.Env.ArtifactsDir, "test"); !strings.Contains(fb, "expected PASS in output") {
+		t.Errorf("feedback = %q, want verify output", fb)
+	}
+}
+
+func TestRun_ParallelVerifyFailureLoopsGroup(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "prep", Type: "script", Run: "echo"},
+			{Name: "lint", Type: "script", Run: "echo", ParallelWith: "test", Loop: &config.Loop{Goto: "prep", Max: 2}},
+			{Name: "test", Type: "script", Run: "echo", Verify: "grep -q PASS $ARTIFACTS_DIR/result.txt || { echo 'expected PASS in result.txt'; exit 1; }"},
+		},
+	}
+
+	counts := make(map[string]int)
+	mu := sync.Mutex{}
+	var prepSawFeedback string
+	mock := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		mu.Lock()
+		counts[phase.Name]++
+		n := counts[phase.Name]
+		mu.Unlock()
+		switch {
+		case phase.Name == "prep" && n == 2:
+			prepSawFeedback, _ = state.ReadAllFeedback(env.ArtifactsDir)
+		case phase.Name == "test":
+			result := "FAIL\n"
+			if n == 2 {
+				result = "PASS\n"
+			}
+			os.WriteFile(filepath.Join(env.ArtifactsDir, "result.txt"), []byte(result), 0644)
+		}
+		return &dispatch.Result{ExitCode: 0}, nil
+	}}
+
+	r := newTestRunner(t, cfg, mock)
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("a failed verify in a looping group should retry the group, got %v", err)
+	}
+	if counts["prep"] != 2 || counts["lint"] != 2 || counts["test"] != 2 {
+		t.Fatalf("expected every phase to run twice, got %v", counts)
+	}
+	if !strings.Contains(prepSawFeedback, "expected PASS in result.txt") {
+		t.Fatalf("feedback missing verify output: %q", prepSawFeedback)
+	}
+}
+
 func TestRun_HooksVarExpansion(t *testing.T) {
 	cfg := &config.Config{
 		Name:   "test",
@@ -3077,36 +4455,104 @@ func TestRun_RePromptRecordsCostAndArchive(t *testing.T) {
 		}, nil
 	}
 
-	if err := r.Run(context.Background()); err != nil {
-		t.Fatal(err)
-	}
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	auditDir := state.AuditDir(r.Env.ProjectRoot, r.Env.Ticket)
+
+	// Both dispatches should be recorded in costs
+	costs, err := state.LoadCosts(auditDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(costs.Phases) != 2 {
+		t.Fatalf("expected 2 cost entries (main + re-prompt), got %d", len(costs.Phases))
+	}
+	const wantTotal = 0.10 + 0.25
+	if costs.TotalCostUSD != wantTotal {
+		t.Fatalf("TotalCostUSD = %f, want %f", costs.TotalCostUSD, wantTotal)
+	}
+
+	// attemptCount for phase 0 should be 2
+	if r.attemptCount[0] != 2 {
+		t.Fatalf("attemptCount[0] = %d, want 2", r.attemptCount[0])
+	}
+
+	// Audit log files must exist for both iter-1 and iter-2
+	if _, err := os.Stat(state.AuditLogPath(auditDir, 0, 1)); err != nil {
+		t.Fatalf("audit log iter-1 missing: %v", err)
+	}
+	if _, err := os.Stat(state.AuditLogPath(auditDir, 0, 2)); err != nil {
+		t.Fatalf("audit log iter-2 missing: %v", err)
+	}
+}
+
+func TestRun_InvalidTypedOutputFailsPhase(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "report", Type: "script", Run: "echo", Outputs: []string{"report.json:json"}},
+			{Name: "after", Type: "script", Run: "echo"},
+		},
+	}
+	mock := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		if phase.Name == "report" {
+			os.WriteFile(filepath.Join(env.ArtifactsDir, "report.json"), []byte(`{"truncated":`), 0644)
+		}
+		return &dispatch.Result{ExitCode: 0}, nil
+	}}
+	r := newTestRunner(t, cfg, mock)
+
+	err := r.Run(context.Background())
+	assertExitCode(t, err, ExitPhaseFailure)
+	if got := r.State.GetFailureCategory(); got != state.FailCategoryOutputInvalid {
+		t.Errorf("failure category = %q, want %q", got, state.FailCategoryOutputInvalid)
+	}
+	if !strings.Contains(r.State.GetFailureDetail(), `output "report.json" is not valid JSON`) {
+		t.Errorf("failure detail = %q", r.State.GetFailureDetail())
+	}
+}
+
+func TestRun_OutputsOptionalMissingStillCompletes(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "fix", Type: "agent", Prompt: "unused.md", Model: "sonnet",
+				Outputs: []string{"fixes.md"}, OutputsOptional: true},
+			{Name: "after", Type: "script", Run: "echo"},
+		},
+	}
+	mock := newMock()
+	r := newTestRunner(t, cfg, mock)
+	reprompted := false
+	r.RePromptFn = func(ctx context.Context, phase config.Phase, env *dispatch.Environment, prompt, sessionID string) (*dispatch.Result, error) {
+		reprompted = true
+		return &dispatch.Result{ExitCode: 0}, nil
+	}
+
+	oldStderr := os.Stderr
+	pr, pw, _ := os.Pipe()
+	os.Stderr = pw
+
+	err := r.Run(context.Background())
 
-	auditDir := state.AuditDir(r.Env.ProjectRoot, r.Env.Ticket)
+	pw.Close()
+	var buf bytes.Buffer
+	io.Copy(&buf, pr)
+	os.Stderr = oldStderr
 
-	// Both dispatches should be recorded in costs
-	costs, err := state.LoadCosts(auditDir)
 	if err != nil {
-		t.Fatal(err)
-	}
-	if len(costs.Phases) != 2 {
-		t.Fatalf("expected 2 cost entries (main + re-prompt), got %d", len(costs.Phases))
-	}
-	const wantTotal = 0.10 + 0.25
-	if costs.TotalCostUSD != wantTotal {
-		t.Fatalf("TotalCostUSD = %f, want %f", costs.TotalCostUSD, wantTotal)
+		t.Fatalf("run should complete with optional outputs missing: %v", err)
 	}
-
-	// attemptCount for phase 0 should be 2
-	if r.attemptCount[0] != 2 {
-		t.Fatalf("attemptCount[0] = %d, want 2", r.attemptCount[0])
+	if reprompted {
+		t.Fatal("outputs-optional phase should not be re-prompted")
 	}
-
-	// Audit log files must exist for both iter-1 and iter-2
-	if _, err := os.Stat(state.AuditLogPath(auditDir, 0, 1)); err != nil {
-		t.Fatalf("audit log iter-1 missing: %v", err)
+	if !strings.Contains(buf.String(), "missing outputs: [fixes.md] (outputs-optional") {
+		t.Fatalf("missing optional output should still be reported, stderr: %q", buf.String())
 	}
-	if _, err := os.Stat(state.AuditLogPath(auditDir, 0, 2)); err != nil {
-		t.Fatalf("audit log iter-2 missing: %v", err)
+	if len(mock.calls) != 2 {
+		t.Fatalf("calls = %v, want both phases to run", mock.calls)
 	}
 }
 
@@ -3555,175 +5001,457 @@ func TestRun_StepModeParallelAbort(t *testing.T) {
 	if calls[0] != "pre" {
 		t.Fatalf("calls[0] = %q, want \"pre\"", calls[0])
 	}
-	pair := []string{calls[1], calls[2]}
-	sort.Strings(pair)
-	if pair[0] != "b" || pair[1] != "c" {
-		t.Fatalf("calls[1:3] = %v, want b+c in any order", calls[1:3])
+	pair := []string{calls[1], calls[2]}
+	sort.Strings(pair)
+	if pair[0] != "b" || pair[1] != "c" {
+		t.Fatalf("calls[1:3] = %v, want b+c in any order", calls[1:3])
+	}
+}
+
+func TestRun_ArchivesOnCompletion(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "a", Type: "script", Run: "echo"},
+			{Name: "b", Type: "script", Run: "echo"},
+		},
+	}
+	mock := newMock()
+	r := newTestRunner(t, cfg, mock)
+	artDir := r.Env.ArtifactsDir
+
+	err := r.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// history/ should exist with exactly one entry
+	histDir := filepath.Join(artDir, "history")
+	entries, err := os.ReadDir(histDir)
+	if err != nil {
+		t.Fatalf("reading history dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 history entry, got %d", len(entries))
+	}
+
+	runDir := filepath.Join(histDir, entries[0].Name())
+
+	// state.json should exist with status "completed"
+	st, err := state.Load(runDir)
+	if err != nil {
+		t.Fatalf("loading archived state: %v", err)
+	}
+	if st.GetStatus() != state.StatusCompleted {
+		t.Fatalf("archived status = %q, want %q", st.GetStatus(), state.StatusCompleted)
+	}
+
+	// timing.json and costs.json should be present
+	if _, err := os.Stat(filepath.Join(runDir, "timing.json")); err != nil {
+		t.Fatalf("timing.json missing from archive: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(runDir, "costs.json")); err != nil {
+		t.Fatalf("costs.json missing from archive: %v", err)
+	}
+
+	// top-level state.json should be gone
+	if _, err := os.Stat(filepath.Join(artDir, "state.json")); !os.IsNotExist(err) {
+		t.Fatal("top-level state.json should have been archived away")
+	}
+}
+
+func TestRun_ArchivesOnFailure(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "a", Type: "script", Run: "echo"},
+			{Name: "b", Type: "script", Run: "echo"},
+		},
+	}
+	mock := newMock()
+	mock.results["a"] = &dispatch.Result{ExitCode: 1}
+	r := newTestRunner(t, cfg, mock)
+	artDir := r.Env.ArtifactsDir
+
+	err := r.Run(context.Background())
+	if err == nil {
+		t.Fatal("expected failure")
+	}
+
+	// state.json should still exist in the artifacts root (not archived)
+	st, loadErr := state.Load(artDir)
+	if loadErr != nil {
+		t.Fatalf("loading state: %v", loadErr)
+	}
+	if st.GetStatus() != state.StatusFailed {
+		t.Fatalf("status = %q, want %q", st.GetStatus(), state.StatusFailed)
+	}
+
+	// history/ should NOT exist — failed runs are not archived
+	histDir := filepath.Join(artDir, "history")
+	if _, err := os.Stat(histDir); err == nil {
+		entries, _ := os.ReadDir(histDir)
+		t.Fatalf("expected no history dir after failure, but found %d entries", len(entries))
+	}
+}
+
+func TestRun_DoesNotArchiveStaleOnStart(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "a", Type: "script", Run: "echo"},
+		},
+	}
+	mock := newMock()
+	r := newTestRunner(t, cfg, mock)
+	artDir := r.Env.ArtifactsDir
+
+	// Pre-create artifacts dir with a stale state.json
+	if err := os.MkdirAll(artDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	staleState := &state.State{Status: state.StatusRunning, PhaseIndex: 1}
+	if err := staleState.Save(artDir); err != nil {
+		t.Fatal(err)
+	}
+
+	err := r.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Runner no longer archives stale state (moved to main.go).
+	// history/ should have exactly 1 entry (from successful completion only).
+	histDir := filepath.Join(artDir, "history")
+	entries, err := os.ReadDir(histDir)
+	if err != nil {
+		t.Fatalf("reading history dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 history entry (completion only), got %d", len(entries))
+	}
+}
+
+func TestRun_FailureCategory_AgentError(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "a", Type: "agent", Prompt: "unused.md", Model: "sonnet"},
+		},
+	}
+	mock := newMock()
+	mock.results["a"] = &dispatch.Result{ExitCode: 1}
+	r := newTestRunner(t, cfg, mock)
+
+	err := r.Run(context.Background())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if got := r.State.GetFailureCategory(); got != state.FailCategoryAgentError {
+		t.Fatalf("FailureCategory = %q, want %q", got, state.FailCategoryAgentError)
+	}
+}
+
+func TestRun_FailureCategory_GateRejection(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "approve", Type: "gate"},
+		},
+	}
+	mock := newMock()
+	mock.results["approve"] = &dispatch.Result{ExitCode: 1, Output: "no"}
+	r := newTestRunner(t, cfg, mock)
+
+	err := r.Run(context.Background())
+	if err == nil {
+		t.Fatal("expected error for gate denial")
+	}
+	assertExitCode(t, err, ExitPhaseFailure)
+	if got := r.State.GetFailureCategory(); got != state.FailCategoryGateRejection {
+		t.Fatalf("FailureCategory = %q, want %q", got, state.FailCategoryGateRejection)
+	}
+}
+
+func TestRun_GateOnRejectStop(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "approve", Type: "gate", OnReject: &config.OnReject{Action: "stop"}},
+			{Name: "after", Type: "script", Run: "true"},
+		},
+	}
+	mock := newMock()
+	mock.results["approve"] = &dispatch.Result{ExitCode: 1, Output: "no", Rejected: true}
+	r := newTestRunner(t, cfg, mock)
+
+	err := r.Run(context.Background())
+	assertExitCode(t, err, ExitPhaseFailure)
+	if got := r.State.GetFailureCategory(); got != state.FailCategoryGateRejection {
+		t.Fatalf("FailureCategory = %q, want %q", got, state.FailCategoryGateRejection)
+	}
+	if len(mock.calls) != 1 {
+		t.Fatalf("called = %v, want only the gate", mock.calls)
+	}
+}
+
+func TestRun_GateOnRejectContinue(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "approve", Type: "gate", OnReject: &config.OnReject{Action: "continue"}},
+			{Name: "after", Type: "script", Run: "true"},
+		},
+	}
+	var feedback string
+	disp := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		if phase.Name == "approve" {
+			return &dispatch.Result{ExitCode: 1, Output: "tighten the error handling", Rejected: true}, nil
+		}
+		feedback, _ = state.ReadAllFeedback(env.ArtifactsDir)
+		return &dispatch.Result{ExitCode: 0}, nil
+	}}
+	r := newTestRunner(t, cfg, disp)
+
+	origStdout := os.Stdout
+	rp, wp, _ := os.Pipe()
+	os.Stdout = wp
+	defer wp.Close()
+	t.Cleanup(func() { os.Stdout = origStdout })
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wp.Close()
+	var buf bytes.Buffer
+	io.Copy(&buf, rp)
+	// The rejection is reported as such, not as a failed phase.
+	if out := buf.String(); !strings.Contains(out, `Gate "approve" rejected`) || strings.Contains(out, "non-zero status") {
+		t.Errorf("expected only a rejection line for the gate:\n%s", out)
+	}
+	if !strings.Contains(feedback, "tighten the error handling") {
+		t.Fatalf("later phase feedback = %q, want the gate's rejection feedback", feedback)
+	}
+	if r.State.GetStatus() != state.StatusCompleted {
+		t.Fatalf("status = %q, want completed", r.State.GetStatus())
+	}
+}
+
+func TestRun_GateOnRejectGoto(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "implement", Type: "script", Run: "true"},
+			{Name: "approve", Type: "gate", OnReject: &config.OnReject{Action: "goto", Goto: "implement"}},
+			{Name: "publish", Type: "script", Run: "true"},
+		},
+	}
+	var calls []string
+	var implementFeedback string
+	gateCalls := 0
+	disp := &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		calls = append(calls, phase.Name)
+		switch phase.Name {
+		case "approve":
+			gateCalls++
+			if gateCalls == 1 {
+				return &dispatch.Result{ExitCode: 1, Output: "add tests", Rejected: true}, nil
+			}
+		case "implement":
+			if fb, _ := state.ReadAllFeedback(env.ArtifactsDir); fb != "" {
+				implementFeedback = fb
+			}
+		}
+		return &dispatch.Result{ExitCode: 0}, nil
+	}}
+	r := newTestRunner(t, cfg, disp)
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"implement", "approve", "implement", "approve", "publish"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	if !strings.Contains(implementFeedback, "add tests") {
+		t.Fatalf("implement feedback = %q, want the gate's rejection feedback", implementFeedback)
 	}
 }
 
-func TestRun_ArchivesOnCompletion(t *testing.T) {
+func TestRun_GateOnRejectGotoForwardSkipsIntermediate(t *testing.T) {
 	cfg := &config.Config{
 		Name: "test",
 		Phases: []config.Phase{
-			{Name: "a", Type: "script", Run: "echo"},
-			{Name: "b", Type: "script", Run: "echo"},
+			{Name: "approve", Type: "gate", OnReject: &config.OnReject{Action: "goto", Goto: "cleanup"}},
+			{Name: "deploy", Type: "script", Run: "true"},
+			{Name: "cleanup", Type: "script", Run: "true"},
 		},
 	}
 	mock := newMock()
+	mock.results["approve"] = &dispatch.Result{ExitCode: 1, Output: "not today", Rejected: true}
 	r := newTestRunner(t, cfg, mock)
-	artDir := r.Env.ArtifactsDir
 
-	err := r.Run(context.Background())
-	if err != nil {
-		t.Fatal(err)
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-
-	// history/ should exist with exactly one entry
-	histDir := filepath.Join(artDir, "history")
-	entries, err := os.ReadDir(histDir)
-	if err != nil {
-		t.Fatalf("reading history dir: %v", err)
+	if strings.Join(mock.calls, ",") != "approve,cleanup" {
+		t.Fatalf("called = %v, want [approve cleanup]", mock.calls)
 	}
-	if len(entries) != 1 {
-		t.Fatalf("expected 1 history entry, got %d", len(entries))
+	if r.skipped["deploy"] == "" {
+		t.Fatal("deploy should be recorded as skipped")
 	}
+}
 
-	runDir := filepath.Join(histDir, entries[0].Name())
-
-	// state.json should exist with status "completed"
-	st, err := state.Load(runDir)
-	if err != nil {
-		t.Fatalf("loading archived state: %v", err)
-	}
-	if st.GetStatus() != state.StatusCompleted {
-		t.Fatalf("archived status = %q, want %q", st.GetStatus(), state.StatusCompleted)
+func TestRun_ConditionTimeoutSkipsPhase(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "probe", Type: "script", Run: "echo", Condition: "sleep 30", ConditionTimeout: 1, OnConditionTimeout: "skip"},
+			{Name: "after", Type: "script", Run: "echo"},
+		},
 	}
+	mock := newMock()
+	r := newTestRunner(t, cfg, mock)
 
-	// timing.json and costs.json should be present
-	if _, err := os.Stat(filepath.Join(runDir, "timing.json")); err != nil {
-		t.Fatalf("timing.json missing from archive: %v", err)
+	start := time.Now()
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	if _, err := os.Stat(filepath.Join(runDir, "costs.json")); err != nil {
-		t.Fatalf("costs.json missing from archive: %v", err)
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("run took %v; the hanging condition should have been killed after 1s", elapsed)
 	}
-
-	// top-level state.json should be gone
-	if _, err := os.Stat(filepath.Join(artDir, "state.json")); !os.IsNotExist(err) {
-		t.Fatal("top-level state.json should have been archived away")
+	if strings.Join(mock.calls, ",") != "after" {
+		t.Fatalf("calls = %v, want only [after]", mock.calls)
+	}
+	if r.skipped["probe"] == "" {
+		t.Fatal("probe should be recorded as skipped")
 	}
 }
 
-func TestRun_ArchivesOnFailure(t *testing.T) {
+func TestRun_ConditionTimeoutFailPolicy(t *testing.T) {
 	cfg := &config.Config{
 		Name: "test",
 		Phases: []config.Phase{
-			{Name: "a", Type: "script", Run: "echo"},
-			{Name: "b", Type: "script", Run: "echo"},
+			{Name: "probe", Type: "script", Run: "echo", Condition: "sleep 30", ConditionTimeout: 1, OnConditionTimeout: "fail"},
 		},
 	}
 	mock := newMock()
-	mock.results["a"] = &dispatch.Result{ExitCode: 1}
 	r := newTestRunner(t, cfg, mock)
-	artDir := r.Env.ArtifactsDir
 
 	err := r.Run(context.Background())
-	if err == nil {
-		t.Fatal("expected failure")
-	}
-
-	// state.json should still exist in the artifacts root (not archived)
-	st, loadErr := state.Load(artDir)
-	if loadErr != nil {
-		t.Fatalf("loading state: %v", loadErr)
-	}
-	if st.GetStatus() != state.StatusFailed {
-		t.Fatalf("status = %q, want %q", st.GetStatus(), state.StatusFailed)
+	assertExitCode(t, err, ExitTimeout)
+	if got := r.State.GetFailureCategory(); got != state.FailCategoryTimeout {
+		t.Fatalf("FailureCategory = %q, want %q", got, state.FailCategoryTimeout)
 	}
-
-	// history/ should NOT exist — failed runs are not archived
-	histDir := filepath.Join(artDir, "history")
-	if _, err := os.Stat(histDir); err == nil {
-		entries, _ := os.ReadDir(histDir)
-		t.Fatalf("expected no history dir after failure, but found %d entries", len(entries))
+	if len(mock.calls) != 0 {
+		t.Fatalf("calls = %v, want none", mock.calls)
 	}
 }
 
-func TestRun_DoesNotArchiveStaleOnStart(t *testing.T) {
+func TestRun_DisabledPhaseSkipped(t *testing.T) {
 	cfg := &config.Config{
 		Name: "test",
 		Phases: []config.Phase{
-			{Name: "a", Type: "script", Run: "echo"},
+			{Name: "a", Type: "script", Run: "echo a"},
+			{Name: "b", Type: "script", Run: "echo b", Disabled: true, Condition: "touch should-not-exist"},
+			{Name: "c", Type: "script", Run: "echo c"},
 		},
 	}
 	mock := newMock()
 	r := newTestRunner(t, cfg, mock)
-	artDir := r.Env.ArtifactsDir
 
-	// Pre-create artifacts dir with a stale state.json
-	if err := os.MkdirAll(artDir, 0755); err != nil {
-		t.Fatal(err)
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	staleState := &state.State{Status: state.StatusRunning, PhaseIndex: 1}
-	if err := staleState.Save(artDir); err != nil {
-		t.Fatal(err)
+	calls := mock.callNames()
+	if len(calls) != 2 || calls[0] != "a" || calls[1] != "c" {
+		t.Fatalf("calls = %v, want [a c]", calls)
+	}
+	// Condition must not be evaluated for a disabled phase.
+	if _, err := os.Stat(filepath.Join(r.Env.WorkDir, "should-not-exist")); err == nil {
+		t.Fatal("condition of disabled phase was evaluated")
+	}
+	if r.State.GetStatus() != state.StatusCompleted {
+		t.Fatalf("status = %q, want %q", r.State.GetStatus(), state.StatusCompleted)
 	}
+}
 
-	err := r.Run(context.Background())
-	if err != nil {
-		t.Fatal(err)
+func TestRun_DisabledParallelPartnerRunsAlone(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "lint", Type: "script", Run: "echo", ParallelWith: "test"},
+			{Name: "test", Type: "script", Run: "echo", Disabled: true},
+		},
 	}
+	mock := newMock()
+	r := newTestRunner(t, cfg, mock)
 
-	// Runner no longer archives stale state (moved to main.go).
-	// history/ should have exactly 1 entry (from successful completion only).
-	histDir := filepath.Join(artDir, "history")
-	entries, err := os.ReadDir(histDir)
-	if err != nil {
-		t.Fatalf("reading history dir: %v", err)
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(entries) != 1 {
-		t.Fatalf("expected 1 history entry (completion only), got %d", len(entries))
+	calls := mock.callNames()
+	if len(calls) != 1 || calls[0] != "lint" {
+		t.Fatalf("calls = %v, want [lint]", calls)
 	}
 }
 
-func TestRun_FailureCategory_AgentError(t *testing.T) {
+func TestRun_ResumeGate_ApprovesAndCompletes(t *testing.T) {
 	cfg := &config.Config{
 		Name: "test",
 		Phases: []config.Phase{
-			{Name: "a", Type: "agent", Prompt: "unused.md", Model: "sonnet"},
+			{Name: "build", Type: "script", Run: "echo"},
+			{Name: "approve", Type: "gate"},
+			{Name: "ship", Type: "script", Run: "echo"},
 		},
 	}
 	mock := newMock()
-	mock.results["a"] = &dispatch.Result{ExitCode: 1}
+	mock.results["approve"] = &dispatch.Result{ExitCode: 1, Output: "no"}
 	r := newTestRunner(t, cfg, mock)
 
 	err := r.Run(context.Background())
-	if err == nil {
-		t.Fatal("expected error")
+	assertExitCode(t, err, ExitPhaseFailure)
+	if r.State.GetPhaseIndex() != 1 {
+		t.Fatalf("phase index = %d, want 1 (stopped at gate)", r.State.GetPhaseIndex())
 	}
-	if got := r.State.GetFailureCategory(); got != state.FailCategoryAgentError {
-		t.Fatalf("FailureCategory = %q, want %q", got, state.FailCategoryAgentError)
+
+	// Re-answer the gate with the same state and approve it this time.
+	mock2 := newMock()
+	r.Dispatcher = mock2
+	r.ResumeGate = true
+	r.State.SetStatus(state.StatusRunning)
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatalf("resume-gate run failed: %v", err)
+	}
+	calls := mock2.callNames()
+	if len(calls) != 2 || calls[0] != "approve" || calls[1] != "ship" {
+		t.Fatalf("calls = %v, want [approve ship]", calls)
+	}
+	if r.State.GetStatus() != state.StatusCompleted {
+		t.Fatalf("status = %q, want %q", r.State.GetStatus(), state.StatusCompleted)
+	}
+	if got := r.State.GetFailureCategory(); got != "" {
+		t.Fatalf("FailureCategory = %q, want empty after approval", got)
 	}
 }
 
-func TestRun_FailureCategory_GateRejection(t *testing.T) {
+func TestRun_ResumeGate_NotAtGate(t *testing.T) {
 	cfg := &config.Config{
 		Name: "test",
 		Phases: []config.Phase{
+			{Name: "build", Type: "script", Run: "echo"},
 			{Name: "approve", Type: "gate"},
 		},
 	}
 	mock := newMock()
-	mock.results["approve"] = &dispatch.Result{ExitCode: 1, Output: "no"}
 	r := newTestRunner(t, cfg, mock)
+	r.ResumeGate = true
 
 	err := r.Run(context.Background())
-	if err == nil {
-		t.Fatal("expected error for gate denial")
-	}
-	assertExitCode(t, err, ExitPhaseFailure)
-	if got := r.State.GetFailureCategory(); got != state.FailCategoryGateRejection {
-		t.Fatalf("FailureCategory = %q, want %q", got, state.FailCategoryGateRejection)
+	assertExitCode(t, err, ExitResumeFailure)
+	if n := mock.callCount(); n != 0 {
+		t.Fatalf("expected no dispatches, got %d", n)
 	}
 }
 
@@ -4376,6 +6104,57 @@ func TestRun_RunResultPhasesWithSkip(t *testing.T) {
 	}
 }
 
+func TestRun_SkipReasonsRecorded(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "lint", Type: "script", Run: "echo", Disabled: true},
+			{Name: "docs", Type: "script", Run: "echo", Condition: "false"},
+			{Name: "approve", Type: "gate", OnReject: &config.OnReject{Action: "goto", Goto: "cleanup"}},
+			{Name: "deploy", Type: "script", Run: "echo"},
+			{Name: "cleanup", Type: "script", Run: "echo"},
+		},
+	}
+	mock := newMock()
+	mock.results["approve"] = &dispatch.Result{ExitCode: 1, Output: "not today", Rejected: true}
+	r := newTestRunner(t, cfg, mock)
+	var events bytes.Buffer
+	r.Env.Events = dispatch.NewEventSink(&events)
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"lint":   "disabled",
+		"docs":   "condition not met",
+		"deploy": "jumped over by approve's on-reject goto",
+	}
+	result, err := state.LoadRunResult(r.Env.ArtifactsDir)
+	if err != nil {
+		t.Fatalf("loading run-result.json: %v", err)
+	}
+	for _, p := range result.Phases {
+		if p.SkipReason != want[p.Name] {
+			t.Errorf("phase %s skip_reason = %q, want %q", p.Name, p.SkipReason, want[p.Name])
+		}
+	}
+
+	got := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(events.String()), "\n") {
+		var ev dispatch.LogEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("invalid event line %q: %v", line, err)
+		}
+		if ev.Type == "phase_skip" {
+			got[ev.Phase] = ev.Reason
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("phase_skip events = %v, want %v", got, want)
+	}
+}
+
 func TestRun_RunResultPhasesLoopReEntryOverridesSkip(t *testing.T) {
 	markerFile := filepath.Join(t.TempDir(), "marker")
 
@@ -4837,3 +6616,190 @@ func TestWaitForRateLimit_CtxCancelReturnsPromptly(t *testing.T) {
 		t.Fatalf("waitForRateLimit took %v after cancel, want < 2s", elapsed)
 	}
 }
+
+func TestRun_EventsStreamedToPipe(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "build", Type: "script", Run: "echo"},
+			{Name: "test", Type: "script", Run: "echo"},
+		},
+	}
+	mock := newMock()
+	mock.results["test"] = &dispatch.Result{ExitCode: 1}
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.Close()
+
+	var lines []string
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		data, _ := io.ReadAll(pr)
+		lines = strings.Split(strings.TrimSpace(string(data)), "\n")
+	}()
+
+	r := newTestRunner(t, cfg, mock)
+	r.Env.Events = dispatch.NewEventSink(pw)
+	runErr := r.Run(context.Background())
+	pw.Close()
+	<-done
+	assertExitCode(t, runErr, ExitPhaseFailure)
+
+	want := []dispatch.LogEvent{
+		{Type: "phase_start", Phase: "build", Index: 1},
+		{Type: "phase_end", Phase: "build", Index: 1, Status: "ok"},
+		{Type: "phase_start", Phase: "test", Index: 2},
+		{Type: "phase_end", Phase: "test", Index: 2, Status: "failed", ExitCode: 1},
+		{Type: "run_end", Status: state.StatusFailed, ExitCode: ExitPhaseFailure},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d events, want %d:\n%s", len(lines), len(want), strings.Join(lines, "\n"))
+	}
+	for i, line := range lines {
+		var ev dispatch.LogEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			t.Fatalf("invalid event line %q: %v", line, err)
+		}
+		ev.DurationSecs = 0
+		if ev != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, ev, want[i])
+		}
+	}
+}
+
+func TestRun_LabelsSelectPhases(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "plan", Type: "script", Run: "echo", Labels: []string{"design"}},
+			{Name: "build", Type: "script", Run: "echo", Labels: []string{"code", "slow"}},
+			{Name: "lint", Type: "script", Run: "echo", Labels: []string{"check"}, ParallelWith: "test"},
+			{Name: "test", Type: "script", Run: "echo", Labels: []string{"slow"}},
+			{Name: "docs", Type: "script", Run: "echo"},
+		},
+	}
+	mock := newMock()
+	r := newTestRunner(t, cfg, mock)
+	r.Labels = []string{"slow"}
+
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// build carries "slow" among several labels; lint runs as test's parallel partner.
+	got := append([]string(nil), mock.calls...)
+	sort.Strings(got)
+	want := []string{"build", "lint", "test"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("dispatched = %v, want %v", got, want)
+	}
+	result, err := state.LoadRunResult(r.Env.ArtifactsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range result.Phases {
+		skipped := p.Name == "plan" || p.Name == "docs"
+		if skipped && p.SkipReason != "not selected by --labels" {
+			t.Errorf("phase %s skip_reason = %q, want label skip", p.Name, p.SkipReason)
+		}
+		if !skipped && p.SkipReason != "" {
+			t.Errorf("phase %s skip_reason = %q, want none", p.Name, p.SkipReason)
+		}
+	}
+}
+
+func TestRun_LabelsMatchingNoPhase(t *testing.T) {
+	cfg := &config.Config{
+		Name:   "test",
+		Phases: []config.Phase{{Name: "build", Type: "script", Run: "echo", Labels: []string{"code"}}},
+	}
+	mock := newMock()
+	r := newTestRunner(t, cfg, mock)
+	r.Labels = []string{"nope"}
+
+	err := r.Run(context.Background())
+	assertExitCode(t, err, ExitConfigError)
+	if len(mock.calls) != 0 {
+		t.Fatalf("dispatched %v, want nothing", mock.calls)
+	}
+}
+
+func TestDryRunPrint_EvalConditions(t *testing.T) {
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "setup", Type: "script", Run: "touch ran"},
+			{Name: "docs", Type: "script", Run: "touch ran", Condition: "false"},
+			{Name: "deploy", Type: "script", Run: "touch ran", Condition: "true"},
+			{Name: "lint", Type: "script", Run: "touch ran", Condition: "true", Disabled: true},
+		},
+	}
+	mock := newMock()
+	r := newTestRunner(t, cfg, mock)
+	r.EvalConditions = true
+
+	want := []ux.ConditionPreview{
+		{Index: 0, Name: "setup", Run: true},
+		{Index: 1, Name: "docs", Condition: "false", Run: false},
+		{Index: 2, Name: "deploy", Condition: "true", Run: true},
+		{Index: 3, Name: "lint", Condition: "true", Disabled: true, Run: true},
+	}
+	if got := r.conditionPlan(context.Background()); !reflect.DeepEqual(got, want) {
+		t.Fatalf("conditionPlan = %+v, want %+v", got, want)
+	}
+
+	oldStdout := os.Stdout
+	pr, pw, _ := os.Pipe()
+	os.Stdout = pw
+
+	r.DryRunPrint(context.Background())
+
+	pw.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, pr)
+	output := buf.String()
+
+	if !regexp.MustCompile(`docs\s+\S*would skip`).MatchString(output) {
+		t.Errorf("expected docs marked would skip, got:\n%s", output)
+	}
+	if !regexp.MustCompile(`deploy\s+\S*would run`).MatchString(output) {
+		t.Errorf("expected deploy marked would run, got:\n%s", output)
+	}
+	if len(mock.calls) != 0 {
+		t.Errorf("dry run dispatched phases: %v", mock.calls)
+	}
+	if _, err := os.Stat(filepath.Join(r.Env.ProjectRoot, "ran")); err == nil {
+		t.Error("dry run executed a phase command")
+	}
+}
+
+func TestRun_DeliverablesOnlyForPublishPhase(t *testing.T) {
+	cfg := &config.Config{
+		Name:         "test",
+		Deliverables: []string{"report.md"},
+		Phases: []config.Phase{
+			{Name: "build", Type: "script", Run: "true"},
+			{Name: "upload", Type: "publish", Run: "true"},
+		},
+	}
+	seen := map[string][]string{}
+	r := newTestRunner(t, cfg, &funcDispatcher{fn: func(ctx context.Context, phase config.Phase, env *dispatch.Environment) (*dispatch.Result, error) {
+		seen[phase.Name] = env.Deliverables
+		return &dispatch.Result{ExitCode: 0}, nil
+	}})
+	if err := r.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if seen["build"] != nil {
+		t.Errorf("script phase got deliverables %v", seen["build"])
+	}
+	if len(seen["upload"]) != 1 || seen["upload"][0] != "report.md" {
+		t.Errorf("publish phase deliverables = %v, want [report.md]", seen["upload"])
+	}
+}
@@ -0,0 +1,99 @@
+package scaffold
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/jorge-barreto/orc/internal/contextgather"
+	"github.com/jorge-barreto/orc/internal/ux"
+)
+
+// initAnswers holds the project details confirmed by the user during
+// 'orc init --interactive'. Empty fields mean "unknown — let the model decide".
+type initAnswers struct {
+	ProjectType   string
+	TestCommand   string
+	TicketPattern string
+}
+
+// ticketRefRe finds ticket-like references (PROJ-123) in commit messages.
+var ticketRefRe = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-\d+\b`)
+
+// detectInitDefaults guesses the project type, test command, and ticket
+// pattern from the gathered context. These are offered as defaults.
+func detectInitDefaults(pc *contextgather.ProjectContext) initAnswers {
+	var a initAnswers
+	has := func(name string) bool { _, ok := pc.Files[name]; return ok }
+	switch {
+	case has("go.mod"):
+		a.ProjectType, a.TestCommand = "Go", "go test ./..."
+	case has("Cargo.toml"):
+		a.ProjectType, a.TestCommand = "Rust", "cargo test"
+	case has("package.json"):
+		a.ProjectType, a.TestCommand = "Node.js", "npm test"
+	case has("pyproject.toml"), has("setup.py"), has("requirements.txt"):
+		a.ProjectType, a.TestCommand = "Python", "pytest"
+	}
+	for _, mk := range []string{"Makefile", "makefile"} {
+		if strings.Contains(pc.Files[mk], "\ntest:") || strings.HasPrefix(pc.Files[mk], "test:") {
+			a.TestCommand = "make test"
+		}
+	}
+	if ticketRefRe.MatchString(pc.GitLog) {
+		a.TicketPattern = `[A-Z]+-\d+`
+	}
+	return a
+}
+
+// askInitQuestions asks the user to confirm or override each detected
+// value. An empty answer keeps the default; "-" clears it. On EOF the
+// remaining defaults are kept.
+func askInitQuestions(in io.Reader, out io.Writer, defaults initAnswers) initAnswers {
+	reader := bufio.NewReader(in)
+	ask := func(question, def string) string {
+		if def != "" {
+			fmt.Fprintf(out, "  %s %s[%s]%s: ", question, ux.Dim, def, ux.Reset)
+		} else {
+			fmt.Fprintf(out, "  %s %s[none]%s: ", question, ux.Dim, ux.Reset)
+		}
+		line, err := reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if err != nil && answer == "" {
+			fmt.Fprintln(out)
+			return def
+		}
+		switch answer {
+		case "":
+			return def
+		case "-":
+			return ""
+		}
+		return answer
+	}
+
+	fmt.Fprintf(out, "\n  %sConfirm project details (Enter keeps the default, - clears it)%s\n", ux.Bold, ux.Reset)
+	return initAnswers{
+		ProjectType:   ask("Project type", defaults.ProjectType),
+		TestCommand:   ask("Test command", defaults.TestCommand),
+		TicketPattern: ask("Ticket pattern (regex)", defaults.TicketPattern),
+	}
+}
+
+// render formats the confirmed answers as a prompt section.
+func (a initAnswers) render() string {
+	var b strings.Builder
+	b.WriteString("\n\n## Confirmed Project Details\n\nThe user confirmed these details. They override anything you would infer from the project context:\n\n")
+	line := func(label, value, unset string) {
+		if value == "" {
+			value = unset
+		}
+		fmt.Fprintf(&b, "- %s: %s\n", label, value)
+	}
+	line("Project type", a.ProjectType, "not specified — infer it from the project context")
+	line("Test command", a.TestCommand, "none — do not add a test phase unless the project context shows one")
+	line("Ticket pattern", a.TicketPattern, "none — omit ticket-pattern")
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package scaffold
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jorge-barreto/orc/internal/contextgather"
+)
+
+func TestDetectInitDefaults(t *testing.T) {
+	pc := &contextgather.ProjectContext{
+		Files:  map[string]string{"go.mod": "module x\n", "Makefile": "build:\n\tgo build\ntest:\n\tgo test ./...\n"},
+		GitLog: "abc123 PROJ-42 fix parser\n",
+	}
+	got := detectInitDefaults(pc)
+	want := initAnswers{ProjectType: "Go", TestCommand: "make test", TicketPattern: `[A-Z]+-\d+`}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	if got := detectInitDefaults(&contextgather.ProjectContext{Files: map[string]string{}}); got != (initAnswers{}) {
+		t.Fatalf("expected no defaults for an empty project, got %+v", got)
+	}
+}
+
+func TestInit_InteractiveAnswersReachPrompt(t *testing.T) {
+	captured := stubClaudeCapture(t)
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module x\n"), 0644)
+
+	// Keep the detected project type, override the test command, clear the
+	// ticket pattern.
+	answers := strings.NewReader("\ngo test -race ./...\n-\n")
+	if err := initWithAI(context.Background(), dir, "", answers, contextgather.Options{}); err != nil {
+		t.Fatalf("initWithAI failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"## Confirmed Project Details",
+		"- Project type: Go",
+		"- Test command: go test -race ./...",
+		"- Ticket pattern: none",
+	} {
+		if !strings.Contains(*captured, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+}
+
+func TestInit_NonInteractiveNoAnswersSection(t *testing.T) {
+	captured := stubClaudeCapture(t)
+	if err := Init(context.Background(), t.TempDir(), "", InitOptions{}); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if strings.Contains(*captured, "Confirmed Project Details") {
+		t.Fatal("answers section should only appear with --interactive")
+	}
+}
+
+func TestAskInitQuestions_EOFKeepsDefaults(t *testing.T) {
+	defaults := initAnswers{ProjectType: "Rust", TestCommand: "cargo test"}
+	var out strings.Builder
+	got := askInitQuestions(strings.NewReader("Rust workspace"), &out, defaults)
+	if got.ProjectType != "Rust workspace" || got.TestCommand != "cargo test" || got.TicketPattern != "" {
+		t.Fatalf("got %+v", got)
+	}
+	if !strings.Contains(out.String(), "Test command") {
+		t.Fatalf("expected questions to be printed, got %q", out.String())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -17,14 +18,28 @@ import (
 	"github.com/jorge-barreto/orc/internal/ux"
 )
 
+// InitOptions tunes AI-powered init.
+type InitOptions struct {
+	// Interactive has the user confirm the detected project type, test
+	// command, and ticket pattern on the terminal before generation.
+	Interactive bool
+	// Gather adds directories to skip and files to include when gathering
+	// project context.
+	Gather contextgather.Options
+}
+
 // Init creates a new .orc/ directory with AI-generated workflow config and prompt files.
-func Init(ctx context.Context, targetDir, userPrompt string) error {
+func Init(ctx context.Context, targetDir, userPrompt string, opts InitOptions) error {
 	orcDir := filepath.Join(targetDir, ".orc")
 	if _, err := os.Stat(orcDir); err == nil {
 		return fmt.Errorf(".orc directory already exists in %s", targetDir)
 	}
 
-	return initWithAI(ctx, targetDir, userPrompt)
+	var in io.Reader
+	if opts.Interactive {
+		in = os.Stdin
+	}
+	return initWithAI(ctx, targetDir, userPrompt, in, opts.Gather)
 }
 
 // InitRecipe scaffolds a .orc/ directory from a named built-in recipe.
@@ -172,17 +187,16 @@ func ListRecipes() {
 }
 
 // initWithAI gathers project context, calls claude with retries, and writes AI-generated files.
-// Falls back to a default template if all attempts fail.
-func initWithAI(ctx context.Context, targetDir, userPrompt string) error {
+// Falls back to a default template if all attempts fail. A non-nil in makes
+// the init interactive: the user's answers read from it are added to the prompt.
+func initWithAI(ctx context.Context, targetDir, userPrompt string, in io.Reader, gatherOpts contextgather.Options) error {
 	fmt.Printf("\n  %sAnalyzing project...%s\n", ux.Dim, ux.Reset)
 
-	pc, err := contextgather.Gather(targetDir)
+	_, prompt, err := composeInitPrompt(targetDir, userPrompt, gatherOpts, in, os.Stdout)
 	if err != nil {
-		return fmt.Errorf("gathering context: %w", err)
+		return err
 	}
 
-	prompt := buildInitPrompt(pc.Render(), userPrompt)
-
 	const maxAttempts = 3
 	var blocks []fileblocks.FileBlock
 	var lastErr error
@@ -237,6 +251,43 @@ func initWithAI(ctx context.Context, targetDir, userPrompt string) error {
 	return nil
 }
 
+// composeInitPrompt gathers project context for targetDir and builds the
+// init prompt from it. A non-nil in asks the --interactive questions,
+// writing them to out. Returns the rendered context and the full prompt.
+func composeInitPrompt(targetDir, userPrompt string, gatherOpts contextgather.Options, in io.Reader, out io.Writer) (projectContext, prompt string, err error) {
+	pc, err := contextgather.GatherWith(targetDir, gatherOpts)
+	if err != nil {
+		return "", "", fmt.Errorf("gathering context: %w", err)
+	}
+
+	var answers *initAnswers
+	if in != nil {
+		a := askInitQuestions(in, out, detectInitDefaults(pc))
+		answers = &a
+	}
+
+	projectContext = pc.Render()
+	return projectContext, buildInitPrompt(projectContext, userPrompt, answers), nil
+}
+
+// DumpInitContext writes the project context init gathers for targetDir and
+// the full prompt it would send to claude, without calling claude or writing
+// any files. With opts.Interactive set, the questions go to stderr so w only
+// receives the dump.
+func DumpInitContext(w io.Writer, targetDir, userPrompt string, opts InitOptions) error {
+	var in io.Reader
+	if opts.Interactive {
+		in = os.Stdin
+	}
+	projectContext, prompt, err := composeInitPrompt(targetDir, userPrompt, opts.Gather, in, os.Stderr)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "===== Gathered project context (%d bytes) =====\n\n%s\n", len(projectContext), projectContext)
+	fmt.Fprintf(w, "===== Init prompt sent to claude (%d bytes) =====\n\n%s\n", len(prompt), prompt)
+	return nil
+}
+
 // generateConfig calls claude, parses the output, and validates the generated config
 // in a temp directory. Returns the validated file blocks or an error.
 func generateConfig(ctx context.Context, prompt string) ([]fileblocks.FileBlock, error) {
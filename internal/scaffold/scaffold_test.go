@@ -62,7 +62,7 @@ func captureOutput(fn func()) string {
 func TestInit_CreatesDirectoryStructure(t *testing.T) {
 	stubClaude(t)
 	dir := t.TempDir()
-	if err := Init(context.Background(), dir, ""); err != nil {
+	if err := Init(context.Background(), dir, "", InitOptions{}); err != nil {
 		t.Fatalf("Init failed: %v", err)
 	}
 
@@ -95,7 +95,7 @@ func TestInit_CreatesDirectoryStructure(t *testing.T) {
 func TestInit_GeneratedConfigIsValid(t *testing.T) {
 	stubClaude(t)
 	dir := t.TempDir()
-	if err := Init(context.Background(), dir, ""); err != nil {
+	if err := Init(context.Background(), dir, "", InitOptions{}); err != nil {
 		t.Fatalf("Init failed: %v", err)
 	}
 
@@ -117,7 +117,7 @@ func TestInit_FailsIfDirExists(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err := Init(context.Background(), dir, "")
+	err := Init(context.Background(), dir, "", InitOptions{})
 	if err == nil {
 		t.Fatal("expected error when .orc already exists")
 	}
@@ -132,7 +132,7 @@ func TestInit_FallbackWhenClaudeUnavailable(t *testing.T) {
 	// Clear PATH so claude binary cannot be found — should fall back to default template.
 	t.Setenv("PATH", "")
 
-	err := Init(context.Background(), dir, "")
+	err := Init(context.Background(), dir, "", InitOptions{})
 	if err != nil {
 		t.Fatalf("Init should succeed via fallback, got: %v", err)
 	}
@@ -152,7 +152,7 @@ func TestInit_UserPromptPassedToClaude(t *testing.T) {
 	captured := stubClaudeCapture(t)
 	dir := t.TempDir()
 
-	if err := Init(context.Background(), dir, "documentation drafting with critique loop"); err != nil {
+	if err := Init(context.Background(), dir, "documentation drafting with critique loop", InitOptions{}); err != nil {
 		t.Fatalf("Init failed: %v", err)
 	}
 
@@ -168,7 +168,7 @@ func TestInit_NoUserPromptNoSection(t *testing.T) {
 	captured := stubClaudeCapture(t)
 	dir := t.TempDir()
 
-	if err := Init(context.Background(), dir, ""); err != nil {
+	if err := Init(context.Background(), dir, "", InitOptions{}); err != nil {
 		t.Fatalf("Init failed: %v", err)
 	}
 
@@ -506,3 +506,40 @@ func TestInitWorkflow_NoRecipeNoPromptFiles(t *testing.T) {
 		t.Fatal(".orc/phases should not exist when no recipe is used")
 	}
 }
+
+func TestDumpInitContext(t *testing.T) {
+	orig := runClaude
+	t.Cleanup(func() { runClaude = orig })
+	runClaude = func(_ context.Context, _ string) ([]fileblocks.FileBlock, error) {
+		t.Fatal("claude must not be called when dumping context")
+		return nil, nil
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Makefile"), []byte("test:\n\tgo test ./... -run Sentinel42\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := DumpInitContext(&buf, dir, "nightly release workflow", InitOptions{}); err != nil {
+		t.Fatalf("DumpInitContext: %v", err)
+	}
+	out := buf.String()
+
+	ctxStart := strings.Index(out, "Gathered project context")
+	promptStart := strings.Index(out, "Init prompt sent to claude")
+	if ctxStart < 0 || promptStart < ctxStart {
+		t.Fatalf("expected context section followed by prompt section, got:\n%s", out)
+	}
+	if !strings.Contains(out[ctxStart:promptStart], "Sentinel42") {
+		t.Error("gathered context should include the Makefile's content")
+	}
+	prompt := out[promptStart:]
+	for _, want := range []string{"Sentinel42", "orc Config Schema Reference", "nightly release workflow"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("composed prompt missing %q", want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".orc")); !os.IsNotExist(err) {
+		t.Error("dumping context must not create .orc/")
+	}
+}
@@ -7,10 +7,23 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
+// FilePrefix is prepended to the per-phase log, prompt, metadata, and
+// feedback file names and to the run's own files (state.json, timing.json,
+// costs.json, loop-counts.json, run-result.json, ORC-README.md,
+// config.snapshot.yaml), so several workflows can share one artifacts
+// directory without clobbering each other; ArchiveRun moves only the
+// current prefix's files. The CLI sets it from
+// artifacts-prefix / --phase-prefix before any command runs; empty by default.
+var FilePrefix string
+
 // EnsureDir creates the artifacts directory structure.
 func EnsureDir(artifactsDir string) error {
 	dirs := []string{
@@ -27,9 +40,27 @@ func EnsureDir(artifactsDir string) error {
 	return nil
 }
 
+// EnsureGitignore writes a .gitignore containing "*" to artifactsDir so
+// nothing inside is tracked by git, wherever the directory lives. It is a
+// no-op if the file already ignores everything.
+func EnsureGitignore(artifactsDir string) error {
+	path := filepath.Join(artifactsDir, ".gitignore")
+	if data, err := os.ReadFile(path); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.TrimSpace(line) == "*" {
+				return nil
+			}
+		}
+	}
+	if err := os.WriteFile(path, []byte("*\n"), 0644); err != nil {
+		return fmt.Errorf("writing artifacts .gitignore: %w", err)
+	}
+	return nil
+}
+
 // LoadLoopCounts reads the loop count map from artifacts.
 func LoadLoopCounts(artifactsDir string) (map[string]int, error) {
-	path := filepath.Join(artifactsDir, "loop-counts.json")
+	path := filepath.Join(artifactsDir, FilePrefix+"loop-counts.json")
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
@@ -46,11 +77,11 @@ func LoadLoopCounts(artifactsDir string) (map[string]int, error) {
 
 // SaveLoopCounts writes the loop count map to artifacts.
 func SaveLoopCounts(artifactsDir string, counts map[string]int) error {
-	data, err := json.MarshalIndent(counts, "", "  ")
+	data, err := marshalArtifact(counts)
 	if err != nil {
 		return err
 	}
-	return WriteFileAtomic(filepath.Join(artifactsDir, "loop-counts.json"), data, 0644)
+	return WriteFileAtomic(filepath.Join(artifactsDir, FilePrefix+"loop-counts.json"), data, 0644)
 }
 
 // LoadAttemptCounts reads the attempt count map from the audit directory.
@@ -85,7 +116,7 @@ func SaveAttemptCounts(auditDir string, counts map[int]int) error {
 	for k, v := range counts {
 		raw[strconv.Itoa(k)] = v
 	}
-	data, err := json.MarshalIndent(raw, "", "  ")
+	data, err := marshalArtifact(raw)
 	if err != nil {
 		return err
 	}
@@ -98,8 +129,67 @@ func WriteFeedback(artifactsDir, fromPhase, content string) error {
 	if err := os.MkdirAll(feedbackDir, 0755); err != nil {
 		return fmt.Errorf("creating feedback dir: %w", err)
 	}
-	path := filepath.Join(feedbackDir, fmt.Sprintf("from-%s.md", fromPhase))
-	return WriteFileAtomic(path, []byte(content), 0644)
+	return WriteFileAtomic(FeedbackPath(artifactsDir, fromPhase), []byte(content), 0644)
+}
+
+// FeedbackPath returns the path of the feedback file written by fromPhase.
+func FeedbackPath(artifactsDir, fromPhase string) string {
+	return filepath.Join(artifactsDir, "feedback", fmt.Sprintf("%sfrom-%s.md", FilePrefix, fromPhase))
+}
+
+// FeedbackSource returns the phase (or "first+second" parallel group) that
+// wrote the feedback file name, and false if name is not a feedback file of
+// this run — including one written under a different FilePrefix.
+func FeedbackSource(name string) (string, bool) {
+	rest, ok := strings.CutPrefix(name, FilePrefix+"from-")
+	if !ok || !strings.HasSuffix(rest, ".md") {
+		return "", false
+	}
+	return strings.TrimSuffix(rest, ".md"), true
+}
+
+// ReadFeedback returns the feedback file written by fromPhase, or "" if
+// there is none.
+func ReadFeedback(artifactsDir, fromPhase string) string {
+	data, err := os.ReadFile(FeedbackPath(artifactsDir, fromPhase))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// feedbackAttemptMarker starts each attempt section of a feedback file kept
+// with history (see FeedbackWithHistory).
+const feedbackAttemptMarker = "<!-- orc:attempt -->\n"
+
+// FeedbackWithHistory appends content, the failure output of the given
+// attempt, to previous (the phase's prior feedback) and keeps only the last
+// keep attempts, oldest first. Each attempt is headed "## Attempt N" so an
+// agent can see what was already tried. Previous feedback written without
+// history is kept as a single earlier attempt.
+func FeedbackWithHistory(previous, content string, attempt, keep int) string {
+	var sections []string
+	if prev := strings.TrimSpace(previous); prev != "" {
+		if !strings.HasPrefix(prev, feedbackAttemptMarker) {
+			prev = feedbackAttemptMarker + "## Earlier attempt\n\n" + prev
+		}
+		for _, s := range strings.Split(prev, feedbackAttemptMarker) {
+			if s = strings.TrimSpace(s); s != "" {
+				sections = append(sections, s)
+			}
+		}
+	}
+	sections = append(sections, fmt.Sprintf("## Attempt %d\n\n%s", attempt, strings.TrimSpace(content)))
+	if keep > 0 && len(sections) > keep {
+		sections = sections[len(sections)-keep:]
+	}
+	var b strings.Builder
+	for _, s := range sections {
+		b.WriteString(feedbackAttemptMarker)
+		b.WriteString(s)
+		b.WriteString("\n\n")
+	}
+	return b.String()
 }
 
 // ReadAllFeedback reads all feedback files and returns them as a formatted string.
@@ -116,7 +206,8 @@ func ReadAllFeedback(artifactsDir string) (string, error) {
 
 	var parts []string
 	for _, e := range entries {
-		if e.IsDir() {
+		name, ok := FeedbackSource(e.Name())
+		if e.IsDir() || !ok {
 			continue
 		}
 		data, err := os.ReadFile(filepath.Join(feedbackDir, e.Name()))
@@ -127,10 +218,6 @@ func ReadAllFeedback(artifactsDir string) (string, error) {
 		if content == "" {
 			continue
 		}
-		// Extract phase name from filename "from-<phase>.md"
-		name := e.Name()
-		name = strings.TrimPrefix(name, "from-")
-		name = strings.TrimSuffix(name, ".md")
 		parts = append(parts, fmt.Sprintf("--- Feedback from %s ---\n%s", name, content))
 	}
 	if len(parts) == 0 {
@@ -139,8 +226,94 @@ func ReadAllFeedback(artifactsDir string) (string, error) {
 	return strings.Join(parts, "\n\n"), nil
 }
 
-// ClearFeedback removes all files from the feedback directory.
-// Returns nil if the directory does not exist.
+// ReadArchivedFeedback reads the feedback files archived to auditDir (see
+// AuditFeedbackPath) and returns them formatted like ReadAllFeedback, oldest
+// phase and attempt first, each labeled with where it was archived. Returns
+// empty string if none exist.
+func ReadArchivedFeedback(auditDir string) (string, error) {
+	feedbackDir := filepath.Join(auditDir, "feedback")
+	entries, err := os.ReadDir(feedbackDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	type archived struct {
+		phase, iter int
+		from, name  string
+	}
+	var files []archived
+	for _, e := range entries {
+		var a archived
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".md") {
+			continue
+		}
+		prefix, from, ok := strings.Cut(e.Name(), ".from-")
+		if !ok {
+			continue
+		}
+		if _, err := fmt.Sscanf(prefix, "phase-%d.iter-%d", &a.phase, &a.iter); err != nil {
+			continue
+		}
+		a.from, a.name = strings.TrimSuffix(from, ".md"), e.Name()
+		files = append(files, a)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].phase != files[j].phase {
+			return files[i].phase < files[j].phase
+		}
+		return files[i].iter < files[j].iter
+	})
+
+	var parts []string
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Join(feedbackDir, f.name))
+		if err != nil {
+			return "", err
+		}
+		content := strings.TrimSpace(string(data))
+		if content == "" {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("--- Feedback from %s (archived at phase %d, attempt %d) ---\n%s", f.from, f.phase, f.iter, content))
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// FeedbackSources returns the names of the phases (or parallel groups, as
+// "first+second") that left non-empty feedback, sorted by name. Returns nil
+// if no feedback exists.
+func FeedbackSources(artifactsDir string) ([]string, error) {
+	feedbackDir := filepath.Join(artifactsDir, "feedback")
+	entries, err := os.ReadDir(feedbackDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		source, ok := FeedbackSource(e.Name())
+		if e.IsDir() || !ok {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(feedbackDir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(string(data)) == "" {
+			continue
+		}
+		names = append(names, source)
+	}
+	return names, nil
+}
+
+// ClearFeedback removes this run's feedback files (see FeedbackSource) from
+// the feedback directory. Returns nil if the directory does not exist.
 func ClearFeedback(artifactsDir string) error {
 	feedbackDir := filepath.Join(artifactsDir, "feedback")
 	entries, err := os.ReadDir(feedbackDir)
@@ -151,7 +324,7 @@ func ClearFeedback(artifactsDir string) error {
 		return err
 	}
 	for _, e := range entries {
-		if e.IsDir() {
+		if _, ok := FeedbackSource(e.Name()); e.IsDir() || !ok {
 			continue
 		}
 		if err := os.Remove(filepath.Join(feedbackDir, e.Name())); err != nil {
@@ -173,6 +346,90 @@ func CheckOutputs(artifactsDir string, outputs []string) []string {
 	return missing
 }
 
+// ValidateOutputs checks the content of declared outputs that carry a
+// content type (path → "json", "yaml", or "markdown") and returns one
+// message per malformed file, in path order. An empty file is never valid.
+// Missing files are skipped; see CheckOutputs.
+func ValidateOutputs(artifactsDir string, types map[string]string) []string {
+	paths := make([]string, 0, len(types))
+	for p := range types {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	var problems []string
+	for _, p := range paths {
+		data, err := os.ReadFile(filepath.Join(artifactsDir, p))
+		if err != nil {
+			continue
+		}
+		if msg := invalidContent(data, types[p]); msg != "" {
+			problems = append(problems, fmt.Sprintf("output %q is not valid %s: %s", p, contentTypeName(types[p]), msg))
+		}
+	}
+	return problems
+}
+
+// invalidContent describes why data isn't valid content of the given type,
+// or returns "" if it is.
+func invalidContent(data []byte, kind string) string {
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return "file is empty"
+	}
+	switch kind {
+	case "json":
+		var v any
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err.Error()
+		}
+	case "yaml":
+		var v any
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return err.Error()
+		}
+	case "markdown":
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "#") {
+				return ""
+			}
+		}
+		return "no headings found"
+	}
+	return ""
+}
+
+func contentTypeName(kind string) string {
+	switch kind {
+	case "json":
+		return "JSON"
+	case "yaml":
+		return "YAML"
+	}
+	return kind
+}
+
+// mtimeResolution is the coarsest modification-time granularity StaleOutputs
+// allows for: FAT records mtimes in 2s steps, and ext3 and HFS+ in whole
+// seconds, so a file written just after a phase starts can carry an mtime
+// slightly before it.
+const mtimeResolution = 2 * time.Second
+
+// StaleOutputs returns the declared outputs that exist but were last modified
+// before since — left over from an earlier run rather than produced by the
+// current dispatch. since is first truncated to mtimeResolution, so a file
+// is only reported when it is clearly older than the dispatch. Missing
+// outputs are not reported; see CheckOutputs.
+func StaleOutputs(artifactsDir string, outputs []string, since time.Time) []string {
+	since = since.Truncate(mtimeResolution)
+	var stale []string
+	for _, o := range outputs {
+		info, err := os.Stat(filepath.Join(artifactsDir, o))
+		if err == nil && info.ModTime().Before(since) {
+			stale = append(stale, o)
+		}
+	}
+	return stale
+}
+
 // ReadDeclaredOutputs reads and concatenates the content of declared output artifact files.
 // Missing or unreadable files are silently skipped. Returns empty string if no content found.
 func ReadDeclaredOutputs(artifactsDir string, outputs []string) string {
@@ -192,22 +449,33 @@ func ReadDeclaredOutputs(artifactsDir string, outputs []string) string {
 
 // PromptPath returns the path for a rendered prompt file.
 func PromptPath(artifactsDir string, idx int) string {
-	return filepath.Join(artifactsDir, "prompts", fmt.Sprintf("phase-%d.md", idx+1))
+	return filepath.Join(artifactsDir, "prompts", fmt.Sprintf("%sphase-%d.md", FilePrefix, idx+1))
+}
+
+// AttemptPromptPath returns the path for the rendered prompt of a specific
+// attempt of a phase (attempt is 1-indexed).
+func AttemptPromptPath(artifactsDir string, idx, attempt int) string {
+	return filepath.Join(artifactsDir, "prompts", fmt.Sprintf("%sphase-%d-attempt-%d.md", FilePrefix, idx+1, attempt))
 }
 
 // LogPath returns the path for a phase log file.
 func LogPath(artifactsDir string, idx int) string {
-	return filepath.Join(artifactsDir, "logs", fmt.Sprintf("phase-%d.log", idx+1))
+	return filepath.Join(artifactsDir, "logs", fmt.Sprintf("%sphase-%d.log", FilePrefix, idx+1))
 }
 
 // StreamLogPath returns the path for a raw stream-json log file.
 func StreamLogPath(artifactsDir string, idx int) string {
-	return filepath.Join(artifactsDir, "logs", fmt.Sprintf("phase-%d.stream.jsonl", idx+1))
+	return filepath.Join(artifactsDir, "logs", fmt.Sprintf("%sphase-%d.stream.jsonl", FilePrefix, idx+1))
+}
+
+// EventLogPath returns the path for a normalized stream event log file.
+func EventLogPath(artifactsDir string, idx int) string {
+	return filepath.Join(artifactsDir, "logs", fmt.Sprintf("%sphase-%d.events.jsonl", FilePrefix, idx+1))
 }
 
 // MetaPath returns the path for a phase metadata file.
 func MetaPath(artifactsDir string, idx int) string {
-	return filepath.Join(artifactsDir, "logs", fmt.Sprintf("phase-%d.meta.json", idx+1))
+	return filepath.Join(artifactsDir, "logs", fmt.Sprintf("%sphase-%d.meta.json", FilePrefix, idx+1))
 }
 
 // AuditMetaPath returns the path for an archived metadata file in the audit dir.
@@ -257,6 +525,11 @@ func AuditStreamLogPath(auditDir string, phaseIdx, iteration int) string {
 	return filepath.Join(auditDir, "logs", fmt.Sprintf("phase-%d.iter-%d.stream.jsonl", phaseIdx+1, iteration))
 }
 
+// AuditEventLogPath returns the path for an archived event log in the audit dir.
+func AuditEventLogPath(auditDir string, phaseIdx, iteration int) string {
+	return filepath.Join(auditDir, "logs", fmt.Sprintf("phase-%d.iter-%d.events.jsonl", phaseIdx+1, iteration))
+}
+
 // AuditLogPath returns the path for an archived iteration log in the audit dir.
 func AuditLogPath(auditDir string, phaseIdx, iteration int) string {
 	return filepath.Join(auditDir, "logs", fmt.Sprintf("phase-%d.iter-%d.log", phaseIdx+1, iteration))
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestEnsureDir(t *testing.T) {
@@ -25,6 +26,30 @@ func TestEnsureDir(t *testing.T) {
 	}
 }
 
+func TestEnsureGitignore(t *testing.T) {
+	dir := t.TempDir()
+	if err := EnsureGitignore(dir); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		t.Fatalf(".gitignore not created: %v", err)
+	}
+	if string(data) != "*\n" {
+		t.Fatalf(".gitignore = %q, want %q", data, "*\n")
+	}
+
+	// Existing catch-all files are left untouched.
+	os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("# custom\n*\n"), 0644)
+	if err := EnsureGitignore(dir); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if string(data) != "# custom\n*\n" {
+		t.Fatalf(".gitignore rewritten: %q", data)
+	}
+}
+
 func TestLoopCounts_RoundTrip(t *testing.T) {
 	dir := t.TempDir()
 	original := map[string]int{"build": 2, "test": 1}
@@ -69,6 +94,41 @@ func TestWriteFeedback(t *testing.T) {
 	}
 }
 
+func TestFeedbackWithHistory_Accumulates(t *testing.T) {
+	dir := t.TempDir()
+	outputs := []string{"tried X: nil pointer", "tried Y: nil pointer again", "tried Z: timeout"}
+	for i, out := range outputs {
+		fb := FeedbackWithHistory(ReadFeedback(dir, "test"), out, i+1, 2)
+		if err := WriteFeedback(dir, "test", fb); err != nil {
+			t.Fatal(err)
+		}
+		if i == 1 {
+			got := ReadFeedback(dir, "test")
+			if !strings.Contains(got, "## Attempt 1\n\ntried X") || !strings.Contains(got, "## Attempt 2\n\ntried Y") {
+				t.Fatalf("attempts 1 and 2 should accumulate, got:\n%s", got)
+			}
+		}
+	}
+
+	got := ReadFeedback(dir, "test")
+	if strings.Contains(got, "tried X") {
+		t.Errorf("attempt 1 should be dropped with keep=2, got:\n%s", got)
+	}
+	if !strings.Contains(got, "## Attempt 2") || !strings.Contains(got, "## Attempt 3") {
+		t.Errorf("expected attempts 2 and 3, got:\n%s", got)
+	}
+	if strings.Index(got, "Attempt 2") > strings.Index(got, "Attempt 3") {
+		t.Errorf("attempts should be oldest first, got:\n%s", got)
+	}
+}
+
+func TestFeedbackWithHistory_PlainPrevious(t *testing.T) {
+	got := FeedbackWithHistory("old failure", "new failure", 2, 3)
+	if !strings.Contains(got, "## Earlier attempt\n\nold failure") || !strings.Contains(got, "## Attempt 2\n\nnew failure") {
+		t.Fatalf("plain previous feedback should be kept as an earlier attempt, got:\n%s", got)
+	}
+}
+
 func TestReadAllFeedback_SingleFile(t *testing.T) {
 	dir := t.TempDir()
 	if err := EnsureDir(dir); err != nil {
@@ -196,6 +256,33 @@ func TestCheckOutputs_SomeMissing(t *testing.T) {
 	}
 }
 
+func TestValidateOutputs_ValidJSON(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "report.json"), []byte(`{"status": "ok", "issues": []}`), 0644)
+	os.WriteFile(filepath.Join(dir, "plan.md"), []byte("intro\n\n## Steps\n1. do it\n"), 0644)
+
+	if problems := ValidateOutputs(dir, map[string]string{"report.json": "json", "plan.md": "markdown"}); len(problems) != 0 {
+		t.Fatalf("expected no problems, got %v", problems)
+	}
+}
+
+func TestValidateOutputs_InvalidJSON(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "report.json"), []byte(`{"status": "ok", "iss`), 0644)
+	os.WriteFile(filepath.Join(dir, "empty.json"), []byte("  \n"), 0644)
+
+	problems := ValidateOutputs(dir, map[string]string{"report.json": "json", "empty.json": "json", "missing.json": "json"})
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems (missing files skipped), got %v", problems)
+	}
+	if !strings.HasPrefix(problems[0], `output "empty.json" is not valid JSON: file is empty`) {
+		t.Errorf("problems[0] = %q", problems[0])
+	}
+	if !strings.HasPrefix(problems[1], `output "report.json" is not valid JSON:`) {
+		t.Errorf("problems[1] = %q", problems[1])
+	}
+}
+
 func TestReadDeclaredOutputs_AllPresent(t *testing.T) {
 	dir := t.TempDir()
 	os.WriteFile(filepath.Join(dir, "findings.md"), []byte("issue A"), 0644)
@@ -275,6 +362,23 @@ func TestClearFeedback_NoDir(t *testing.T) {
 	}
 }
 
+func TestFeedbackSources(t *testing.T) {
+	dir := t.TempDir()
+	if names, err := FeedbackSources(dir); err != nil || names != nil {
+		t.Fatalf("no feedback dir: got %v, %v", names, err)
+	}
+	WriteFeedback(dir, "test", "FAIL")
+	WriteFeedback(dir, "lint+vet", "lint failed")
+	WriteFeedback(dir, "review", "  \n")
+	names, err := FeedbackSources(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(names, ","); got != "lint+vet,test" {
+		t.Fatalf("sources = %q, want lint+vet,test (empty feedback skipped)", got)
+	}
+}
+
 func TestAuditFeedbackPath(t *testing.T) {
 	got := AuditFeedbackPath("/audit", 0, 1, "review")
 	want := filepath.Join("/audit", "feedback", "phase-1.iter-1.from-review.md")
@@ -380,6 +484,22 @@ func TestStreamLogPath(t *testing.T) {
 	}
 }
 
+func TestAttemptPromptPath(t *testing.T) {
+	got := AttemptPromptPath("/art", 1, 3)
+	want := filepath.Join("/art", "prompts", "phase-2-attempt-3.md")
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestEventLogPath(t *testing.T) {
+	got := EventLogPath("/art", 2)
+	want := filepath.Join("/art", "logs", "phase-3.events.jsonl")
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
 func TestMetaPath(t *testing.T) {
 	got := MetaPath("/art", 0)
 	want := filepath.Join("/art", "logs", "phase-1.meta.json")
@@ -393,6 +513,121 @@ func TestMetaPath(t *testing.T) {
 	}
 }
 
+func setFilePrefix(t *testing.T, prefix string) {
+	t.Helper()
+	orig := FilePrefix
+	FilePrefix = prefix
+	t.Cleanup(func() { FilePrefix = orig })
+}
+
+func TestFilePrefix_Paths(t *testing.T) {
+	setFilePrefix(t, "build-")
+	cases := []struct{ got, want string }{
+		{LogPath("/art", 0), filepath.Join("/art", "logs", "build-phase-1.log")},
+		{PromptPath("/art", 1), filepath.Join("/art", "prompts", "build-phase-2.md")},
+		{AttemptPromptPath("/art", 0, 2), filepath.Join("/art", "prompts", "build-phase-1-attempt-2.md")},
+		{StreamLogPath("/art", 0), filepath.Join("/art", "logs", "build-phase-1.stream.jsonl")},
+		{EventLogPath("/art", 0), filepath.Join("/art", "logs", "build-phase-1.events.jsonl")},
+		{MetaPath("/art", 0), filepath.Join("/art", "logs", "build-phase-1.meta.json")},
+		{statePath("/art"), filepath.Join("/art", "build-state.json")},
+		{RunResultPath("/art"), filepath.Join("/art", "build-run-result.json")},
+		{ReadmePath("/art"), filepath.Join("/art", "build-ORC-README.md")},
+		{ConfigSnapshotPath("/art"), filepath.Join("/art", "build-config.snapshot.yaml")},
+	}
+	for _, c := range cases {
+		if c.got != c.want {
+			t.Errorf("got %q, want %q", c.got, c.want)
+		}
+	}
+}
+
+func TestFilePrefix_NoCollision(t *testing.T) {
+	dir := t.TempDir()
+	if err := EnsureDir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	setFilePrefix(t, "a-")
+	stA := &State{PhaseIndex: 1, Ticket: "T-1", Status: StatusRunning}
+	if err := stA.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveLoopCounts(dir, map[string]int{"review": 2}); err != nil {
+		t.Fatal(err)
+	}
+	logA := LogPath(dir, 0)
+	if err := os.WriteFile(logA, []byte("workflow a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	FilePrefix = "b-"
+	if HasState(dir) {
+		t.Fatal("prefix b- should not see a-'s state")
+	}
+	stB := &State{PhaseIndex: 3, Ticket: "T-1", Status: StatusRunning}
+	if err := stB.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	counts, err := LoadLoopCounts(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counts) != 0 {
+		t.Fatalf("b- loop counts = %v, want empty", counts)
+	}
+	if LogPath(dir, 0) == logA {
+		t.Fatal("log paths collide across prefixes")
+	}
+
+	FilePrefix = "a-"
+	got, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PhaseIndex != 1 {
+		t.Fatalf("a- state PhaseIndex = %d, want 1 (clobbered by b-)", got.PhaseIndex)
+	}
+	data, err := os.ReadFile(LogPath(dir, 0))
+	if err != nil || string(data) != "workflow a" {
+		t.Fatalf("a- log = %q, %v", data, err)
+	}
+}
+
+func TestFilePrefix_Feedback(t *testing.T) {
+	dir := t.TempDir()
+	setFilePrefix(t, "a-")
+	if err := WriteFeedback(dir, "test", "a failed"); err != nil {
+		t.Fatal(err)
+	}
+	if got := filepath.Base(FeedbackPath(dir, "test")); got != "a-from-test.md" {
+		t.Fatalf("feedback file = %q, want a-from-test.md", got)
+	}
+
+	FilePrefix = "b-"
+	if err := WriteFeedback(dir, "lint", "b failed"); err != nil {
+		t.Fatal(err)
+	}
+	sources, err := FeedbackSources(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sources) != 1 || sources[0] != "lint" {
+		t.Fatalf("b- sources = %v, want [lint]", sources)
+	}
+	if err := ClearFeedback(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	FilePrefix = "a-"
+	all, err := ReadAllFeedback(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(all, "--- Feedback from test ---\na failed") || strings.Contains(all, "b failed") {
+		t.Fatalf("a- feedback = %q", all)
+	}
+}
+
 func TestAuditMetaPath(t *testing.T) {
 	got := AuditMetaPath("/audit", 0, 1)
 	want := filepath.Join("/audit", "logs", "phase-1.iter-1.meta.json")
@@ -405,3 +640,23 @@ func TestAuditMetaPath(t *testing.T) {
 		t.Fatalf("got %q, want %q", got, want)
 	}
 }
+
+func TestStaleOutputs_AllowsCoarseMtimes(t *testing.T) {
+	dir := t.TempDir()
+	since := time.Date(2026, 3, 1, 10, 0, 1, 700_000_000, time.UTC)
+	for name, mtime := range map[string]time.Time{
+		// Written just after since on a filesystem with 2s mtimes.
+		"fresh.md": time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		"old.md":   since.Add(-time.Hour),
+	} {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(name), 0644)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := StaleOutputs(dir, []string{"fresh.md", "old.md", "missing.md"}, since)
+	if len(got) != 1 || got[0] != "old.md" {
+		t.Fatalf("StaleOutputs = %v, want [old.md]", got)
+	}
+}
@@ -33,7 +33,7 @@ type CostData struct {
 }
 
 func costsPath(artifactsDir string) string {
-	return filepath.Join(artifactsDir, "costs.json")
+	return filepath.Join(artifactsDir, FilePrefix+"costs.json")
 }
 
 // LoadCosts reads cost data from the artifacts directory.
@@ -131,7 +131,7 @@ func (c *CostData) Merge(other *CostData, prefix string) {
 func (c *CostData) Flush(artifactsDir string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	data, err := json.MarshalIndent(c, "", "  ")
+	data, err := marshalArtifact(c)
 	if err != nil {
 		return err
 	}
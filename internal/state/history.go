@@ -7,7 +7,9 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -27,6 +29,18 @@ func HistoryDir(artifactsDir string) string {
 	return filepath.Join(artifactsDir, "history")
 }
 
+// ReadmePath returns the path of the README the runner writes at run start
+// within a run directory, describing the ticket, phases, and layout.
+func ReadmePath(runDir string) string {
+	return filepath.Join(runDir, FilePrefix+"ORC-README.md")
+}
+
+// ConfigSnapshotPath returns the path of the config copy saved at run start
+// within a run directory (the live artifacts dir or a history entry).
+func ConfigSnapshotPath(runDir string) string {
+	return filepath.Join(runDir, FilePrefix+"config.snapshot.yaml")
+}
+
 // LatestHistoryDir returns the path to the most recent history entry
 // (newest timestamped subdirectory) within artifactsDir/history/.
 // Returns ("", nil) if no history entries exist.
@@ -56,6 +70,25 @@ func CopyTree(src, dst string) error {
 	return copyEntry(src, dst)
 }
 
+// CopyRun copies a run directory's contents, except history/, into dst,
+// which must exist. run --bisect uses it to replay trials from a saved run
+// without touching it.
+func CopyRun(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.Name() == "history" {
+			continue
+		}
+		if err := copyEntry(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // copyEntry recursively copies src to dst.
 func copyEntry(src, dst string) error {
 	info, err := os.Lstat(src)
@@ -106,8 +139,85 @@ func copyEntry(src, dst string) error {
 	return nil
 }
 
-// ArchiveRun copies all non-history entries from artifactsDir into a timestamped
-// history subdirectory, then removes the originals. Returns the run ID.
+// runFiles are the top-level files a run writes under its FilePrefix.
+var runFiles = []string{"state.json", "timing.json", "costs.json", "loop-counts.json",
+	"run-result.json", "ORC-README.md", "config.snapshot.yaml"}
+
+// runFileDirs hold per-phase files named <prefix>phase-N... or
+// <prefix>from-<phase>.md.
+var runFileDirs = []string{"logs", "prompts", "feedback"}
+
+// filePrefixes returns FilePrefix plus the prefix of every other workflow
+// with a run in an artifacts directory listing, read from its
+// <prefix>state.json.
+func filePrefixes(entries []os.DirEntry) []string {
+	prefixes := []string{FilePrefix}
+	for _, e := range entries {
+		if p, ok := strings.CutSuffix(e.Name(), "state.json"); ok && !e.IsDir() && p != FilePrefix {
+			prefixes = append(prefixes, p)
+		}
+	}
+	return prefixes
+}
+
+// owner returns the longest of prefixes that name carries in front of one of
+// stems, or "" when none does.
+func owner(name string, prefixes []string, stems ...string) string {
+	best := ""
+	for _, p := range prefixes {
+		for _, stem := range stems {
+			if strings.HasPrefix(name, p+stem) && len(p) > len(best) {
+				best = p
+			}
+		}
+	}
+	return best
+}
+
+// archiveEntries lists the paths, relative to artifactsDir, that belong to
+// the current FilePrefix's run. When no other prefix has a run there, that is
+// every entry except history/ and .gitignore. Otherwise it is only the run's
+// own prefixed files, plus unprefixed entries (phase outputs) when FilePrefix
+// is empty, so another workflow's state, logs and prompts stay in place.
+func archiveEntries(artifactsDir string) ([]string, error) {
+	entries, err := os.ReadDir(artifactsDir)
+	if err != nil {
+		return nil, err
+	}
+	prefixes := filePrefixes(entries)
+	var paths []string
+	for _, e := range entries {
+		name := e.Name()
+		if name == "history" || name == ".gitignore" {
+			continue
+		}
+		if len(prefixes) == 1 {
+			paths = append(paths, name)
+			continue
+		}
+		if e.IsDir() && slices.Contains(runFileDirs, name) {
+			files, err := os.ReadDir(filepath.Join(artifactsDir, name))
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range files {
+				if owner(f.Name(), prefixes, "phase-", "from-") == FilePrefix {
+					paths = append(paths, filepath.Join(name, f.Name()))
+				}
+			}
+			continue
+		}
+		if owner(name, prefixes, runFiles...) == FilePrefix {
+			paths = append(paths, name)
+		}
+	}
+	return paths, nil
+}
+
+// ArchiveRun copies the current run's entries in artifactsDir into a
+// timestamped history subdirectory, then removes the originals. Returns the
+// run ID. Runs of workflows with another FilePrefix sharing the directory
+// are left in place (see archiveEntries).
 func ArchiveRun(artifactsDir string) (string, error) {
 	runID := time.Now().Format("2006-01-02T15-04-05.000")
 	destDir := filepath.Join(artifactsDir, "history", runID)
@@ -115,18 +225,19 @@ func ArchiveRun(artifactsDir string) (string, error) {
 		return "", err
 	}
 
-	entries, err := os.ReadDir(artifactsDir)
+	paths, err := archiveEntries(artifactsDir)
 	if err != nil {
 		os.RemoveAll(destDir)
 		return "", err
 	}
 
-	for _, e := range entries {
-		if e.Name() == "history" {
-			continue
+	for _, rel := range paths {
+		src := filepath.Join(artifactsDir, rel)
+		dst := filepath.Join(destDir, rel)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			os.RemoveAll(destDir)
+			return "", err
 		}
-		src := filepath.Join(artifactsDir, e.Name())
-		dst := filepath.Join(destDir, e.Name())
 		if err := copyEntry(src, dst); err != nil {
 			os.RemoveAll(destDir) // clean partial archive, leave originals intact
 			return "", err
@@ -134,13 +245,9 @@ func ArchiveRun(artifactsDir string) (string, error) {
 	}
 
 	// All copies succeeded — remove originals
-	for _, e := range entries {
-		if e.Name() == "history" {
-			continue
-		}
-		target := filepath.Join(artifactsDir, e.Name())
-		if err := os.RemoveAll(target); err != nil {
-			return runID, fmt.Errorf("removing %s after archive: %w", e.Name(), err)
+	for _, rel := range paths {
+		if err := os.RemoveAll(filepath.Join(artifactsDir, rel)); err != nil {
+			return runID, fmt.Errorf("removing %s after archive: %w", rel, err)
 		}
 	}
 
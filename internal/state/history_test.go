@@ -82,6 +82,65 @@ func TestArchiveRun_Empty(t *testing.T) {
 	}
 }
 
+func TestArchiveRun_LeavesOtherPrefixRun(t *testing.T) {
+	dir := t.TempDir()
+	if err := EnsureDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	write := func(rel string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, rel), []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setFilePrefix(t, "b-")
+	writeTestArtifacts(t, dir, StatusRunning, "T-1")
+	write("b-run-result.json")
+	write(filepath.Join("logs", "b-phase-1.log"))
+	write(filepath.Join("feedback", "b-from-review.md"))
+
+	FilePrefix = "a-"
+	writeTestArtifacts(t, dir, StatusCompleted, "T-1")
+	write("a-ORC-README.md")
+	write(filepath.Join("logs", "a-phase-1.log"))
+	write(filepath.Join("prompts", "a-phase-1.md"))
+	write("plan.md") // unprefixed phase output
+
+	runID, err := ArchiveRun(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hist := filepath.Join(dir, "history", runID)
+	for _, rel := range []string{"a-state.json", "a-timing.json", "a-ORC-README.md", filepath.Join("logs", "a-phase-1.log"), filepath.Join("prompts", "a-phase-1.md")} {
+		if _, err := os.Stat(filepath.Join(hist, rel)); err != nil {
+			t.Errorf("expected %s in history: %v", rel, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, rel)); !os.IsNotExist(err) {
+			t.Errorf("%s should have been moved out of the artifacts dir", rel)
+		}
+	}
+	for _, rel := range []string{"b-state.json", "b-timing.json", "b-run-result.json", filepath.Join("logs", "b-phase-1.log"), filepath.Join("feedback", "b-from-review.md"), "plan.md"} {
+		if _, err := os.Stat(filepath.Join(dir, rel)); err != nil {
+			t.Errorf("%s should survive another prefix's archive: %v", rel, err)
+		}
+		if _, err := os.Stat(filepath.Join(hist, rel)); !os.IsNotExist(err) {
+			t.Errorf("%s should not be archived with prefix a-", rel)
+		}
+	}
+
+	// The last run out takes what is left, unprefixed outputs included.
+	FilePrefix = "b-"
+	runID, err = ArchiveRun(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rel := range []string{"b-state.json", "plan.md"} {
+		if _, err := os.Stat(filepath.Join(dir, "history", runID, rel)); err != nil {
+			t.Errorf("expected %s in the second archive: %v", rel, err)
+		}
+	}
+}
+
 func TestArchiveRun_PartialFailure(t *testing.T) {
 	tempDir := t.TempDir()
 	if err := os.WriteFile(filepath.Join(tempDir, "state.json"), []byte("{}"), 0644); err != nil {
@@ -1,31 +1,63 @@
 package state
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"time"
 )
 
 // PhaseMetadata holds structured metadata for a completed phase.
 type PhaseMetadata struct {
-	PhaseName    string    `json:"phase_name"`
-	PhaseType    string    `json:"phase_type"`
-	PhaseIndex   int       `json:"phase_index"`
-	Model        string    `json:"model,omitempty"`
-	Effort       string    `json:"effort,omitempty"`
-	SessionID    string    `json:"session_id,omitempty"`
-	StartTime    time.Time `json:"start_time"`
-	EndTime      time.Time `json:"end_time"`
-	DurationSecs float64   `json:"duration_seconds"`
-	CostUSD      float64   `json:"cost_usd,omitempty"`
-	InputTokens  int       `json:"input_tokens,omitempty"`
-	OutputTokens int       `json:"output_tokens,omitempty"`
-	ExitCode     int       `json:"exit_code"`
-	ToolsUsed    []string  `json:"tools_used"`
-	ToolsDenied  []string  `json:"tools_denied"`
-	TimedOut     bool      `json:"timed_out,omitempty"`
+	PhaseName    string         `json:"phase_name"`
+	PhaseType    string         `json:"phase_type"`
+	PhaseIndex   int            `json:"phase_index"`
+	Model        string         `json:"model,omitempty"`
+	Effort       string         `json:"effort,omitempty"`
+	SessionID    string         `json:"session_id,omitempty"`
+	StartTime    time.Time      `json:"start_time"`
+	EndTime      time.Time      `json:"end_time"`
+	DurationSecs float64        `json:"duration_seconds"`
+	CostUSD      float64        `json:"cost_usd,omitempty"`
+	InputTokens  int            `json:"input_tokens,omitempty"`
+	OutputTokens int            `json:"output_tokens,omitempty"`
+	ExitCode     int            `json:"exit_code"`
+	ToolsUsed    []string       `json:"tools_used"`
+	ToolsDenied  []string       `json:"tools_denied"`
+	TimedOut     bool           `json:"timed_out,omitempty"`
+	Outputs      []OutputRecord `json:"outputs,omitempty"`
+}
+
+// OutputRecord fingerprints a declared output as it stood when its phase
+// finished.
+type OutputRecord struct {
+	Name    string    `json:"name"`
+	ModTime time.Time `json:"mod_time"`
+	SHA256  string    `json:"sha256"`
+}
+
+// RecordOutputs fingerprints the declared outputs that exist. Missing or
+// unreadable files are skipped.
+func RecordOutputs(artifactsDir string, outputs []string) []OutputRecord {
+	var records []OutputRecord
+	for _, o := range outputs {
+		path := filepath.Join(artifactsDir, o)
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		records = append(records, OutputRecord{Name: o, ModTime: info.ModTime(), SHA256: hex.EncodeToString(sum[:])})
+	}
+	return records
 }
 
 // SaveMetadata writes phase metadata to a .meta.json file atomically.
@@ -39,7 +71,7 @@ func SaveMetadata(path string, meta *PhaseMetadata) error {
 	if local.ToolsDenied == nil {
 		local.ToolsDenied = []string{}
 	}
-	data, err := json.MarshalIndent(&local, "", "  ")
+	data, err := marshalArtifact(&local)
 	if err != nil {
 		return err
 	}
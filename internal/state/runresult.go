@@ -2,6 +2,7 @@ package state
 
 import (
 	"encoding/json"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
@@ -13,6 +14,7 @@ type PhaseResult struct {
 	Status          string  `json:"status"`
 	DurationSeconds float64 `json:"duration_seconds"`
 	CostUSD         float64 `json:"cost_usd"`
+	SkipReason      string  `json:"skip_reason,omitempty"` // skipped and disabled phases: why
 }
 
 // RunResult holds the outcome of a completed workflow run.
@@ -33,7 +35,20 @@ type RunResult struct {
 
 // RunResultPath returns the path to the run-result.json file.
 func RunResultPath(dir string) string {
-	return filepath.Join(dir, "run-result.json")
+	return filepath.Join(dir, FilePrefix+"run-result.json")
+}
+
+// LoadRunResult reads run-result.json from dir.
+func LoadRunResult(dir string) (*RunResult, error) {
+	data, err := os.ReadFile(RunResultPath(dir))
+	if err != nil {
+		return nil, err
+	}
+	var result RunResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
 // WriteRunResult writes the run result to disk atomically. It does not modify the passed RunResult.
@@ -45,7 +60,7 @@ func WriteRunResult(dir string, result *RunResult) error {
 	if local.Phases == nil {
 		local.Phases = []PhaseResult{}
 	}
-	data, err := json.MarshalIndent(local, "", "  ")
+	data, err := marshalArtifact(local)
 	if err != nil {
 		return err
 	}
@@ -21,6 +21,7 @@ const (
 const (
 	PhaseStatusPending     = "pending"
 	PhaseStatusSkipped     = "skipped"
+	PhaseStatusDisabled    = "disabled"
 	PhaseStatusCompleted   = "completed"
 	PhaseStatusFailed      = "failed"
 	PhaseStatusInterrupted = "interrupted"
@@ -32,6 +33,8 @@ const (
 	FailCategoryGateRejection  = "gate_rejection"
 	FailCategoryScriptFailure  = "script_failure"
 	FailCategoryOutputMissing  = "output_missing"
+	FailCategoryVerifyFailure  = "verify_failure"
+	FailCategoryOutputInvalid  = "output_invalid"
 	FailCategoryInterrupted    = "interrupted"
 	FailCategoryAgentError     = "agent_error"
 	FailCategoryTimeout        = "timeout"
@@ -52,7 +55,7 @@ type State struct {
 }
 
 func statePath(artifactsDir string) string {
-	return filepath.Join(artifactsDir, "state.json")
+	return filepath.Join(artifactsDir, FilePrefix+"state.json")
 }
 
 // HasState reports whether a state file exists in the artifacts directory.
@@ -66,7 +69,7 @@ func HasState(artifactsDir string) bool {
 // state.json). Used by stats and improve to discover past-run dirs.
 func IsAuditDir(dir string) bool {
 	for _, f := range []string{"timing.json", "costs.json", "state.json"} {
-		if _, err := os.Stat(filepath.Join(dir, f)); err == nil {
+		if _, err := os.Stat(filepath.Join(dir, FilePrefix+f)); err == nil {
 			return true
 		}
 	}
@@ -75,19 +78,20 @@ func IsAuditDir(dir string) bool {
 
 // ResolveStateDir finds the directory containing state.json for a ticket.
 // It checks the live artifacts directory first, then falls back to the
-// latest history entry. Returns an error if no state is found anywhere.
+// latest history entry with a state file under the current FilePrefix.
+// Returns an error if no state is found anywhere.
 func ResolveStateDir(artifactsDir string) (string, error) {
 	if HasState(artifactsDir) {
 		return artifactsDir, nil
 	}
-	histDir, err := LatestHistoryDir(artifactsDir)
+	entries, err := ListHistory(artifactsDir)
 	if err != nil {
 		return "", fmt.Errorf("checking history: %w", err)
 	}
-	if histDir == "" || !HasState(histDir) {
+	if len(entries) == 0 {
 		return "", fmt.Errorf("no state found in %s — has this ticket been run before?", artifactsDir)
 	}
-	return histDir, nil
+	return entries[0].Dir, nil
 }
 
 // Load reads the state from the artifacts directory. Returns a new state if not found.
@@ -107,10 +111,23 @@ func Load(artifactsDir string) (*State, error) {
 	return &s, nil
 }
 
+// CompactArtifacts makes the state writers emit compact JSON instead of
+// indented JSON. Compact files are smaller and cheaper to rewrite on every
+// phase flush; indented files are easier to read, so that stays the default.
+var CompactArtifacts bool
+
+// marshalArtifact serializes v for an artifact file, honoring CompactArtifacts.
+func marshalArtifact(v any) ([]byte, error) {
+	if CompactArtifacts {
+		return json.Marshal(v)
+	}
+	return json.MarshalIndent(v, "", "  ")
+}
+
 // Save writes the state to the artifacts directory.
 func (s *State) Save(artifactsDir string) error {
 	s.mu.RLock()
-	data, err := json.MarshalIndent(s, "", "  ")
+	data, err := marshalArtifact(s)
 	s.mu.RUnlock()
 	if err != nil {
 		return err
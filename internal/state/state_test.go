@@ -3,6 +3,7 @@ package state
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -90,6 +91,42 @@ func TestSaveAndLoad_RoundTrip(t *testing.T) {
 	}
 }
 
+func TestSaveAndLoad_CompactAndIndented(t *testing.T) {
+	defer func(prev bool) { CompactArtifacts = prev }(CompactArtifacts)
+	for _, compact := range []bool{false, true} {
+		CompactArtifacts = compact
+		dir := t.TempDir()
+		original := &State{PhaseIndex: 2, Ticket: "ABC-123", Status: StatusRunning}
+		if err := original.Save(dir); err != nil {
+			t.Fatal(err)
+		}
+		if err := SaveLoopCounts(dir, map[string]int{"review": 2}); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(statePath(dir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if indented := strings.Contains(string(data), "\n"); indented == compact {
+			t.Fatalf("compact=%v: state.json = %q", compact, data)
+		}
+		loaded, err := Load(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loaded.PhaseIndex != 2 || loaded.GetTicket() != "ABC-123" || loaded.Status != StatusRunning {
+			t.Fatalf("compact=%v: loaded = %+v", compact, loaded)
+		}
+		counts, err := LoadLoopCounts(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if counts["review"] != 2 {
+			t.Fatalf("compact=%v: loop counts = %v", compact, counts)
+		}
+	}
+}
+
 func TestAdvance(t *testing.T) {
 	s := &State{PhaseIndex: 2}
 	s.Advance()
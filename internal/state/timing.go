@@ -18,8 +18,11 @@ type TimingEntry struct {
 	Duration string    `json:"duration,omitempty"`
 }
 
+// Timing is safe for concurrent use: parallel phases record their own
+// entries from separate goroutines while the runner flushes.
 type Timing struct {
-	mu      sync.Mutex
+	mu      sync.Mutex // guards entries
+	flushMu sync.Mutex // serializes Flush so the file never goes back in time
 	entries []TimingEntry
 }
 
@@ -38,7 +41,7 @@ func (t *Timing) Entries() []TimingEntry {
 }
 
 func timingPath(artifactsDir string) string {
-	return filepath.Join(artifactsDir, "timing.json")
+	return filepath.Join(artifactsDir, FilePrefix+"timing.json")
 }
 
 // LoadTiming reads timing data from the artifacts directory.
@@ -60,9 +63,9 @@ func LoadTiming(artifactsDir string) (*Timing, error) {
 
 // marshalJSON serializes without locking — caller must hold mu.
 func (t *Timing) marshalJSON() ([]byte, error) {
-	return json.MarshalIndent(struct {
+	return marshalArtifact(struct {
 		Entries []TimingEntry `json:"entries"`
-	}{Entries: t.entries}, "", "  ")
+	}{Entries: t.entries})
 }
 
 // MarshalJSON implements json.Marshaler for external callers.
@@ -80,18 +83,12 @@ func (t *Timing) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return err
 	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	t.entries = raw.Entries
 	return nil
 }
 
-func (t *Timing) save(artifactsDir string) error {
-	data, err := t.marshalJSON()
-	if err != nil {
-		return err
-	}
-	return WriteFileAtomic(timingPath(artifactsDir), data, 0644)
-}
-
 // AddStart appends a new timing entry for the given phase.
 func (t *Timing) AddStart(phaseName string) {
 	t.mu.Lock()
@@ -112,7 +109,9 @@ func (t *Timing) AddStartAt(phaseName string, startTime time.Time) {
 	})
 }
 
-// AddEnd records the end time for the most recent entry matching phaseName.
+// AddEnd records the end time for the most recent open entry matching
+// phaseName. Parallel partners have distinct names, so concurrent calls
+// never close each other's entries.
 func (t *Timing) AddEnd(phaseName string) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -140,11 +139,21 @@ func (t *Timing) AddEndAt(phaseName string, endTime time.Time) {
 	}
 }
 
-// Flush writes the in-memory timing data to disk.
+// Flush writes the in-memory timing data to disk. The entries are
+// snapshotted under the lock and written outside it, so a slow disk never
+// blocks AddStart/AddEnd in a parallel branch. Flushes are serialized and
+// each one snapshots after the previous write, so the newest snapshot is
+// always the one left on disk.
 func (t *Timing) Flush(artifactsDir string) error {
+	t.flushMu.Lock()
+	defer t.flushMu.Unlock()
 	t.mu.Lock()
-	defer t.mu.Unlock()
-	return t.save(artifactsDir)
+	data, err := t.marshalJSON()
+	t.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(timingPath(artifactsDir), data, 0644)
 }
 
 // TotalElapsed returns the sum of all completed timing entry durations.
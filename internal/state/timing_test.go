@@ -2,6 +2,7 @@ package state
 
 import (
 	"encoding/json"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -152,3 +153,82 @@ func TestTimingJSON_FlushDoesNotDeadlock(t *testing.T) {
 		t.Fatalf("Flush: %v", err)
 	}
 }
+
+// TestTiming_ConcurrentParallelWrites mimics a parallel group: each branch
+// opens and closes its own entries while another goroutine flushes. Run
+// with -race to check for data races.
+func TestTiming_ConcurrentParallelWrites(t *testing.T) {
+	dir := t.TempDir()
+	timing := &Timing{}
+	const branches, iterations = 4, 50
+
+	var wg sync.WaitGroup
+	for b := 0; b < branches; b++ {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				start := time.Now()
+				timing.AddStartAt(name, start)
+				timing.AddEndAt(name, start.Add(time.Millisecond))
+			}
+		}(fmt.Sprintf("branch-%d", b))
+	}
+	done := make(chan struct{})
+	flushErr := make(chan error, 1)
+	go func() {
+		defer close(flushErr)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if err := timing.Flush(dir); err != nil {
+				flushErr <- err
+				return
+			}
+			_ = timing.Entries()
+			_ = timing.TotalElapsed()
+		}
+	}()
+	wg.Wait()
+	close(done)
+	if err := <-flushErr; err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if err := timing.Flush(dir); err != nil {
+		t.Fatalf("final Flush: %v", err)
+	}
+
+	entries := timing.Entries()
+	if len(entries) != branches*iterations {
+		t.Fatalf("got %d entries, want %d", len(entries), branches*iterations)
+	}
+	perPhase := make(map[string]int)
+	for _, e := range entries {
+		if e.End.IsZero() || e.End.Sub(e.Start) != time.Millisecond {
+			t.Fatalf("entry %+v was not closed by its own branch", e)
+		}
+		perPhase[e.Phase]++
+	}
+	for name, n := range perPhase {
+		if n != iterations {
+			t.Errorf("%s has %d entries, want %d", name, n, iterations)
+		}
+	}
+
+	onDisk, err := LoadTiming(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := onDisk.Entries()
+	if len(got) != len(entries) {
+		t.Fatalf("flushed %d entries, want %d (last flush must win)", len(got), len(entries))
+	}
+	for i := range got {
+		if got[i].Phase != entries[i].Phase || !got[i].Start.Equal(entries[i].Start) || !got[i].End.Equal(entries[i].End) {
+			t.Fatalf("entry %d on disk = %+v, in memory = %+v", i, got[i], entries[i])
+		}
+	}
+}
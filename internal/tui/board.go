@@ -0,0 +1,334 @@
+// Package tui renders run --interactive-run. Board is the view model: the
+// phase list with live statuses, timing and cost, updated from runner
+// events, and a pane holding the current phase's output. Screen draws it
+// full-screen and turns keys into gate answers and agent steering; Follow
+// prints the board inline when a full-screen view isn't possible.
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/dispatch"
+	"github.com/jorge-barreto/orc/internal/ux"
+)
+
+// Phase statuses shown on the board.
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusOK      = "ok"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// PhaseView is one row of the board.
+type PhaseView struct {
+	Name     string
+	Type     string
+	Status   string
+	Runs     int // dispatches started, counting loop-backs and retries
+	Duration time.Duration
+	CostUSD  float64
+}
+
+// maxOutputLines caps the lines the output pane keeps.
+const maxOutputLines = 500
+
+// Board is the view model for a run: the phase list with live statuses,
+// the run's elapsed time and cost, and the output of the phase in progress.
+// It is updated by Observe and Write and is safe for concurrent use, since
+// parallel phases report from their own goroutines.
+type Board struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	title   string
+	start   time.Time
+	phases  []PhaseView
+	phrases []string // each phase's gate require-phrase
+	cost    float64
+	done    string // final run status once run_end is seen
+
+	outTitle string   // phases running, whose output the pane shows
+	output   []string // complete output lines, ANSI codes removed
+	partial  []byte   // output after the last newline
+	endMark  int      // len(output) when run_end was seen
+	after    []byte   // raw output written after run_end
+}
+
+// NewBoard returns a board listing cfg's phases as pending.
+func NewBoard(cfg *config.Config, ticket string) *Board {
+	b := &Board{now: time.Now, start: time.Now(), title: ticket + " — " + cfg.Name}
+	for _, p := range cfg.Phases {
+		b.phases = append(b.phases, PhaseView{Name: p.Name, Type: p.Type, Status: StatusPending})
+		b.phrases = append(b.phrases, p.RequirePhrase)
+	}
+	return b
+}
+
+// Observe updates the board from a runner event. Events for phases the
+// board doesn't list are ignored.
+func (b *Board) Observe(ev dispatch.LogEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ev.Type == "run_end" {
+		b.done = ev.Status
+		b.endMark = len(b.output)
+		return
+	}
+	p := b.phase(ev)
+	if p == nil {
+		return
+	}
+	switch ev.Type {
+	case "phase_start":
+		// The pane scrolls on rather than clearing: output reaches it
+		// through a pipe, so the previous phase's last lines may still be
+		// on their way. Its title names the phases now running.
+		if b.running() == 0 {
+			b.outTitle = p.Name
+		} else {
+			b.outTitle += " + " + p.Name
+		}
+		p.Status = StatusRunning
+		p.Runs++
+		b.done = ""
+		b.after = nil
+	case "phase_end":
+		p.Status = StatusOK
+		if ev.Status == "failed" {
+			p.Status = StatusFailed
+		}
+		p.Duration += time.Duration(ev.DurationSecs * float64(time.Second))
+		p.CostUSD += ev.CostUSD
+		b.cost += ev.CostUSD
+	case "phase_skip":
+		p.Status = StatusSkipped
+	}
+}
+
+// phase returns the row an event refers to, by its 1-indexed position.
+func (b *Board) phase(ev dispatch.LogEvent) *PhaseView {
+	i := ev.Index - 1
+	if i < 0 || i >= len(b.phases) || b.phases[i].Name != ev.Phase {
+		return nil
+	}
+	return &b.phases[i]
+}
+
+// Phases returns a copy of the board's rows.
+func (b *Board) Phases() []PhaseView {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]PhaseView(nil), b.phases...)
+}
+
+// Running reports how many phases are in progress.
+func (b *Board) Running() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.running()
+}
+
+func (b *Board) running() int {
+	n := 0
+	for _, p := range b.phases {
+		if p.Status == StatusRunning {
+			n++
+		}
+	}
+	return n
+}
+
+// Awaiting returns the gate waiting for an answer, if exactly one phase is
+// running and it is a gate, with the phrase that approves it ("" for y).
+func (b *Board) Awaiting() (phrase string, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.running() != 1 {
+		return "", false
+	}
+	for i, p := range b.phases {
+		if p.Status == StatusRunning && p.Type == "gate" {
+			return b.phrases[i], true
+		}
+	}
+	return "", false
+}
+
+// Write adds run output to the pane. Lines are kept without ANSI codes,
+// and a carriage return starts its line over, as a terminal would show it.
+// Output written after the run ends is also kept as written, for Tail.
+func (b *Board) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.done != "" {
+		b.after = append(b.after, p...)
+	}
+	data := append(b.partial, p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		b.output = append(b.output, plainLine(data[:i]))
+		data = data[i+1:]
+	}
+	b.partial = append([]byte(nil), data...)
+	if over := len(b.output) - maxOutputLines; over > 0 {
+		b.output = b.output[over:]
+		b.endMark = max(0, b.endMark-over)
+	}
+	return len(p), nil
+}
+
+// Output returns the pane's title and its last n lines, the unfinished one
+// included.
+func (b *Board) Output(n int) (string, []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	lines := b.output
+	if len(b.partial) > 0 {
+		lines = append(lines[:len(lines):len(lines)], plainLine(b.partial))
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return b.outTitle, append([]string(nil), lines...)
+}
+
+// Tail returns up to n lines of output from before the run ended and
+// everything written since, as written, so the run's last phase output and
+// its summary can be printed once the full-screen view is gone.
+func (b *Board) Tail(n int) ([]string, []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	end := len(b.output)
+	if b.done != "" {
+		end = b.endMark
+	}
+	lines := b.output[max(0, end-n):end]
+	return append([]string(nil), lines...), append([]byte(nil), b.after...)
+}
+
+// plainLine returns line as the terminal would leave it: ANSI escape
+// sequences removed, and only the text after the last carriage return.
+func plainLine(line []byte) string {
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if i := bytes.LastIndexByte(line, '\r'); i >= 0 {
+		line = line[i+1:]
+	}
+	text := ansiRe.ReplaceAllString(string(line), "")
+	return strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return ' '
+		}
+		if r < ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// ansiRe matches CSI escape sequences (colors, cursor movement).
+var ansiRe = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+// Render writes the board: a header with elapsed time and cost, then one
+// line per phase with its status icon, runs, duration, and cost.
+func (b *Board) Render(w io.Writer) {
+	for _, line := range b.lines(-1) {
+		fmt.Fprintln(w, line)
+	}
+}
+
+// lines returns the board's lines with at most limit phase rows (all when
+// limit < 0), keeping the first running phase in view.
+func (b *Board) lines(limit int) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := "running"
+	if b.done != "" {
+		status = b.done
+	}
+	rule := strings.Repeat("─", 48)
+	out := []string{
+		fmt.Sprintf("%s%s%s  %s  %s  $%.2f", ux.Bold, b.title, ux.Reset, status, duration(b.now().Sub(b.start)), b.cost),
+		rule,
+	}
+	first, last := 0, len(b.phases)
+	if limit >= 0 && limit < last {
+		focus := 0
+		for i, p := range b.phases {
+			if p.Status == StatusRunning {
+				focus = i
+				break
+			}
+			if p.Status != StatusPending {
+				focus = i
+			}
+		}
+		first = min(max(0, focus-limit/2), len(b.phases)-limit)
+		last = first + limit
+	}
+	for i, p := range b.phases[first:last] {
+		line := fmt.Sprintf("%s %2d  %-20s %-7s", icon(p.Status), first+i+1, p.Name, p.Type)
+		if p.Status == StatusOK || p.Status == StatusFailed {
+			line += fmt.Sprintf("  %7s", duration(p.Duration))
+			if p.CostUSD > 0 {
+				line += fmt.Sprintf("  $%.2f", p.CostUSD)
+			}
+		}
+		if p.Runs > 1 {
+			line += fmt.Sprintf("  ×%d", p.Runs)
+		}
+		out = append(out, strings.TrimRight(line, " "))
+	}
+	return append(out, rule)
+}
+
+// Follow returns an event observer that feeds the board and prints it
+// whenever a phase starts on its own, so the board sits above that phase's
+// streamed output and prompts, and once more when the run ends. The board
+// is appended, never drawn over earlier output; while parallel phases run
+// it is not printed, so their streams aren't interleaved with it.
+func (b *Board) Follow(w io.Writer) func(dispatch.LogEvent) {
+	return func(ev dispatch.LogEvent) {
+		b.Observe(ev)
+		if (ev.Type == "phase_start" && b.Running() == 1) || ev.Type == "run_end" {
+			fmt.Fprintln(w)
+			b.Render(w)
+		}
+	}
+}
+
+// icon returns the status marker for a phase row.
+func icon(status string) string {
+	switch status {
+	case StatusRunning:
+		return ux.Cyan + "▶" + ux.Reset
+	case StatusOK:
+		return ux.Green + "✓" + ux.Reset
+	case StatusFailed:
+		return ux.Red + "✗" + ux.Reset
+	case StatusSkipped:
+		return ux.Dim + "–" + ux.Reset
+	}
+	return ux.Dim + "○" + ux.Reset
+}
+
+// duration formats d like the run summary: "<1s", "42s", or "3m 05s".
+func duration(d time.Duration) string {
+	if d < time.Second {
+		return "<1s"
+	}
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	return fmt.Sprintf("%dm %02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
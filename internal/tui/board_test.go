@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/dispatch"
+	"github.com/jorge-barreto/orc/internal/ux"
+	"github.com/jorge-barreto/orc/internal/ux/uxtest"
+)
+
+func testBoard() *Board {
+	cfg := &config.Config{Name: "build", Phases: []config.Phase{
+		{Name: "plan", Type: "agent"},
+		{Name: "implement", Type: "agent"},
+		{Name: "test", Type: "script"},
+		{Name: "docs", Type: "script"},
+	}}
+	return NewBoard(cfg, "T-1")
+}
+
+func statuses(b *Board) []string {
+	var out []string
+	for _, p := range b.Phases() {
+		out = append(out, p.Status)
+	}
+	return out
+}
+
+func TestBoard_ObserveUpdatesPhases(t *testing.T) {
+	b := testBoard()
+	if got := strings.Join(statuses(b), ","); got != "pending,pending,pending,pending" {
+		t.Fatalf("initial statuses = %s", got)
+	}
+
+	b.Observe(dispatch.LogEvent{Type: "phase_start", Phase: "plan", Index: 1})
+	if b.Running() != 1 || b.Phases()[0].Status != StatusRunning {
+		t.Fatalf("after phase_start: %v", statuses(b))
+	}
+	b.Observe(dispatch.LogEvent{Type: "phase_end", Phase: "plan", Index: 1, Status: "ok", DurationSecs: 12, CostUSD: 0.5})
+	b.Observe(dispatch.LogEvent{Type: "phase_start", Phase: "implement", Index: 2})
+	b.Observe(dispatch.LogEvent{Type: "phase_end", Phase: "implement", Index: 2, Status: "ok", DurationSecs: 30, CostUSD: 1.25})
+	b.Observe(dispatch.LogEvent{Type: "phase_start", Phase: "test", Index: 3})
+	b.Observe(dispatch.LogEvent{Type: "phase_end", Phase: "test", Index: 3, Status: "failed", DurationSecs: 3})
+
+	// A loop back to implement re-runs it and accumulates its totals.
+	b.Observe(dispatch.LogEvent{Type: "phase_start", Phase: "implement", Index: 2})
+	if got := b.Phases()[1]; got.Status != StatusRunning || got.Runs != 2 {
+		t.Fatalf("loop-back: implement = %+v", got)
+	}
+	b.Observe(dispatch.LogEvent{Type: "phase_end", Phase: "implement", Index: 2, Status: "ok", DurationSecs: 10, CostUSD: 0.25})
+	b.Observe(dispatch.LogEvent{Type: "phase_skip", Phase: "docs", Index: 4, Reason: "condition"})
+
+	if got := strings.Join(statuses(b), ","); got != "ok,ok,failed,skipped" {
+		t.Fatalf("statuses = %s", got)
+	}
+	impl := b.Phases()[1]
+	if impl.Duration != 40*time.Second || impl.CostUSD != 1.5 {
+		t.Errorf("implement totals = %v, $%.2f; want 40s, $1.50", impl.Duration, impl.CostUSD)
+	}
+	if b.cost != 2 {
+		t.Errorf("run cost = %.2f, want 2.00", b.cost)
+	}
+
+	// Events for phases the board doesn't list (a sub-workflow's) are ignored.
+	b.Observe(dispatch.LogEvent{Type: "phase_start", Phase: "other", Index: 1})
+	if b.Phases()[0].Status != StatusOK || b.Running() != 0 {
+		t.Errorf("foreign event changed the board: %v", statuses(b))
+	}
+}
+
+func TestBoard_Render(t *testing.T) {
+	uxtest.SaveState(t)
+	ux.DisableColor()
+	b := testBoard()
+	b.Observe(dispatch.LogEvent{Type: "phase_start", Phase: "plan", Index: 1})
+	b.Observe(dispatch.LogEvent{Type: "phase_end", Phase: "plan", Index: 1, Status: "ok", DurationSecs: 65, CostUSD: 0.5})
+	b.Observe(dispatch.LogEvent{Type: "phase_start", Phase: "implement", Index: 2})
+
+	var buf bytes.Buffer
+	b.Render(&buf)
+	out := buf.String()
+	for _, want := range []string{
+		"T-1 — build  running",
+		"$0.50\n",
+		"✓  1  plan                 agent     1m 05s  $0.50",
+		"▶  2  implement            agent\n",
+		"○  3  test                 script\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("board missing %q:\n%s", want, out)
+		}
+	}
+
+	b.Observe(dispatch.LogEvent{Type: "run_end", Status: "failed"})
+	buf.Reset()
+	b.Render(&buf)
+	if !strings.Contains(buf.String(), "T-1 — build  failed") {
+		t.Errorf("header after run_end:\n%s", buf.String())
+	}
+}
+
+func TestBoard_FollowPrintsOnSequentialPhaseStartAndRunEnd(t *testing.T) {
+	uxtest.SaveState(t)
+	ux.DisableColor()
+	b := testBoard()
+	var buf bytes.Buffer
+	follow := b.Follow(&buf)
+
+	follow(dispatch.LogEvent{Type: "phase_start", Phase: "plan", Index: 1})
+	if !strings.Contains(buf.String(), "▶  1  plan") {
+		t.Fatalf("phase_start did not print the board: %q", buf.String())
+	}
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("board cleared or moved the cursor: %q", buf.String())
+	}
+	follow(dispatch.LogEvent{Type: "phase_end", Phase: "plan", Index: 1, Status: "ok"})
+	// Two phases starting together (a parallel pair) print once.
+	buf.Reset()
+	follow(dispatch.LogEvent{Type: "phase_start", Phase: "implement", Index: 2})
+	follow(dispatch.LogEvent{Type: "phase_start", Phase: "test", Index: 3})
+	if n := strings.Count(buf.String(), "T-1 — build"); n != 1 {
+		t.Errorf("boards printed for a parallel start = %d, want 1", n)
+	}
+
+	buf.Reset()
+	follow(dispatch.LogEvent{Type: "run_end", Status: "failed"})
+	if !strings.Contains(buf.String(), "T-1 — build  failed") {
+		t.Errorf("run_end did not print the final board: %q", buf.String())
+	}
+}
+
+func TestBoard_OutputPaneFollowsPhases(t *testing.T) {
+	b := testBoard()
+	b.Observe(dispatch.LogEvent{Type: "phase_start", Phase: "plan", Index: 1})
+	b.Write([]byte("\033[1mplanning\033[0m\nstep 1\rstep 2\nhalf"))
+	title, lines := b.Output(10)
+	if title != "plan" || strings.Join(lines, "|") != "planning|step 2|half" {
+		t.Fatalf("pane = %q %q", title, lines)
+	}
+	if _, lines := b.Output(2); strings.Join(lines, "|") != "step 2|half" {
+		t.Errorf("last 2 lines = %q", lines)
+	}
+	b.Write([]byte(" done\n"))
+	b.Observe(dispatch.LogEvent{Type: "phase_end", Phase: "plan", Index: 1, Status: "ok"})
+
+	// The pane scrolls on into the next phase; a parallel partner joins the
+	// title.
+	b.Observe(dispatch.LogEvent{Type: "phase_start", Phase: "test", Index: 3})
+	b.Observe(dispatch.LogEvent{Type: "phase_start", Phase: "docs", Index: 4})
+	title, lines = b.Output(1)
+	if title != "test + docs" || strings.Join(lines, "|") != "half done" {
+		t.Errorf("pane after a parallel start = %q %q", title, lines)
+	}
+	b.Write([]byte("FAIL: TestX\n"))
+	b.Observe(dispatch.LogEvent{Type: "phase_end", Phase: "test", Index: 3, Status: "failed"})
+	b.Observe(dispatch.LogEvent{Type: "phase_end", Phase: "docs", Index: 4, Status: "ok"})
+
+	// Output after run_end (the run summary) is kept as written for Tail.
+	b.Observe(dispatch.LogEvent{Type: "run_end", Status: "failed"})
+	b.Write([]byte("\033[31mrun failed\033[0m\n"))
+	tail, after := b.Tail(2)
+	if strings.Join(tail, "|") != "half done|FAIL: TestX" || string(after) != "\033[31mrun failed\033[0m\n" {
+		t.Errorf("Tail = %q, %q", tail, after)
+	}
+}
+
+func TestBoard_Awaiting(t *testing.T) {
+	cfg := &config.Config{Name: "build", Phases: []config.Phase{
+		{Name: "plan", Type: "agent"},
+		{Name: "review", Type: "gate"},
+		{Name: "ship", Type: "gate", RequirePhrase: "ship it"},
+	}}
+	b := NewBoard(cfg, "T-1")
+	b.Observe(dispatch.LogEvent{Type: "phase_start", Phase: "plan", Index: 1})
+	if _, ok := b.Awaiting(); ok {
+		t.Fatal("an agent phase is not a gate awaiting an answer")
+	}
+	b.Observe(dispatch.LogEvent{Type: "phase_end", Phase: "plan", Index: 1, Status: "ok"})
+	b.Observe(dispatch.LogEvent{Type: "phase_start", Phase: "review", Index: 2})
+	if phrase, ok := b.Awaiting(); !ok || phrase != "" {
+		t.Errorf("review: Awaiting = %q, %v", phrase, ok)
+	}
+	b.Observe(dispatch.LogEvent{Type: "phase_end", Phase: "review", Index: 2, Status: "ok"})
+	b.Observe(dispatch.LogEvent{Type: "phase_start", Phase: "ship", Index: 3})
+	if phrase, ok := b.Awaiting(); !ok || phrase != "ship it" {
+		t.Errorf("ship: Awaiting = %q, %v", phrase, ok)
+	}
+}
+
+func TestFrame_FillsScreen(t *testing.T) {
+	uxtest.SaveState(t)
+	ux.DisableColor()
+	b := testBoard()
+	b.Observe(dispatch.LogEvent{Type: "phase_start", Phase: "plan", Index: 1})
+	b.Write([]byte("line 1\nline 2\n"))
+
+	lines := frame(b, 60, 24, "also fix the docs")
+	if len(lines) != 24 {
+		t.Fatalf("frame has %d lines, want 24:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	out := strings.Join(lines, "\n")
+	for _, want := range []string{"▶  1  plan", "output — plan", "line 2", "steer the agent", "> also fix the docs"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("frame missing %q:\n%s", want, out)
+		}
+	}
+	if lines[len(lines)-1] != "> also fix the docs" {
+		t.Errorf("input line = %q, want it last", lines[len(lines)-1])
+	}
+
+	// A short screen keeps the running phase's row in view.
+	b.Observe(dispatch.LogEvent{Type: "phase_end", Phase: "plan", Index: 1, Status: "ok"})
+	b.Observe(dispatch.LogEvent{Type: "phase_start", Phase: "docs", Index: 4})
+	if out := strings.Join(frame(b, 60, 8, ""), "\n"); !strings.Contains(out, "▶  4  docs") || strings.Contains(out, " 1  plan") {
+		t.Errorf("short frame should window around docs:\n%s", out)
+	}
+}
+
+func TestFit(t *testing.T) {
+	uxtest.SaveState(t)
+	ux.DisableColor()
+	if got := fit("\033[1mhello\033[0m world", 3); got != "\033[1mhel" {
+		t.Errorf("fit = %q", got)
+	}
+	if got := fit("héllo", 10); got != "héllo" {
+		t.Errorf("fit = %q", got)
+	}
+}
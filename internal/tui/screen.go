@@ -0,0 +1,333 @@
+package tui
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jorge-barreto/orc/internal/dispatch"
+	"github.com/jorge-barreto/orc/internal/ux"
+)
+
+// tailLines is how much of the last phase's output Stop prints back.
+const tailLines = 20
+
+// Screen is the full-screen --interactive-run view: the board on top, the
+// output pane below it, and an input line at the bottom. While it runs,
+// the process's stdout and stderr feed the pane and its stdin is fed from
+// the input line, so gate prompts and agent steering read what is typed
+// there without changes to the dispatchers. At a gate, y approves and a
+// approves every later gate, like typing y or ya.
+type Screen struct {
+	board *Board
+	tty   *os.File // the terminal: keys are read from it, frames drawn to it
+	saved string   // stty settings restored by Stop
+
+	stdin, stdout, stderr *os.File // the process's own, restored by Stop
+	inW, outW             *os.File
+	copied                chan struct{} // closed once the pane has read all output
+
+	mu     sync.Mutex
+	input  []rune
+	width  int
+	height int
+
+	dirty   chan struct{}
+	stop    chan struct{}
+	drawing sync.WaitGroup
+}
+
+// NewScreen returns a screen for board, or an error when stdin and stdout
+// aren't both a terminal orc can switch to unbuffered input.
+func NewScreen(board *Board) (*Screen, error) {
+	if !ux.IsTerminal(os.Stdin) || !ux.IsTerminal(os.Stdout) {
+		return nil, errors.New("stdin and stdout must be a terminal")
+	}
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := stty(tty, "-g")
+	if err != nil {
+		tty.Close()
+		return nil, err
+	}
+	return &Screen{board: board, tty: tty, saved: saved, dirty: make(chan struct{}, 1), stop: make(chan struct{})}, nil
+}
+
+// Start switches the terminal to the full-screen view and routes the
+// process's stdio through it until Stop.
+func (s *Screen) Start() error {
+	if _, err := stty(s.tty, "-icanon", "-echo", "min", "1", "time", "0"); err != nil {
+		return err
+	}
+	inR, inW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	s.stdin, s.stdout, s.stderr = os.Stdin, os.Stdout, os.Stderr
+	s.inW, s.outW = inW, outW
+	os.Stdin, os.Stdout, os.Stderr = inR, outW, outW
+	s.resize()
+	fmt.Fprint(s.tty, "\033[?1049h")
+
+	s.copied = make(chan struct{})
+	go func() {
+		defer close(s.copied)
+		io.Copy(writerFunc(func(p []byte) (int, error) {
+			n, err := s.board.Write(p)
+			s.redraw()
+			return n, err
+		}), outR)
+	}()
+	go s.readKeys()
+	s.drawing.Add(1)
+	go s.drawLoop()
+	return nil
+}
+
+// Observe feeds a runner event to the board and redraws.
+func (s *Screen) Observe(ev dispatch.LogEvent) {
+	s.board.Observe(ev)
+	s.redraw()
+}
+
+// Stop restores the process's stdio and the terminal, then prints the last
+// phase's output, the final board, and whatever the run printed after it
+// ended (its summary).
+func (s *Screen) Stop() {
+	os.Stdin, os.Stdout, os.Stderr = s.stdin, s.stdout, s.stderr
+	s.outW.Close()
+	s.inW.Close()
+	select {
+	case <-s.copied:
+	case <-time.After(time.Second): // a leftover child still holds the pipe
+	}
+	close(s.stop)
+	s.drawing.Wait()
+	fmt.Fprint(s.tty, "\033[?25h\033[?1049l")
+	stty(s.tty, s.saved)
+	s.tty.Close()
+
+	lines, after := s.board.Tail(tailLines)
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	fmt.Println()
+	s.board.Render(os.Stdout)
+	os.Stdout.Write(after)
+}
+
+// readKeys edits the input line from the terminal's keys. Enter sends the
+// line to the process's stdin; at a gate awaiting y, the y and a keys on an
+// empty line answer it at once.
+func (s *Screen) readKeys() {
+	in := bufio.NewReader(s.tty)
+	for {
+		r, _, err := in.ReadRune()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		var send string
+		switch {
+		case r == '\r' || r == '\n':
+			send = strings.TrimSpace(string(s.input))
+			s.input = nil
+		case r == 0x7f || r == '\b':
+			if len(s.input) > 0 {
+				s.input = s.input[:len(s.input)-1]
+			}
+		case r == 0x15: // Ctrl-U
+			s.input = nil
+		case r == 0x1b: // escape sequence (arrow keys): ignored
+			if next, _ := in.Peek(1); len(next) == 1 && next[0] == '[' {
+				in.ReadByte()
+				for {
+					c, err := in.ReadByte()
+					if err != nil || (c >= '@' && c <= '~') {
+						break
+					}
+				}
+			}
+		case r < ' ':
+		default:
+			if phrase, ok := s.board.Awaiting(); ok && phrase == "" && len(s.input) == 0 && (r == 'y' || r == 'a') {
+				send = map[rune]string{'y': "y", 'a': "ya"}[r]
+				break
+			}
+			s.input = append(s.input, r)
+		}
+		s.mu.Unlock()
+		if send != "" {
+			// Echo it into the pane, as the terminal would have.
+			s.board.Write([]byte(send + "\n"))
+			io.WriteString(s.inW, send+"\n")
+		}
+		s.redraw()
+	}
+}
+
+// redraw asks the draw loop for a frame without blocking.
+func (s *Screen) redraw() {
+	select {
+	case s.dirty <- struct{}{}:
+	default:
+	}
+}
+
+// drawLoop draws a frame whenever something changed, and every second so
+// the elapsed time keeps moving, at most every 50ms.
+func (s *Screen) drawLoop() {
+	defer s.drawing.Done()
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	defer signal.Stop(winch)
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-winch:
+			s.resize()
+		case <-s.dirty:
+		case <-tick.C:
+		}
+		s.draw()
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// resize reads the terminal size, keeping the last one if that fails.
+func (s *Screen) resize() {
+	out, err := stty(s.tty, "size")
+	var rows, cols int
+	if err == nil {
+		_, err = fmt.Sscan(out, &rows, &cols)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil && rows > 0 && cols > 0 {
+		s.height, s.width = rows, cols
+	} else if s.width == 0 {
+		s.height, s.width = 24, 80
+	}
+}
+
+// draw writes one frame over the whole screen and leaves the cursor at the
+// end of the input line.
+func (s *Screen) draw() {
+	s.mu.Lock()
+	width, height, input := s.width, s.height, string(s.input)
+	s.mu.Unlock()
+	lines := frame(s.board, width, height, input)
+	var buf bytes.Buffer
+	buf.WriteString("\033[?25l\033[H")
+	for i, line := range lines {
+		buf.WriteString(fit(line, width))
+		buf.WriteString("\033[K")
+		if i < len(lines)-1 {
+			buf.WriteString("\r\n")
+		}
+	}
+	buf.WriteString("\033[J")
+	col := min(utf8.RuneCountInString(input)+3, width)
+	fmt.Fprintf(&buf, "\033[%d;%dH\033[?25h", len(lines), col)
+	s.tty.Write(buf.Bytes())
+}
+
+// frame lays out a screen of height lines: the board, the output pane
+// filling the space between, a hint for what keys do now, and the input
+// line.
+func frame(b *Board, width, height int, input string) []string {
+	top := b.lines(max(3, height/2-4))
+	rule := ux.Dim + strings.Repeat("─", width) + ux.Reset
+	bottom := []string{rule, ux.Dim + hint(b) + ux.Reset, "> " + input}
+	paneHeight := height - len(top) - len(bottom) - 1
+	lines := top
+	if paneHeight > 0 {
+		title, out := b.Output(paneHeight)
+		if title == "" {
+			title = "waiting for the first phase"
+		}
+		lines = append(lines, ux.Dim+"output — "+title+ux.Reset)
+		lines = append(lines, out...)
+		for i := len(out); i < paneHeight; i++ {
+			lines = append(lines, "")
+		}
+	}
+	return append(lines, bottom...)
+}
+
+// hint describes what typing does in the board's current state.
+func hint(b *Board) string {
+	if phrase, ok := b.Awaiting(); ok {
+		if phrase != "" {
+			return fmt.Sprintf("gate: type %s and Enter to approve, or feedback and Enter to revise", phrase)
+		}
+		return "gate: y approve · a approve this and all later gates · or type feedback and Enter to revise"
+	}
+	for _, p := range b.Phases() {
+		if p.Status == StatusRunning && p.Type == "agent" {
+			return "type to steer the agent, Enter sends it after the turn · extend <minutes> pushes the timeout back · Ctrl-C interrupts"
+		}
+	}
+	return "Enter sends the line to the running phase · Ctrl-C interrupts"
+}
+
+// ansiPrefixRe matches an ANSI escape sequence at the start of a string.
+var ansiPrefixRe = regexp.MustCompile(`^\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+// fit cuts s to width visible characters, passing escape sequences through.
+func fit(s string, width int) string {
+	var out strings.Builder
+	n := 0
+	for s != "" {
+		if loc := ansiPrefixRe.FindStringIndex(s); loc != nil {
+			out.WriteString(s[:loc[1]])
+			s = s[loc[1]:]
+			continue
+		}
+		if n == width {
+			out.WriteString(ux.Reset)
+			break
+		}
+		r, size := utf8.DecodeRuneInString(s)
+		out.WriteRune(r)
+		n++
+		s = s[size:]
+	}
+	return out.String()
+}
+
+// stty runs stty with args against tty and returns its output.
+func stty(tty *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = tty
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("stty %s: %w", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// writerFunc adapts a function to io.Writer.
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
@@ -0,0 +1,53 @@
+package ux
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// ArtifactsUsage summarizes the disk space used by an artifacts directory.
+type ArtifactsUsage struct {
+	TotalBytes      int64
+	LargestLog      string // path relative to the artifacts dir; "" if no logs
+	LargestLogBytes int64
+}
+
+// MeasureArtifacts sums the size of every file under artifactsDir, including
+// archived history, and finds the largest log file. Unreadable entries are
+// skipped.
+func MeasureArtifacts(artifactsDir string) ArtifactsUsage {
+	var u ArtifactsUsage
+	filepath.WalkDir(artifactsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		u.TotalBytes += info.Size()
+		if strings.HasSuffix(d.Name(), ".log") && info.Size() > u.LargestLogBytes {
+			rel, _ := filepath.Rel(artifactsDir, path)
+			u.LargestLog = rel
+			u.LargestLogBytes = info.Size()
+		}
+		return nil
+	})
+	return u
+}
+
+// FormatBytes renders a byte count for display, e.g. "4.2 KB".
+func FormatBytes(n int64) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%d bytes", n)
+	case n < 1<<20:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	case n < 1<<30:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	default:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	}
+}
@@ -0,0 +1,56 @@
+package ux
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMeasureArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]int{
+		"plan.md":                         100,
+		"logs/phase-1.log":                300,
+		"logs/phase-2.log":                700,
+		"history/2026-01-01/logs/old.log": 50,
+		"history/2026-01-01/state.json":   25,
+		"prompts/phase-1.md":              10,
+	}
+	total := 0
+	for name, size := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		total += size
+	}
+
+	u := MeasureArtifacts(dir)
+	if u.TotalBytes != int64(total) {
+		t.Errorf("TotalBytes = %d, want %d", u.TotalBytes, total)
+	}
+	if u.LargestLog != filepath.Join("logs", "phase-2.log") || u.LargestLogBytes != 700 {
+		t.Errorf("largest log = %q (%d), want logs/phase-2.log (700)", u.LargestLog, u.LargestLogBytes)
+	}
+}
+
+func TestMeasureArtifacts_Missing(t *testing.T) {
+	u := MeasureArtifacts(filepath.Join(t.TempDir(), "nope"))
+	if u.TotalBytes != 0 || u.LargestLog != "" {
+		t.Errorf("expected zero usage, got %+v", u)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[int64]string{
+		512:     "512 bytes",
+		2048:    "2.0 KB",
+		5 << 20: "5.0 MB",
+		3 << 30: "3.0 GB",
+	} {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package ux
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/state"
+)
+
+// HistoricalTimings loads timing data from the completed runs archived for
+// every ticket next to artifactsDir (including its own history). Runs that
+// failed or were interrupted are ignored — their durations aren't
+// representative. Returns nil when no history exists.
+func HistoricalTimings(artifactsDir string) []*state.Timing {
+	dirs, _ := filepath.Glob(filepath.Join(filepath.Dir(artifactsDir), "*", "history", "*"))
+	var timings []*state.Timing
+	for _, dir := range dirs {
+		st, err := state.Load(dir)
+		if err != nil || st.GetStatus() != state.StatusCompleted {
+			continue
+		}
+		t, err := state.LoadTiming(dir)
+		if err != nil || len(t.Entries()) == 0 {
+			continue
+		}
+		timings = append(timings, t)
+	}
+	return timings
+}
+
+// EstimateRemaining estimates the time left in a run positioned at
+// phaseIdx, whose current phase has been running for currentElapsed. Each
+// phase's expected duration is its average total time (all loop iterations)
+// across the historical runs that include it. ok is false when history
+// covers none of the remaining phases.
+func EstimateRemaining(phases []config.Phase, phaseIdx int, currentElapsed time.Duration, history []*state.Timing) (remaining time.Duration, ok bool) {
+	expected := ExpectedDurations(history)
+	for i := phaseIdx; i < len(phases); i++ {
+		avg, known := expected[phases[i].Name]
+		if !known {
+			continue
+		}
+		if i == phaseIdx {
+			avg = max(avg-currentElapsed, 0)
+		}
+		remaining += avg
+		ok = true
+	}
+	return remaining, ok
+}
+
+// ExpectedDurations returns each phase's average total time (all loop
+// iterations) across the historical runs that include it.
+func ExpectedDurations(history []*state.Timing) map[string]time.Duration {
+	totals := make(map[string]time.Duration)
+	counts := make(map[string]int)
+	for _, t := range history {
+		perRun := make(map[string]time.Duration)
+		for _, e := range t.Entries() {
+			if e.Start.IsZero() || e.End.IsZero() {
+				continue
+			}
+			perRun[e.Phase] += e.End.Sub(e.Start)
+		}
+		for name, d := range perRun {
+			totals[name] += d
+			counts[name]++
+		}
+	}
+	expected := make(map[string]time.Duration, len(totals))
+	for name, total := range totals {
+		expected[name] = total / time.Duration(counts[name])
+	}
+	return expected
+}
+
+// longPhaseMin is the shortest expected duration marked as long.
+const longPhaseMin = time.Minute
+
+// LongPhases returns the phases expected to take far longer than the rest:
+// at least a minute, and at least twice the average expected duration of
+// the other phases with history.
+func LongPhases(phases []config.Phase, expected map[string]time.Duration) map[string]bool {
+	var total time.Duration
+	n := 0
+	for _, p := range phases {
+		if d, ok := expected[p.Name]; ok {
+			total += d
+			n++
+		}
+	}
+	long := make(map[string]bool)
+	if n < 2 {
+		return long
+	}
+	for _, p := range phases {
+		d, ok := expected[p.Name]
+		if !ok || d < longPhaseMin {
+			continue
+		}
+		if others := (total - d) / time.Duration(n-1); d >= 2*others {
+			long[p.Name] = true
+		}
+	}
+	return long
+}
+
+// formatMinutes renders an expected duration, e.g. "~12 min".
+func formatMinutes(d time.Duration) string {
+	if d < time.Minute {
+		return "<1 min"
+	}
+	return fmt.Sprintf("~%d min", int(math.Round(d.Minutes())))
+}
+
+// LongPhaseNote prints the dry-run line naming the phases past runs show
+// take far longer than the rest (see LongPhases). Nothing is printed when
+// there are none.
+func LongPhaseNote(phases []config.Phase, history []*state.Timing) {
+	expected := ExpectedDurations(history)
+	long := LongPhases(phases, expected)
+	var parts []string
+	for _, p := range phases {
+		if long[p.Name] {
+			parts = append(parts, fmt.Sprintf("%s (%s)", p.Name, formatMinutes(expected[p.Name])))
+		}
+	}
+	if len(parts) > 0 {
+		fmt.Printf("\n%sExpected long phases%s %s(from %d past runs)%s: %s\n", Bold, Reset, Dim, len(history), Reset, strings.Join(parts, ", "))
+	}
+}
+
+// FormatEstimate renders a remaining-time estimate, e.g. "est. 12 min remaining".
+func FormatEstimate(d time.Duration) string {
+	if d < time.Minute {
+		return "est. <1 min remaining"
+	}
+	return fmt.Sprintf("est. %d min remaining", int(math.Round(d.Minutes())))
+}
+
+// PromptEstimate is one agent phase's row in the dry-run cost estimate.
+type PromptEstimate struct {
+	Index   int // 0-based phase index
+	Name    string
+	Model   string
+	Bytes   int // rendered prompt size
+	Tokens  int
+	CostUSD float64
+	Err     error // prompt could not be rendered; row shows the error
+}
+
+// PromptCostEstimate prints the dry-run table of estimated prompt tokens and
+// input cost per agent phase, with a total.
+func PromptCostEstimate(rows []PromptEstimate) {
+	if len(rows) == 0 {
+		return
+	}
+	fmt.Printf("\n%sEstimated prompt cost%s %s(~4 chars/token, input only — output and tool use add more)%s\n", Bold, Reset, Dim, Reset)
+	var totalTokens int
+	var total float64
+	for _, r := range rows {
+		if r.Err != nil {
+			fmt.Printf("  %s%2d.%s %-20s %-7s %s%v%s\n", Cyan, r.Index+1, Reset, r.Name, r.Model, Yellow, r.Err, Reset)
+			continue
+		}
+		totalTokens += r.Tokens
+		total += r.CostUSD
+		fmt.Printf("  %s%2d.%s %-20s %-7s %10s  ~$%.4f\n", Cyan, r.Index+1, Reset, r.Name, r.Model, fmt.Sprintf("~%d tok", r.Tokens), r.CostUSD)
+	}
+	fmt.Printf("  %s    %-20s %-7s %10s  ~$%.4f%s\n", Bold, "total", "", fmt.Sprintf("~%d tok", totalTokens), total, Reset)
+}
+
+// RunPreview is the summary shown by run --confirm before anything executes.
+type RunPreview struct {
+	Workflow       string
+	Ticket         string
+	Phases         int      // enabled phases
+	CostUSD        float64  // estimated prompt input cost of the agent phases
+	EstimateErrors int      // agent phases whose prompt could not be rendered
+	Gates          []string // gate phases that will prompt
+}
+
+// ConfirmSummary prints the run --confirm preview.
+func ConfirmSummary(p RunPreview) {
+	fmt.Printf("%sAbout to run%s %s for %s\n", Bold, Reset, p.Workflow, p.Ticket)
+	fmt.Printf("  phases:          %d\n", p.Phases)
+	cost := fmt.Sprintf("~$%.4f %s(prompt input only)%s", p.CostUSD, Dim, Reset)
+	if p.EstimateErrors > 0 {
+		cost += fmt.Sprintf(" %s%d prompt(s) could not be rendered%s", Yellow, p.EstimateErrors, Reset)
+	}
+	fmt.Printf("  estimated cost:  %s\n", cost)
+	gates := "none"
+	if len(p.Gates) > 0 {
+		gates = strings.Join(p.Gates, ", ")
+	}
+	fmt.Printf("  gates to answer: %s\n", gates)
+}
+
+// ConditionPreview is one row of the dry-run condition plan.
+type ConditionPreview struct {
+	Index     int
+	Name      string
+	Condition string // empty when the phase has no condition
+	Disabled  bool
+	Run       bool // condition passed (or there is none)
+	TimedOut  bool
+}
+
+// ConditionPlan prints the dry-run --eval-conditions table: whether each
+// phase would run or be skipped given its condition's current result.
+func ConditionPlan(rows []ConditionPreview) {
+	if len(rows) == 0 {
+		return
+	}
+	fmt.Printf("\n%sCondition preview%s %s(conditions evaluated now; phases not executed)%s\n", Bold, Reset, Dim, Reset)
+	for _, r := range rows {
+		var verdict string
+		switch {
+		case r.Disabled:
+			verdict = Dim + "disabled" + Reset
+		case r.TimedOut:
+			verdict = Yellow + "would skip (condition timed out)" + Reset
+		case !r.Run:
+			verdict = Yellow + "would skip" + Reset
+		default:
+			verdict = Green + "would run" + Reset
+		}
+		cond := ""
+		if r.Condition != "" && !r.Disabled {
+			cond = fmt.Sprintf("  %s[%s]%s", Dim, r.Condition, Reset)
+		}
+		fmt.Printf("  %s%2d.%s %-20s %s%s\n", Cyan, r.Index+1, Reset, r.Name, verdict, cond)
+	}
+}
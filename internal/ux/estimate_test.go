@@ -0,0 +1,173 @@
+package ux
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/state"
+)
+
+// writeHistoryRun archives a run with the given per-phase durations under
+// artifactsRoot/<ticket>/history/<runID>.
+func writeHistoryRun(t *testing.T, artifactsRoot, ticket, runID, status string, durations map[string]time.Duration) {
+	t.Helper()
+	dir := filepath.Join(artifactsRoot, ticket, "history", runID)
+	if err := state.EnsureDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	st := &state.State{Status: status, Ticket: ticket}
+	if err := st.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	var entries []state.TimingEntry
+	for _, name := range []string{"plan", "implement", "review"} {
+		d, ok := durations[name]
+		if !ok {
+			continue
+		}
+		entries = append(entries, state.TimingEntry{Phase: name, Start: start, End: start.Add(d)})
+		start = start.Add(d)
+	}
+	if err := state.NewTiming(entries).Flush(dir); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEstimateRemaining_FromHistory(t *testing.T) {
+	root := t.TempDir()
+	writeHistoryRun(t, root, "T-1", "2026-01-01T09-00-00", state.StatusCompleted, map[string]time.Duration{
+		"plan": 4 * time.Minute, "implement": 20 * time.Minute, "review": 6 * time.Minute,
+	})
+	writeHistoryRun(t, root, "T-2", "2026-01-02T09-00-00", state.StatusCompleted, map[string]time.Duration{
+		"plan": 6 * time.Minute, "implement": 30 * time.Minute, "review": 4 * time.Minute,
+	})
+	// Failed runs are not representative and must be ignored.
+	writeHistoryRun(t, root, "T-2", "2026-01-03T09-00-00", state.StatusFailed, map[string]time.Duration{
+		"implement": 300 * time.Minute,
+	})
+
+	history := HistoricalTimings(filepath.Join(root, "T-3"))
+	if len(history) != 2 {
+		t.Fatalf("loaded %d historical runs, want 2", len(history))
+	}
+
+	phases := []config.Phase{{Name: "plan"}, {Name: "implement"}, {Name: "review"}}
+
+	// At implement, 5 minutes in: (25m avg - 5m) + 5m review avg.
+	est, ok := EstimateRemaining(phases, 1, 5*time.Minute, history)
+	if !ok {
+		t.Fatal("expected an estimate")
+	}
+	if est != 25*time.Minute {
+		t.Fatalf("estimate = %v, want 25m", est)
+	}
+	if got := FormatEstimate(est); got != "est. 25 min remaining" {
+		t.Fatalf("FormatEstimate = %q", got)
+	}
+}
+
+func TestEstimateRemaining_NoHistory(t *testing.T) {
+	phases := []config.Phase{{Name: "plan"}}
+	if _, ok := EstimateRemaining(phases, 0, 0, HistoricalTimings(filepath.Join(t.TempDir(), "T-1"))); ok {
+		t.Fatal("expected no estimate without history")
+	}
+}
+
+func TestPhaseHeader_ProgressAndETA(t *testing.T) {
+	root := t.TempDir()
+	writeHistoryRun(t, root, "T-1", "2026-01-01T09-00-00", state.StatusCompleted, map[string]time.Duration{
+		"plan": 4 * time.Minute, "implement": 20 * time.Minute, "review": 6 * time.Minute,
+	})
+	writeHistoryRun(t, root, "T-2", "2026-01-02T09-00-00", state.StatusCompleted, map[string]time.Duration{
+		"plan": 6 * time.Minute, "implement": 30 * time.Minute, "review": 4 * time.Minute,
+	})
+	history := HistoricalTimings(filepath.Join(root, "T-3"))
+	phases := []config.Phase{{Name: "plan", Type: "agent"}, {Name: "implement", Type: "agent"}, {Name: "review", Type: "agent"}}
+
+	out := captureOutput(func() { PhaseHeader(1, phases, history) })
+	if !strings.Contains(out, "Phase 2/3: implement") || !strings.Contains(out, "33%") {
+		t.Errorf("header missing phase or progress fraction:\n%s", out)
+	}
+	// implement (25m avg) + review (5m avg).
+	if !strings.Contains(out, "est. 30 min remaining") {
+		t.Errorf("header missing ETA:\n%s", out)
+	}
+
+	out = captureOutput(func() { PhaseHeader(1, phases, nil) })
+	if !strings.Contains(out, "33%") {
+		t.Errorf("header without history missing progress fraction:\n%s", out)
+	}
+	if strings.Contains(out, "remaining") {
+		t.Errorf("header without history should not show an ETA:\n%s", out)
+	}
+}
+
+func TestLongPhases_FromHistory(t *testing.T) {
+	root := t.TempDir()
+	writeHistoryRun(t, root, "T-1", "2026-01-01T09-00-00", state.StatusCompleted, map[string]time.Duration{
+		"plan": 4 * time.Minute, "implement": 20 * time.Minute, "review": 6 * time.Minute,
+	})
+	writeHistoryRun(t, root, "T-2", "2026-01-02T09-00-00", state.StatusCompleted, map[string]time.Duration{
+		"plan": 6 * time.Minute, "implement": 30 * time.Minute, "review": 4 * time.Minute,
+	})
+	history := HistoricalTimings(filepath.Join(root, "T-3"))
+	phases := []config.Phase{{Name: "plan", Type: "agent"}, {Name: "implement", Type: "agent"}, {Name: "review", Type: "agent"}, {Name: "ship", Type: "script"}}
+
+	expected := ExpectedDurations(history)
+	if expected["implement"] != 25*time.Minute || expected["plan"] != 5*time.Minute {
+		t.Fatalf("expected durations = %v", expected)
+	}
+	// implement averages 25m against 5m for the others; ship has no history.
+	long := LongPhases(phases, expected)
+	if len(long) != 1 || !long["implement"] {
+		t.Errorf("long phases = %v, want only implement", long)
+	}
+
+	out := captureOutput(func() { LongPhaseNote(phases, history) })
+	if !strings.Contains(out, "Expected long phases") || !strings.Contains(out, "from 2 past runs") || !strings.Contains(out, "implement (~25 min)") {
+		t.Errorf("note missing implement:\n%s", out)
+	}
+	if out := captureOutput(func() { LongPhaseNote(phases, nil) }); out != "" {
+		t.Errorf("note without history = %q, want nothing", out)
+	}
+
+	// Phases under a minute are never long, however they compare.
+	short := map[string]time.Duration{"plan": 2 * time.Second, "implement": 50 * time.Second}
+	if long := LongPhases(phases, short); len(long) != 0 {
+		t.Errorf("long phases under a minute = %v", long)
+	}
+}
+
+func TestRenderStatus_MarksExpectedLongPhases(t *testing.T) {
+	root := t.TempDir()
+	writeHistoryRun(t, root, "T-1", "2026-01-01T09-00-00", state.StatusCompleted, map[string]time.Duration{
+		"plan": 4 * time.Minute, "implement": 20 * time.Minute, "review": 6 * time.Minute,
+	})
+	artDir := filepath.Join(root, "T-2")
+	if err := state.EnsureDir(artDir); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Phases: []config.Phase{{Name: "plan", Type: "agent"}, {Name: "implement", Type: "agent"}, {Name: "review", Type: "agent"}}}
+	st := &state.State{PhaseIndex: 0, Ticket: "T-2", Status: state.StatusFailed}
+
+	out := captureOutput(func() { RenderStatus(cfg, st, artDir, t.TempDir()) })
+	var implLine, planLine string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, "implement") {
+			implLine = line
+		}
+		if strings.Contains(line, "plan") {
+			planLine = line
+		}
+	}
+	if !strings.Contains(implLine, "[expected long: ~20 min]") {
+		t.Errorf("implement not marked long: %q\n%s", implLine, out)
+	}
+	if strings.Contains(planLine, "expected long") {
+		t.Errorf("plan marked long: %q", planLine)
+	}
+}
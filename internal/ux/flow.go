@@ -83,13 +83,16 @@ func FlowDiagram(cfg *config.Config, customVars map[string]string, expandFn func
 		if p.Description != "" {
 			phaseLine += fmt.Sprintf(" — %s", p.Description)
 		}
+		if p.Disabled {
+			phaseLine += " (disabled)"
+		}
 		fmt.Printf("  %s%s\n", margin, phaseLine)
 
 		// Detail lines
 		detailMargin := buildDetailMargin(scopes, i)
 
 		// Script: run command
-		if p.Type == "script" && p.Run != "" {
+		if (p.Type == "script" || p.Type == "publish") && p.Run != "" {
 			expanded := expandFn(p.Run)
 			if len(expanded) > 60 {
 				expanded = expanded[:57] + "..."
@@ -125,7 +128,7 @@ func FlowDiagram(cfg *config.Config, customVars map[string]string, expandFn func
 
 		// Outputs
 		if len(p.Outputs) > 0 {
-			fmt.Printf("  %s  outputs: %s\n", detailMargin, strings.Join(p.Outputs, ", "))
+			fmt.Printf("  %s  outputs: %s\n", detailMargin, strings.Join(cfg.OutputPaths(p), ", "))
 		}
 
 		// Condition
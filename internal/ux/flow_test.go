@@ -258,3 +258,24 @@ func TestFlowDiagram_VarsAndMaxCost(t *testing.T) {
 		t.Errorf("SRC should appear before WORKTREE (alphabetical)\nfull output:\n%s", output)
 	}
 }
+
+func TestFlowDiagram_DisabledPhase(t *testing.T) {
+	cfg := &config.Config{
+		Name: "disabled",
+		Phases: []config.Phase{
+			{Name: "plan", Type: "agent", Model: "opus", Timeout: 30},
+			{Name: "lint", Type: "script", Run: "make lint", Disabled: true},
+		},
+	}
+
+	output := captureOutput(func() {
+		FlowDiagram(cfg, nil, nil)
+	})
+
+	if !strings.Contains(output, "2. lint [script] (disabled)") {
+		t.Errorf("output missing disabled marker\nfull output:\n%s", output)
+	}
+	if strings.Contains(output, "1. plan [agent/opus] (disabled)") {
+		t.Errorf("enabled phase marked disabled\nfull output:\n%s", output)
+	}
+}
@@ -138,6 +138,8 @@ func phaseIcon(phaseType string, c flowColors) string {
 		return c.scriptIcon + "▸" + c.reset
 	case "gate":
 		return c.gateIcon + "⏸" + c.reset
+	case "manual":
+		return c.gateIcon + "☐" + c.reset
 	case "workflow":
 		return c.agentIcon + "⊞" + c.reset
 	case "branch":
@@ -175,7 +177,7 @@ func FlowViz(cfg *config.Config) {
 	fmt.Printf("  %sorc%s · %s%s%s\n", c.bold, c.reset, c.projectName, cfg.Name, c.reset)
 
 	// Stats
-	var agents, scripts, gates, workflows, branches, loops int
+	var agents, scripts, gates, manuals, workflows, branches, loops int
 	for _, p := range cfg.Phases {
 		switch p.Type {
 		case "agent":
@@ -184,6 +186,8 @@ func FlowViz(cfg *config.Config) {
 			scripts++
 		case "gate":
 			gates++
+		case "manual":
+			manuals++
 		case "workflow":
 			workflows++
 		case "branch":
@@ -204,6 +208,9 @@ func FlowViz(cfg *config.Config) {
 	if gates > 0 {
 		statParts = append(statParts, pluralize(gates, "gate"))
 	}
+	if manuals > 0 {
+		statParts = append(statParts, pluralize(manuals, "manual step"))
+	}
 	if workflows > 0 {
 		statParts = append(statParts, pluralize(workflows, "workflow"))
 	}
@@ -286,7 +293,7 @@ func FlowViz(cfg *config.Config) {
 
 		// Line 3: Outputs
 		if len(p.Outputs) > 0 {
-			fmt.Printf("%s%s→ %s%s\n", indent, c.outputs, strings.Join(p.Outputs, "  "), c.reset)
+			fmt.Printf("%s%s→ %s%s\n", indent, c.outputs, strings.Join(cfg.OutputPaths(p), "  "), c.reset)
 		}
 
 		// Line 4: Loop-back annotation
@@ -0,0 +1,51 @@
+package ux
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jorge-barreto/orc/internal/config"
+)
+
+// RenderPhaseList prints a one-line-per-phase reference table of cfg's
+// phases. cfg should already be validated so defaulted settings (model,
+// timeout) show their effective values.
+func RenderPhaseList(cfg *config.Config) {
+	fmt.Printf("%s%3s  %-20s %-9s %-7s %-8s %-24s %-14s %s%s\n",
+		Bold, "#", "Phase", "Type", "Model", "Timeout", "Outputs", "On fail", "Notes", Reset)
+	for i, p := range cfg.Phases {
+		model := "—"
+		if p.Type == "agent" {
+			model = p.Model
+		}
+		timeout := "—"
+		if p.Timeout > 0 {
+			timeout = fmt.Sprintf("%dm", p.Timeout)
+		}
+		outputs := "—"
+		if len(p.Outputs) > 0 {
+			outputs = strings.Join(cfg.OutputPaths(p), ", ")
+		}
+		onFail := "—"
+		if p.Loop != nil {
+			onFail = fmt.Sprintf("→ %s (max %d)", p.Loop.Goto, p.Loop.Max)
+		}
+		fmt.Printf("%s%3d.%s %-20s %-9s %-7s %-8s %-24s %-14s %s\n",
+			Cyan, i+1, Reset, p.Name, p.Type, model, timeout, outputs, onFail, phaseListNotes(p))
+	}
+}
+
+// phaseListNotes returns the markers shown in the list's Notes column.
+func phaseListNotes(p config.Phase) string {
+	var notes []string
+	if p.ParallelWith != "" {
+		notes = append(notes, "parallel with "+p.ParallelWith)
+	}
+	if p.Condition != "" {
+		notes = append(notes, "conditional")
+	}
+	if p.Disabled {
+		notes = append(notes, "disabled")
+	}
+	return Dim + strings.Join(notes, ", ") + Reset
+}
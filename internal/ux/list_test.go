@@ -0,0 +1,50 @@
+package ux
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jorge-barreto/orc/internal/config"
+)
+
+func TestRenderPhaseList_ShowsResolvedDefaults(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Name: "test",
+		Phases: []config.Phase{
+			{Name: "plan", Type: "script", Run: "echo"},
+			{Name: "implement", Type: "agent", Prompt: "implement.md", Outputs: []string{"summary.md"}},
+			{Name: "review", Type: "script", Run: "true", Condition: "true",
+				Loop: &config.Loop{Goto: "implement", Max: 3}},
+		},
+	}
+	if err := os.WriteFile(filepath.Join(dir, "implement.md"), []byte("do it"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Validate(cfg, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureOutput(func() { RenderPhaseList(cfg) })
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("want header + 3 rows, got %d lines:\n%s", len(lines), out)
+	}
+	if cfg.Phases[1].Model == "" {
+		t.Fatal("validation did not resolve a default model")
+	}
+	agent := lines[2]
+	for _, want := range []string{"implement", "agent", cfg.Phases[1].Model, "30m", "summary.md"} {
+		if !strings.Contains(agent, want) {
+			t.Errorf("agent row %q missing %q", agent, want)
+		}
+	}
+	if !strings.Contains(lines[1], "10m") {
+		t.Errorf("script row %q missing resolved 10m timeout", lines[1])
+	}
+	if !strings.Contains(lines[3], "→ implement (max 3)") || !strings.Contains(lines[3], "conditional") {
+		t.Errorf("review row %q missing loop target or condition marker", lines[3])
+	}
+}
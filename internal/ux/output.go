@@ -1,14 +1,18 @@
 package ux
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jorge-barreto/orc/internal/config"
+	"github.com/jorge-barreto/orc/internal/state"
 )
 
 // ANSI color helpers
@@ -32,6 +36,38 @@ var QuietMode bool
 
 var quietMu sync.Mutex
 
+// CompactMode prints one line per phase ("[3/8] test ... ok (0m 04s)")
+// instead of headers, and suppresses streamed phase output on the terminal.
+// QuietMode takes precedence.
+var CompactMode bool
+
+// warnings counts the warnings printed by Warnf in this process, including
+// those of earlier --workflow-retries attempts.
+var warnings atomic.Int64
+
+// Warnf prints a warning to stderr — format carries its own "warning: "
+// prefix and trailing newline — and counts it, so the end-of-run banner can
+// point at orc doctor.
+func Warnf(format string, a ...any) {
+	warnings.Add(1)
+	fmt.Fprintf(os.Stderr, format, a...)
+}
+
+// Warnings reports how many warnings Warnf has printed.
+func Warnings() int64 {
+	return warnings.Load()
+}
+
+// PrefixOutput prefixes every line of streamed phase output with
+// "[phase-name] " so captured logs show which phase printed what.
+var PrefixOutput bool
+
+// RawStream passes each streamed agent text delta to the terminal as it
+// arrives. By default StreamOutput holds partial lines and writes whole
+// lines, which some terminals render more smoothly than a burst of tiny
+// writes.
+var RawStream bool
+
 // IsTerminal reports whether the given file is a terminal.
 // It is a var so tests can override it to control the TTY check.
 var IsTerminal = func(f *os.File) bool {
@@ -64,6 +100,130 @@ func EnableQuiet() {
 	DisableColor()
 }
 
+// EnableCompact activates the one-line-per-phase progress mode.
+func EnableCompact() {
+	CompactMode = true
+}
+
+// PhaseOutput returns where a running phase's streamed output is shown:
+// os.Stdout normally, io.Discard in compact mode. With PrefixOutput each line
+// is prefixed with the phase name. Phase logs are unaffected.
+func PhaseOutput(phase string) io.Writer {
+	if CompactMode && !QuietMode {
+		return io.Discard
+	}
+	return prefixed(os.Stdout, phase)
+}
+
+// PhaseErrOutput is PhaseOutput for a phase's stderr, which is always shown.
+func PhaseErrOutput(phase string) io.Writer {
+	return prefixed(os.Stderr, phase)
+}
+
+// StreamOutput is PhaseOutput for an agent's streamed text. Unless RawStream
+// is set, output is line-buffered: complete lines are written as they form
+// and the caller flushes a trailing partial line with (*LineWriter).Flush.
+func StreamOutput(phase string) io.Writer {
+	w := PhaseOutput(phase)
+	if RawStream || w == io.Discard {
+		return w
+	}
+	return NewLineWriter(w)
+}
+
+// LineWriter buffers writes and passes them on only up to the last newline,
+// so the underlying writer sees whole lines. Flush writes whatever remains.
+type LineWriter struct {
+	mu  sync.Mutex
+	w   io.Writer
+	buf []byte
+}
+
+// NewLineWriter returns a LineWriter over w.
+func NewLineWriter(w io.Writer) *LineWriter {
+	return &LineWriter{w: w}
+}
+
+func (l *LineWriter) Write(b []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buf = append(l.buf, b...)
+	i := bytes.LastIndexByte(l.buf, '\n')
+	if i < 0 {
+		return len(b), nil
+	}
+	_, err := l.w.Write(l.buf[:i+1])
+	l.buf = append(l.buf[:0], l.buf[i+1:]...)
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Flush writes any buffered partial line.
+func (l *LineWriter) Flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.buf) == 0 {
+		return nil
+	}
+	_, err := l.w.Write(l.buf)
+	l.buf = l.buf[:0]
+	return err
+}
+
+func prefixed(w io.Writer, phase string) io.Writer {
+	if !PrefixOutput {
+		return w
+	}
+	return &prefixWriter{w: w, prefix: []byte("[" + phase + "] "), atLineStart: true}
+}
+
+// prefixWriter writes prefix at the start of every line passed through it.
+// Partial lines are written immediately, so streamed output isn't delayed.
+type prefixWriter struct {
+	mu          sync.Mutex
+	w           io.Writer
+	prefix      []byte
+	atLineStart bool
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := len(b)
+	out := make([]byte, 0, n+len(p.prefix))
+	for len(b) > 0 {
+		if p.atLineStart {
+			out = append(out, p.prefix...)
+			p.atLineStart = false
+		}
+		i := bytes.IndexByte(b, '\n')
+		if i < 0 {
+			out = append(out, b...)
+			break
+		}
+		out = append(out, b[:i+1]...)
+		b = b[i+1:]
+		p.atLineStart = true
+	}
+	if _, err := p.w.Write(out); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// compactLine prints a phase's single compact-mode line. On a terminal,
+// PhaseHeader has already written "[i/N] name ..." without a newline, so the
+// line is redrawn in place.
+func compactLine(index, total int, phaseName, outcome string) {
+	clear := ""
+	if IsTerminal(os.Stdout) {
+		clear = "\r\033[K"
+	}
+	fmt.Printf("%s[%d/%d] %s ... %s\n", clear, index+1, total, phaseName, outcome)
+}
+
 // QuietPhaseEvent emits a single JSON line for a phase transition.
 // extra keys are merged into the event object.
 func QuietPhaseEvent(phase string, status string, extra map[string]interface{}) {
@@ -87,42 +247,64 @@ func timestamp() string {
 	return time.Now().Format("15:04:05")
 }
 
-// PhaseHeader prints a timestamped phase header.
-func PhaseHeader(index, total int, phase config.Phase) {
+// PhaseHeader prints a timestamped header for phases[index] with the run's
+// progress so far and, when history has timings for the remaining phases,
+// an estimate of the time left (this phase included).
+func PhaseHeader(index int, phases []config.Phase, history []*state.Timing) {
+	phase := phases[index]
 	if QuietMode {
 		QuietPhaseEvent(phase.Name, "started", nil)
 		return
 	}
+	total := len(phases)
+	if CompactMode {
+		if IsTerminal(os.Stdout) {
+			fmt.Printf("\r\033[K[%d/%d] %s ...", index+1, total, phase.Name)
+		}
+		return
+	}
 	fmt.Printf("\n%s[%s]%s %s══════════════════════════════════════%s\n",
 		Dim, timestamp(), Reset, Cyan, Reset)
 	desc := ""
 	if phase.Description != "" {
 		desc = fmt.Sprintf(" — %s", phase.Description)
 	}
-	fmt.Printf("%s[%s]%s  %sPhase %d/%d: %s (%s)%s%s\n",
-		Dim, timestamp(), Reset, Bold, index+1, total, phase.Name, phase.Type, desc, Reset)
+	progress := fmt.Sprintf("%d%%", index*100/total)
+	if est, ok := EstimateRemaining(phases, index, 0, history); ok {
+		progress += " · " + FormatEstimate(est)
+	}
+	fmt.Printf("%s[%s]%s  %sPhase %d/%d: %s (%s)%s%s  %s%s%s\n",
+		Dim, timestamp(), Reset, Bold, index+1, total, phase.Name, phase.Type, desc, Reset, Dim, progress, Reset)
 	fmt.Printf("%s[%s]%s %s══════════════════════════════════════%s\n",
 		Dim, timestamp(), Reset, Cyan, Reset)
 }
 
 // PhaseComplete prints a phase completion message.
-func PhaseComplete(index int, phaseName string, duration time.Duration) {
+func PhaseComplete(index, total int, phaseName string, duration time.Duration) {
 	if QuietMode {
 		QuietPhaseEvent(phaseName, "complete", map[string]interface{}{"duration_s": duration.Seconds()})
 		return
 	}
 	m := int(duration.Minutes())
 	s := int(duration.Seconds()) % 60
+	if CompactMode {
+		compactLine(index, total, phaseName, fmt.Sprintf("%sok%s (%dm %02ds)", Green, Reset, m, s))
+		return
+	}
 	fmt.Printf("%s[%s]%s  %s✓ Phase %d complete (%dm %02ds)%s\n",
 		Dim, timestamp(), Reset, Green, index+1, m, s, Reset)
 }
 
 // PhaseFail prints a phase failure message.
-func PhaseFail(index int, phaseName, errMsg string) {
+func PhaseFail(index, total int, phaseName, errMsg string) {
 	if QuietMode {
 		QuietPhaseEvent(phaseName, "failed", map[string]interface{}{"error": errMsg})
 		return
 	}
+	if CompactMode {
+		compactLine(index, total, phaseName, fmt.Sprintf("%sfailed%s: %s", Red, Reset, errMsg))
+		return
+	}
 	fmt.Printf("%s[%s]%s  %s✗ Phase %d (%s) failed: %s%s\n",
 		Dim, timestamp(), Reset, Red, index+1, phaseName, errMsg, Reset)
 }
@@ -150,6 +332,17 @@ func LoopBack(fromPhase, toPhase string, iteration, max int) {
 		Dim, timestamp(), Reset, Yellow, fromPhase, iteration, max, toPhase, Reset)
 }
 
+// GateRejected prints a message for a rejected gate whose on-reject lets
+// the run go on to another phase.
+func GateRejected(phaseName, nextPhase string) {
+	if QuietMode {
+		QuietPhaseEvent(phaseName, "rejected", map[string]interface{}{"next": nextPhase})
+		return
+	}
+	fmt.Printf("%s[%s]%s  %s↷ Gate %q rejected — feedback saved, continuing at %q%s\n",
+		Dim, timestamp(), Reset, Yellow, phaseName, nextPhase, Reset)
+}
+
 // LoopExhausted prints a message when a loop has exhausted its max iterations.
 func LoopExhausted(phaseName string, iteration int) {
 	if QuietMode {
@@ -160,19 +353,38 @@ func LoopExhausted(phaseName string, iteration int) {
 		Dim, timestamp(), Reset, Red, phaseName, iteration, Reset)
 }
 
-// PhaseSkip prints a phase skip message (condition not met).
-func PhaseSkip(index int, phaseName string) {
+// PhaseSkip prints a phase skip message with the reason it was skipped
+// (e.g. "condition not met").
+func PhaseSkip(index, total int, phaseName, reason string) {
+	if QuietMode {
+		QuietPhaseEvent(phaseName, "skipped", map[string]interface{}{"reason": reason})
+		return
+	}
+	if CompactMode {
+		compactLine(index, total, phaseName, "skipped ("+reason+")")
+		return
+	}
+	fmt.Printf("%s[%s]%s  %s– Phase %d (%s) skipped (%s)%s\n",
+		Dim, timestamp(), Reset, Dim, index+1, phaseName, reason, Reset)
+}
+
+// PhaseDisabled prints a message for a phase skipped via disabled: true.
+func PhaseDisabled(index, total int, phaseName string) {
 	if QuietMode {
-		QuietPhaseEvent(phaseName, "skipped", nil)
+		QuietPhaseEvent(phaseName, "disabled", nil)
+		return
+	}
+	if CompactMode {
+		compactLine(index, total, phaseName, "disabled")
 		return
 	}
-	fmt.Printf("%s[%s]%s  %s– Phase %d (%s) skipped (condition not met)%s\n",
+	fmt.Printf("%s[%s]%s  %s– Phase %d (%s) disabled%s\n",
 		Dim, timestamp(), Reset, Dim, index+1, phaseName, Reset)
 }
 
 // ToolUse prints an inline tool call.
 func ToolUse(name, input string) {
-	if QuietMode {
+	if QuietMode || CompactMode {
 		return
 	}
 	fmt.Printf("  %s⚡ %s%s %s\n", Cyan, name, Reset, input)
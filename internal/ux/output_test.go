@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/jorge-barreto/orc/internal/config"
 )
@@ -201,7 +202,7 @@ func TestPhaseHeader_QuietMode_EmitsJSONLine(t *testing.T) {
 	QuietMode = true
 
 	out := captureOutput(func() {
-		PhaseHeader(0, 3, config.Phase{Name: "plan", Type: "agent"})
+		PhaseHeader(0, []config.Phase{{Name: "plan", Type: "agent"}, {Name: "b"}, {Name: "c"}}, nil)
 	})
 	out = strings.TrimSpace(out)
 	var event map[string]interface{}
@@ -215,3 +216,41 @@ func TestPhaseHeader_QuietMode_EmitsJSONLine(t *testing.T) {
 		t.Errorf("status = %v, want \"started\"", event["status"])
 	}
 }
+
+func TestCompactMode_OneLinePerPhase(t *testing.T) {
+	origCompact, origTerm, origGreen, origRed, origReset := CompactMode, IsTerminal, Green, Red, Reset
+	t.Cleanup(func() {
+		CompactMode, IsTerminal, Green, Red, Reset = origCompact, origTerm, origGreen, origRed, origReset
+	})
+	CompactMode = true
+	IsTerminal = func(*os.File) bool { return false }
+	Green, Red, Reset = "", "", ""
+
+	phases := []config.Phase{{Name: "plan", Type: "agent"}, {Name: "lint", Type: "script"}, {Name: "test", Type: "script"}}
+	out := captureOutput(func() {
+		PhaseHeader(0, phases, nil)
+		ToolUse("Read", "main.go")
+		PhaseComplete(0, len(phases), "plan", 64*time.Second)
+		PhaseSkip(1, len(phases), "lint", "condition not met")
+		PhaseHeader(2, phases, nil)
+		PhaseFail(2, len(phases), "test", "exit code 1")
+	})
+
+	want := []string{
+		"[1/3] plan ... ok (1m 04s)",
+		"[2/3] lint ... skipped (condition not met)",
+		"[3/3] test ... failed: exit code 1",
+	}
+	got := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(got) != len(want) {
+		t.Fatalf("expected %d lines, got %d:\n%s", len(want), len(got), out)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i+1, got[i], want[i])
+		}
+	}
+	if PhaseOutput("test") != io.Discard {
+		t.Error("compact mode should discard streamed phase output")
+	}
+}
@@ -74,16 +74,24 @@ func RenderStatus(cfg *config.Config, st *state.State, artifactsDir, auditDir st
 			fmt.Printf("%sElapsed:%s %s\n", Bold, Reset, state.FormatDuration(elapsed))
 		}
 	}
+	var currentElapsed time.Duration
 	if st.GetStatus() == state.StatusRunning && timing != nil {
 		runEntries := timing.Entries()
 		for i := len(runEntries) - 1; i >= 0; i-- {
 			te := runEntries[i]
 			if te.End.IsZero() && !te.Start.IsZero() {
-				fmt.Printf("%sRunning:%s %s (%s)\n", Bold, Reset, te.Phase, state.FormatDuration(time.Since(te.Start)))
+				currentElapsed = time.Since(te.Start)
+				fmt.Printf("%sRunning:%s %s (%s)\n", Bold, Reset, te.Phase, state.FormatDuration(currentElapsed))
 				break
 			}
 		}
 	}
+	history := HistoricalTimings(artifactsDir)
+	if st.GetPhaseIndex() < len(cfg.Phases) && st.GetStatus() != state.StatusCompleted {
+		if est, ok := EstimateRemaining(cfg.Phases, st.GetPhaseIndex(), currentElapsed, history); ok {
+			fmt.Printf("%sEstimate:%s %s\n", Bold, Reset, FormatEstimate(est))
+		}
+	}
 	if len(loopCounts) > 0 {
 		var parts []string
 		for _, p := range cfg.Phases {
@@ -172,9 +180,27 @@ func RenderStatus(cfg *config.Config, st *state.State, artifactsDir, auditDir st
 		}
 	}
 
+	// Skipped phases and why, from the run's run-result.json
+	if result, err := state.LoadRunResult(artifactsDir); err == nil {
+		var skipped []state.PhaseResult
+		for _, p := range result.Phases {
+			if p.Status == state.PhaseStatusSkipped && p.SkipReason != "" {
+				skipped = append(skipped, p)
+			}
+		}
+		if len(skipped) > 0 {
+			fmt.Printf("\n%sSkipped:%s\n", Bold, Reset)
+			for _, p := range skipped {
+				fmt.Printf("  %-20s %s%s%s\n", p.Name, Dim, p.SkipReason, Reset)
+			}
+		}
+	}
+
 	// Remaining phases
 	if st.GetPhaseIndex() < len(cfg.Phases) {
 		fmt.Printf("\n%sRemaining:%s\n", Bold, Reset)
+		expected := ExpectedDurations(history)
+		long := LongPhases(cfg.Phases, expected)
 		for i := st.GetPhaseIndex(); i < len(cfg.Phases); i++ {
 			p := cfg.Phases[i]
 			marker := "  "
@@ -182,13 +208,18 @@ func RenderStatus(cfg *config.Config, st *state.State, artifactsDir, auditDir st
 				marker = fmt.Sprintf("%s→%s ", Yellow, Reset)
 			}
 			loopInfo := ""
-			if p.Loop != nil {
+			if p.Disabled {
+				loopInfo = fmt.Sprintf(" %s[disabled]%s", Dim, Reset)
+			} else if p.Loop != nil {
 				if count, ok := loopCounts[p.Name]; ok {
 					loopInfo = fmt.Sprintf(" %s[iter %d/%d]%s", Dim, count, p.Loop.Max, Reset)
 				} else {
 					loopInfo = fmt.Sprintf(" %s[loop: max %d]%s", Dim, p.Loop.Max, Reset)
 				}
 			}
+			if long[p.Name] {
+				loopInfo += fmt.Sprintf(" %s[expected long: %s]%s", Yellow, formatMinutes(expected[p.Name]), Reset)
+			}
 			fmt.Printf("  %s%s%d%s  %-20s %s(%s)%s%s\n",
 				marker, Dim, i+1, Reset, p.Name, Dim, p.Type, Reset, loopInfo)
 		}
@@ -201,7 +232,16 @@ func RenderStatus(cfg *config.Config, st *state.State, artifactsDir, auditDir st
 		fmt.Printf("  %s(none)%s\n", Dim, Reset)
 		return
 	}
+	usage := MeasureArtifacts(artifactsDir)
+	sizeLine := fmt.Sprintf("Total size: %s", FormatBytes(usage.TotalBytes))
+	if usage.LargestLog != "" {
+		sizeLine += fmt.Sprintf(", largest log: %s (%s)", usage.LargestLog, FormatBytes(usage.LargestLogBytes))
+	}
+	fmt.Printf("  %s%s%s\n", Dim, sizeLine, Reset)
 	for _, e := range entries {
+		if e.Name() == ".gitignore" {
+			continue
+		}
 		if e.IsDir() {
 			subEntries, _ := os.ReadDir(filepath.Join(artifactsDir, e.Name()))
 			if len(subEntries) > 0 {
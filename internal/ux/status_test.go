@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -404,6 +405,35 @@ func TestRenderStatus(t *testing.T) {
 			wantContains:    []string{"Progress:", "100%"},
 			wantNotContains: []string{"░"},
 		},
+		{
+			name: "artifacts size and largest log",
+			cfg:  &config.Config{Phases: []config.Phase{{Name: "plan", Type: "agent"}}},
+			st:   &state.State{PhaseIndex: 0, Ticket: "SIZE-1", Status: state.StatusRunning},
+			setupArt: func(t *testing.T, dir string) {
+				os.MkdirAll(filepath.Join(dir, "logs"), 0755)
+				os.WriteFile(filepath.Join(dir, "logs", "phase-1.log"), make([]byte, 2048), 0644)
+				os.WriteFile(filepath.Join(dir, "plan.md"), make([]byte, 1024), 0644)
+			},
+			wantContains: []string{"Total size: 3.0 KB", "largest log: " + filepath.Join("logs", "phase-1.log") + " (2.0 KB)"},
+		},
+		{
+			name: "skipped phases show their reason",
+			cfg:  &config.Config{Phases: []config.Phase{{Name: "docs", Type: "script"}, {Name: "test", Type: "script"}}},
+			st:   &state.State{PhaseIndex: 2, Ticket: "SKIP-1", Status: state.StatusCompleted},
+			setupArt: func(t *testing.T, dir string) {
+				if err := state.WriteRunResult(dir, &state.RunResult{Phases: []state.PhaseResult{
+					{Name: "docs", Status: state.PhaseStatusSkipped, SkipReason: "condition not met"},
+					{Name: "test", Status: state.PhaseStatusCompleted},
+				}}); err != nil {
+					t.Fatal(err)
+				}
+			},
+			customAssert: func(t *testing.T, out string) {
+				if !regexp.MustCompile(`Skipped:\S*\n\s+docs\s+\S*condition not met`).MatchString(out) {
+					t.Errorf("expected docs listed under Skipped: with its reason:\n%s", out)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
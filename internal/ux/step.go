@@ -59,7 +59,7 @@ func listStepArtifacts(artifactsDir string, phaseIdx int) []string {
 	var result []string
 	if err == nil {
 		for _, e := range entries {
-			if !e.IsDir() {
+			if !e.IsDir() && e.Name() != ".gitignore" {
 				result = append(result, e.Name())
 			}
 		}
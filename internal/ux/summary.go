@@ -15,6 +15,7 @@ type phaseOutcome struct {
 	runs     int
 	duration time.Duration
 	result   string // "pass", "FAIL", "skip"
+	group    string // phase's config group; "" when ungrouped
 }
 
 func fmtDuration(d time.Duration) string {
@@ -57,7 +58,7 @@ func lastDuration(timing *state.Timing, phaseName string) time.Duration {
 }
 
 // RunSummary prints the run summary table showing phase outcomes.
-func RunSummary(phases []config.Phase, timing *state.Timing, failedPhase int, skipped map[string]bool) {
+func RunSummary(phases []config.Phase, timing *state.Timing, failedPhase int, skipped map[string]string) {
 	if QuietMode {
 		return
 	}
@@ -70,16 +71,20 @@ func RunSummary(phases []config.Phase, timing *state.Timing, failedPhase int, sk
 	var totalDuration, agentDuration, scriptDuration time.Duration
 
 	for i := 0; i <= lastIdx; i++ {
+		if phases[i].Disabled {
+			continue
+		}
 		name := phases[i].Name
 		runs := countRuns(timing, name)
 
-		if skipped[name] {
+		if _, ok := skipped[name]; ok {
 			outcomes = append(outcomes, phaseOutcome{
 				index:  i,
 				name:   name,
 				typ:    phases[i].Type,
 				runs:   0,
 				result: "skip",
+				group:  phases[i].Group,
 			})
 			continue
 		}
@@ -110,6 +115,7 @@ func RunSummary(phases []config.Phase, timing *state.Timing, failedPhase int, sk
 			runs:     runs,
 			duration: dur,
 			result:   result,
+			group:    phases[i].Group,
 		})
 	}
 
@@ -123,6 +129,14 @@ func RunSummary(phases []config.Phase, timing *state.Timing, failedPhase int, sk
 	if nameWidth > 20 {
 		nameWidth = 20
 	}
+	// A group that collapses to one line shows its label in the name column.
+	for j := 0; j < len(outcomes); {
+		k, failed := groupEnd(outcomes, j)
+		if outcomes[j].group != "" && !failed {
+			nameWidth = max(nameWidth, len(groupLabel(outcomes[j].group, k-j)))
+		}
+		j = k
+	}
 
 	displayCount := len(outcomes)
 
@@ -136,8 +150,9 @@ func RunSummary(phases []config.Phase, timing *state.Timing, failedPhase int, sk
 	// Table header
 	fmt.Printf("\n  %-3s %-*s %-8s %5s %10s  %s\n", "#", nameWidth, "Phase", "Type", "Runs", "Duration", "Result")
 
-	// Table rows
-	for _, o := range outcomes {
+	// Table rows. Consecutive phases of a group that didn't fail collapse
+	// into one summary line; a failing group is expanded under its name.
+	printRow := func(o phaseOutcome) {
 		num := fmt.Sprintf("%d", o.index+1)
 		var runsStr, durStr, resultStr string
 
@@ -161,6 +176,25 @@ func RunSummary(phases []config.Phase, timing *state.Timing, failedPhase int, sk
 
 		fmt.Printf("  %-3s %-*s %-8s %5s %10s  %s\n", num, nameWidth, o.name, o.typ, runsStr, durStr, resultStr)
 	}
+	for j := 0; j < len(outcomes); {
+		group := outcomes[j].group
+		if group == "" {
+			printRow(outcomes[j])
+			j++
+			continue
+		}
+		k, failed := groupEnd(outcomes, j)
+		members := outcomes[j:k]
+		if failed {
+			fmt.Printf("  %s%s%s\n", Bold, group, Reset)
+			for _, o := range members {
+				printRow(o)
+			}
+		} else {
+			printGroupSummary(group, members, nameWidth)
+		}
+		j = k
+	}
 
 	// Totals
 	fmt.Println()
@@ -172,3 +206,80 @@ func RunSummary(phases []config.Phase, timing *state.Timing, failedPhase int, sk
 	}
 	fmt.Println()
 }
+
+// groupEnd returns the end of the run of outcomes starting at j that share
+// outcomes[j]'s group (just j+1 when it is ungrouped), and whether any of
+// them failed.
+func groupEnd(outcomes []phaseOutcome, j int) (k int, failed bool) {
+	group := outcomes[j].group
+	k = j
+	for k < len(outcomes) && (k == j || group != "" && outcomes[k].group == group) {
+		failed = failed || outcomes[k].result == "FAIL"
+		k++
+	}
+	return k, failed
+}
+
+// groupLabel is the name shown for a collapsed group of n phases.
+func groupLabel(group string, n int) string {
+	noun := "phases"
+	if n == 1 {
+		noun = "phase"
+	}
+	return fmt.Sprintf("%s (%d %s)", group, n, noun)
+}
+
+// printGroupSummary prints the single collapsed line for a group whose
+// phases all passed or were skipped. A group whose phases were all skipped
+// is shown as skipped.
+func printGroupSummary(group string, members []phaseOutcome, nameWidth int) {
+	var runs int
+	var dur time.Duration
+	allSkipped := true
+	for _, o := range members {
+		runs += o.runs
+		dur += o.duration
+		allSkipped = allSkipped && o.result == "skip"
+	}
+	num := fmt.Sprintf("%d", members[0].index+1)
+	if last := members[len(members)-1].index; last != members[0].index {
+		num = fmt.Sprintf("%d-%d", members[0].index+1, last+1)
+	}
+	label := groupLabel(group, len(members))
+	if allSkipped {
+		fmt.Printf("  %-3s %-*s %-8s %5s %10s  %sskip%s\n", num, nameWidth, label, "group", "—", "—", Dim, Reset)
+		return
+	}
+	fmt.Printf("  %-3s %-*s %-8s %5d %10s  %s✓ pass%s\n", num, nameWidth, label, "group", runs, fmtDuration(dur), Green, Reset)
+}
+
+// ProducedArtifact is a declared output that exists at the end of a run.
+type ProducedArtifact struct {
+	Phase string
+	Path  string // where the file can be found, as it should be shown
+}
+
+// CompletionBanner prints the outputs a successful run produced and the
+// commands to look at it next, including orc doctor when the run printed
+// warnings.
+func CompletionBanner(ticket string, artifacts []ProducedArtifact, warned bool) {
+	if QuietMode {
+		return
+	}
+	if len(artifacts) > 0 {
+		fmt.Printf("  %sProduced artifacts:%s\n", Bold, Reset)
+		phaseWidth := 0
+		for _, a := range artifacts {
+			phaseWidth = max(phaseWidth, len(a.Phase))
+		}
+		for _, a := range artifacts {
+			fmt.Printf("    %s%-*s%s  %s\n", Dim, phaseWidth, a.Phase, Reset, a.Path)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("  %sNext:%s orc status %s   %s# phase results%s\n", Cyan, Reset, ticket, Dim, Reset)
+	fmt.Printf("        orc history %s  %s# archived runs%s\n", ticket, Dim, Reset)
+	if warned {
+		fmt.Printf("        orc doctor %s   %s# the run printed warnings%s\n", ticket, Dim, Reset)
+	}
+}
@@ -151,6 +151,74 @@ func TestRunSummary_AllPass(t *testing.T) {
 	}
 }
 
+func TestRunSummary_Groups(t *testing.T) {
+	phases := []config.Phase{
+		{Name: "fetch", Type: "script", Group: "Setup"},
+		{Name: "deps", Type: "script", Group: "Setup"},
+		{Name: "env", Type: "script", Group: "Setup"},
+		{Name: "unit", Type: "script", Group: "Checks"},
+		{Name: "lint", Type: "script", Group: "Checks"},
+	}
+	now := time.Now()
+	var entries []state.TimingEntry
+	for _, p := range phases {
+		entries = append(entries, state.TimingEntry{Phase: p.Name, Start: now, End: now.Add(10 * time.Second)})
+	}
+
+	output := captureOutput(func() {
+		RunSummary(phases, makeTiming(entries), 4, nil)
+	})
+
+	var setupLines []string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "Setup") || strings.Contains(line, "fetch") || strings.Contains(line, "deps") {
+			setupLines = append(setupLines, line)
+		}
+	}
+	if len(setupLines) != 1 || !strings.Contains(setupLines[0], "Setup (3 phases)") || !strings.Contains(setupLines[0], "✓") || !strings.Contains(setupLines[0], "30s") {
+		t.Errorf("successful group should collapse to one summary line, got %q\nfull output:\n%s", setupLines, output)
+	}
+	for _, want := range []string{"Checks", "unit", "lint", "FAIL"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("failing group should be expanded, missing %q\nfull output:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Checks (") {
+		t.Errorf("failing group should not collapse:\n%s", output)
+	}
+}
+
+func TestRunSummary_GroupAllSkippedAndLabelWidth(t *testing.T) {
+	phases := []config.Phase{
+		{Name: "build", Type: "script"},
+		{Name: "a", Type: "script", Group: "Docs"},
+		{Name: "b", Type: "script", Group: "Docs"},
+	}
+	now := time.Now()
+	timing := makeTiming([]state.TimingEntry{{Phase: "build", Start: now, End: now.Add(5 * time.Second)}})
+
+	output := captureOutput(func() {
+		RunSummary(phases, timing, -1, map[string]string{"a": "condition not met", "b": "condition not met"})
+	})
+
+	var groupLine, buildLine string
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.Contains(line, "Docs (2 phases)"):
+			groupLine = line
+		case strings.Contains(line, "build"):
+			buildLine = line
+		}
+	}
+	if !strings.Contains(groupLine, "skip") || strings.Contains(groupLine, "pass") {
+		t.Errorf("all-skipped group should show skip, got %q", groupLine)
+	}
+	// The group label is wider than any phase name; the columns still line up.
+	if strings.Index(groupLine, "group") != strings.Index(buildLine, "script") {
+		t.Errorf("type column misaligned:\n%s\n%s", buildLine, groupLine)
+	}
+}
+
 func TestRunSummary_WithRetries(t *testing.T) {
 	phases := []config.Phase{
 		{Name: "plan", Type: "agent"},
@@ -275,7 +343,7 @@ func TestRunSummary_WithSkippedPhases(t *testing.T) {
 		{Phase: "test", Start: now, End: now.Add(5 * time.Second)},
 	})
 
-	skipped := map[string]bool{"optional-lint": true}
+	skipped := map[string]string{"optional-lint": "condition not met"}
 
 	output := captureOutput(func() {
 		RunSummary(phases, timing, -1, skipped)
@@ -429,3 +497,42 @@ func TestRunSummary_ColumnAlignment(t *testing.T) {
 		}
 	}
 }
+
+func TestCompletionBanner_ListsArtifacts(t *testing.T) {
+	output := captureOutput(func() {
+		CompletionBanner("KS-42", []ProducedArtifact{
+			{Phase: "plan", Path: ".orc/artifacts/KS-42/history/run-1/plan.md"},
+			{Phase: "review", Path: ".orc/artifacts/KS-42/history/run-1/review.md"},
+		}, false)
+	})
+	for _, want := range []string{
+		"Produced artifacts:",
+		".orc/artifacts/KS-42/history/run-1/plan.md",
+		".orc/artifacts/KS-42/history/run-1/review.md",
+		"orc status KS-42",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q\nfull output:\n%s", want, output)
+		}
+	}
+}
+
+func TestCompletionBanner_NoArtifacts(t *testing.T) {
+	output := captureOutput(func() { CompletionBanner("KS-42", nil, false) })
+	if strings.Contains(output, "Produced artifacts") {
+		t.Fatalf("no artifacts section expected:\n%s", output)
+	}
+	if !strings.Contains(output, "orc status KS-42") {
+		t.Fatalf("next steps missing:\n%s", output)
+	}
+	if strings.Contains(output, "orc doctor") {
+		t.Fatalf("doctor hint without warnings:\n%s", output)
+	}
+}
+
+func TestCompletionBanner_DoctorHintWhenWarned(t *testing.T) {
+	output := captureOutput(func() { CompletionBanner("KS-42", nil, true) })
+	if !strings.Contains(output, "orc doctor KS-42") {
+		t.Fatalf("expected an orc doctor hint:\n%s", output)
+	}
+}
@@ -19,6 +19,8 @@ import (
 func SaveState(t testing.TB) {
 	t.Helper()
 	origQuiet := ux.QuietMode
+	origCompact := ux.CompactMode
+	origRawStream := ux.RawStream
 	origReset := ux.Reset
 	origBold := ux.Bold
 	origDim := ux.Dim
@@ -34,6 +36,8 @@ func SaveState(t testing.TB) {
 	origIsTerminal := ux.IsTerminal
 	t.Cleanup(func() {
 		ux.QuietMode = origQuiet
+		ux.CompactMode = origCompact
+		ux.RawStream = origRawStream
 		ux.Reset = origReset
 		ux.Bold = origBold
 		ux.Dim = origDim